@@ -2,29 +2,260 @@
 package apikey
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/jmcarbo/fullmcp/auth"
 )
 
-// Provider implements API key authentication
+// prefixLength is the number of leading characters of a key kept in clear
+// to identify it
+const prefixLength = 8
+
+// hashScheme tags the encoding produced by HashKey
+const hashScheme = "sha256"
+
+// Provider implements API key authentication. Keys are stored as salted
+// SHA-256 hashes indexed by their prefix, so the provider never retains a
+// raw key, and are compared in constant time.
 type Provider struct {
-	keys map[string]auth.Claims
+	mu      sync.RWMutex
+	keys    map[string][]*storedKey // By key prefix
+	limiter *auth.RateLimiter       // Per-subject limits; nil when unlimited
+}
+
+// Option configures the provider
+type Option func(*Provider)
+
+// WithDefaultLimits applies a request rate and daily quota to every key
+// without limits of its own
+func WithDefaultLimits(limits auth.Limits) Option {
+	return func(p *Provider) {
+		p.limiter = auth.NewRateLimiter(limits)
+	}
+}
+
+// storedKey is a salted hash of an API key with its claims
+type storedKey struct {
+	prefix string
+	salt   []byte
+	hash   []byte
+	claims auth.Claims
+}
+
+// limitKey identifies the budget the key's requests count against: its
+// claims' subject, or the key itself when it has none
+func (k *storedKey) limitKey() string {
+	if k.claims.Subject != "" {
+		return k.claims.Subject
+	}
+	return hashScheme + ":" + hex.EncodeToString(k.hash)
 }
 
 // New creates a new API key provider
-func New() *Provider {
-	return &Provider{
-		keys: make(map[string]auth.Claims),
+func New(opts ...Option) *Provider {
+	p := &Provider{
+		keys: make(map[string][]*storedKey),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// SetLimits sets the request rate and daily quota of the keys whose claims
+// have the given subject; they share one budget. The middleware rejects
+// requests over the limits with 429.
+func (p *Provider) SetLimits(subject string, limits auth.Limits) {
+	p.mu.Lock()
+	if p.limiter == nil {
+		p.limiter = auth.NewRateLimiter(auth.Limits{})
 	}
+	limiter := p.limiter
+	p.mu.Unlock()
+
+	limiter.SetLimits(subject, limits)
 }
 
-// AddKey adds an API key with associated claims
+// Prefix returns the leading part of an API key used to identify it in logs
+// and claims without revealing the key. Keys shorter than twice the prefix
+// length only keep half their characters.
+func Prefix(apiKey string) string {
+	n := prefixLength
+	if len(apiKey) < 2*prefixLength {
+		n = len(apiKey) / 2
+	}
+	return apiKey[:n]
+}
+
+// HashKey returns the salted hash of an API key in the form accepted by
+// AddHashedKey, for storing keys in configuration files
+func HashKey(apiKey string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+	return strings.Join([]string{
+		hashScheme,
+		Prefix(apiKey),
+		hex.EncodeToString(salt),
+		hex.EncodeToString(hashWithSalt(salt, apiKey)),
+	}, ":"), nil
+}
+
+// AddKey adds an API key with associated claims, replacing the claims of
+// the key if it is already registered. Only a salted hash of the key is
+// kept.
 func (p *Provider) AddKey(apiKey string, claims auth.Claims) {
-	p.keys[apiKey] = claims
+	salt := make([]byte, 16)
+	_, _ = rand.Read(salt)
+	key := &storedKey{
+		prefix: Prefix(apiKey),
+		salt:   salt,
+		hash:   hashWithSalt(salt, apiKey),
+		claims: claims,
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys[key.prefix] = append(p.without(apiKey), key)
+}
+
+// AddHashedKey adds a key hashed with HashKey, so the raw key never has to
+// be in the server's configuration. Adding the same hashed key again, or its
+// raw key with AddKey, replaces the claims of the key.
+func (p *Provider) AddHashedKey(hashed string, claims auth.Claims) error {
+	// The prefix may itself contain colons, so fields are taken from the ends
+	scheme, rest, ok := strings.Cut(hashed, ":")
+	if !ok || scheme != hashScheme {
+		return fmt.Errorf("invalid hashed key: expected %s:<prefix>:<salt>:<hash>", hashScheme)
+	}
+	hashStart := strings.LastIndex(rest, ":")
+	if hashStart < 0 {
+		return fmt.Errorf("invalid hashed key: missing hash")
+	}
+	saltStart := strings.LastIndex(rest[:hashStart], ":")
+	if saltStart < 0 {
+		return fmt.Errorf("invalid hashed key: missing salt")
+	}
+
+	salt, err := hex.DecodeString(rest[saltStart+1 : hashStart])
+	if err != nil || len(salt) == 0 {
+		return fmt.Errorf("invalid hashed key: bad salt")
+	}
+	hash, err := hex.DecodeString(rest[hashStart+1:])
+	if err != nil || len(hash) != sha256.Size {
+		return fmt.Errorf("invalid hashed key: bad hash")
+	}
+
+	key := &storedKey{prefix: rest[:saltStart], salt: salt, hash: hash, claims: claims}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys[key.prefix] = append(p.withoutHashed(key), key)
+	return nil
+}
+
+// RemoveKey removes an API key and reports whether it was registered
+func (p *Provider) RemoveKey(apiKey string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	prefix := Prefix(apiKey)
+	keys := p.without(apiKey)
+	removed := len(keys) < len(p.keys[prefix])
+	if len(keys) == 0 {
+		delete(p.keys, prefix)
+	} else {
+		p.keys[prefix] = keys
+	}
+	return removed
+}
+
+// without returns the keys stored under apiKey's prefix other than those
+// matching apiKey. The caller must hold p.mu.
+func (p *Provider) without(apiKey string) []*storedKey {
+	candidates := p.keys[Prefix(apiKey)]
+	keys := make([]*storedKey, 0, len(candidates))
+	for _, key := range candidates {
+		if subtle.ConstantTimeCompare(hashWithSalt(key.salt, apiKey), key.hash) != 1 {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// withoutHashed returns the keys stored under key's prefix other than those
+// with its salt and hash. The caller must hold p.mu.
+func (p *Provider) withoutHashed(key *storedKey) []*storedKey {
+	candidates := p.keys[key.prefix]
+	keys := make([]*storedKey, 0, len(candidates))
+	for _, k := range candidates {
+		if !bytes.Equal(k.salt, key.salt) || subtle.ConstantTimeCompare(k.hash, key.hash) != 1 {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// RemovePrefix removes the key with the given prefix, for revoking a key
+// known only by its prefix. Prefixes shared by several keys, such as a
+// vendor prefix, are rejected rather than revoking them all.
+func (p *Provider) RemovePrefix(prefix string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch n := len(p.keys[prefix]); n {
+	case 0:
+		return fmt.Errorf("no API key with prefix %q", prefix)
+	case 1:
+		delete(p.keys, prefix)
+		return nil
+	default:
+		return fmt.Errorf("prefix %q is shared by %d API keys", prefix, n)
+	}
+}
+
+// Prefixes returns the prefixes of the registered keys, sorted
+func (p *Provider) Prefixes() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	prefixes := make([]string, 0, len(p.keys))
+	for prefix := range p.keys {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	return prefixes
+}
+
+// lookup finds the stored key matching apiKey
+func (p *Provider) lookup(apiKey string) (*storedKey, bool) {
+	p.mu.RLock()
+	candidates := p.keys[Prefix(apiKey)]
+	p.mu.RUnlock()
+
+	var match *storedKey
+	for _, key := range candidates {
+		// Check every candidate so timing doesn't reveal which one matched
+		if subtle.ConstantTimeCompare(hashWithSalt(key.salt, apiKey), key.hash) == 1 {
+			match = key
+		}
+	}
+	return match, match != nil
+}
+
+func hashWithSalt(salt []byte, apiKey string) []byte {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(apiKey))
+	return h.Sum(nil)
 }
 
 // Authenticate validates an API key
@@ -34,23 +265,53 @@ func (p *Provider) Authenticate(_ context.Context, credentials interface{}) (str
 		return "", fmt.Errorf("invalid credentials type")
 	}
 
-	if _, exists := p.keys[apiKey]; !exists {
+	if _, exists := p.lookup(apiKey); !exists {
 		return "", fmt.Errorf("invalid API key")
 	}
 
 	return apiKey, nil
 }
 
-// ValidateToken validates an API key token
+// ValidateToken validates an API key token. The key's prefix is added to
+// the claims' Extra as "key_prefix".
 func (p *Provider) ValidateToken(_ context.Context, token string) (auth.Claims, error) {
-	claims, exists := p.keys[token]
+	key, exists := p.lookup(token)
 	if !exists {
 		return auth.Claims{}, fmt.Errorf("invalid API key")
 	}
 
+	claims := key.claims
+	extra := make(map[string]interface{}, len(claims.Extra)+1)
+	for k, v := range claims.Extra {
+		extra[k] = v
+	}
+	extra["key_prefix"] = key.prefix
+	claims.Extra = extra
 	return claims, nil
 }
 
+// AcceptsAPIKeys reports that the provider validates API keys, so
+// auth.Chain sends it the X-API-Key header
+func (p *Provider) AcceptsAPIKeys() bool {
+	return true
+}
+
+// Allow records a request made with apiKey against its subject's limits,
+// returning an *auth.LimitError when they are exceeded
+func (p *Provider) Allow(apiKey string) error {
+	p.mu.RLock()
+	limiter := p.limiter
+	p.mu.RUnlock()
+	if limiter == nil {
+		return nil
+	}
+	key, exists := p.lookup(apiKey)
+	if !exists {
+		return nil
+	}
+	return limiter.Allow(key.limitKey())
+}
+
 // Middleware returns HTTP middleware for API key authentication
 func (p *Provider) Middleware() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -67,6 +328,11 @@ func (p *Provider) Middleware() func(http.Handler) http.Handler {
 				return
 			}
 
+			if err := p.Allow(token); err != nil {
+				auth.WriteLimitError(w, err.(*auth.LimitError))
+				return
+			}
+
 			ctx := auth.WithClaims(r.Context(), claims)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/jmcarbo/fullmcp/auth"
@@ -262,3 +263,205 @@ func TestExtractAPIKey_InvalidBearer(t *testing.T) {
 		t.Errorf("expected empty string for invalid scheme, got '%s'", key)
 	}
 }
+
+func TestProvider_StoresOnlyHashes(t *testing.T) {
+	provider := New()
+	provider.AddKey("mcp_live_0123456789abcdef", auth.Claims{Subject: "user-1"})
+
+	for prefix, keys := range provider.keys {
+		if prefix != "mcp_live" {
+			t.Errorf("expected prefix 'mcp_live', got %q", prefix)
+		}
+		for _, key := range keys {
+			if strings.Contains(string(key.hash), "0123456789abcdef") {
+				t.Error("raw key material stored in the provider")
+			}
+		}
+	}
+
+	claims, err := provider.ValidateToken(context.Background(), "mcp_live_0123456789abcdef")
+	if err != nil {
+		t.Fatalf("validation failed: %v", err)
+	}
+	if claims.Extra["key_prefix"] != "mcp_live" {
+		t.Errorf("expected key_prefix claim, got %v", claims.Extra)
+	}
+
+	if _, err := provider.ValidateToken(context.Background(), "mcp_live_0123456789abcdee"); err == nil {
+		t.Error("expected error for a key sharing the prefix")
+	}
+}
+
+func TestProvider_AddHashedKey(t *testing.T) {
+	hashed, err := HashKey("svc:prod:s3cr3t-value-xyz")
+	if err != nil {
+		t.Fatalf("hash failed: %v", err)
+	}
+	if strings.Contains(hashed, "s3cr3t") {
+		t.Errorf("hashed key exposes the secret: %s", hashed)
+	}
+
+	provider := New()
+	if err := provider.AddHashedKey(hashed, auth.Claims{Subject: "svc"}); err != nil {
+		t.Fatalf("AddHashedKey failed: %v", err)
+	}
+
+	claims, err := provider.ValidateToken(context.Background(), "svc:prod:s3cr3t-value-xyz")
+	if err != nil {
+		t.Fatalf("validation failed: %v", err)
+	}
+	if claims.Subject != "svc" {
+		t.Errorf("expected subject 'svc', got %q", claims.Subject)
+	}
+	if got := provider.Prefixes(); len(got) != 1 || got[0] != "svc:prod" {
+		t.Errorf("expected prefix 'svc:prod', got %v", got)
+	}
+}
+
+func TestProvider_AddHashedKey_Replaces(t *testing.T) {
+	hashed, err := HashKey("rotating-key-0002")
+	if err != nil {
+		t.Fatalf("hash failed: %v", err)
+	}
+
+	provider := New()
+	_ = provider.AddHashedKey(hashed, auth.Claims{Subject: "admin"})
+	_ = provider.AddHashedKey(hashed, auth.Claims{Subject: "reader"})
+
+	claims, err := provider.ValidateToken(context.Background(), "rotating-key-0002")
+	if err != nil {
+		t.Fatalf("validation failed: %v", err)
+	}
+	if claims.Subject != "reader" {
+		t.Errorf("expected the re-added claims, got subject %q", claims.Subject)
+	}
+
+	if !provider.RemoveKey("rotating-key-0002") {
+		t.Fatal("expected key to be removed")
+	}
+	if _, err := provider.ValidateToken(context.Background(), "rotating-key-0002"); err == nil {
+		t.Error("expected the revoked key to be rejected")
+	}
+}
+
+func TestProvider_AddHashedKey_Invalid(t *testing.T) {
+	provider := New()
+	for _, hashed := range []string{
+		"plain-key",
+		"md5:abc:00:00",
+		"sha256:abc:zz:" + strings.Repeat("00", 32),
+		"sha256:abc:00:00",
+	} {
+		if err := provider.AddHashedKey(hashed, auth.Claims{}); err == nil {
+			t.Errorf("expected error for %q", hashed)
+		}
+	}
+}
+
+func TestProvider_RemoveKey(t *testing.T) {
+	provider := New()
+	provider.AddKey("team-a-key-000000", auth.Claims{Subject: "a"})
+	provider.AddKey("team-a-key-111111", auth.Claims{Subject: "b"})
+
+	if !provider.RemoveKey("team-a-key-000000") {
+		t.Fatal("expected key to be removed")
+	}
+	if provider.RemoveKey("team-a-key-000000") {
+		t.Error("expected second removal to report false")
+	}
+	if _, err := provider.ValidateToken(context.Background(), "team-a-key-000000"); err == nil {
+		t.Error("expected removed key to be rejected")
+	}
+	if _, err := provider.ValidateToken(context.Background(), "team-a-key-111111"); err != nil {
+		t.Errorf("expected key with the same prefix to remain: %v", err)
+	}
+
+	if err := provider.RemovePrefix("team-a-k"); err != nil {
+		t.Fatalf("expected prefix to be removed: %v", err)
+	}
+	if _, err := provider.ValidateToken(context.Background(), "team-a-key-111111"); err == nil {
+		t.Error("expected keys with the removed prefix to be rejected")
+	}
+}
+
+func TestProvider_AddKey_Replaces(t *testing.T) {
+	provider := New()
+	provider.AddKey("rotating-key-0001", auth.Claims{Subject: "admin", Scopes: []string{"admin"}})
+	provider.AddKey("rotating-key-0001", auth.Claims{Subject: "reader", Scopes: []string{"read"}})
+
+	claims, err := provider.ValidateToken(context.Background(), "rotating-key-0001")
+	if err != nil {
+		t.Fatalf("validation failed: %v", err)
+	}
+	if claims.Subject != "reader" {
+		t.Errorf("expected the re-added claims, got subject %q", claims.Subject)
+	}
+
+	if !provider.RemoveKey("rotating-key-0001") {
+		t.Fatal("expected key to be removed")
+	}
+	if _, err := provider.ValidateToken(context.Background(), "rotating-key-0001"); err == nil {
+		t.Error("expected the revoked key to be rejected")
+	}
+}
+
+func TestProvider_Middleware_Limits(t *testing.T) {
+	provider := New(WithDefaultLimits(auth.Limits{DailyQuota: 5}))
+	provider.AddKey("limited-key-0001", auth.Claims{Subject: "a"})
+	provider.AddKey("default-key-0002", auth.Claims{Subject: "b"})
+	provider.SetLimits("a", auth.Limits{DailyQuota: 1})
+
+	handler := provider.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	request := func(key string) int {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.Header.Set("X-API-Key", key)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := request("limited-key-0001"); code != http.StatusOK {
+		t.Fatalf("first request: status %d", code)
+	}
+	if code := request("limited-key-0001"); code != http.StatusTooManyRequests {
+		t.Errorf("expected 429 over quota, got %d", code)
+	}
+	for i := 0; i < 5; i++ {
+		if code := request("default-key-0002"); code != http.StatusOK {
+			t.Fatalf("request %d with default limits: status %d", i, code)
+		}
+	}
+	if code := request("default-key-0002"); code != http.StatusTooManyRequests {
+		t.Errorf("expected 429 over the default quota, got %d", code)
+	}
+}
+
+func TestProvider_Limits_SharedPrefix(t *testing.T) {
+	provider := New(WithDefaultLimits(auth.Limits{DailyQuota: 1}))
+	provider.AddKey("mcp_live_0000000000000001", auth.Claims{Subject: "a"})
+	provider.AddKey("mcp_live_0000000000000002", auth.Claims{Subject: "b"})
+	provider.AddKey("mcp_live_0000000000000003", auth.Claims{})
+	provider.SetLimits("a", auth.Limits{DailyQuota: 2})
+
+	// Keys sharing a vendor prefix have budgets of their own
+	for _, key := range []string{"mcp_live_0000000000000001", "mcp_live_0000000000000002", "mcp_live_0000000000000003"} {
+		if err := provider.Allow(key); err != nil {
+			t.Errorf("first request with %s: %v", key, err)
+		}
+	}
+	if err := provider.Allow("mcp_live_0000000000000001"); err != nil {
+		t.Errorf("expected the raised quota to apply to its subject only: %v", err)
+	}
+	if err := provider.Allow("mcp_live_0000000000000002"); err == nil {
+		t.Error("expected the default quota to apply")
+	}
+
+	if err := provider.RemovePrefix("mcp_live"); err == nil {
+		t.Error("expected a shared prefix to be rejected")
+	}
+	if _, err := provider.ValidateToken(context.Background(), "mcp_live_0000000000000002"); err != nil {
+		t.Errorf("expected keys to survive a rejected prefix removal: %v", err)
+	}
+}
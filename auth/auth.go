@@ -3,6 +3,8 @@ package auth
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 )
 
@@ -26,6 +28,29 @@ type Claims struct {
 	Extra   map[string]interface{}
 }
 
+// HasScope reports whether the claims grant scope
+func (c Claims) HasScope(scope string) bool {
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrUnauthenticated is returned when a request carries no claims
+var ErrUnauthenticated = errors.New("authentication required")
+
+// ScopeError reports that the authenticated caller lacks a required scope
+type ScopeError struct {
+	Subject string
+	Scope   string
+}
+
+func (e *ScopeError) Error() string {
+	return fmt.Sprintf("permission denied: scope %q required", e.Scope)
+}
+
 // contextKey is the type for context keys
 type contextKey string
 
@@ -41,3 +66,26 @@ func GetClaims(ctx context.Context) (Claims, bool) {
 	claims, ok := ctx.Value(claimsContextKey).(Claims)
 	return claims, ok
 }
+
+// SubjectFromContext returns the subject of the claims in the context
+func SubjectFromContext(ctx context.Context) (string, bool) {
+	claims, ok := GetClaims(ctx)
+	if !ok {
+		return "", false
+	}
+	return claims.Subject, true
+}
+
+// RequireScope checks that the caller was authenticated with scope,
+// returning ErrUnauthenticated or a *ScopeError otherwise. Tool handlers can
+// return the error as is.
+func RequireScope(ctx context.Context, scope string) error {
+	claims, ok := GetClaims(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	if !claims.HasScope(scope) {
+		return &ScopeError{Subject: claims.Subject, Scope: scope}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"testing"
 )
 
@@ -103,3 +104,30 @@ func TestClaims_NilExtra(t *testing.T) {
 		t.Error("expected Extra to be nil")
 	}
 }
+
+func TestSubjectFromContext(t *testing.T) {
+	if _, ok := SubjectFromContext(context.Background()); ok {
+		t.Error("expected no subject without claims")
+	}
+
+	ctx := WithClaims(context.Background(), Claims{Subject: "user-1"})
+	subject, ok := SubjectFromContext(ctx)
+	if !ok || subject != "user-1" {
+		t.Errorf("expected subject 'user-1', got %q", subject)
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	if err := RequireScope(context.Background(), "write"); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("expected ErrUnauthenticated, got %v", err)
+	}
+
+	ctx := WithClaims(context.Background(), Claims{Subject: "user-1", Scopes: []string{"read"}})
+	var scopeErr *ScopeError
+	if err := RequireScope(ctx, "write"); !errors.As(err, &scopeErr) || scopeErr.Scope != "write" {
+		t.Errorf("expected ScopeError for 'write', got %v", err)
+	}
+	if err := RequireScope(ctx, "read"); err != nil {
+		t.Errorf("expected 'read' to be granted, got %v", err)
+	}
+}
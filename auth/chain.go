@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// RequestLimiter is implemented by providers that limit the requests made
+// with each credential, such as the API key provider
+type RequestLimiter interface {
+	// Allow records a request made with token, returning a *LimitError
+	// when its limits are exceeded
+	Allow(token string) error
+}
+
+// APIKeyProvider is implemented by providers validating API keys, such as
+// the apikey package's provider
+type APIKeyProvider interface {
+	Provider
+
+	// AcceptsAPIKeys reports whether the provider validates API keys
+	AcceptsAPIKeys() bool
+}
+
+// Chain returns HTTP middleware accepting credentials for any of the
+// providers, tried in order, and attaching the claims of the first that
+// validates to the request, so one endpoint can accept API keys, JWTs and
+// OAuth tokens. The X-API-Key header is only sent to providers that accept
+// API keys and the Bearer token only to the others, so an API key never
+// reaches a remote token endpoint. Providers implementing RequestLimiter
+// have their limits enforced.
+func Chain(providers ...Provider) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			apiKey := r.Header.Get("X-API-Key")
+			bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok {
+				// Other schemes, such as Basic, are not tokens
+				bearer = ""
+			}
+			if apiKey == "" && bearer == "" {
+				http.Error(w, "unauthorized: missing credentials", http.StatusUnauthorized)
+				return
+			}
+
+			for _, provider := range providers {
+				token := bearer
+				if acceptsAPIKeys(provider) {
+					token = apiKey
+				}
+				if token == "" {
+					continue
+				}
+
+				claims, err := provider.ValidateToken(r.Context(), token)
+				if err != nil {
+					continue
+				}
+
+				if limiter, ok := provider.(RequestLimiter); ok {
+					var limitErr *LimitError
+					if err := limiter.Allow(token); errors.As(err, &limitErr) {
+						WriteLimitError(w, limitErr)
+						return
+					}
+				}
+
+				next.ServeHTTP(w, r.WithContext(WithClaims(r.Context(), claims)))
+				return
+			}
+
+			http.Error(w, "unauthorized: invalid credentials", http.StatusUnauthorized)
+		})
+	}
+}
+
+// acceptsAPIKeys reports whether provider should be sent the X-API-Key
+// header rather than the Bearer token
+func acceptsAPIKeys(provider Provider) bool {
+	p, ok := provider.(APIKeyProvider)
+	return ok && p.AcceptsAPIKeys()
+}
@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// fakeProvider accepts a fixed set of tokens
+type fakeProvider struct {
+	tokens  map[string]string // Token to subject
+	limited bool
+	calls   []string
+}
+
+func (p *fakeProvider) Authenticate(context.Context, interface{}) (string, error) {
+	return "", fmt.Errorf("not supported")
+}
+
+func (p *fakeProvider) Middleware() func(http.Handler) http.Handler {
+	return Chain(p)
+}
+
+func (p *fakeProvider) ValidateToken(_ context.Context, token string) (Claims, error) {
+	p.calls = append(p.calls, token)
+	subject, ok := p.tokens[token]
+	if !ok {
+		return Claims{}, fmt.Errorf("invalid token")
+	}
+	return Claims{Subject: subject}, nil
+}
+
+// fakeAPIKeyProvider accepts a fixed set of API keys
+type fakeAPIKeyProvider struct {
+	fakeProvider
+}
+
+func (p *fakeAPIKeyProvider) AcceptsAPIKeys() bool {
+	return true
+}
+
+// limitedProvider rejects every request over its limits
+type limitedProvider struct {
+	fakeAPIKeyProvider
+}
+
+func (p *limitedProvider) Allow(string) error {
+	return &LimitError{Reason: ReasonRateLimited, Limit: 1, ResetAt: time.Now().Add(time.Second)}
+}
+
+func TestChain(t *testing.T) {
+	apiKeys := &fakeAPIKeyProvider{fakeProvider{tokens: map[string]string{"key-1": "key-user"}}}
+	jwts := &fakeProvider{tokens: map[string]string{"jwt-1": "jwt-user"}}
+
+	var subject string
+	handler := Chain(apiKeys, jwts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := GetClaims(r.Context())
+		subject = claims.Subject
+	}))
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		status  int
+		subject string
+	}{
+		{"api key header", map[string]string{"X-API-Key": "key-1"}, http.StatusOK, "key-user"},
+		{"bearer api key", map[string]string{"Authorization": "Bearer key-1"}, http.StatusUnauthorized, ""},
+		{"api key header jwt", map[string]string{"X-API-Key": "jwt-1"}, http.StatusUnauthorized, ""},
+		{"bearer jwt", map[string]string{"Authorization": "Bearer jwt-1"}, http.StatusOK, "jwt-user"},
+		{"invalid key falls back to bearer", map[string]string{"X-API-Key": "bad", "Authorization": "Bearer jwt-1"}, http.StatusOK, "jwt-user"},
+		{"invalid", map[string]string{"Authorization": "Bearer bad"}, http.StatusUnauthorized, ""},
+		{"missing", nil, http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subject = ""
+			req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if subject != tt.subject {
+				t.Errorf("subject = %q, want %q", subject, tt.subject)
+			}
+		})
+	}
+}
+
+func TestChain_StopsAtFirstMatch(t *testing.T) {
+	first := &fakeProvider{tokens: map[string]string{"token": "first"}}
+	second := &fakeProvider{tokens: map[string]string{"token": "second"}}
+
+	handler := Chain(first, second)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if len(second.calls) != 0 {
+		t.Errorf("later providers should not be tried after a match, got %v", second.calls)
+	}
+}
+
+func TestChain_EnforcesProviderLimits(t *testing.T) {
+	limited := &limitedProvider{fakeAPIKeyProvider{fakeProvider{tokens: map[string]string{"key-1": "user"}}}}
+
+	handler := Chain(limited)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Error("handler should not run over the limit")
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set("X-API-Key", "key-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("expected 429, got %d", rec.Code)
+	}
+}
+
+func TestChain_RoutesCredentials(t *testing.T) {
+	apiKeys := &fakeAPIKeyProvider{}
+	oauth := &fakeProvider{}
+
+	handler := Chain(apiKeys, oauth)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set("X-API-Key", "mistyped-key")
+	req.Header.Set("Authorization", "Bearer opaque-token")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if len(apiKeys.calls) != 1 || apiKeys.calls[0] != "mistyped-key" {
+		t.Errorf("expected the API key provider to get only the API key, got %v", apiKeys.calls)
+	}
+	if len(oauth.calls) != 1 || oauth.calls[0] != "opaque-token" {
+		t.Errorf("expected the token provider to get only the Bearer token, got %v", oauth.calls)
+	}
+}
+
+func TestChain_IgnoresOtherSchemes(t *testing.T) {
+	oauth := &fakeProvider{}
+
+	handler := Chain(oauth)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+	if len(oauth.calls) != 0 {
+		t.Errorf("expected non-Bearer credentials not to reach providers, got %v", oauth.calls)
+	}
+}
@@ -19,6 +19,8 @@ type Provider struct {
 	signingMethod jwt.SigningMethod
 	issuer        string
 	expiration    time.Duration
+	verifyKey     interface{} // Public key for RSA and EC tokens
+	audience      string      // Required aud claim, if set
 }
 
 // Option configures the JWT provider
@@ -61,6 +63,22 @@ func WithExpiration(expiration time.Duration) Option {
 	}
 }
 
+// WithVerificationKey validates tokens signed with an asymmetric key, such
+// as the *rsa.PublicKey or *ecdsa.PublicKey matching a Signer's private key.
+// The signing method must be set to match.
+func WithVerificationKey(key interface{}) Option {
+	return func(p *Provider) {
+		p.verifyKey = key
+	}
+}
+
+// WithAudience only accepts tokens whose aud claim includes audience
+func WithAudience(audience string) Option {
+	return func(p *Provider) {
+		p.audience = audience
+	}
+}
+
 // GenerateRandomKey generates a random signing key
 func GenerateRandomKey(size int) ([]byte, error) {
 	key := make([]byte, size)
@@ -108,13 +126,21 @@ func (p *Provider) Authenticate(_ context.Context, credentials interface{}) (str
 
 // ValidateToken validates a JWT token and returns claims
 func (p *Provider) ValidateToken(_ context.Context, tokenString string) (auth.Claims, error) {
+	var parserOpts []jwt.ParserOption
+	if p.audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(p.audience))
+	}
+
 	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
 		// Verify signing method
 		if token.Method != p.signingMethod {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
+		if p.verifyKey != nil {
+			return p.verifyKey, nil
+		}
 		return p.signingKey, nil
-	})
+	}, parserOpts...)
 	if err != nil {
 		return auth.Claims{}, fmt.Errorf("failed to parse token: %w", err)
 	}
@@ -0,0 +1,152 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jmcarbo/fullmcp/auth"
+	"golang.org/x/oauth2"
+)
+
+// Signer mints tokens for authenticating to other MCP servers, such as a
+// fullmcp server acting as a client of another, without an external
+// identity provider. Tokens carry the same claims the Provider validates.
+type Signer struct {
+	method   jwt.SigningMethod
+	key      interface{}
+	issuer   string
+	audience []string
+	ttl      time.Duration
+	keyID    string
+}
+
+// SignerOption configures a Signer
+type SignerOption func(*Signer)
+
+// NewHMACSigner creates a signer using HS256 with a shared secret
+func NewHMACSigner(secret []byte, opts ...SignerOption) *Signer {
+	return newSigner(jwt.SigningMethodHS256, secret, opts)
+}
+
+// NewRSASigner creates a signer using RS256 with an RSA private key
+func NewRSASigner(key *rsa.PrivateKey, opts ...SignerOption) *Signer {
+	return newSigner(jwt.SigningMethodRS256, key, opts)
+}
+
+// NewECSigner creates a signer using ES256, ES384 or ES512 to match the
+// curve of the ECDSA private key
+func NewECSigner(key *ecdsa.PrivateKey, opts ...SignerOption) (*Signer, error) {
+	var method jwt.SigningMethod
+	switch key.Curve {
+	case elliptic.P256():
+		method = jwt.SigningMethodES256
+	case elliptic.P384():
+		method = jwt.SigningMethodES384
+	case elliptic.P521():
+		method = jwt.SigningMethodES512
+	default:
+		return nil, fmt.Errorf("unsupported curve %s", key.Curve.Params().Name)
+	}
+	return newSigner(method, key, opts), nil
+}
+
+func newSigner(method jwt.SigningMethod, key interface{}, opts []SignerOption) *Signer {
+	s := &Signer{
+		method: method,
+		key:    key,
+		issuer: "mcp-server",
+		ttl:    time.Hour,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// WithTokenIssuer sets the iss claim of minted tokens
+func WithTokenIssuer(issuer string) SignerOption {
+	return func(s *Signer) {
+		s.issuer = issuer
+	}
+}
+
+// WithTokenAudience sets the aud claim, naming the servers the token is for
+func WithTokenAudience(audience ...string) SignerOption {
+	return func(s *Signer) {
+		s.audience = audience
+	}
+}
+
+// WithTokenTTL sets how long minted tokens are valid
+func WithTokenTTL(ttl time.Duration) SignerOption {
+	return func(s *Signer) {
+		s.ttl = ttl
+	}
+}
+
+// WithKeyID sets the kid header, so verifiers can select the key
+func WithKeyID(keyID string) SignerOption {
+	return func(s *Signer) {
+		s.keyID = keyID
+	}
+}
+
+// Sign mints a token carrying claims
+func (s *Signer) Sign(claims auth.Claims) (string, error) {
+	token, _, err := s.sign(claims)
+	return token, err
+}
+
+func (s *Signer) sign(claims auth.Claims) (string, time.Time, error) {
+	now := time.Now()
+	expiry := now.Add(s.ttl)
+
+	jwtClaims := CustomClaims{
+		Subject: claims.Subject,
+		Email:   claims.Email,
+		Scopes:  claims.Scopes,
+		Extra:   claims.Extra,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.issuer,
+			Subject:   claims.Subject,
+			Audience:  s.audience,
+			ExpiresAt: jwt.NewNumericDate(expiry),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+	}
+
+	token := jwt.NewWithClaims(s.method, jwtClaims)
+	if s.keyID != "" {
+		token.Header["kid"] = s.keyID
+	}
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign token: %w", err)
+	}
+	return signed, expiry, nil
+}
+
+// TokenSource returns a token source minting tokens for claims and reusing
+// each until shortly before it expires. It plugs into the WithBearerToken
+// options of the HTTP, Streamable HTTP and WebSocket transports.
+func (s *Signer) TokenSource(claims auth.Claims) oauth2.TokenSource {
+	return oauth2.ReuseTokenSource(nil, &signerTokenSource{signer: s, claims: claims})
+}
+
+type signerTokenSource struct {
+	signer *Signer
+	claims auth.Claims
+}
+
+func (ts *signerTokenSource) Token() (*oauth2.Token, error) {
+	signed, expiry, err := ts.signer.sign(ts.claims)
+	if err != nil {
+		return nil, err
+	}
+	return &oauth2.Token{AccessToken: signed, TokenType: "Bearer", Expiry: expiry}, nil
+}
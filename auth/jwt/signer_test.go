@@ -0,0 +1,105 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jmcarbo/fullmcp/auth"
+)
+
+func TestSigner_HMAC(t *testing.T) {
+	secret := []byte("shared-secret-between-servers")
+	signer := NewHMACSigner(secret, WithTokenIssuer("gateway"), WithTokenTTL(time.Minute))
+
+	token, err := signer.Sign(auth.Claims{Subject: "gateway", Scopes: []string{"tools"}})
+	if err != nil {
+		t.Fatalf("sign failed: %v", err)
+	}
+
+	claims, err := New(secret).ValidateToken(context.Background(), token)
+	if err != nil {
+		t.Fatalf("validation failed: %v", err)
+	}
+	if claims.Subject != "gateway" || len(claims.Scopes) != 1 || claims.Scopes[0] != "tools" {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+}
+
+func TestSigner_RSA(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("key generation failed: %v", err)
+	}
+	signer := NewRSASigner(key, WithKeyID("key-1"), WithTokenAudience("backend"))
+
+	token, err := signer.Sign(auth.Claims{Subject: "gateway"})
+	if err != nil {
+		t.Fatalf("sign failed: %v", err)
+	}
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &CustomClaims{})
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if parsed.Header["kid"] != "key-1" || parsed.Method != jwt.SigningMethodRS256 {
+		t.Errorf("unexpected header: %v", parsed.Header)
+	}
+
+	provider := New(nil, WithSigningMethod(jwt.SigningMethodRS256), WithVerificationKey(&key.PublicKey), WithAudience("backend"))
+	if _, err := provider.ValidateToken(context.Background(), token); err != nil {
+		t.Errorf("validation failed: %v", err)
+	}
+
+	other := New(nil, WithSigningMethod(jwt.SigningMethodRS256), WithVerificationKey(&key.PublicKey), WithAudience("billing"))
+	if _, err := other.ValidateToken(context.Background(), token); err == nil {
+		t.Error("expected token for another audience to be rejected")
+	}
+}
+
+func TestSigner_EC(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("key generation failed: %v", err)
+	}
+	signer, err := NewECSigner(key)
+	if err != nil {
+		t.Fatalf("NewECSigner failed: %v", err)
+	}
+
+	token, err := signer.Sign(auth.Claims{Subject: "gateway"})
+	if err != nil {
+		t.Fatalf("sign failed: %v", err)
+	}
+
+	provider := New(nil, WithSigningMethod(jwt.SigningMethodES384), WithVerificationKey(&key.PublicKey))
+	if _, err := provider.ValidateToken(context.Background(), token); err != nil {
+		t.Errorf("validation failed: %v", err)
+	}
+}
+
+func TestSigner_TokenSource(t *testing.T) {
+	signer := NewHMACSigner([]byte("secret"), WithTokenTTL(time.Hour))
+	ts := signer.TokenSource(auth.Claims{Subject: "gateway"})
+
+	first, err := ts.Token()
+	if err != nil {
+		t.Fatalf("token failed: %v", err)
+	}
+	second, err := ts.Token()
+	if err != nil {
+		t.Fatalf("token failed: %v", err)
+	}
+
+	if first.AccessToken != second.AccessToken {
+		t.Error("expected the token to be reused until it expires")
+	}
+	if first.TokenType != "Bearer" || time.Until(first.Expiry) <= 0 {
+		t.Errorf("unexpected token: %+v", first)
+	}
+}
@@ -0,0 +1,132 @@
+package oauth21
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/auth"
+)
+
+// ErrInvalidToken is returned when the authorization server rejects an
+// access token, as opposed to failing to answer
+var ErrInvalidToken = errors.New("invalid access token")
+
+// WithTokenCache caches validated tokens for ttl, so ValidateToken doesn't
+// query the authorization server on every request. A revoked token stays
+// accepted until its entry expires.
+func WithTokenCache(ttl time.Duration) Option {
+	return func(p *Provider) {
+		p.cacheTTL = ttl
+	}
+}
+
+// WithNegativeCacheTTL caches rejected tokens for ttl, so repeated requests
+// with a bad token don't reach the authorization server. Only rejections
+// (ErrInvalidToken) are cached, not network or server failures. Requires
+// WithTokenCache.
+func WithNegativeCacheTTL(ttl time.Duration) Option {
+	return func(p *Provider) {
+		p.negativeCacheTTL = ttl
+	}
+}
+
+// tokenCache keeps validation results by token hash
+type tokenCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	now     func() time.Time
+}
+
+type cacheEntry struct {
+	claims  auth.Claims
+	err     error
+	expires time.Time
+}
+
+func newTokenCache() *tokenCache {
+	return &tokenCache{
+		entries: make(map[string]cacheEntry),
+		now:     time.Now,
+	}
+}
+
+func (c *tokenCache) get(key string) (auth.Claims, error, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return auth.Claims{}, nil, false
+	}
+	if !c.now().Before(entry.expires) {
+		delete(c.entries, key)
+		return auth.Claims{}, nil, false
+	}
+	return entry.claims, entry.err, true
+}
+
+// put stores a result until expires, dropping expired entries so tokens
+// seen once don't accumulate
+func (c *tokenCache) put(key string, claims auth.Claims, err error, expires time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for k, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	if now.Before(expires) {
+		c.entries[key] = cacheEntry{claims: claims, err: err, expires: expires}
+	}
+}
+
+// ValidateToken validates an access token through the introspection
+// endpoint, if configured, locally for JWTs of OIDC providers, or at the
+// user info endpoint, caching the result when WithTokenCache is set
+func (p *Provider) ValidateToken(ctx context.Context, accessToken string) (auth.Claims, error) {
+	if p.cache == nil {
+		claims, _, err := p.fetchClaims(ctx, accessToken)
+		return claims, err
+	}
+
+	key := hashToken(accessToken)
+	if claims, err, ok := p.cache.get(key); ok {
+		return claims, err
+	}
+
+	claims, expiry, err := p.fetchClaims(ctx, accessToken)
+	now := p.cache.now()
+	switch {
+	case err == nil:
+		expires := now.Add(p.cacheTTL)
+		// Never trust a token past its own expiry
+		if !expiry.IsZero() && expiry.Before(expires) {
+			expires = expiry
+		}
+		p.cache.put(key, claims, nil, expires)
+	case errors.Is(err, ErrInvalidToken) && p.negativeCacheTTL > 0:
+		p.cache.put(key, auth.Claims{}, err, now.Add(p.negativeCacheTTL))
+	}
+	return claims, err
+}
+
+// fetchClaims asks the authorization server about a token, returning the
+// token's expiry when known
+func (p *Provider) fetchClaims(ctx context.Context, accessToken string) (auth.Claims, time.Time, error) {
+	if p.introspectionURL != "" {
+		return p.introspect(ctx, accessToken)
+	}
+	if p.oidc != nil && isJWT(accessToken) {
+		audiences := p.audiences
+		if len(audiences) == 0 {
+			audiences = []string{p.config.ClientID}
+		}
+		return p.verifyJWT(ctx, accessToken, audiences, true)
+	}
+	claims, err := p.fetchUserInfo(ctx, accessToken)
+	return claims, time.Time{}, err
+}
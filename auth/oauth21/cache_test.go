@@ -0,0 +1,121 @@
+package oauth21
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingUserInfoServer accepts "good" tokens and rejects others
+func countingUserInfoServer(t *testing.T) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.Header.Get("Authorization") != "Bearer good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"id": "user-1", "email": "user@example.com"})
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestProvider_ValidateToken_NoCache(t *testing.T) {
+	server, calls := countingUserInfoServer(t)
+	provider := New(Google, "client", "secret", "http://localhost/callback", nil,
+		WithUserInfoURL(server.URL))
+
+	for i := 0; i < 2; i++ {
+		if _, err := provider.ValidateToken(context.Background(), "good"); err != nil {
+			t.Fatalf("validate failed: %v", err)
+		}
+	}
+	if atomic.LoadInt32(calls) != 2 {
+		t.Errorf("expected every validation to reach the server, got %d calls", *calls)
+	}
+}
+
+func TestProvider_ValidateToken_Cache(t *testing.T) {
+	server, calls := countingUserInfoServer(t)
+	provider := New(Google, "client", "secret", "http://localhost/callback", nil,
+		WithUserInfoURL(server.URL), WithTokenCache(time.Minute))
+	now := time.Now()
+	provider.cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		claims, err := provider.ValidateToken(ctx, "good")
+		if err != nil {
+			t.Fatalf("validate failed: %v", err)
+		}
+		if claims.Subject != "user-1" {
+			t.Errorf("expected cached claims, got subject %q", claims.Subject)
+		}
+	}
+	if atomic.LoadInt32(calls) != 1 {
+		t.Errorf("expected one server call, got %d", *calls)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := provider.ValidateToken(ctx, "good"); err != nil {
+		t.Fatalf("validate failed: %v", err)
+	}
+	if atomic.LoadInt32(calls) != 2 {
+		t.Errorf("expected expired entry to be revalidated, got %d calls", *calls)
+	}
+}
+
+func TestProvider_ValidateToken_NegativeCache(t *testing.T) {
+	server, calls := countingUserInfoServer(t)
+	ctx := context.Background()
+
+	provider := New(Google, "client", "secret", "http://localhost/callback", nil,
+		WithUserInfoURL(server.URL), WithTokenCache(time.Minute))
+	for i := 0; i < 2; i++ {
+		if _, err := provider.ValidateToken(ctx, "bad"); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("expected ErrInvalidToken, got %v", err)
+		}
+	}
+	if atomic.LoadInt32(calls) != 2 {
+		t.Errorf("expected rejections not to be cached by default, got %d calls", *calls)
+	}
+
+	atomic.StoreInt32(calls, 0)
+	provider = New(Google, "client", "secret", "http://localhost/callback", nil,
+		WithUserInfoURL(server.URL), WithTokenCache(time.Minute), WithNegativeCacheTTL(time.Minute))
+	for i := 0; i < 2; i++ {
+		if _, err := provider.ValidateToken(ctx, "bad"); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("expected ErrInvalidToken, got %v", err)
+		}
+	}
+	if atomic.LoadInt32(calls) != 1 {
+		t.Errorf("expected rejection to be cached, got %d calls", *calls)
+	}
+}
+
+func TestProvider_ValidateToken_ServerErrorNotCached(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	provider := New(Google, "client", "secret", "http://localhost/callback", nil,
+		WithUserInfoURL(server.URL), WithTokenCache(time.Minute), WithNegativeCacheTTL(time.Minute))
+	for i := 0; i < 2; i++ {
+		if _, err := provider.ValidateToken(context.Background(), "token"); err == nil || errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("expected a server error, got %v", err)
+		}
+	}
+	if atomic.LoadInt32(&calls) != 2 {
+		t.Errorf("expected server failures not to be cached, got %d calls", calls)
+	}
+}
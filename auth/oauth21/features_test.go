@@ -66,7 +66,7 @@ func TestProvider_HandleCallback_Success(t *testing.T) {
 	_ = provider.AuthCodeURLWithPKCE(state, challenge)
 
 	// Verify verifier was stored
-	if provider.pkceVerifiers[state] != challenge.CodeVerifier {
+	if verifier, ok, _ := provider.states.Take(context.Background(), state); !ok || verifier != challenge.CodeVerifier {
 		t.Error("verifier was not stored correctly")
 	}
 }
@@ -126,6 +126,7 @@ func TestProvider_ProviderTypes(t *testing.T) {
 		{
 			name:             "Azure",
 			providerType:     Azure,
+			expectedUserInfo: "https://graph.microsoft.com/oidc/userinfo",
 			expectedSubKey:   "sub",
 			expectedEmailKey: "email",
 		},
@@ -0,0 +1,101 @@
+package oauth21
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/auth"
+)
+
+// WithIntrospection validates tokens with RFC 7662 token introspection at
+// endpoint instead of the user info endpoint. The provider authenticates
+// with its client ID and secret. Introspection also works for opaque tokens
+// not meant for the user info endpoint and reports the granted scopes.
+func WithIntrospection(endpoint string) Option {
+	return func(p *Provider) {
+		p.introspectionURL = endpoint
+	}
+}
+
+// introspectionResponse holds the standard RFC 7662 response members
+type introspectionResponse struct {
+	Active   bool   `json:"active"`
+	Scope    string `json:"scope"`
+	Subject  string `json:"sub"`
+	Username string `json:"username"`
+	Expiry   int64  `json:"exp"`
+}
+
+// introspect validates a token at the introspection endpoint
+func (p *Provider) introspect(ctx context.Context, accessToken string) (auth.Claims, time.Time, error) {
+	form := url.Values{
+		"token":           {accessToken},
+		"token_type_hint": {"access_token"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.introspectionURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return auth.Claims{}, time.Time{}, fmt.Errorf("failed to create introspection request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	// RFC 6749 section 2.3.1 form-encodes the client credentials
+	req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return auth.Claims{}, time.Time{}, fmt.Errorf("failed to introspect token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return auth.Claims{}, time.Time{}, fmt.Errorf("introspection request failed with status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return auth.Claims{}, time.Time{}, fmt.Errorf("failed to read introspection response: %w", err)
+	}
+	var result introspectionResponse
+	var raw map[string]interface{}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return auth.Claims{}, time.Time{}, fmt.Errorf("failed to decode introspection response: %w", err)
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return auth.Claims{}, time.Time{}, fmt.Errorf("failed to decode introspection response: %w", err)
+	}
+
+	if !result.Active {
+		return auth.Claims{}, time.Time{}, fmt.Errorf("%w: token is not active", ErrInvalidToken)
+	}
+
+	var expiry time.Time
+	if result.Expiry > 0 {
+		expiry = time.Unix(result.Expiry, 0)
+		if !time.Now().Before(expiry) {
+			return auth.Claims{}, time.Time{}, fmt.Errorf("%w: token expired", ErrInvalidToken)
+		}
+	}
+
+	claims := auth.Claims{
+		Subject: result.Subject,
+		Scopes:  strings.Fields(result.Scope),
+		Extra:   raw,
+	}
+	if claims.Subject == "" {
+		claims.Subject = result.Username
+	}
+	if email, ok := raw[p.emailKey]; ok {
+		claims.Email = fmt.Sprintf("%v", email)
+	}
+	if len(p.scopeMapping) > 0 {
+		claims.Scopes = append(claims.Scopes, p.mapScopes(raw)...)
+	}
+
+	return claims, expiry, nil
+}
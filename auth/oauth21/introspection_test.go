@@ -0,0 +1,78 @@
+package oauth21
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func introspectionServer(t *testing.T, exp int64) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "client" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("parse form: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.PostForm.Get("token") != "opaque-token" {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"active": false})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"active":    true,
+			"sub":       "user-1",
+			"scope":     "read write",
+			"email":     "user@example.com",
+			"client_id": "client",
+			"exp":       exp,
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestProvider_Introspection(t *testing.T) {
+	server := introspectionServer(t, time.Now().Add(time.Hour).Unix())
+	provider := New(Google, "client", "secret", "http://localhost/callback", nil,
+		WithIntrospection(server.URL))
+
+	claims, err := provider.ValidateToken(context.Background(), "opaque-token")
+	if err != nil {
+		t.Fatalf("validate failed: %v", err)
+	}
+	if claims.Subject != "user-1" || claims.Email != "user@example.com" {
+		t.Errorf("unexpected claims %+v", claims)
+	}
+	if !claims.HasScope("read") || !claims.HasScope("write") {
+		t.Errorf("expected scopes from introspection, got %v", claims.Scopes)
+	}
+	if claims.Extra["client_id"] != "client" {
+		t.Errorf("expected response members in Extra, got %v", claims.Extra)
+	}
+
+	if _, err := provider.ValidateToken(context.Background(), "other"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected inactive token to be invalid, got %v", err)
+	}
+}
+
+func TestProvider_Introspection_CacheBoundedByExpiry(t *testing.T) {
+	exp := time.Now().Add(30 * time.Second)
+	server := introspectionServer(t, exp.Unix())
+	provider := New(Google, "client", "secret", "http://localhost/callback", nil,
+		WithIntrospection(server.URL), WithTokenCache(time.Hour))
+
+	if _, err := provider.ValidateToken(context.Background(), "opaque-token"); err != nil {
+		t.Fatalf("validate failed: %v", err)
+	}
+	entry := provider.cache.entries[hashToken("opaque-token")]
+	if entry.expires.After(exp) {
+		t.Errorf("expected cache entry to expire with the token at %v, got %v", exp, entry.expires)
+	}
+}
@@ -12,11 +12,13 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/jmcarbo/fullmcp/auth"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/github"
 	"golang.org/x/oauth2/google"
+	"golang.org/x/oauth2/microsoft"
 )
 
 // Provider implements OAuth 2.1 authentication with mandatory PKCE
@@ -27,7 +29,15 @@ type Provider struct {
 	subjectKey        string
 	verifyEmail       bool
 	scopeMapping      map[string][]string
-	pkceVerifiers     map[string]string // state -> code_verifier
+	states            StateStore // state -> code_verifier
+	stateTTL          time.Duration
+	refreshes         RefreshStore
+	introspectionURL  string
+	cache             *tokenCache // nil when caching is disabled
+	cacheTTL          time.Duration
+	negativeCacheTTL  time.Duration
+	oidc              *oidcVerifier // Set by OIDC
+	audiences         []string
 	strictRedirectURI bool
 }
 
@@ -39,7 +49,8 @@ const (
 	Google ProviderType = "google"
 	// GitHub OAuth provider
 	GitHub ProviderType = "github"
-	// Azure OAuth provider
+	// Azure OAuth provider using the multi-tenant Azure AD endpoints. Use
+	// OIDC with AzureIssuer to validate tokens of a single tenant locally.
 	Azure ProviderType = "azure"
 )
 
@@ -77,6 +88,18 @@ func New(providerType ProviderType, clientID, clientSecret string, redirectURL s
 		emailKey = "email"
 		subjectKey = "id"
 
+	case Azure:
+		config = &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURL,
+			Scopes:       scopes,
+			Endpoint:     microsoft.AzureADEndpoint("common"),
+		}
+		userInfoURL = "https://graph.microsoft.com/oidc/userinfo"
+		emailKey = "email"
+		subjectKey = "sub"
+
 	default:
 		// Generic OAuth2
 		config = &oauth2.Config{
@@ -96,7 +119,9 @@ func New(providerType ProviderType, clientID, clientSecret string, redirectURL s
 		subjectKey:        subjectKey,
 		verifyEmail:       false,
 		scopeMapping:      make(map[string][]string),
-		pkceVerifiers:     make(map[string]string),
+		states:            NewMemoryStateStore(),
+		stateTTL:          DefaultStateTTL,
+		refreshes:         NewMemoryRefreshStore(),
 		strictRedirectURI: true, // OAuth 2.1 requires exact string matching
 	}
 
@@ -104,9 +129,29 @@ func New(providerType ProviderType, clientID, clientSecret string, redirectURL s
 		opt(p)
 	}
 
+	if p.cacheTTL > 0 {
+		p.cache = newTokenCache()
+	}
+
 	return p
 }
 
+// WithStateStore keeps PKCE verifiers in store instead of process memory,
+// so any replica can complete an authorization
+func WithStateStore(store StateStore) Option {
+	return func(p *Provider) {
+		p.states = store
+	}
+}
+
+// WithStateTTL sets how long an authorization may take before its PKCE
+// verifier expires
+func WithStateTTL(ttl time.Duration) Option {
+	return func(p *Provider) {
+		p.stateTTL = ttl
+	}
+}
+
 // WithVerifyEmail enables email verification
 func WithVerifyEmail(verify bool) Option {
 	return func(p *Provider) {
@@ -168,34 +213,63 @@ func GeneratePKCEChallenge() (*PKCEChallenge, error) {
 }
 
 // AuthCodeURLWithPKCE returns the URL for OAuth authorization with PKCE
-// PKCE is mandatory in OAuth 2.1
+// PKCE is mandatory in OAuth 2.1. With a state store that can fail, use
+// AuthCodeURLWithPKCEContext to see the error.
 func (p *Provider) AuthCodeURLWithPKCE(state string, challenge *PKCEChallenge) string {
+	url, _ := p.AuthCodeURLWithPKCEContext(context.Background(), state, challenge)
+	return url
+}
+
+// AuthCodeURLWithPKCEContext stores the challenge's verifier for state and
+// returns the authorization URL
+func (p *Provider) AuthCodeURLWithPKCEContext(ctx context.Context, state string, challenge *PKCEChallenge) (string, error) {
 	// Store verifier for later exchange
-	p.pkceVerifiers[state] = challenge.CodeVerifier
+	if err := p.states.Save(ctx, state, challenge.CodeVerifier, p.stateTTL); err != nil {
+		return "", fmt.Errorf("failed to store code verifier: %w", err)
+	}
 
 	// OAuth 2.1 requires PKCE parameters
 	return p.config.AuthCodeURL(state,
 		oauth2.AccessTypeOffline,
 		oauth2.SetAuthURLParam("code_challenge", challenge.CodeChallenge),
 		oauth2.SetAuthURLParam("code_challenge_method", challenge.Method),
-	)
+	), nil
 }
 
 // ExchangeWithPKCE exchanges an authorization code for a token using PKCE
 // OAuth 2.1 requires the code_verifier parameter
 func (p *Provider) ExchangeWithPKCE(ctx context.Context, code, state string) (*oauth2.Token, error) {
-	verifier, ok := p.pkceVerifiers[state]
+	// Taking the verifier removes it, so a state can't be replayed
+	verifier, ok, err := p.states.Take(ctx, state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load code verifier: %w", err)
+	}
 	if !ok {
 		return nil, fmt.Errorf("code verifier not found for state")
 	}
 
-	// Clean up verifier after use
-	defer delete(p.pkceVerifiers, state)
-
 	// Exchange with code_verifier (OAuth 2.1 requirement)
-	return p.config.Exchange(ctx, code,
+	token, err := p.config.Exchange(ctx, code,
 		oauth2.SetAuthURLParam("code_verifier", verifier),
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	// OpenID Connect providers return an ID token to verify
+	if rawIDToken, ok := token.Extra("id_token").(string); ok && p.oidc != nil {
+		if _, err := p.VerifyIDToken(ctx, rawIDToken); err != nil {
+			return nil, fmt.Errorf("invalid ID token: %w", err)
+		}
+	}
+
+	// The grant's refresh token starts a new rotation family
+	if token.RefreshToken != "" {
+		if err := p.refreshes.Rotate(ctx, "", token.RefreshToken); err != nil {
+			return nil, err
+		}
+	}
+	return token, nil
 }
 
 // ValidateRedirectURI validates redirect URI using exact string matching
@@ -236,8 +310,8 @@ func (p *Provider) Authenticate(ctx context.Context, credentials interface{}) (s
 	return token.AccessToken, nil
 }
 
-// ValidateToken validates an OAuth token and retrieves user info
-func (p *Provider) ValidateToken(ctx context.Context, accessToken string) (auth.Claims, error) {
+// fetchUserInfo validates a token by retrieving user info with it
+func (p *Provider) fetchUserInfo(ctx context.Context, accessToken string) (auth.Claims, error) {
 	// Create HTTP client with token
 	token := &oauth2.Token{AccessToken: accessToken}
 	client := p.config.Client(ctx, token)
@@ -249,6 +323,9 @@ func (p *Provider) ValidateToken(ctx context.Context, accessToken string) (auth.
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return auth.Claims{}, fmt.Errorf("%w: user info request failed with status: %d", ErrInvalidToken, resp.StatusCode)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return auth.Claims{}, fmt.Errorf("user info request failed with status: %d", resp.StatusCode)
 	}
@@ -88,7 +88,7 @@ func TestAuthCodeURLWithPKCE(t *testing.T) {
 	}
 
 	// Verify verifier is stored
-	if provider.pkceVerifiers[state] != challenge.CodeVerifier {
+	if verifier, ok, _ := provider.states.Take(context.Background(), state); !ok || verifier != challenge.CodeVerifier {
 		t.Error("code verifier not stored correctly")
 	}
 }
@@ -295,6 +295,9 @@ func TestProviderType_Azure(t *testing.T) {
 	if provider.subjectKey != "sub" {
 		t.Errorf("expected subject key 'sub', got %s", provider.subjectKey)
 	}
+	if provider.config.Endpoint.AuthURL != "https://login.microsoftonline.com/common/oauth2/v2.0/authorize" {
+		t.Errorf("expected Azure AD authorize endpoint, got %q", provider.config.Endpoint.AuthURL)
+	}
 }
 
 func TestExchangeWithPKCE_MissingVerifier(t *testing.T) {
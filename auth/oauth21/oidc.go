@@ -0,0 +1,260 @@
+package oauth21
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jmcarbo/fullmcp/auth"
+	"golang.org/x/oauth2"
+)
+
+// tenantPlaceholder appears in the issuer of Azure AD's multi-tenant
+// endpoints (common, organizations, consumers) in place of the tenant ID
+const tenantPlaceholder = "{tenantid}"
+
+// Discovery holds the OpenID Provider metadata used by the provider, as
+// published at {issuer}/.well-known/openid-configuration
+type Discovery struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	UserInfoEndpoint      string   `json:"userinfo_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	IntrospectionEndpoint string   `json:"introspection_endpoint,omitempty"`
+	RevocationEndpoint    string   `json:"revocation_endpoint,omitempty"`
+	ScopesSupported       []string `json:"scopes_supported,omitempty"`
+}
+
+// oidcVerifier validates tokens signed by an OpenID Provider
+type oidcVerifier struct {
+	issuer string // May contain tenantPlaceholder
+	keys   *keySet
+}
+
+// Discover fetches the OpenID Provider metadata of issuerURL
+func Discover(ctx context.Context, issuerURL string) (*Discovery, error) {
+	issuerURL = strings.TrimSuffix(issuerURL, "/")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, issuerURL+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch discovery document: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("discovery request failed with status: %d", resp.StatusCode)
+	}
+
+	var d Discovery
+	if err := json.NewDecoder(resp.Body).Decode(&d); err != nil {
+		return nil, fmt.Errorf("failed to decode discovery document: %w", err)
+	}
+
+	// OpenID Connect Discovery requires the issuer to match exactly; Azure
+	// AD's multi-tenant endpoints publish a per-tenant issuer template
+	if strings.TrimSuffix(d.Issuer, "/") != issuerURL && !strings.Contains(d.Issuer, tenantPlaceholder) {
+		return nil, fmt.Errorf("discovery issuer %q does not match %q", d.Issuer, issuerURL)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, fmt.Errorf("discovery document for %s is missing required endpoints", issuerURL)
+	}
+	return &d, nil
+}
+
+// AzureIssuer returns the OpenID Connect issuer URL of an Azure AD
+// (Microsoft Entra ID) tenant. tenant is a tenant ID or domain, or
+// "common", "organizations" or "consumers" for multi-tenant applications.
+func AzureIssuer(tenant string) string {
+	return "https://login.microsoftonline.com/" + tenant + "/v2.0"
+}
+
+// OIDC creates a provider for any OpenID Connect identity provider, such as
+// Azure AD, Okta or Keycloak, configured through discovery at issuerURL.
+// Bearer tokens that are JWTs, and ID tokens returned by ExchangeWithPKCE,
+// are validated locally against the provider's JWKS; opaque tokens are
+// validated at the user info endpoint. The openid scope is always
+// requested.
+//
+// Issuer URLs look like:
+//
+//	Azure AD:  https://login.microsoftonline.com/{tenant}/v2.0 (see AzureIssuer)
+//	Okta:      https://{domain}/oauth2/default
+//	Keycloak:  https://{host}/realms/{realm}
+func OIDC(ctx context.Context, issuerURL, clientID, clientSecret, redirectURL string, scopes []string, opts ...Option) (*Provider, error) {
+	d, err := Discover(ctx, issuerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	verifier := &oidcVerifier{issuer: d.Issuer, keys: newKeySet(d.JWKSURI)}
+	// Fetching the keys now reports a misconfigured provider at startup
+	verifier.keys.mu.Lock()
+	err = verifier.keys.fetch(ctx)
+	verifier.keys.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if !containsString(scopes, "openid") {
+		scopes = append([]string{"openid"}, scopes...)
+	}
+
+	discovered := func(p *Provider) {
+		p.config.Endpoint = oauth2.Endpoint{
+			AuthURL:  d.AuthorizationEndpoint,
+			TokenURL: d.TokenEndpoint,
+		}
+		p.userInfoURL = d.UserInfoEndpoint
+		p.subjectKey = "sub"
+		p.oidc = verifier
+	}
+	// Discovered settings come first so options can override them
+	return New(oidcType, clientID, clientSecret, redirectURL, scopes, append([]Option{discovered}, opts...)...), nil
+}
+
+// oidcType marks providers created by OIDC
+const oidcType ProviderType = "oidc"
+
+// WithAudiences sets the aud values accepted in JWT access tokens of an
+// OIDC provider, such as "api://default" for Okta or an application ID URI
+// for Azure AD. By default tokens must be issued for the client ID.
+func WithAudiences(audiences ...string) Option {
+	return func(p *Provider) {
+		p.audiences = audiences
+	}
+}
+
+// VerifyIDToken validates an OpenID Connect ID token: its signature against
+// the provider's JWKS, issuer, expiry and that it was issued to this client.
+// It is only available for providers created with OIDC.
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken string) (auth.Claims, error) {
+	if p.oidc == nil {
+		return auth.Claims{}, fmt.Errorf("ID token verification requires an OIDC provider")
+	}
+
+	claims, _, err := p.verifyJWT(ctx, rawIDToken, []string{p.config.ClientID}, false)
+	return claims, err
+}
+
+// verifyJWT validates a token signed by the OIDC provider. The aud claim
+// must include one of audiences; with allowAuthorizedParty, a token whose
+// azp claim is the client ID is accepted too, as Keycloak issues access
+// tokens for the client with aud "account".
+func (p *Provider) verifyJWT(ctx context.Context, raw string, audiences []string, allowAuthorizedParty bool) (auth.Claims, time.Time, error) {
+	var keyErr error
+	mapClaims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(raw, mapClaims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := p.oidc.keys.key(ctx, kid)
+		keyErr = err
+		return key, err
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if keyErr != nil && !errors.Is(keyErr, errUnknownKey) {
+		// The keys couldn't be fetched, which says nothing about the token
+		return auth.Claims{}, time.Time{}, keyErr
+	}
+	if err != nil {
+		return auth.Claims{}, time.Time{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	issuer, _ := mapClaims["iss"].(string)
+	if issuer != p.oidc.expectedIssuer(mapClaims) {
+		return auth.Claims{}, time.Time{}, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidToken, issuer)
+	}
+
+	tokenAudiences, _ := mapClaims.GetAudience()
+	accepted := false
+	for _, aud := range tokenAudiences {
+		if containsString(audiences, aud) {
+			accepted = true
+			break
+		}
+	}
+	if azp, _ := mapClaims["azp"].(string); !accepted && allowAuthorizedParty && azp == p.config.ClientID {
+		accepted = true
+	}
+	if !accepted {
+		return auth.Claims{}, time.Time{}, fmt.Errorf("%w: token audience %v not accepted", ErrInvalidToken, tokenAudiences)
+	}
+
+	var expiry time.Time
+	if exp, err := mapClaims.GetExpirationTime(); err == nil && exp != nil {
+		expiry = exp.Time
+	}
+	return p.oidcClaims(mapClaims), expiry, nil
+}
+
+// expectedIssuer resolves the issuer template of Azure AD's multi-tenant
+// endpoints with the token's tenant
+func (v *oidcVerifier) expectedIssuer(claims jwt.MapClaims) string {
+	if !strings.Contains(v.issuer, tenantPlaceholder) {
+		return v.issuer
+	}
+	tid, _ := claims["tid"].(string)
+	if tid == "" {
+		return v.issuer
+	}
+	return strings.ReplaceAll(v.issuer, tenantPlaceholder, tid)
+}
+
+// oidcClaims maps token claims, covering the claim names used by Azure AD
+// (scp, preferred_username), Okta (scp as a list) and Keycloak (scope)
+func (p *Provider) oidcClaims(mapClaims jwt.MapClaims) auth.Claims {
+	claims := auth.Claims{Extra: map[string]interface{}(mapClaims)}
+
+	if sub, ok := mapClaims[p.subjectKey]; ok {
+		claims.Subject = fmt.Sprintf("%v", sub)
+	}
+	if email, ok := mapClaims[p.emailKey].(string); ok {
+		claims.Email = email
+	} else if username, ok := mapClaims["preferred_username"].(string); ok && strings.Contains(username, "@") {
+		claims.Email = username
+	}
+
+	for _, key := range []string{"scope", "scp"} {
+		switch v := mapClaims[key].(type) {
+		case string:
+			claims.Scopes = append(claims.Scopes, strings.Fields(v)...)
+		case []interface{}:
+			for _, s := range v {
+				if str, ok := s.(string); ok {
+					claims.Scopes = append(claims.Scopes, str)
+				}
+			}
+		}
+	}
+	if len(p.scopeMapping) > 0 {
+		claims.Scopes = append(claims.Scopes, p.mapScopes(mapClaims)...)
+	}
+
+	return claims
+}
+
+// isJWT reports whether token has the three segments of a signed JWT
+func isJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
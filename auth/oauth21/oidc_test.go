@@ -0,0 +1,304 @@
+package oauth21
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// fakeOIDCServer serves discovery, JWKS and user info for an issuer
+type fakeOIDCServer struct {
+	*httptest.Server
+	issuer    string // Published issuer, defaults to the server URL
+	keys      []map[string]string
+	jwksCalls int32
+}
+
+func newFakeOIDCServer(t *testing.T) *fakeOIDCServer {
+	t.Helper()
+	s := &fakeOIDCServer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		issuer := s.issuer
+		if issuer == "" {
+			issuer = s.URL
+		}
+		_ = json.NewEncoder(w).Encode(Discovery{
+			Issuer:                issuer,
+			AuthorizationEndpoint: s.URL + "/authorize",
+			TokenEndpoint:         s.URL + "/token",
+			UserInfoEndpoint:      s.URL + "/userinfo",
+			JWKSURI:               s.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&s.jwksCalls, 1)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": s.keys})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer opaque" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"sub": "opaque-user"})
+	})
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (s *fakeOIDCServer) addRSAKey(t *testing.T, kid string) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	s.keys = append(s.keys, map[string]string{
+		"kty": "RSA", "kid": kid, "use": "sig", "alg": "RS256",
+		"n": b64(key.N.Bytes()),
+		"e": b64(big.NewInt(int64(key.E)).Bytes()),
+	})
+	return key
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+func (s *fakeOIDCServer) claims(extra jwt.MapClaims) jwt.MapClaims {
+	claims := jwt.MapClaims{
+		"iss":   s.URL,
+		"sub":   "user-1",
+		"aud":   "client",
+		"email": "user@example.com",
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}
+	for k, v := range extra {
+		claims[k] = v
+	}
+	return claims
+}
+
+func TestOIDC_Discovery(t *testing.T) {
+	server := newFakeOIDCServer(t)
+	server.addRSAKey(t, "key-1")
+
+	provider, err := OIDC(context.Background(), server.URL, "client", "secret", "http://localhost/callback", []string{"email"})
+	if err != nil {
+		t.Fatalf("OIDC failed: %v", err)
+	}
+
+	if provider.config.Endpoint.AuthURL != server.URL+"/authorize" || provider.config.Endpoint.TokenURL != server.URL+"/token" {
+		t.Errorf("unexpected endpoints %+v", provider.config.Endpoint)
+	}
+	if provider.userInfoURL != server.URL+"/userinfo" {
+		t.Errorf("unexpected user info URL %q", provider.userInfoURL)
+	}
+	if provider.config.Scopes[0] != "openid" {
+		t.Errorf("expected openid scope to be added, got %v", provider.config.Scopes)
+	}
+}
+
+func TestOIDC_IssuerMismatch(t *testing.T) {
+	server := newFakeOIDCServer(t)
+	server.issuer = "https://evil.example.com"
+	server.addRSAKey(t, "key-1")
+
+	if _, err := OIDC(context.Background(), server.URL, "client", "secret", "http://localhost/callback", nil); err == nil {
+		t.Error("expected mismatched issuer to be rejected")
+	}
+}
+
+func TestOIDC_VerifyIDToken(t *testing.T) {
+	server := newFakeOIDCServer(t)
+	key := server.addRSAKey(t, "key-1")
+	provider, err := OIDC(context.Background(), server.URL, "client", "secret", "http://localhost/callback", nil)
+	if err != nil {
+		t.Fatalf("OIDC failed: %v", err)
+	}
+	ctx := context.Background()
+
+	claims, err := provider.VerifyIDToken(ctx, signToken(t, jwt.SigningMethodRS256, key, "key-1", server.claims(nil)))
+	if err != nil {
+		t.Fatalf("verify failed: %v", err)
+	}
+	if claims.Subject != "user-1" || claims.Email != "user@example.com" {
+		t.Errorf("unexpected claims %+v", claims)
+	}
+
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+	tests := map[string]string{
+		"wrong audience": signToken(t, jwt.SigningMethodRS256, key, "key-1", server.claims(jwt.MapClaims{"aud": "other"})),
+		"wrong issuer":   signToken(t, jwt.SigningMethodRS256, key, "key-1", server.claims(jwt.MapClaims{"iss": "https://evil.example.com"})),
+		"expired":        signToken(t, jwt.SigningMethodRS256, key, "key-1", server.claims(jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()})),
+		"wrong key":      signToken(t, jwt.SigningMethodRS256, other, "key-1", server.claims(nil)),
+		"unsigned":       signToken(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "key-1", server.claims(nil)),
+	}
+	for name, token := range tests {
+		if _, err := provider.VerifyIDToken(ctx, token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: expected ErrInvalidToken, got %v", name, err)
+		}
+	}
+}
+
+func TestOIDC_ValidateToken(t *testing.T) {
+	server := newFakeOIDCServer(t)
+	key := server.addRSAKey(t, "key-1")
+	provider, err := OIDC(context.Background(), server.URL, "client", "secret", "http://localhost/callback", nil,
+		WithAudiences("api://default"))
+	if err != nil {
+		t.Fatalf("OIDC failed: %v", err)
+	}
+	ctx := context.Background()
+
+	// Okta access token with the scopes as a list
+	okta := signToken(t, jwt.SigningMethodRS256, key, "key-1", server.claims(jwt.MapClaims{
+		"aud": "api://default",
+		"scp": []string{"mcp:read", "mcp:write"},
+	}))
+	claims, err := provider.ValidateToken(ctx, okta)
+	if err != nil {
+		t.Fatalf("validate failed: %v", err)
+	}
+	if !claims.HasScope("mcp:read") || !claims.HasScope("mcp:write") {
+		t.Errorf("expected scopes from scp, got %v", claims.Scopes)
+	}
+
+	// Keycloak access token issued to the client for another audience
+	keycloak := signToken(t, jwt.SigningMethodRS256, key, "key-1", server.claims(jwt.MapClaims{
+		"aud":   "account",
+		"azp":   "client",
+		"scope": "openid mcp:read",
+	}))
+	claims, err = provider.ValidateToken(ctx, keycloak)
+	if err != nil {
+		t.Fatalf("validate failed: %v", err)
+	}
+	if !claims.HasScope("mcp:read") {
+		t.Errorf("expected scopes from scope, got %v", claims.Scopes)
+	}
+
+	// ID tokens don't accept azp in place of aud
+	if _, err := provider.VerifyIDToken(ctx, keycloak); err == nil {
+		t.Error("expected ID token for another audience to be rejected")
+	}
+
+	// Opaque tokens are validated at the user info endpoint
+	claims, err = provider.ValidateToken(ctx, "opaque")
+	if err != nil || claims.Subject != "opaque-user" {
+		t.Errorf("expected user info validation, got %+v, %v", claims, err)
+	}
+}
+
+func TestOIDC_KeyRotation(t *testing.T) {
+	server := newFakeOIDCServer(t)
+	server.addRSAKey(t, "key-1")
+	provider, err := OIDC(context.Background(), server.URL, "client", "secret", "http://localhost/callback", nil)
+	if err != nil {
+		t.Fatalf("OIDC failed: %v", err)
+	}
+	ctx := context.Background()
+	now := time.Now().Add(2 * jwksRefreshInterval)
+	provider.oidc.keys.now = func() time.Time { return now }
+
+	// The provider publishes a new EC key after startup
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	server.keys = append(server.keys, map[string]string{
+		"kty": "EC", "kid": "key-2", "crv": "P-256",
+		"x": b64(ecKey.X.FillBytes(make([]byte, 32))),
+		"y": b64(ecKey.Y.FillBytes(make([]byte, 32))),
+	})
+
+	token := signToken(t, jwt.SigningMethodES256, ecKey, "key-2", server.claims(nil))
+	if _, err := provider.ValidateToken(ctx, token); err != nil {
+		t.Fatalf("expected unknown key to trigger a JWKS refresh: %v", err)
+	}
+
+	// Unknown key IDs don't refetch the keys on every request
+	calls := atomic.LoadInt32(&server.jwksCalls)
+	for i := 0; i < 3; i++ {
+		bogus := signToken(t, jwt.SigningMethodES256, ecKey, "bogus", server.claims(nil))
+		if _, err := provider.ValidateToken(ctx, bogus); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("expected ErrInvalidToken, got %v", err)
+		}
+	}
+	if atomic.LoadInt32(&server.jwksCalls) != calls {
+		t.Errorf("expected JWKS refetches to be rate limited")
+	}
+}
+
+func TestOIDC_AzureMultiTenantIssuer(t *testing.T) {
+	server := newFakeOIDCServer(t)
+	key := server.addRSAKey(t, "key-1")
+	// Azure AD's common endpoint publishes an issuer template
+	server.issuer = "https://login.microsoftonline.com/{tenantid}/v2.0"
+	provider, err := OIDC(context.Background(), server.URL, "client", "secret", "http://localhost/callback", nil)
+	if err != nil {
+		t.Fatalf("OIDC failed: %v", err)
+	}
+	ctx := context.Background()
+
+	tenant := "72f988bf-86f1-41af-91ab-2d7cd011db47"
+	token := signToken(t, jwt.SigningMethodRS256, key, "key-1", server.claims(jwt.MapClaims{
+		"iss":                "https://login.microsoftonline.com/" + tenant + "/v2.0",
+		"tid":                tenant,
+		"email":              nil,
+		"preferred_username": "user@contoso.com",
+		"scp":                "mcp.read",
+	}))
+	claims, err := provider.ValidateToken(ctx, token)
+	if err != nil {
+		t.Fatalf("validate failed: %v", err)
+	}
+	if claims.Email != "user@contoso.com" || !claims.HasScope("mcp.read") {
+		t.Errorf("unexpected claims %+v", claims)
+	}
+
+	// The issuer must match the token's own tenant
+	spoofed := signToken(t, jwt.SigningMethodRS256, key, "key-1", server.claims(jwt.MapClaims{
+		"iss": "https://login.microsoftonline.com/" + tenant + "/v2.0",
+		"tid": "another-tenant",
+	}))
+	if _, err := provider.ValidateToken(ctx, spoofed); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected tenant mismatch to be rejected, got %v", err)
+	}
+}
+
+func TestAzureIssuer(t *testing.T) {
+	if got := AzureIssuer("contoso.onmicrosoft.com"); !strings.HasPrefix(got, "https://login.microsoftonline.com/contoso.onmicrosoft.com/") {
+		t.Errorf("unexpected issuer %q", got)
+	}
+}
+
+func TestProvider_VerifyIDToken_RequiresOIDC(t *testing.T) {
+	provider := New(Google, "client", "secret", "http://localhost/callback", nil)
+	if _, err := provider.VerifyIDToken(context.Background(), "token"); err == nil {
+		t.Error("expected an error without discovery")
+	}
+}
@@ -0,0 +1,61 @@
+// Package redisstore provides a Redis-backed PKCE state store for the
+// oauth21 provider, for servers running several replicas.
+package redisstore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/auth/oauth21"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultKeyPrefix namespaces the keys written by the store
+const DefaultKeyPrefix = "mcp:oauth21:state:"
+
+// Store keeps PKCE verifiers in Redis with the state's TTL. Take uses
+// GETDEL, which requires Redis 6.2 or later.
+type Store struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+var _ oauth21.StateStore = (*Store)(nil)
+
+// Option configures the store
+type Option func(*Store)
+
+// WithKeyPrefix sets the prefix of the keys written by the store
+func WithKeyPrefix(prefix string) Option {
+	return func(s *Store) {
+		s.prefix = prefix
+	}
+}
+
+// New creates a store using client, which may be a single-node, cluster or
+// sentinel client
+func New(client redis.UniversalClient, opts ...Option) *Store {
+	s := &Store{client: client, prefix: DefaultKeyPrefix}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Save stores the verifier for state, letting Redis expire it after ttl
+func (s *Store) Save(ctx context.Context, state, verifier string, ttl time.Duration) error {
+	return s.client.Set(ctx, s.prefix+state, verifier, ttl).Err()
+}
+
+// Take atomically returns and deletes the verifier for state
+func (s *Store) Take(ctx context.Context, state string) (string, bool, error) {
+	verifier, err := s.client.GetDel(ctx, s.prefix+state).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return verifier, true, nil
+}
@@ -0,0 +1,52 @@
+package redisstore
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestStore connects to the Redis server named by MCP_TEST_REDIS_ADDR
+func newTestStore(t *testing.T) *Store {
+	addr := os.Getenv("MCP_TEST_REDIS_ADDR")
+	if addr == "" {
+		t.Skip("MCP_TEST_REDIS_ADDR not set")
+	}
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	t.Cleanup(func() { _ = client.Close() })
+	return New(client, WithKeyPrefix("mcp:test:"+t.Name()+":"))
+}
+
+func TestStore_TakeOnce(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	if err := store.Save(ctx, "state-1", "verifier-1", time.Minute); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	verifier, ok, err := store.Take(ctx, "state-1")
+	if err != nil || !ok || verifier != "verifier-1" {
+		t.Fatalf("Take() = %q, %v, %v", verifier, ok, err)
+	}
+	if _, ok, err := store.Take(ctx, "state-1"); ok || err != nil {
+		t.Errorf("expected a state to be usable only once, got %v, %v", ok, err)
+	}
+}
+
+func TestStore_Expiry(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	if err := store.Save(ctx, "state-1", "verifier-1", 50*time.Millisecond); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	if _, ok, _ := store.Take(ctx, "state-1"); ok {
+		t.Error("expected expired verifier to be gone")
+	}
+}
@@ -0,0 +1,190 @@
+package oauth21
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// DefaultRefreshRetention is how long the memory store remembers a refresh
+// token after it was issued
+const DefaultRefreshRetention = 30 * 24 * time.Hour
+
+// ErrRefreshTokenReused is returned when a refresh token that was already
+// rotated is presented again. OAuth 2.1 treats this as a sign the token was
+// stolen, so every token descended from the same grant is revoked.
+var ErrRefreshTokenReused = errors.New("refresh token reuse detected; token family revoked")
+
+// RefreshStore tracks refresh token rotation. Tokens issued by one grant
+// and its refreshes form a family; presenting a rotated token revokes the
+// family.
+type RefreshStore interface {
+	// Check returns ErrRefreshTokenReused when token was already rotated,
+	// revoking its family, or when its family was revoked
+	Check(ctx context.Context, token string) error
+
+	// Rotate records that token was exchanged for next, which joins its
+	// family. An empty token starts a new family with next.
+	Rotate(ctx context.Context, token, next string) error
+}
+
+// MemoryRefreshStore is a RefreshStore for a single server process. Only
+// hashes of the tokens are kept.
+type MemoryRefreshStore struct {
+	mu         sync.Mutex
+	tokens     map[string]*refreshEntry // By token hash
+	revoked    map[uint64]bool          // Revoked families
+	nextFamily uint64
+	retention  time.Duration
+	now        func() time.Time
+}
+
+type refreshEntry struct {
+	family  uint64
+	rotated bool
+	issued  time.Time
+}
+
+// NewMemoryRefreshStore creates an in-memory refresh store
+func NewMemoryRefreshStore() *MemoryRefreshStore {
+	return &MemoryRefreshStore{
+		tokens:    make(map[string]*refreshEntry),
+		revoked:   make(map[uint64]bool),
+		retention: DefaultRefreshRetention,
+		now:       time.Now,
+	}
+}
+
+// Check reports whether token may be used
+func (s *MemoryRefreshStore) Check(_ context.Context, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.tokens[hashToken(token)]
+	if !ok {
+		return nil
+	}
+	if entry.rotated {
+		s.revoked[entry.family] = true
+	}
+	if s.revoked[entry.family] {
+		return ErrRefreshTokenReused
+	}
+	return nil
+}
+
+// Rotate records the exchange of token for next
+func (s *MemoryRefreshStore) Rotate(_ context.Context, token, next string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for key, entry := range s.tokens {
+		if now.Sub(entry.issued) > s.retention {
+			delete(s.tokens, key)
+		}
+	}
+
+	rotated := next != "" && next != token
+	var family uint64
+	if entry, ok := s.tokens[hashToken(token)]; token != "" && ok {
+		if entry.rotated || s.revoked[entry.family] {
+			s.revoked[entry.family] = true
+			return ErrRefreshTokenReused
+		}
+		if !rotated {
+			// The server kept the refresh token
+			return nil
+		}
+		entry.rotated = true
+		family = entry.family
+	} else {
+		s.nextFamily++
+		family = s.nextFamily
+		if token != "" {
+			// A token issued before the store knew of it is now used
+			s.tokens[hashToken(token)] = &refreshEntry{family: family, rotated: rotated, issued: now}
+		}
+	}
+
+	if rotated {
+		s.tokens[hashToken(next)] = &refreshEntry{family: family, issued: now}
+	}
+	return nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// WithRefreshStore tracks refresh token rotation in store instead of
+// process memory
+func WithRefreshStore(store RefreshStore) Option {
+	return func(p *Provider) {
+		p.refreshes = store
+	}
+}
+
+// Refresh exchanges a refresh token for new tokens. When the authorization
+// server rotates the refresh token, the old one is recorded as used;
+// presenting it again fails with ErrRefreshTokenReused and revokes every
+// token descended from the same grant.
+func (p *Provider) Refresh(ctx context.Context, refreshToken string) (*oauth2.Token, error) {
+	if refreshToken == "" {
+		return nil, fmt.Errorf("refresh token is required")
+	}
+	if err := p.refreshes.Check(ctx, refreshToken); err != nil {
+		return nil, err
+	}
+
+	// A token without an access token is always refreshed
+	token, err := p.config.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh token: %w", err)
+	}
+
+	if err := p.refreshes.Rotate(ctx, refreshToken, token.RefreshToken); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// TokenSource returns a token source that starts with token and refreshes
+// it through Refresh when it expires, following rotated refresh tokens. It
+// plugs into the WithBearerToken options of the client transports to keep
+// long-lived sessions authenticated.
+func (p *Provider) TokenSource(ctx context.Context, token *oauth2.Token) oauth2.TokenSource {
+	return oauth2.ReuseTokenSource(token, &refreshingTokenSource{
+		ctx:          ctx,
+		provider:     p,
+		refreshToken: token.RefreshToken,
+	})
+}
+
+type refreshingTokenSource struct {
+	ctx          context.Context
+	provider     *Provider
+	mu           sync.Mutex
+	refreshToken string
+}
+
+func (ts *refreshingTokenSource) Token() (*oauth2.Token, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	token, err := ts.provider.Refresh(ts.ctx, ts.refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if token.RefreshToken != "" {
+		ts.refreshToken = token.RefreshToken
+	}
+	return token, nil
+}
@@ -0,0 +1,143 @@
+package oauth21
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// rotatingTokenServer issues a new refresh token on every refresh
+func rotatingTokenServer(t *testing.T) (*httptest.Server, *int) {
+	t.Helper()
+	var mu sync.Mutex
+	refreshes := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("parse form: %v", err)
+		}
+		if r.Form.Get("grant_type") != "refresh_token" {
+			t.Errorf("unexpected grant_type %q", r.Form.Get("grant_type"))
+		}
+
+		mu.Lock()
+		refreshes++
+		n := refreshes
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  fmt.Sprintf("access-%d", n),
+			"refresh_token": fmt.Sprintf("refresh-%d", n),
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, &refreshes
+}
+
+func TestProvider_Refresh_Rotation(t *testing.T) {
+	server, _ := rotatingTokenServer(t)
+	provider := New(Google, "client", "secret", "http://localhost/callback", nil,
+		WithCustomEndpoint(server.URL+"/auth", server.URL+"/token"))
+	ctx := context.Background()
+
+	token, err := provider.Refresh(ctx, "refresh-0")
+	if err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+	if token.AccessToken != "access-1" || token.RefreshToken != "refresh-1" {
+		t.Fatalf("unexpected token %+v", token)
+	}
+
+	if _, err := provider.Refresh(ctx, "refresh-1"); err != nil {
+		t.Fatalf("refresh with rotated token failed: %v", err)
+	}
+}
+
+func TestProvider_Refresh_ReuseRevokesFamily(t *testing.T) {
+	server, refreshes := rotatingTokenServer(t)
+	provider := New(Google, "client", "secret", "http://localhost/callback", nil,
+		WithCustomEndpoint(server.URL+"/auth", server.URL+"/token"))
+	ctx := context.Background()
+
+	if _, err := provider.Refresh(ctx, "refresh-0"); err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+
+	// Replaying the rotated token is reuse
+	if _, err := provider.Refresh(ctx, "refresh-0"); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("expected ErrRefreshTokenReused, got %v", err)
+	}
+
+	// The legitimate descendant is revoked with its family
+	if _, err := provider.Refresh(ctx, "refresh-1"); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("expected family to be revoked, got %v", err)
+	}
+	if *refreshes != 1 {
+		t.Errorf("expected reused tokens not to reach the server, got %d refreshes", *refreshes)
+	}
+}
+
+func TestMemoryRefreshStore_UnrotatedToken(t *testing.T) {
+	store := NewMemoryRefreshStore()
+	ctx := context.Background()
+
+	_ = store.Rotate(ctx, "", "refresh-a")
+	// The server returned the same refresh token
+	if err := store.Rotate(ctx, "refresh-a", "refresh-a"); err != nil {
+		t.Fatalf("rotate failed: %v", err)
+	}
+	if err := store.Check(ctx, "refresh-a"); err != nil {
+		t.Errorf("expected an unrotated token to stay valid, got %v", err)
+	}
+}
+
+func TestMemoryRefreshStore_Retention(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryRefreshStore()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_ = store.Rotate(ctx, "", "old")
+	now = now.Add(DefaultRefreshRetention + time.Hour)
+	_ = store.Rotate(ctx, "", "new")
+
+	if len(store.tokens) != 1 {
+		t.Errorf("expected expired tokens to be pruned, have %d", len(store.tokens))
+	}
+}
+
+func TestProvider_TokenSource(t *testing.T) {
+	server, refreshes := rotatingTokenServer(t)
+	provider := New(Google, "client", "secret", "http://localhost/callback", nil,
+		WithCustomEndpoint(server.URL+"/auth", server.URL+"/token"))
+
+	expired := &oauth2.Token{
+		AccessToken:  "access-0",
+		RefreshToken: "refresh-0",
+		Expiry:       time.Now().Add(-time.Minute),
+	}
+	ts := provider.TokenSource(context.Background(), expired)
+
+	token, err := ts.Token()
+	if err != nil {
+		t.Fatalf("token failed: %v", err)
+	}
+	if token.AccessToken != "access-1" {
+		t.Errorf("expected refreshed token, got %q", token.AccessToken)
+	}
+
+	// The fresh token is reused until it expires
+	if token, _ := ts.Token(); token.AccessToken != "access-1" || *refreshes != 1 {
+		t.Errorf("expected cached token, got %q after %d refreshes", token.AccessToken, *refreshes)
+	}
+}
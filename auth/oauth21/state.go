@@ -0,0 +1,83 @@
+package oauth21
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultStateTTL is how long a PKCE verifier is kept while the user
+// completes the authorization redirect
+const DefaultStateTTL = 10 * time.Minute
+
+// StateStore keeps PKCE code verifiers between the authorization redirect
+// and the callback. Servers running several replicas need a shared store,
+// such as redisstore.Store, since the callback may reach any replica.
+type StateStore interface {
+	// Save stores the verifier for state until ttl elapses
+	Save(ctx context.Context, state, verifier string, ttl time.Duration) error
+
+	// Take returns the verifier for state and removes it, so each state is
+	// used once. ok is false when the state is unknown or expired.
+	Take(ctx context.Context, state string) (verifier string, ok bool, err error)
+}
+
+// MemoryStateStore is a StateStore for a single server process
+type MemoryStateStore struct {
+	mu      sync.Mutex
+	entries map[string]stateEntry
+	now     func() time.Time
+}
+
+type stateEntry struct {
+	verifier string
+	expires  time.Time
+}
+
+// NewMemoryStateStore creates an in-memory state store
+func NewMemoryStateStore() *MemoryStateStore {
+	return &MemoryStateStore{
+		entries: make(map[string]stateEntry),
+		now:     time.Now,
+	}
+}
+
+// Save stores the verifier for state, dropping expired entries so abandoned
+// authorizations don't accumulate
+func (s *MemoryStateStore) Save(_ context.Context, state, verifier string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for key, entry := range s.entries {
+		if !now.Before(entry.expires) {
+			delete(s.entries, key)
+		}
+	}
+	s.entries[state] = stateEntry{verifier: verifier, expires: now.Add(ttl)}
+	return nil
+}
+
+// Take returns and removes the verifier for state
+func (s *MemoryStateStore) Take(_ context.Context, state string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[state]
+	if !ok {
+		return "", false, nil
+	}
+	delete(s.entries, state)
+	if !s.now().Before(entry.expires) {
+		return "", false, nil
+	}
+	return entry.verifier, true, nil
+}
+
+// Len returns the number of stored verifiers, including expired ones not
+// yet dropped
+func (s *MemoryStateStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
@@ -0,0 +1,84 @@
+package oauth21
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryStateStore_TakeOnce(t *testing.T) {
+	store := NewMemoryStateStore()
+	ctx := context.Background()
+
+	if err := store.Save(ctx, "state-1", "verifier-1", time.Minute); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	verifier, ok, err := store.Take(ctx, "state-1")
+	if err != nil || !ok || verifier != "verifier-1" {
+		t.Fatalf("Take() = %q, %v, %v", verifier, ok, err)
+	}
+	if _, ok, _ := store.Take(ctx, "state-1"); ok {
+		t.Error("expected a state to be usable only once")
+	}
+}
+
+func TestMemoryStateStore_Expiry(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryStateStore()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_ = store.Save(ctx, "old", "v1", time.Minute)
+	now = now.Add(2 * time.Minute)
+
+	if _, ok, _ := store.Take(ctx, "old"); ok {
+		t.Error("expected expired verifier to be rejected")
+	}
+
+	_ = store.Save(ctx, "abandoned", "v2", time.Minute)
+	now = now.Add(2 * time.Minute)
+	_ = store.Save(ctx, "new", "v3", time.Minute)
+	if store.Len() != 1 {
+		t.Errorf("expected expired entries to be dropped on save, have %d", store.Len())
+	}
+}
+
+func TestMemoryStateStore_Concurrent(t *testing.T) {
+	store := NewMemoryStateStore()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			state := fmt.Sprintf("state-%d", i)
+			_ = store.Save(ctx, state, "verifier", time.Minute)
+			if _, ok, _ := store.Take(ctx, state); !ok {
+				t.Errorf("verifier for %s not found", state)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestProvider_WithStateStore(t *testing.T) {
+	store := NewMemoryStateStore()
+	provider := New(Google, "client-id", "client-secret", "http://localhost/callback", nil,
+		WithStateStore(store), WithStateTTL(time.Minute))
+
+	challenge, err := GeneratePKCEChallenge()
+	if err != nil {
+		t.Fatalf("challenge failed: %v", err)
+	}
+	if _, err := provider.AuthCodeURLWithPKCEContext(context.Background(), "state-1", challenge); err != nil {
+		t.Fatalf("auth URL failed: %v", err)
+	}
+
+	if verifier, ok, _ := store.Take(context.Background(), "state-1"); !ok || verifier != challenge.CodeVerifier {
+		t.Error("expected the verifier in the configured store")
+	}
+}
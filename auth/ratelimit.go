@@ -0,0 +1,207 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Limit reasons reported in LimitError
+const (
+	ReasonRateLimited   = "rate_limited"
+	ReasonQuotaExceeded = "quota_exceeded"
+)
+
+// Limits configures the request budget of a caller. Zero values disable the
+// corresponding limit.
+type Limits struct {
+	RequestsPerSecond float64 // Sustained request rate
+	Burst             int     // Requests allowed at once (defaults to 1 with a rate)
+	DailyQuota        int     // Requests per UTC day
+}
+
+// LimitError reports a request rejected by a RateLimiter
+type LimitError struct {
+	Key     string    // Subject the limit applies to
+	Reason  string    // ReasonRateLimited or ReasonQuotaExceeded
+	Limit   float64   // Requests per second, or per day for quotas
+	ResetAt time.Time // When requests will be accepted again
+}
+
+func (e *LimitError) Error() string {
+	if e.Reason == ReasonQuotaExceeded {
+		return fmt.Sprintf("daily quota of %d requests exceeded, resets at %s", int(e.Limit), e.ResetAt.UTC().Format(time.RFC3339))
+	}
+	return fmt.Sprintf("rate limit of %g requests per second exceeded", e.Limit)
+}
+
+// RetryAfter returns how long the caller should wait before retrying
+func (e *LimitError) RetryAfter(now time.Time) time.Duration {
+	if d := e.ResetAt.Sub(now); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// pruneInterval is how often Allow drops the budgets of idle callers
+const pruneInterval = time.Minute
+
+// RateLimiter enforces per-caller request rates and daily quotas, keyed by
+// an identifier such as the claims' subject
+type RateLimiter struct {
+	mu       sync.Mutex
+	defaults Limits
+	limits   map[string]Limits
+	states   map[string]*limitState
+	pruned   time.Time
+	now      func() time.Time
+}
+
+// limitState is the budget used by one caller
+type limitState struct {
+	tokens float64
+	last   time.Time
+	day    time.Time // Start of the UTC day the count applies to
+	count  int
+}
+
+// NewRateLimiter creates a limiter applying defaults to callers without
+// limits of their own
+func NewRateLimiter(defaults Limits) *RateLimiter {
+	return &RateLimiter{
+		defaults: defaults,
+		limits:   make(map[string]Limits),
+		states:   make(map[string]*limitState),
+		now:      time.Now,
+	}
+}
+
+// SetLimits overrides the limits for one caller
+func (rl *RateLimiter) SetLimits(key string, limits Limits) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.limits[key] = limits
+	delete(rl.states, key)
+}
+
+// Allow records a request by key, returning a *LimitError when it exceeds
+// the caller's rate or daily quota. Rejected requests don't count against
+// the quota.
+func (rl *RateLimiter) Allow(key string) error {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	limits, ok := rl.limits[key]
+	if !ok {
+		limits = rl.defaults
+	}
+	burst := float64(limits.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+
+	now := rl.now()
+	if now.Sub(rl.pruned) >= pruneInterval {
+		rl.prune(now)
+	}
+	state, ok := rl.states[key]
+	if !ok {
+		state = &limitState{tokens: burst, last: now}
+		rl.states[key] = state
+	}
+
+	if limits.DailyQuota > 0 {
+		day := now.UTC().Truncate(24 * time.Hour)
+		if !state.day.Equal(day) {
+			state.day = day
+			state.count = 0
+		}
+		if state.count >= limits.DailyQuota {
+			return &LimitError{
+				Key:     key,
+				Reason:  ReasonQuotaExceeded,
+				Limit:   float64(limits.DailyQuota),
+				ResetAt: day.Add(24 * time.Hour),
+			}
+		}
+	}
+
+	if limits.RequestsPerSecond > 0 {
+		state.tokens = math.Min(burst, state.tokens+now.Sub(state.last).Seconds()*limits.RequestsPerSecond)
+		state.last = now
+		if state.tokens < 1 {
+			wait := (1 - state.tokens) / limits.RequestsPerSecond
+			return &LimitError{
+				Key:     key,
+				Reason:  ReasonRateLimited,
+				Limit:   limits.RequestsPerSecond,
+				ResetAt: now.Add(time.Duration(wait * float64(time.Second))),
+			}
+		}
+		state.tokens--
+	}
+
+	state.count++
+	return nil
+}
+
+// prune drops the budgets of callers whose tokens have refilled and whose
+// quota day has ended, as a new budget would behave the same. The caller
+// must hold rl.mu.
+func (rl *RateLimiter) prune(now time.Time) {
+	rl.pruned = now
+	day := now.UTC().Truncate(24 * time.Hour)
+	for key, state := range rl.states {
+		limits, ok := rl.limits[key]
+		if !ok {
+			limits = rl.defaults
+		}
+		burst := math.Max(1, float64(limits.Burst))
+
+		refilled := limits.RequestsPerSecond <= 0 ||
+			state.tokens+now.Sub(state.last).Seconds()*limits.RequestsPerSecond >= burst
+		quotaReset := limits.DailyQuota <= 0 || !state.day.Equal(day)
+		if refilled && quotaReset {
+			delete(rl.states, key)
+		}
+	}
+}
+
+// Middleware returns HTTP middleware limiting requests by the subject of
+// the claims added by an authentication middleware, which must run first.
+// Requests without claims are not limited.
+func (rl *RateLimiter) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if claims, ok := GetClaims(r.Context()); ok {
+				if err := rl.Allow(claims.Subject); err != nil {
+					WriteLimitError(w, err.(*LimitError))
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// WriteLimitError answers a rejected request with 429 Too Many Requests, a
+// Retry-After header and a JSON body describing the limit
+func WriteLimitError(w http.ResponseWriter, err *LimitError) {
+	retryAfter := int(math.Ceil(err.RetryAfter(time.Now()).Seconds()))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(err.ResetAt.Unix(), 10))
+	w.WriteHeader(http.StatusTooManyRequests)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":      err.Reason,
+		"message":    err.Error(),
+		"limit":      err.Limit,
+		"resetAt":    err.ResetAt.UTC().Format(time.RFC3339),
+		"retryAfter": retryAfter,
+	})
+}
@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func testLimiter(defaults Limits, now *time.Time) *RateLimiter {
+	rl := NewRateLimiter(defaults)
+	rl.now = func() time.Time { return *now }
+	return rl
+}
+
+func TestRateLimiter_Rate(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	rl := testLimiter(Limits{RequestsPerSecond: 2, Burst: 2}, &now)
+
+	for i := 0; i < 2; i++ {
+		if err := rl.Allow("user-1"); err != nil {
+			t.Fatalf("request %d rejected: %v", i, err)
+		}
+	}
+
+	err := rl.Allow("user-1")
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) || limitErr.Reason != ReasonRateLimited {
+		t.Fatalf("expected rate limit error, got %v", err)
+	}
+	if want := now.Add(500 * time.Millisecond); !limitErr.ResetAt.Equal(want) {
+		t.Errorf("ResetAt = %v, want %v", limitErr.ResetAt, want)
+	}
+
+	if err := rl.Allow("user-2"); err != nil {
+		t.Errorf("other callers should have their own budget: %v", err)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if err := rl.Allow("user-1"); err != nil {
+		t.Errorf("expected a token after waiting: %v", err)
+	}
+}
+
+func TestRateLimiter_DailyQuota(t *testing.T) {
+	now := time.Date(2025, 6, 1, 23, 0, 0, 0, time.UTC)
+	rl := testLimiter(Limits{}, &now)
+	rl.SetLimits("user-1", Limits{DailyQuota: 2})
+
+	_ = rl.Allow("user-1")
+	_ = rl.Allow("user-1")
+
+	err := rl.Allow("user-1")
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) || limitErr.Reason != ReasonQuotaExceeded {
+		t.Fatalf("expected quota error, got %v", err)
+	}
+	if want := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC); !limitErr.ResetAt.Equal(want) {
+		t.Errorf("ResetAt = %v, want %v", limitErr.ResetAt, want)
+	}
+	if got := limitErr.RetryAfter(now); got != time.Hour {
+		t.Errorf("RetryAfter = %v, want 1h", got)
+	}
+
+	for i := 0; i < 10; i++ {
+		if err := rl.Allow("user-2"); err != nil {
+			t.Fatalf("default limits should be unlimited: %v", err)
+		}
+	}
+
+	now = now.Add(time.Hour)
+	if err := rl.Allow("user-1"); err != nil {
+		t.Errorf("expected quota to reset at midnight: %v", err)
+	}
+}
+
+func TestRateLimiter_PrunesIdleCallers(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	rl := testLimiter(Limits{RequestsPerSecond: 1, Burst: 2}, &now)
+	rl.SetLimits("quota", Limits{DailyQuota: 1})
+
+	for i := 0; i < 100; i++ {
+		_ = rl.Allow("user-" + strconv.Itoa(i))
+	}
+	_ = rl.Allow("quota")
+
+	now = now.Add(time.Hour)
+	_ = rl.Allow("active")
+	if len(rl.states) != 2 {
+		t.Errorf("expected only the active and quota budgets to be kept, got %d", len(rl.states))
+	}
+	if err := rl.Allow("quota"); err == nil {
+		t.Error("expected the quota to survive pruning until the day ends")
+	}
+
+	now = now.Add(12 * time.Hour)
+	_ = rl.Allow("active")
+	if _, ok := rl.states["quota"]; ok {
+		t.Error("expected the quota budget to be pruned after the day ended")
+	}
+}
+
+func TestRateLimiter_Middleware(t *testing.T) {
+	now := time.Now()
+	rl := testLimiter(Limits{DailyQuota: 1}, &now)
+	handler := rl.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	request := func(claims *Claims) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		if claims != nil {
+			req = req.WithContext(WithClaims(context.Background(), *claims))
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	claims := &Claims{Subject: "user-1"}
+	if rec := request(claims); rec.Code != http.StatusOK {
+		t.Fatalf("first request: status %d", rec.Code)
+	}
+
+	rec := request(claims)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("expected Retry-After header")
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid body: %v", err)
+	}
+	if body["error"] != ReasonQuotaExceeded || body["resetAt"] == nil {
+		t.Errorf("unexpected body: %v", body)
+	}
+
+	if rec := request(nil); rec.Code != http.StatusOK {
+		t.Errorf("requests without claims should pass, got %d", rec.Code)
+	}
+}
@@ -2,6 +2,7 @@ package builder
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
 	"github.com/jmcarbo/fullmcp/mcp"
@@ -142,3 +143,22 @@ func BenchmarkComplexToolBuildComplete(b *testing.B) {
 			Build()
 	}
 }
+
+// BenchmarkGenerateSchema compares generating the schema of a struct type
+// with reflection against copying the cached schema
+func BenchmarkGenerateSchema(b *testing.B) {
+	typ := reflect.TypeOf(ComplexTool{})
+
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = newSchema(typ)
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = generateSchema(typ)
+		}
+	})
+}
@@ -0,0 +1,340 @@
+// Package elicit provides a fluent builder for elicitation request schemas.
+//
+// The elicitation spec only allows flat objects whose properties are
+// primitive strings, numbers, integers, booleans or string enums. The builder
+// can only express those schemas and reports invalid combinations when the
+// schema is built, instead of leaving clients to reject them.
+package elicit
+
+import (
+	"fmt"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// Primitive property types allowed in elicitation schemas
+const (
+	TypeString  = "string"
+	TypeNumber  = "number"
+	TypeInteger = "integer"
+	TypeBoolean = "boolean"
+)
+
+// formats lists the string formats allowed in elicitation schemas
+var formats = map[string]bool{
+	"email":     true,
+	"uri":       true,
+	"date":      true,
+	"date-time": true,
+}
+
+// field describes one schema property
+type field struct {
+	name        string
+	typ         string
+	title       string
+	description string
+	required    bool
+	def         interface{}
+	format      string
+	minLength   *int
+	maxLength   *int
+	minimum     *float64
+	maximum     *float64
+	enum        []string
+	enumNames   []string
+}
+
+// FieldOption configures a schema property
+type FieldOption func(*field)
+
+// Required marks the property as required
+func Required() FieldOption {
+	return func(f *field) {
+		f.required = true
+	}
+}
+
+// Title sets the property's display title
+func Title(title string) FieldOption {
+	return func(f *field) {
+		f.title = title
+	}
+}
+
+// Description sets the property description
+func Description(desc string) FieldOption {
+	return func(f *field) {
+		f.description = desc
+	}
+}
+
+// Default sets the property's default value
+func Default(value interface{}) FieldOption {
+	return func(f *field) {
+		f.def = value
+	}
+}
+
+// Format sets the string format: email, uri, date or date-time
+func Format(format string) FieldOption {
+	return func(f *field) {
+		f.format = format
+	}
+}
+
+// MinLength sets the minimum string length
+func MinLength(n int) FieldOption {
+	return func(f *field) {
+		f.minLength = &n
+	}
+}
+
+// MaxLength sets the maximum string length
+func MaxLength(n int) FieldOption {
+	return func(f *field) {
+		f.maxLength = &n
+	}
+}
+
+// Minimum sets the minimum numeric value
+func Minimum(v float64) FieldOption {
+	return func(f *field) {
+		f.minimum = &v
+	}
+}
+
+// Maximum sets the maximum numeric value
+func Maximum(v float64) FieldOption {
+	return func(f *field) {
+		f.maximum = &v
+	}
+}
+
+// EnumNames sets display names for enum values, in the same order
+func EnumNames(names ...string) FieldOption {
+	return func(f *field) {
+		f.enumNames = names
+	}
+}
+
+// SchemaBuilder builds flat elicitation schemas using a fluent API
+type SchemaBuilder struct {
+	fields []*field
+	byName map[string]*field
+	errs   []error
+}
+
+// NewSchema creates a new schema builder
+func NewSchema() *SchemaBuilder {
+	return &SchemaBuilder{byName: make(map[string]*field)}
+}
+
+// String adds a string property
+func (sb *SchemaBuilder) String(name string, opts ...FieldOption) *SchemaBuilder {
+	return sb.add(name, TypeString, opts)
+}
+
+// Number adds a number property
+func (sb *SchemaBuilder) Number(name string, opts ...FieldOption) *SchemaBuilder {
+	return sb.add(name, TypeNumber, opts)
+}
+
+// Integer adds an integer property
+func (sb *SchemaBuilder) Integer(name string, opts ...FieldOption) *SchemaBuilder {
+	return sb.add(name, TypeInteger, opts)
+}
+
+// Boolean adds a boolean property
+func (sb *SchemaBuilder) Boolean(name string, opts ...FieldOption) *SchemaBuilder {
+	return sb.add(name, TypeBoolean, opts)
+}
+
+// Enum adds a string property restricted to the given values
+func (sb *SchemaBuilder) Enum(name string, values ...string) *SchemaBuilder {
+	return sb.EnumWith(name, values)
+}
+
+// EnumWith adds a string enum property with options
+func (sb *SchemaBuilder) EnumWith(name string, values []string, opts ...FieldOption) *SchemaBuilder {
+	sb.add(name, TypeString, opts)
+	if f, ok := sb.byName[name]; ok && f.typ == TypeString {
+		f.enum = append([]string{}, values...)
+	}
+	return sb
+}
+
+// Require marks previously added properties as required
+func (sb *SchemaBuilder) Require(names ...string) *SchemaBuilder {
+	for _, name := range names {
+		f, ok := sb.byName[name]
+		if !ok {
+			sb.errs = append(sb.errs, fmt.Errorf("cannot require unknown property %q", name))
+			continue
+		}
+		f.required = true
+	}
+	return sb
+}
+
+func (sb *SchemaBuilder) add(name, typ string, opts []FieldOption) *SchemaBuilder {
+	if name == "" {
+		sb.errs = append(sb.errs, fmt.Errorf("property name is required"))
+		return sb
+	}
+	if _, exists := sb.byName[name]; exists {
+		sb.errs = append(sb.errs, fmt.Errorf("duplicate property %q", name))
+		return sb
+	}
+
+	f := &field{name: name, typ: typ}
+	for _, opt := range opts {
+		opt(f)
+	}
+	sb.fields = append(sb.fields, f)
+	sb.byName[name] = f
+	return sb
+}
+
+// Build validates the schema and returns it as a JSON Schema object
+func (sb *SchemaBuilder) Build() (map[string]interface{}, error) {
+	if len(sb.errs) > 0 {
+		return nil, &mcp.ValidationError{Field: "schema", Message: sb.errs[0].Error()}
+	}
+
+	properties := make(map[string]interface{}, len(sb.fields))
+	required := []string{}
+
+	for _, f := range sb.fields {
+		if err := f.validate(); err != nil {
+			return nil, &mcp.ValidationError{Field: f.name, Message: err.Error()}
+		}
+		properties[f.name] = f.schema()
+		if f.required {
+			required = append(required, f.name)
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema, nil
+}
+
+// Request builds the schema into an elicitation request
+func (sb *SchemaBuilder) Request(description string) (*mcp.ElicitationRequest, error) {
+	schema, err := sb.Build()
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.ElicitationRequest{Schema: schema, Description: description}, nil
+}
+
+// validate checks that the property's options fit its type
+func (f *field) validate() error {
+	isString := f.typ == TypeString
+	isNumeric := f.typ == TypeNumber || f.typ == TypeInteger
+
+	if !isString && (f.format != "" || f.minLength != nil || f.maxLength != nil) {
+		return fmt.Errorf("format and length constraints only apply to strings")
+	}
+	if !isNumeric && (f.minimum != nil || f.maximum != nil) {
+		return fmt.Errorf("minimum and maximum only apply to numbers")
+	}
+	if f.format != "" && !formats[f.format] {
+		return fmt.Errorf("unsupported string format %q", f.format)
+	}
+	if f.minLength != nil && *f.minLength < 0 {
+		return fmt.Errorf("minLength must not be negative")
+	}
+	if f.minLength != nil && f.maxLength != nil && *f.minLength > *f.maxLength {
+		return fmt.Errorf("minLength exceeds maxLength")
+	}
+	if f.minimum != nil && f.maximum != nil && *f.minimum > *f.maximum {
+		return fmt.Errorf("minimum exceeds maximum")
+	}
+
+	if f.enum != nil {
+		if len(f.enum) == 0 {
+			return fmt.Errorf("enum requires at least one value")
+		}
+		if f.format != "" || f.minLength != nil || f.maxLength != nil {
+			return fmt.Errorf("enum properties cannot have format or length constraints")
+		}
+	}
+	if f.enumNames != nil && len(f.enumNames) != len(f.enum) {
+		return fmt.Errorf("enumNames must match the number of enum values")
+	}
+
+	if f.def != nil && !f.acceptsDefault() {
+		return fmt.Errorf("default value %v does not match type %s", f.def, f.typ)
+	}
+	return nil
+}
+
+// acceptsDefault reports whether the default value matches the property type
+func (f *field) acceptsDefault() bool {
+	switch v := f.def.(type) {
+	case string:
+		if f.typ != TypeString {
+			return false
+		}
+		if f.enum == nil {
+			return true
+		}
+		for _, allowed := range f.enum {
+			if v == allowed {
+				return true
+			}
+		}
+		return false
+	case bool:
+		return f.typ == TypeBoolean
+	case int, int32, int64:
+		return f.typ == TypeInteger || f.typ == TypeNumber
+	case float32, float64:
+		return f.typ == TypeNumber
+	default:
+		return false
+	}
+}
+
+// schema returns the JSON Schema for the property
+func (f *field) schema() map[string]interface{} {
+	s := map[string]interface{}{"type": f.typ}
+	if f.title != "" {
+		s["title"] = f.title
+	}
+	if f.description != "" {
+		s["description"] = f.description
+	}
+	if f.def != nil {
+		s["default"] = f.def
+	}
+	if f.format != "" {
+		s["format"] = f.format
+	}
+	if f.minLength != nil {
+		s["minLength"] = *f.minLength
+	}
+	if f.maxLength != nil {
+		s["maxLength"] = *f.maxLength
+	}
+	if f.minimum != nil {
+		s["minimum"] = *f.minimum
+	}
+	if f.maximum != nil {
+		s["maximum"] = *f.maximum
+	}
+	if f.enum != nil {
+		s["enum"] = f.enum
+	}
+	if f.enumNames != nil {
+		s["enumNames"] = f.enumNames
+	}
+	return s
+}
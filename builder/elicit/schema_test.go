@@ -0,0 +1,88 @@
+package elicit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func TestSchemaBuilder_Build(t *testing.T) {
+	schema, err := NewSchema().
+		String("api_key", Required(), Title("API key"), MinLength(8)).
+		Enum("region", "us-east", "eu").
+		Integer("retries", Minimum(0), Maximum(5), Default(3)).
+		Boolean("verbose").
+		Require("region").
+		Build()
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+
+	data, _ := json.Marshal(schema)
+	var got struct {
+		Type       string                            `json:"type"`
+		Properties map[string]map[string]interface{} `json:"properties"`
+		Required   []string                          `json:"required"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("failed to unmarshal schema: %v", err)
+	}
+
+	if got.Type != "object" {
+		t.Errorf("expected object schema, got %q", got.Type)
+	}
+	if len(got.Properties) != 4 {
+		t.Errorf("expected 4 properties, got %d", len(got.Properties))
+	}
+	if got.Properties["api_key"]["minLength"] != float64(8) || got.Properties["api_key"]["title"] != "API key" {
+		t.Errorf("unexpected api_key schema: %v", got.Properties["api_key"])
+	}
+	if enum, ok := got.Properties["region"]["enum"].([]interface{}); !ok || len(enum) != 2 {
+		t.Errorf("unexpected region schema: %v", got.Properties["region"])
+	}
+	if got.Properties["retries"]["maximum"] != float64(5) {
+		t.Errorf("unexpected retries schema: %v", got.Properties["retries"])
+	}
+	if len(got.Required) != 2 || got.Required[0] != "api_key" || got.Required[1] != "region" {
+		t.Errorf("unexpected required list: %v", got.Required)
+	}
+}
+
+func TestSchemaBuilder_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *SchemaBuilder
+	}{
+		{"duplicate property", NewSchema().String("a").Number("a")},
+		{"empty name", NewSchema().Boolean("")},
+		{"unknown required", NewSchema().String("a").Require("b")},
+		{"length on number", NewSchema().Number("n", MinLength(1))},
+		{"minimum on string", NewSchema().String("s", Minimum(1))},
+		{"unsupported format", NewSchema().String("s", Format("ipv4"))},
+		{"inverted range", NewSchema().Integer("n", Minimum(5), Maximum(1))},
+		{"empty enum", NewSchema().Enum("e")},
+		{"enum names mismatch", NewSchema().EnumWith("e", []string{"a", "b"}, EnumNames("A"))},
+		{"default type mismatch", NewSchema().Boolean("b", Default("yes"))},
+		{"default not in enum", NewSchema().EnumWith("e", []string{"a"}, Default("z"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.builder.Build()
+			if _, ok := err.(*mcp.ValidationError); !ok {
+				t.Errorf("expected validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSchemaBuilder_Request(t *testing.T) {
+	req, err := NewSchema().String("email", Format("email"), Required()).Request("Contact details")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if req.Description != "Contact details" || req.Schema["type"] != "object" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+}
@@ -9,10 +9,13 @@ import (
 // PromptBuilder creates prompts using a fluent API
 type PromptBuilder struct {
 	name        string
+	title       string
 	description string
 	arguments   []mcp.PromptArgument
 	renderer    server.PromptFunc
 	tags        []string
+	meta        map[string]interface{}
+	deprecated  string
 }
 
 // NewPrompt creates a new prompt builder
@@ -20,6 +23,12 @@ func NewPrompt(name string) *PromptBuilder {
 	return &PromptBuilder{name: name}
 }
 
+// Title sets the human-readable prompt title
+func (pb *PromptBuilder) Title(title string) *PromptBuilder {
+	pb.title = title
+	return pb
+}
+
 // Description sets the prompt description
 func (pb *PromptBuilder) Description(desc string) *PromptBuilder {
 	pb.description = desc
@@ -54,13 +63,32 @@ func (pb *PromptBuilder) Tags(tags ...string) *PromptBuilder {
 	return pb
 }
 
+// Meta sets a _meta entry on the prompt
+func (pb *PromptBuilder) Meta(key string, value interface{}) *PromptBuilder {
+	if pb.meta == nil {
+		pb.meta = make(map[string]interface{})
+	}
+	pb.meta[key] = value
+	return pb
+}
+
+// Deprecated marks the prompt deprecated. It still renders, but clients are
+// warned with message, which should say what to use instead.
+func (pb *PromptBuilder) Deprecated(message string) *PromptBuilder {
+	pb.deprecated = message
+	return pb
+}
+
 // Build creates the PromptHandler
 func (pb *PromptBuilder) Build() *server.PromptHandler {
 	return &server.PromptHandler{
 		Name:        pb.name,
+		Title:       pb.title,
 		Description: pb.description,
 		Arguments:   pb.arguments,
 		Renderer:    pb.renderer,
 		Tags:        pb.tags,
+		Meta:        pb.meta,
+		Deprecated:  pb.deprecated,
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/jmcarbo/fullmcp/mcp"
+	"github.com/jmcarbo/fullmcp/server"
 )
 
 func TestPromptBuilder_Build(t *testing.T) {
@@ -239,3 +240,27 @@ func TestPromptBuilder_MultipleContentBlocks(t *testing.T) {
 		t.Fatalf("expected 2 content blocks, got %d", len(messages[0].Content))
 	}
 }
+
+func TestPromptBuilder_Meta(t *testing.T) {
+	prompt := NewPrompt("meta").
+		Meta("example.com/locale", "en").
+		Build()
+
+	if prompt.Meta["example.com/locale"] != "en" {
+		t.Errorf("expected _meta entry, got %v", prompt.Meta)
+	}
+}
+
+func TestPromptBuilder_Title(t *testing.T) {
+	prompt := NewPrompt("code_review").
+		Title("Request Code Review").
+		Build()
+
+	prompts := server.NewPromptManager()
+	_ = prompts.Register(prompt)
+
+	listed := prompts.List()
+	if len(listed) != 1 || listed[0].Title != "Request Code Review" {
+		t.Errorf("expected prompt title to be listed, got %+v", listed)
+	}
+}
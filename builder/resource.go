@@ -3,6 +3,7 @@ package builder
 import (
 	"context"
 
+	"github.com/jmcarbo/fullmcp/mcp"
 	"github.com/jmcarbo/fullmcp/server"
 )
 
@@ -10,10 +11,14 @@ import (
 type ResourceBuilder struct {
 	uri         string
 	name        string
+	title       string
 	description string
 	mimeType    string
+	annotations *mcp.Annotations
+	binary      bool
 	reader      server.ResourceFunc
 	tags        []string
+	meta        map[string]interface{}
 }
 
 // NewResource creates a new resource builder
@@ -27,6 +32,12 @@ func (rb *ResourceBuilder) Name(name string) *ResourceBuilder {
 	return rb
 }
 
+// Title sets the human-readable resource title
+func (rb *ResourceBuilder) Title(title string) *ResourceBuilder {
+	rb.title = title
+	return rb
+}
+
 // Description sets the resource description
 func (rb *ResourceBuilder) Description(desc string) *ResourceBuilder {
 	rb.description = desc
@@ -39,6 +50,18 @@ func (rb *ResourceBuilder) MimeType(mimeType string) *ResourceBuilder {
 	return rb
 }
 
+// Annotations sets the resource's audience, priority and modification time
+func (rb *ResourceBuilder) Annotations(annotations *mcp.Annotations) *ResourceBuilder {
+	rb.annotations = annotations
+	return rb
+}
+
+// Binary sends the resource's data as a base64 blob instead of text
+func (rb *ResourceBuilder) Binary() *ResourceBuilder {
+	rb.binary = true
+	return rb
+}
+
 // Reader sets the resource reader function
 func (rb *ResourceBuilder) Reader(fn server.ResourceFunc) *ResourceBuilder {
 	rb.reader = fn
@@ -51,15 +74,28 @@ func (rb *ResourceBuilder) Tags(tags ...string) *ResourceBuilder {
 	return rb
 }
 
+// Meta sets a _meta entry on the resource
+func (rb *ResourceBuilder) Meta(key string, value interface{}) *ResourceBuilder {
+	if rb.meta == nil {
+		rb.meta = make(map[string]interface{})
+	}
+	rb.meta[key] = value
+	return rb
+}
+
 // Build creates the ResourceHandler
 func (rb *ResourceBuilder) Build() *server.ResourceHandler {
 	return &server.ResourceHandler{
 		URI:         rb.uri,
 		Name:        rb.name,
+		Title:       rb.title,
 		Description: rb.description,
 		MimeType:    rb.mimeType,
+		Annotations: rb.annotations,
+		Binary:      rb.binary,
 		Reader:      rb.reader,
 		Tags:        rb.tags,
+		Meta:        rb.meta,
 	}
 }
 
@@ -67,10 +103,14 @@ func (rb *ResourceBuilder) Build() *server.ResourceHandler {
 type ResourceTemplateBuilder struct {
 	uriTemplate string
 	name        string
+	title       string
 	description string
 	mimeType    string
+	annotations *mcp.Annotations
+	binary      bool
 	reader      server.ResourceTemplateFunc
 	tags        []string
+	meta        map[string]interface{}
 }
 
 // NewResourceTemplate creates a new resource template builder
@@ -84,6 +124,12 @@ func (rtb *ResourceTemplateBuilder) Name(name string) *ResourceTemplateBuilder {
 	return rtb
 }
 
+// Title sets the human-readable resource template title
+func (rtb *ResourceTemplateBuilder) Title(title string) *ResourceTemplateBuilder {
+	rtb.title = title
+	return rtb
+}
+
 // Description sets the resource template description
 func (rtb *ResourceTemplateBuilder) Description(desc string) *ResourceTemplateBuilder {
 	rtb.description = desc
@@ -96,6 +142,18 @@ func (rtb *ResourceTemplateBuilder) MimeType(mimeType string) *ResourceTemplateB
 	return rtb
 }
 
+// Annotations sets the template's audience, priority and modification time
+func (rtb *ResourceTemplateBuilder) Annotations(annotations *mcp.Annotations) *ResourceTemplateBuilder {
+	rtb.annotations = annotations
+	return rtb
+}
+
+// Binary sends the template's data as a base64 blob instead of text
+func (rtb *ResourceTemplateBuilder) Binary() *ResourceTemplateBuilder {
+	rtb.binary = true
+	return rtb
+}
+
 // Reader sets the resource template reader function
 func (rtb *ResourceTemplateBuilder) Reader(fn server.ResourceTemplateFunc) *ResourceTemplateBuilder {
 	rtb.reader = fn
@@ -120,14 +178,27 @@ func (rtb *ResourceTemplateBuilder) Tags(tags ...string) *ResourceTemplateBuilde
 	return rtb
 }
 
+// Meta sets a _meta entry on the resource template
+func (rtb *ResourceTemplateBuilder) Meta(key string, value interface{}) *ResourceTemplateBuilder {
+	if rtb.meta == nil {
+		rtb.meta = make(map[string]interface{})
+	}
+	rtb.meta[key] = value
+	return rtb
+}
+
 // Build creates the ResourceTemplateHandler
 func (rtb *ResourceTemplateBuilder) Build() *server.ResourceTemplateHandler {
 	return &server.ResourceTemplateHandler{
 		URITemplate: rtb.uriTemplate,
 		Name:        rtb.name,
+		Title:       rtb.title,
 		Description: rtb.description,
 		MimeType:    rtb.mimeType,
+		Annotations: rtb.annotations,
+		Binary:      rtb.binary,
 		Reader:      rtb.reader,
 		Tags:        rtb.tags,
+		Meta:        rtb.meta,
 	}
 }
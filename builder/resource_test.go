@@ -2,7 +2,11 @@ package builder
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+	"github.com/jmcarbo/fullmcp/server"
 )
 
 func TestResourceBuilder_Build(t *testing.T) {
@@ -196,3 +200,72 @@ func TestResourceTemplateBuilder_Chaining(t *testing.T) {
 		t.Errorf("expected name 'User Data', got '%s'", template.Name)
 	}
 }
+
+func TestResourceBuilder_Meta(t *testing.T) {
+	resource := NewResource("config://app").
+		Meta("example.com/source", "disk").
+		Meta("example.com/ttl", 60).
+		Build()
+
+	if resource.Meta["example.com/source"] != "disk" || resource.Meta["example.com/ttl"] != 60 {
+		t.Errorf("expected _meta entries, got %v", resource.Meta)
+	}
+
+	template := NewResourceTemplate("user:///{id}").
+		Meta("example.com/source", "db").
+		Build()
+
+	if template.Meta["example.com/source"] != "db" {
+		t.Errorf("expected template _meta entry, got %v", template.Meta)
+	}
+}
+
+func TestResourceBuilder_Annotations(t *testing.T) {
+	annotations := mcp.NewAnnotations().WithAudience(mcp.RoleUser).WithPriority(0.4)
+
+	resource := NewResource("config://app").Annotations(annotations).Build()
+	if resource.Annotations != annotations {
+		t.Errorf("expected resource annotations, got %+v", resource.Annotations)
+	}
+
+	template := NewResourceTemplate("user:///{id}").Annotations(annotations).Build()
+	if template.Annotations != annotations {
+		t.Errorf("expected template annotations, got %+v", template.Annotations)
+	}
+}
+
+func TestResourceBuilder_Title(t *testing.T) {
+	resource := NewResource("config://app").
+		Name("app_config").
+		Title("App Configuration").
+		Build()
+
+	srv := server.New("test")
+	_ = srv.AddResource(resource)
+	_ = srv.AddResourceTemplate(NewResourceTemplate("user:///{id}").
+		Name("user").
+		Title("User Profile").
+		Build())
+
+	resp := srv.HandleMessage(context.Background(), &mcp.Message{JSONRPC: "2.0", ID: 1, Method: "resources/list"})
+	var resources struct {
+		Resources []*mcp.Resource `json:"resources"`
+	}
+	if err := json.Unmarshal(resp.Result, &resources); err != nil {
+		t.Fatalf("failed to unmarshal resources: %v", err)
+	}
+	if len(resources.Resources) != 1 || resources.Resources[0].Title != "App Configuration" {
+		t.Errorf("expected resource title to be listed, got %+v", resources.Resources)
+	}
+
+	resp = srv.HandleMessage(context.Background(), &mcp.Message{JSONRPC: "2.0", ID: 2, Method: "resources/templates/list"})
+	var templates struct {
+		ResourceTemplates []*mcp.ResourceTemplate `json:"resourceTemplates"`
+	}
+	if err := json.Unmarshal(resp.Result, &templates); err != nil {
+		t.Fatalf("failed to unmarshal templates: %v", err)
+	}
+	if len(templates.ResourceTemplates) != 1 || templates.ResourceTemplates[0].Title != "User Profile" {
+		t.Errorf("expected template title to be listed, got %+v", templates.ResourceTemplates)
+	}
+}
@@ -0,0 +1,401 @@
+package builder
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// schemaGenerator builds JSON schemas from Go types. Struct types are inlined
+// where they are used; types that refer to themselves are also emitted once
+// under $defs so the recursive uses can point at them with $ref.
+type schemaGenerator struct {
+	names     map[reflect.Type]string
+	taken     map[string]bool
+	active    map[reflect.Type]bool // Struct types being generated
+	recursive map[reflect.Type]bool
+	defs      map[string]interface{}
+	err       error // First invalid jsonschema tag
+}
+
+// schemaCache holds the schema generated for each type, so tools sharing
+// input or output types reflect over them once
+var schemaCache sync.Map // reflect.Type -> cachedSchema
+
+type cachedSchema struct {
+	schema map[string]interface{}
+	err    error
+}
+
+// generateSchema returns the JSON schema for values of type t. It fails when
+// a jsonschema struct tag is malformed. Each call returns a copy, so callers
+// may modify it.
+func generateSchema(t reflect.Type) (map[string]interface{}, error) {
+	if cached, ok := schemaCache.Load(t); ok {
+		c := cached.(cachedSchema)
+		return cloneSchema(c.schema), c.err
+	}
+
+	schema, err := newSchema(t)
+	schemaCache.Store(t, cachedSchema{schema: schema, err: err})
+	return cloneSchema(schema), err
+}
+
+// newSchema generates the JSON schema for values of type t
+func newSchema(t reflect.Type) (map[string]interface{}, error) {
+	g := &schemaGenerator{
+		names:     make(map[reflect.Type]string),
+		taken:     make(map[string]bool),
+		active:    make(map[reflect.Type]bool),
+		recursive: make(map[reflect.Type]bool),
+		defs:      make(map[string]interface{}),
+	}
+
+	schema := g.schemaFor(t)
+	if g.err != nil {
+		return nil, g.err
+	}
+	if len(g.defs) > 0 {
+		schema["$defs"] = g.defs
+	}
+	return schema, nil
+}
+
+// cloneSchema deep copies the maps and slices of a generated schema
+func cloneSchema(schema map[string]interface{}) map[string]interface{} {
+	if schema == nil {
+		return nil
+	}
+	return cloneValue(schema).(map[string]interface{})
+}
+
+func cloneValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		clone := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			clone[key] = cloneValue(value)
+		}
+		return clone
+	case []interface{}:
+		clone := make([]interface{}, len(v))
+		for i, value := range v {
+			clone[i] = cloneValue(value)
+		}
+		return clone
+	case []string:
+		return slices.Clone(v)
+	default:
+		return v
+	}
+}
+
+func (g *schemaGenerator) schemaFor(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	// Types with custom encodings have no shape that can be derived
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case implements(t, jsonMarshalerType):
+		return map[string]interface{}{}
+	case implements(t, textMarshalerType):
+		return map[string]interface{}{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]interface{}{"type": "array", "items": g.schemaFor(t.Elem())}
+	case reflect.Array:
+		return map[string]interface{}{
+			"type":     "array",
+			"items":    g.schemaFor(t.Elem()),
+			"minItems": t.Len(),
+			"maxItems": t.Len(),
+		}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		return g.structSchema(t)
+	default:
+		// Interfaces accept any value
+		return map[string]interface{}{}
+	}
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]interface{} {
+	if t.Name() != "" {
+		if g.active[t] {
+			g.recursive[t] = true
+			return map[string]interface{}{"$ref": "#/$defs/" + g.nameOf(t)}
+		}
+		g.active[t] = true
+		defer delete(g.active, t)
+	}
+
+	properties := make(map[string]interface{})
+	required := []string{}
+	g.addFields(t, properties, &required)
+
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	if g.recursive[t] {
+		g.defs[g.nameOf(t)] = maps.Clone(schema)
+	}
+	return schema
+}
+
+// addFields adds the properties of struct t, following encoding/json's rules
+// for names, omitted fields and embedded structs
+func (g *schemaGenerator) addFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		name, jsonOpts, _ := strings.Cut(jsonTag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.addFields(embedded, properties, required)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		tag, err := parseSchemaTag(field.Tag.Get("jsonschema"), field.Type)
+		if err != nil {
+			if g.err == nil {
+				g.err = fmt.Errorf("field %s.%s: %w", t.Name(), field.Name, err)
+			}
+			continue
+		}
+		if tag.skip {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := g.schemaFor(field.Type)
+		if hasOption(jsonOpts, "string") {
+			prop = map[string]interface{}{"type": "string"}
+		}
+		tag.apply(prop)
+
+		properties[name] = prop
+		if tag.required || !hasOption(jsonOpts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// nameOf returns the $defs name for t, made unique across packages
+func (g *schemaGenerator) nameOf(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+
+	base := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, t.Name())
+
+	name := base
+	for n := 2; g.taken[name]; n++ {
+		name = base + strconv.Itoa(n)
+	}
+	g.names[t] = name
+	g.taken[name] = true
+	return name
+}
+
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PointerTo(t).Implements(iface)
+}
+
+func hasOption(opts, option string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaTag holds the settings of a `jsonschema:"..."` struct tag, such as
+// `jsonschema:"required,description=Age,minimum=0,maximum=150"`
+type schemaTag struct {
+	skip     bool
+	required bool
+	keywords map[string]interface{} // Schema keywords added to the property
+}
+
+// Keywords accepted in jsonschema tags, by the kind of value they take
+var (
+	textKeywords    = map[string]bool{"title": true, "description": true, "format": true, "pattern": true}
+	numberKeywords  = map[string]bool{"minimum": true, "maximum": true, "exclusiveMinimum": true, "exclusiveMaximum": true, "multipleOf": true}
+	countKeywords   = map[string]bool{"minLength": true, "maxLength": true, "minItems": true, "maxItems": true, "minProperties": true, "maxProperties": true}
+	booleanKeywords = map[string]bool{"uniqueItems": true}
+)
+
+// parseSchemaTag parses a comma-separated jsonschema tag on a field of type
+// t. Commas inside values, as in a pattern, are escaped as `\,`.
+func parseSchemaTag(tag string, t reflect.Type) (schemaTag, error) {
+	st := schemaTag{keywords: make(map[string]interface{})}
+	if tag == "-" {
+		st.skip = true
+		return st, nil
+	}
+
+	var enum []interface{}
+	for _, part := range splitTag(tag) {
+		key, value, hasValue := strings.Cut(part, "=")
+
+		switch {
+		case key == "required":
+			st.required = true
+		case key == "enum":
+			for _, v := range strings.Split(value, "|") {
+				enum = append(enum, tagValue(v, t))
+			}
+		case key == "default":
+			st.keywords[key] = tagValue(value, t)
+		case textKeywords[key]:
+			if key == "pattern" {
+				if _, err := regexp.Compile(value); err != nil {
+					return st, fmt.Errorf("invalid pattern %q: %w", value, err)
+				}
+			}
+			st.keywords[key] = value
+		case numberKeywords[key]:
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return st, fmt.Errorf("%s must be a number, got %q", key, value)
+			}
+			st.keywords[key] = n
+		case countKeywords[key]:
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return st, fmt.Errorf("%s must be a non-negative integer, got %q", key, value)
+			}
+			st.keywords[key] = n
+		case booleanKeywords[key]:
+			b := true
+			if hasValue {
+				var err error
+				if b, err = strconv.ParseBool(value); err != nil {
+					return st, fmt.Errorf("%s must be a boolean, got %q", key, value)
+				}
+			}
+			st.keywords[key] = b
+		default:
+			return st, fmt.Errorf("unknown jsonschema tag option %q", key)
+		}
+	}
+
+	if enum != nil {
+		st.keywords["enum"] = enum
+	}
+	return st, nil
+}
+
+// apply adds the tag's keywords to a property schema
+func (st schemaTag) apply(prop map[string]interface{}) {
+	for key, value := range st.keywords {
+		prop[key] = value
+	}
+}
+
+// tagValue converts a tag value to the JSON type of fields of type t
+func tagValue(value string, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
+
+func splitTag(tag string) []string {
+	var (
+		parts   []string
+		current strings.Builder
+	)
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			current.WriteByte(',')
+			i++
+		case tag[i] == ',':
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(tag[i])
+		}
+	}
+	if current.Len() > 0 {
+		parts = append(parts, current.String())
+	}
+	return parts
+}
@@ -0,0 +1,284 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+	"github.com/jmcarbo/fullmcp/server"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden schema files")
+
+type schemaAddress struct {
+	Street string `json:"street"`
+	City   string `json:"city"`
+}
+
+type schemaItem struct {
+	SKU      string  `json:"sku"`
+	Quantity int     `json:"quantity"`
+	Price    float64 `json:"price,omitempty"`
+}
+
+type schemaOrder struct {
+	ID        string            `json:"id" jsonschema:"description=Order identifier"`
+	Status    string            `json:"status" jsonschema:"enum=open,enum=closed"`
+	Customer  *schemaAddress    `json:"customer,omitempty"`
+	Shipping  schemaAddress     `json:"shipping"`
+	Items     []schemaItem      `json:"items"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Note      *string           `json:"note,omitempty"`
+	Raw       json.RawMessage   `json:"raw,omitempty"`
+	Payload   []byte            `json:"payload,omitempty"`
+	Any       interface{}       `json:"any,omitempty"`
+	internal  string
+	Ignored   string `json:"-"`
+}
+
+type schemaTree struct {
+	Value    int           `json:"value"`
+	Children []*schemaTree `json:"children,omitempty"`
+}
+
+type schemaForest struct {
+	Trees []schemaTree `json:"trees"`
+}
+
+type schemaPerson struct {
+	Name    string         `json:"name"`
+	Company *schemaCompany `json:"company,omitempty"`
+}
+
+type schemaCompany struct {
+	Name      string         `json:"name"`
+	Employees []schemaPerson `json:"employees"`
+}
+
+type schemaBase struct {
+	ID      string `json:"id"`
+	Version int    `json:"version,omitempty"`
+}
+
+type schemaEmbedded struct {
+	schemaBase
+	Name string `json:"name"`
+}
+
+type schemaSignup struct {
+	Username string   `json:"username" jsonschema:"minLength=3,maxLength=16,pattern=^[a-z]+$"`
+	Email    string   `json:"email" jsonschema:"format=email,description=Contact address"`
+	Age      int      `json:"age" jsonschema:"minimum=0,maximum=150"`
+	Score    float64  `json:"score,omitempty" jsonschema:"exclusiveMinimum=0,multipleOf=0.5,default=1"`
+	Tags     []string `json:"tags,omitempty" jsonschema:"minItems=1,maxItems=5,uniqueItems"`
+	Code     string   `json:"code,omitempty" jsonschema:"pattern=^[A-Z]{2\\,3}$"`
+	Plan     string   `json:"plan,omitempty" jsonschema:"enum=free|pro"`
+	Level    int      `json:"level,omitempty" jsonschema:"enum=1,enum=2"`
+}
+
+func TestGenerateSchema_Golden(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  reflect.Type
+	}{
+		{"nested", reflect.TypeOf(schemaOrder{})},
+		{"recursive", reflect.TypeOf(schemaForest{})},
+		{"recursive_root", reflect.TypeOf(&schemaTree{})},
+		{"mutual", reflect.TypeOf(schemaPerson{})},
+		{"embedded", reflect.TypeOf(schemaEmbedded{})},
+		{"map", reflect.TypeOf(map[string][]schemaItem{})},
+		{"constraints", reflect.TypeOf(schemaSignup{})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := generateSchema(tt.typ)
+			if err != nil {
+				t.Fatalf("generateSchema failed: %v", err)
+			}
+			got, err := json.MarshalIndent(schema, "", "  ")
+			if err != nil {
+				t.Fatalf("marshal failed: %v", err)
+			}
+			got = append(got, '\n')
+
+			path := filepath.Join("testdata", "schema", tt.name+".json")
+			if *updateGolden {
+				if err := os.WriteFile(path, got, 0o644); err != nil {
+					t.Fatalf("write golden file: %v", err)
+				}
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read golden file: %v", err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("schema mismatch for %s (run with -update to accept)\ngot:\n%s\nwant:\n%s", tt.name, got, want)
+			}
+		})
+	}
+}
+
+func TestToolBuilder_RecursiveInputValidation(t *testing.T) {
+	tool, err := NewTool("sum").
+		Handler(func(_ context.Context, input schemaForest) (int, error) {
+			var sum func(schemaTree) int
+			sum = func(tree schemaTree) int {
+				total := tree.Value
+				for _, child := range tree.Children {
+					total += sum(*child)
+				}
+				return total
+			}
+
+			total := 0
+			for _, tree := range input.Trees {
+				total += sum(tree)
+			}
+			return total, nil
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	tm := server.NewToolManager()
+	if err := tm.Register(tool); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	result, err := tm.Call(context.Background(), "sum", json.RawMessage(`{"trees":[{"value":1,"children":[{"value":2,"children":[{"value":3}]}]}]}`))
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if result != 6 {
+		t.Errorf("expected 6, got %v", result)
+	}
+
+	_, err = tm.Call(context.Background(), "sum", json.RawMessage(`{"trees":[{"value":1,"children":[{"value":2,"children":[{"value":"three"}]}]}]}`))
+	if err == nil {
+		t.Error("expected validation error for nested invalid value")
+	}
+}
+
+func TestGenerateSchema_InvalidTags(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  reflect.Type
+	}{
+		{"number", reflect.TypeOf(struct {
+			Age int `json:"age" jsonschema:"minimum=zero"`
+		}{})},
+		{"count", reflect.TypeOf(struct {
+			Name string `json:"name" jsonschema:"minLength=-1"`
+		}{})},
+		{"pattern", reflect.TypeOf(struct {
+			Name string `json:"name" jsonschema:"pattern=[a-z"`
+		}{})},
+		{"unknown", reflect.TypeOf(struct {
+			Name string `json:"name" jsonschema:"minimun=1"`
+		}{})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := generateSchema(tt.typ); err == nil {
+				t.Error("expected error for invalid tag")
+			}
+		})
+	}
+
+	_, err := NewTool("bad").
+		Handler(func(_ context.Context, input struct {
+			Age int `json:"age" jsonschema:"maximum=lots"`
+		}) (int, error) {
+			return input.Age, nil
+		}).
+		Build()
+	if err == nil {
+		t.Error("expected Build to report the invalid tag")
+	}
+}
+
+func TestToolBuilder_ConstraintValidation(t *testing.T) {
+	tool, err := NewTool("signup").
+		Handler(func(_ context.Context, input schemaSignup) (string, error) {
+			return input.Username, nil
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	tm := server.NewToolManager()
+	if err := tm.Register(tool); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	valid := `{"username":"alice","email":"alice@example.com","age":30,"tags":["a"],"code":"ABC"}`
+	if _, err := tm.Call(context.Background(), "signup", json.RawMessage(valid)); err != nil {
+		t.Fatalf("expected valid arguments to pass: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		args  string
+		field string
+	}{
+		{"too short", `{"username":"al","email":"al@example.com","age":30}`, "username"},
+		{"pattern", `{"username":"Alice","email":"alice@example.com","age":30}`, "username"},
+		{"format", `{"username":"alice","email":"not-an-email","age":30}`, "email"},
+		{"minimum", `{"username":"alice","email":"alice@example.com","age":-1}`, "age"},
+		{"maximum", `{"username":"alice","email":"alice@example.com","age":200}`, "age"},
+		{"unique items", `{"username":"alice","email":"alice@example.com","age":30,"tags":["a","a"]}`, "tags"},
+		{"escaped comma", `{"username":"alice","email":"alice@example.com","age":30,"code":"ABCD"}`, "code"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tm.Call(context.Background(), "signup", json.RawMessage(tt.args))
+			var verr *mcp.ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+			if verr.Field != tt.field {
+				t.Errorf("expected error on %q, got %q: %v", tt.field, verr.Field, verr)
+			}
+		})
+	}
+}
+
+func TestGenerateSchema_CachedCopies(t *testing.T) {
+	typ := reflect.TypeOf(ComplexTool{})
+
+	first, err := generateSchema(typ)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if _, ok := schemaCache.Load(typ); !ok {
+		t.Fatal("expected the schema to be cached")
+	}
+
+	// Changes to a returned schema must not leak into later tools
+	requiredCount := len(first["required"].([]string))
+	first["properties"].(map[string]interface{})["name"].(map[string]interface{})["description"] = "changed"
+	first["required"] = append(first["required"].([]string), "extra")
+
+	second, _ := generateSchema(typ)
+	name := second["properties"].(map[string]interface{})["name"].(map[string]interface{})
+	if name["description"] != "Name field" {
+		t.Errorf("expected an unmodified copy, got description %q", name["description"])
+	}
+	if required := second["required"].([]string); len(required) != requiredCount {
+		t.Errorf("expected an unmodified required list, got %v", required)
+	}
+}
@@ -6,15 +6,20 @@ import (
 	"fmt"
 	"reflect"
 
-	"github.com/invopop/jsonschema"
+	"github.com/jmcarbo/fullmcp/mcp"
 	"github.com/jmcarbo/fullmcp/server"
 )
 
+// RawToolHandler handles tool calls with the unparsed arguments
+type RawToolHandler func(ctx context.Context, args json.RawMessage) ([]mcp.Content, error)
+
 // ToolBuilder creates tools from functions
 type ToolBuilder struct {
 	name         string
 	description  string
 	fn           interface{}
+	rawHandler   RawToolHandler
+	inputSchema  map[string]interface{}
 	tags         []string
 	outputSchema map[string]interface{} // 2025-06-18
 	// 2025-03-26 annotations
@@ -23,6 +28,11 @@ type ToolBuilder struct {
 	destructiveHint *bool
 	idempotentHint  *bool
 	openWorldHint   *bool
+	// Custom annotations
+	annotations map[string]interface{}
+	meta        map[string]interface{} // 2025-06-18 _meta
+	deprecated  string
+	schemaErr   error // From OutputSchemaFromType
 }
 
 // NewTool creates a new tool builder
@@ -42,6 +52,22 @@ func (tb *ToolBuilder) Handler(fn interface{}) *ToolBuilder {
 	return tb
 }
 
+// RawHandler sets a handler that receives the arguments as raw JSON, for
+// tools whose inputs cannot be modeled as a Go struct, such as dynamic
+// schemas or pass-through proxies. No reflection is involved; the input
+// schema comes from InputSchema.
+func (tb *ToolBuilder) RawHandler(fn RawToolHandler) *ToolBuilder {
+	tb.rawHandler = fn
+	return tb
+}
+
+// InputSchema sets the tool input schema, replacing the one generated from
+// the handler's input type
+func (tb *ToolBuilder) InputSchema(schema map[string]interface{}) *ToolBuilder {
+	tb.inputSchema = schema
+	return tb
+}
+
 // OutputSchema sets the tool output schema (2025-06-18)
 func (tb *ToolBuilder) OutputSchema(schema map[string]interface{}) *ToolBuilder {
 	tb.outputSchema = schema
@@ -50,14 +76,9 @@ func (tb *ToolBuilder) OutputSchema(schema map[string]interface{}) *ToolBuilder
 
 // OutputSchemaFromType generates output schema from a Go type (2025-06-18)
 func (tb *ToolBuilder) OutputSchemaFromType(outputType interface{}) *ToolBuilder {
-	reflector := jsonschema.Reflector{
-		DoNotReference: true, // Inline all schemas instead of using $ref
+	if outputType != nil {
+		tb.outputSchema, tb.schemaErr = generateSchema(reflect.TypeOf(outputType))
 	}
-	jsonSchema := reflector.Reflect(outputType)
-	schemaBytes, _ := json.Marshal(jsonSchema)
-	var schema map[string]interface{}
-	_ = json.Unmarshal(schemaBytes, &schema)
-	tb.outputSchema = schema
 	return tb
 }
 
@@ -101,6 +122,32 @@ func (tb *ToolBuilder) OpenWorld() *ToolBuilder {
 	return tb
 }
 
+// Annotation adds a custom annotation, such as cost tier, owner or SLA,
+// serialized alongside the hints in the tool's annotations object
+func (tb *ToolBuilder) Annotation(key string, value interface{}) *ToolBuilder {
+	if tb.annotations == nil {
+		tb.annotations = make(map[string]interface{})
+	}
+	tb.annotations[key] = value
+	return tb
+}
+
+// Meta sets a _meta entry on the tool
+func (tb *ToolBuilder) Meta(key string, value interface{}) *ToolBuilder {
+	if tb.meta == nil {
+		tb.meta = make(map[string]interface{})
+	}
+	tb.meta[key] = value
+	return tb
+}
+
+// Deprecated marks the tool deprecated. Calls still work, but clients are
+// warned with message, which should say what to use instead.
+func (tb *ToolBuilder) Deprecated(message string) *ToolBuilder {
+	tb.deprecated = message
+	return tb
+}
+
 // validateFunctionSignature validates the handler function signature
 func validateFunctionSignature(fnType reflect.Type) error {
 	if fnType.Kind() != reflect.Func {
@@ -119,18 +166,20 @@ func validateFunctionSignature(fnType reflect.Type) error {
 	return nil
 }
 
+// callToolRequestType is the input of handlers that take the full request
+var callToolRequestType = reflect.TypeOf(&mcp.CallToolRequest{})
+
 // generateJSONSchema generates JSON schema from input type
-func generateJSONSchema(fnType reflect.Type) map[string]interface{} {
-	if fnType.NumIn() > 1 {
-		inputType := fnType.In(1)
-		reflector := jsonschema.Reflector{}
-		jsonSchema := reflector.Reflect(reflect.New(inputType).Interface())
-		schemaBytes, _ := json.Marshal(jsonSchema)
-		var schema map[string]interface{}
-		_ = json.Unmarshal(schemaBytes, &schema)
-		return schema
+func generateJSONSchema(fnType reflect.Type) (map[string]interface{}, error) {
+	if fnType.NumIn() > 1 && fnType.In(1) != callToolRequestType {
+		return generateSchema(fnType.In(1))
 	}
 
+	return emptyObjectSchema(), nil
+}
+
+// emptyObjectSchema is the input schema of tools without arguments
+func emptyObjectSchema() map[string]interface{} {
 	return map[string]interface{}{
 		"type":       "object",
 		"properties": map[string]interface{}{},
@@ -143,7 +192,10 @@ func (tb *ToolBuilder) createHandlerWrapper(fnType reflect.Type) func(context.Co
 		fnValue := reflect.ValueOf(tb.fn)
 		callArgs := []reflect.Value{reflect.ValueOf(ctx)}
 
-		if fnType.NumIn() > 1 {
+		switch {
+		case fnType.NumIn() > 1 && fnType.In(1) == callToolRequestType:
+			callArgs = append(callArgs, reflect.ValueOf(toolCallRequest(ctx, tb.name, args)))
+		case fnType.NumIn() > 1:
 			inputType := fnType.In(1)
 			input := reflect.New(inputType).Interface()
 
@@ -169,31 +221,78 @@ func (tb *ToolBuilder) createHandlerWrapper(fnType reflect.Type) func(context.Co
 	}
 }
 
+// toolCallRequest returns the request being handled, or one carrying just
+// the arguments when the tool is called outside a tools/call request
+func toolCallRequest(ctx context.Context, name string, args json.RawMessage) *mcp.CallToolRequest {
+	if req, ok := server.CallToolRequestFromContext(ctx); ok {
+		return req
+	}
+	return &mcp.CallToolRequest{Name: name, Arguments: args}
+}
+
 // Build creates the ToolHandler
 func (tb *ToolBuilder) Build() (*server.ToolHandler, error) {
-	if tb.fn == nil {
+	if tb.fn == nil && tb.rawHandler == nil {
 		return nil, fmt.Errorf("handler function is required")
 	}
+	if tb.fn != nil && tb.rawHandler != nil {
+		return nil, fmt.Errorf("handler and raw handler are mutually exclusive")
+	}
+	if tb.schemaErr != nil {
+		return nil, fmt.Errorf("output schema: %w", tb.schemaErr)
+	}
 
-	fnType := reflect.TypeOf(tb.fn)
-	if err := validateFunctionSignature(fnType); err != nil {
+	schema, handler, err := tb.buildHandler()
+	if err != nil {
 		return nil, err
 	}
-
-	schema := generateJSONSchema(fnType)
-	handler := tb.createHandlerWrapper(fnType)
+	if tb.inputSchema != nil {
+		schema = tb.inputSchema
+	}
 
 	return &server.ToolHandler{
-		Name:            tb.name,
-		Description:     tb.description,
-		Schema:          schema,
-		OutputSchema:    tb.outputSchema, // 2025-06-18
-		Handler:         handler,
-		Tags:            tb.tags,
-		Title:           tb.title,
-		ReadOnlyHint:    tb.readOnlyHint,
-		DestructiveHint: tb.destructiveHint,
-		IdempotentHint:  tb.idempotentHint,
-		OpenWorldHint:   tb.openWorldHint,
+		Name:              tb.name,
+		Description:       tb.description,
+		Schema:            schema,
+		OutputSchema:      tb.outputSchema, // 2025-06-18
+		Handler:           handler,
+		Tags:              tb.tags,
+		Title:             tb.title,
+		ReadOnlyHint:      tb.readOnlyHint,
+		DestructiveHint:   tb.destructiveHint,
+		IdempotentHint:    tb.idempotentHint,
+		OpenWorldHint:     tb.openWorldHint,
+		CustomAnnotations: tb.annotations,
+		Meta:              tb.meta,
+		Deprecated:        tb.deprecated,
 	}, nil
 }
+
+// buildHandler returns the generated input schema and the server handler
+func (tb *ToolBuilder) buildHandler() (map[string]interface{}, func(context.Context, json.RawMessage) (interface{}, error), error) {
+	if tb.rawHandler != nil {
+		raw := tb.rawHandler
+		handler := func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+			content, err := raw(ctx, args)
+			if err != nil {
+				return nil, err
+			}
+			if content == nil {
+				content = []mcp.Content{}
+			}
+			return content, nil
+		}
+		return emptyObjectSchema(), handler, nil
+	}
+
+	fnType := reflect.TypeOf(tb.fn)
+	if err := validateFunctionSignature(fnType); err != nil {
+		return nil, nil, err
+	}
+
+	schema, err := generateJSONSchema(fnType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("input schema: %w", err)
+	}
+	return schema, tb.createHandlerWrapper(fnType), nil
+}
@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+	"github.com/jmcarbo/fullmcp/server"
 )
 
 type TestInput struct {
@@ -283,3 +286,230 @@ func TestToolBuilder_EmptyArgs(t *testing.T) {
 		t.Errorf("expected 0 for empty args, got %d", sum)
 	}
 }
+
+func TestToolBuilder_Annotations(t *testing.T) {
+	handler, err := NewTool("annotated").
+		Annotation("costTier", "high").
+		Annotation("owner", "platform-team").
+		ReadOnly().
+		Handler(func(ctx context.Context) (string, error) {
+			return "ok", nil
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build tool: %v", err)
+	}
+
+	if handler.CustomAnnotations["costTier"] != "high" || handler.CustomAnnotations["owner"] != "platform-team" {
+		t.Errorf("unexpected annotations: %v", handler.CustomAnnotations)
+	}
+
+	srv := server.New("test")
+	if err := srv.AddTool(handler); err != nil {
+		t.Fatalf("failed to add tool: %v", err)
+	}
+
+	resp := srv.HandleMessage(context.Background(), &mcp.Message{JSONRPC: "2.0", ID: 1, Method: "tools/list"})
+	var result struct {
+		Tools []map[string]interface{} `json:"tools"`
+	}
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatalf("failed to unmarshal tools: %v", err)
+	}
+
+	annotations, ok := result.Tools[0]["annotations"].(map[string]interface{})
+	if !ok || annotations["costTier"] != "high" || annotations["readOnlyHint"] != true {
+		t.Errorf("expected hints and custom annotations in one object, got %v", result.Tools[0])
+	}
+	if _, ok := result.Tools[0]["readOnlyHint"]; ok {
+		t.Errorf("expected no top-level hints, got %v", result.Tools[0])
+	}
+}
+
+func TestToolBuilder_Meta(t *testing.T) {
+	handler, err := NewTool("meta").
+		Meta("example.com/version", "2").
+		Handler(func(ctx context.Context) (string, error) {
+			return "ok", nil
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build tool: %v", err)
+	}
+
+	srv := server.New("test")
+	if err := srv.AddTool(handler); err != nil {
+		t.Fatalf("failed to add tool: %v", err)
+	}
+
+	resp := srv.HandleMessage(context.Background(), &mcp.Message{JSONRPC: "2.0", ID: 1, Method: "tools/list"})
+	var result struct {
+		Tools []map[string]interface{} `json:"tools"`
+	}
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatalf("failed to unmarshal tools: %v", err)
+	}
+
+	meta, ok := result.Tools[0]["_meta"].(map[string]interface{})
+	if !ok || meta["example.com/version"] != "2" {
+		t.Errorf("expected _meta to be serialized, got %v", result.Tools[0])
+	}
+}
+
+func TestToolBuilder_RawHandler(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"query": map[string]interface{}{"type": "string"},
+		},
+		"required": []string{"query"},
+	}
+
+	var received json.RawMessage
+	tool, err := NewTool("proxy").
+		InputSchema(schema).
+		RawHandler(func(_ context.Context, args json.RawMessage) ([]mcp.Content, error) {
+			received = args
+			return []mcp.Content{&mcp.TextContent{Type: "text", Text: "forwarded"}}, nil
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if tool.Schema["required"] == nil {
+		t.Errorf("expected the provided input schema, got %v", tool.Schema)
+	}
+
+	tm := server.NewToolManager()
+	_ = tm.Register(tool)
+
+	args := json.RawMessage(`{"query":"q","extra":{"nested":true}}`)
+	result, err := tm.Call(context.Background(), "proxy", args)
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if string(received) != string(args) {
+		t.Errorf("expected raw arguments %s, got %s", args, received)
+	}
+	content, ok := result.([]mcp.Content)
+	if !ok || len(content) != 1 {
+		t.Fatalf("expected content to pass through, got %#v", result)
+	}
+
+	if _, err := tm.Call(context.Background(), "proxy", json.RawMessage(`{}`)); err == nil {
+		t.Error("expected arguments to be validated against the input schema")
+	}
+}
+
+func TestToolBuilder_RawHandlerDefaults(t *testing.T) {
+	tool, err := NewTool("raw").
+		RawHandler(func(context.Context, json.RawMessage) ([]mcp.Content, error) {
+			return nil, nil
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if tool.Schema["type"] != "object" {
+		t.Errorf("expected an object schema by default, got %v", tool.Schema)
+	}
+
+	_, err = NewTool("both").
+		Handler(func(context.Context) (string, error) { return "", nil }).
+		RawHandler(func(context.Context, json.RawMessage) ([]mcp.Content, error) { return nil, nil }).
+		Build()
+	if err == nil {
+		t.Error("expected error when both handlers are set")
+	}
+}
+
+func TestToolBuilder_CallToolRequestHandler(t *testing.T) {
+	var got *mcp.CallToolRequest
+	tool, err := NewTool("inspect").
+		Handler(func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			got = req
+			return &mcp.CallToolResult{
+				Content:           []mcp.Content{&mcp.TextContent{Type: "text", Text: "quota exceeded"}},
+				StructuredContent: map[string]interface{}{"remaining": 0},
+				IsError:           true,
+			}, nil
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if props, _ := tool.Schema["properties"].(map[string]interface{}); len(props) != 0 {
+		t.Errorf("expected no schema generated from the request type, got %v", tool.Schema)
+	}
+
+	srv := server.New("test")
+	_ = srv.AddTool(tool)
+
+	resp := srv.HandleMessage(context.Background(), &mcp.Message{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"inspect","arguments":{"x":1},"_meta":{"progressToken":"tok","example.com/trace":"abc"}}`),
+	})
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+
+	if got == nil {
+		t.Fatal("handler was not called")
+	}
+	if got.Name != "inspect" || string(got.Arguments) != `{"x":1}` {
+		t.Errorf("unexpected request: %+v", got)
+	}
+	if got.ProgressToken() != "tok" || got.Meta["example.com/trace"] != "abc" {
+		t.Errorf("expected _meta to reach the handler, got %v", got.Meta)
+	}
+
+	var result mcp.CallToolResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatalf("failed to decode result: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected isError to be set")
+	}
+	if len(result.Content) != 1 || result.Content[0].(mcp.TextContent).Text != "quota exceeded" {
+		t.Errorf("unexpected content: %+v", result.Content)
+	}
+	if structured, _ := result.StructuredContent.(map[string]interface{}); structured["remaining"] != float64(0) {
+		t.Errorf("unexpected structured content: %v", result.StructuredContent)
+	}
+}
+
+func TestToolBuilder_CallToolRequestHandlerDirect(t *testing.T) {
+	tool, err := NewTool("echo").
+		Handler(func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Type: "text", Text: string(req.Arguments)}}}, nil
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"a":1}`))
+	if err != nil {
+		t.Fatalf("handler failed: %v", err)
+	}
+	full, ok := result.(*mcp.CallToolResult)
+	if !ok || full.Content[0].(*mcp.TextContent).Text != `{"a":1}` {
+		t.Errorf("unexpected result: %#v", result)
+	}
+}
+
+func TestToolBuilder_Deprecated(t *testing.T) {
+	tool, err := NewTool("search_v1").
+		Handler(func(_ context.Context, input TestInput) (int, error) { return input.A, nil }).
+		Deprecated("use search_v2").
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if tool.Deprecated != "use search_v2" {
+		t.Errorf("expected the deprecation message, got %q", tool.Deprecated)
+	}
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// listCache holds list results until the server reports a list change
+type listCache struct {
+	tools     cachedList[*mcp.Tool]
+	resources cachedList[*mcp.Resource]
+	prompts   cachedList[*mcp.Prompt]
+}
+
+// cachedList is one cached list result. Each invalidation starts a new
+// generation, so a request in flight when the list changed doesn't store
+// its stale result.
+type cachedList[T any] struct {
+	mu         sync.Mutex
+	items      []T
+	valid      bool
+	generation uint64
+}
+
+// WithListCache caches ListTools, ListResources and ListPrompts results.
+// Each cache entry is dropped when the server sends the corresponding
+// notifications/*/list_changed notification, and all entries are dropped
+// after a reconnect. Callers get copies of the cached lists.
+func WithListCache() Option {
+	return func(c *Client) {
+		c.cache = &listCache{}
+	}
+}
+
+// InvalidateCache drops all cached list results
+func (c *Client) InvalidateCache() {
+	c.cache.toolList().invalidate()
+	c.cache.resourceList().invalidate()
+	c.cache.promptList().invalidate()
+}
+
+func (lc *listCache) toolList() *cachedList[*mcp.Tool] {
+	if lc == nil {
+		return nil
+	}
+	return &lc.tools
+}
+
+func (lc *listCache) resourceList() *cachedList[*mcp.Resource] {
+	if lc == nil {
+		return nil
+	}
+	return &lc.resources
+}
+
+func (lc *listCache) promptList() *cachedList[*mcp.Prompt] {
+	if lc == nil {
+		return nil
+	}
+	return &lc.prompts
+}
+
+// get returns a copy of the cached list, if any, and the generation a
+// fetched result must be stored under
+func (cl *cachedList[T]) get() ([]T, uint64, bool) {
+	if cl == nil {
+		return nil, 0, false
+	}
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	return slices.Clone(cl.items), cl.generation, cl.valid
+}
+
+// set caches a copy of items fetched during generation, unless the list
+// was invalidated since
+func (cl *cachedList[T]) set(items []T, generation uint64) {
+	if cl == nil {
+		return
+	}
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	if generation != cl.generation {
+		return
+	}
+	cl.items = slices.Clone(items)
+	cl.valid = true
+}
+
+// invalidate drops the cached list
+func (cl *cachedList[T]) invalidate() {
+	if cl == nil {
+		return
+	}
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	cl.items = nil
+	cl.valid = false
+	cl.generation++
+}
+
+// handleListChanged invalidates the cache entry for a list_changed notification
+func (c *Client) handleListChanged(method string) {
+	switch method {
+	case "notifications/tools/list_changed":
+		c.cache.toolList().invalidate()
+	case "notifications/resources/list_changed":
+		c.cache.resourceList().invalidate()
+	case "notifications/prompts/list_changed":
+		c.cache.promptList().invalidate()
+	}
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestClient_ListCache(t *testing.T) {
+	c, fs := connectWithResponder(t, nil, WithListCache())
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.ListTools(ctx); err != nil {
+			t.Fatalf("list tools failed: %v", err)
+		}
+	}
+	if got := fs.count("tools/list"); got != 1 {
+		t.Fatalf("expected 1 tools/list request, got %d", got)
+	}
+
+	fs.notify("notifications/tools/list_changed", nil)
+
+	deadline := time.Now().Add(time.Second)
+	for fs.count("tools/list") < 2 && time.Now().Before(deadline) {
+		if _, err := c.ListTools(ctx); err != nil {
+			t.Fatalf("list tools failed: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if got := fs.count("tools/list"); got != 2 {
+		t.Errorf("expected cache to be invalidated by list_changed, got %d requests", got)
+	}
+}
+
+func TestClient_ListCacheDisabled(t *testing.T) {
+	c, fs := connectWithResponder(t, nil)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.ListResources(ctx); err != nil {
+			t.Fatalf("list resources failed: %v", err)
+		}
+	}
+	if got := fs.count("resources/list"); got != 2 {
+		t.Errorf("expected 2 resources/list requests without cache, got %d", got)
+	}
+}
+
+func TestClient_InvalidateCache(t *testing.T) {
+	c, fs := connectWithResponder(t, nil, WithListCache())
+	ctx := context.Background()
+
+	if _, err := c.ListPrompts(ctx); err != nil {
+		t.Fatalf("list prompts failed: %v", err)
+	}
+	c.InvalidateCache()
+	if _, err := c.ListPrompts(ctx); err != nil {
+		t.Fatalf("list prompts failed: %v", err)
+	}
+
+	if got := fs.count("prompts/list"); got != 2 {
+		t.Errorf("expected 2 prompts/list requests after invalidation, got %d", got)
+	}
+}
+
+func TestClient_ListCacheEmptyAndCopies(t *testing.T) {
+	c, fs := connectWithResponder(t, nil, WithListCache())
+	ctx := context.Background()
+
+	// The fake server has no resources; the empty list is cached too
+	for i := 0; i < 2; i++ {
+		if _, err := c.ListResources(ctx); err != nil {
+			t.Fatalf("list resources failed: %v", err)
+		}
+	}
+	if got := fs.count("resources/list"); got != 1 {
+		t.Errorf("expected the empty list to be cached, got %d requests", got)
+	}
+
+	tools, err := c.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("list tools failed: %v", err)
+	}
+	tools[0] = nil
+	tools, err = c.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("list tools failed: %v", err)
+	}
+	if len(tools) != 1 || tools[0] == nil || tools[0].Name != "echo" {
+		t.Errorf("expected callers' changes to leave the cache intact, got %v", tools)
+	}
+}
+
+func TestCachedList_StaleWrite(t *testing.T) {
+	var cl cachedList[string]
+
+	_, generation, _ := cl.get()
+	cl.invalidate() // list_changed arrives while the request is in flight
+	cl.set([]string{"stale"}, generation)
+	if _, _, cached := cl.get(); cached {
+		t.Error("expected a result fetched before the invalidation to be discarded")
+	}
+
+	_, generation, _ = cl.get()
+	cl.set([]string{"fresh"}, generation)
+	if items, _, cached := cl.get(); !cached || len(items) != 1 || items[0] != "fresh" {
+		t.Errorf("expected the fresh result to be cached, got %v", items)
+	}
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// WithTimeout bounds a single request, including any retries. When the
+// deadline passes the client sends notifications/cancelled for the request.
+func WithTimeout(timeout time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithMeta adds fields to the request's _meta object. Fields set by the
+// client itself, such as progressToken, take precedence.
+func WithMeta(meta map[string]interface{}) CallOption {
+	return func(o *callOptions) {
+		if o.meta == nil {
+			o.meta = make(map[string]interface{}, len(meta))
+		}
+		for k, v := range meta {
+			o.meta[k] = v
+		}
+	}
+}
+
+// WithIdempotencyKey sends key as the tools/call request's idempotency key.
+// Servers configured with server.WithIdempotency run the call at most once
+// per key, so the request is retried under the retry policy even for tools
+// without IdempotentHint. Use a new key, such as one from NewIdempotencyKey,
+// for each logical call.
+func WithIdempotencyKey(key string) CallOption {
+	return func(o *callOptions) {
+		o.idempotencyKey = key
+	}
+}
+
+// NewIdempotencyKey returns a random idempotency key
+func NewIdempotencyKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// withMeta merges meta into the _meta field of the request params
+func withMeta(params interface{}, meta map[string]interface{}) (interface{}, error) {
+	fields := make(map[string]json.RawMessage)
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, fmt.Errorf("request params must be an object to carry _meta: %w", err)
+		}
+	}
+
+	merged := make(map[string]interface{}, len(meta))
+	for k, v := range meta {
+		merged[k] = v
+	}
+	if existing, ok := fields["_meta"]; ok {
+		var current map[string]interface{}
+		if err := json.Unmarshal(existing, &current); err == nil {
+			for k, v := range current {
+				merged[k] = v
+			}
+		}
+	}
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	fields["_meta"] = data
+
+	return fields, nil
+}
+
+// cancelCall notifies the server that the client stopped waiting for a
+// request. The notification is sent asynchronously so an unresponsive
+// transport doesn't delay the caller. The initialize request is never
+// cancelled.
+func (c *Client) cancelCall(msg *mcp.Message, cause error) {
+	if msg.Method == "initialize" || c.closed.Load() {
+		return
+	}
+	go func() {
+		_ = c.CancelRequest(msg.ID, cause.Error())
+	}()
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func TestClient_WithTimeoutCancels(t *testing.T) {
+	release := make(chan struct{})
+	blockTools := func(msg *mcp.Message) *mcp.Message {
+		if msg.Method == "tools/call" {
+			<-release
+		}
+		return nil
+	}
+
+	c, fs := connectWithResponder(t, blockTools)
+
+	_, err := c.CallTool(context.Background(), "slow", nil, WithTimeout(20*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	close(release)
+
+	deadline := time.Now().Add(time.Second)
+	for !fs.received("notifications/cancelled") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !fs.received("notifications/cancelled") {
+		t.Error("expected a cancellation notification after the deadline")
+	}
+}
+
+func TestClient_WithMeta(t *testing.T) {
+	metas := make(chan map[string]interface{}, 1)
+	captureMeta := func(msg *mcp.Message) *mcp.Message {
+		if msg.Method == "tools/call" {
+			var params struct {
+				Meta map[string]interface{} `json:"_meta"`
+			}
+			_ = json.Unmarshal(msg.Params, &params)
+			metas <- params.Meta
+		}
+		return nil
+	}
+
+	c, _ := connectWithResponder(t, captureMeta)
+
+	_, err := c.CallToolWithProgress(context.Background(), "echo", map[string]string{"x": "y"},
+		func(context.Context, *mcp.ProgressNotification) {},
+		WithMeta(map[string]interface{}{"traceId": "abc", "progressToken": "override"}))
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+
+	meta := <-metas
+	if meta["traceId"] != "abc" {
+		t.Errorf("expected traceId in _meta, got %v", meta)
+	}
+	if meta["progressToken"] == "override" {
+		t.Error("expected client progress token to take precedence")
+	}
+}
+
+func TestWithMeta_NonObjectParams(t *testing.T) {
+	if _, err := withMeta([]string{"a"}, map[string]interface{}{"k": "v"}); err == nil {
+		t.Error("expected error for non-object params")
+	}
+
+	params, err := withMeta(nil, map[string]interface{}{"k": "v"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := json.Marshal(params)
+	if string(data) != `{"_meta":{"k":"v"}}` {
+		t.Errorf("unexpected params: %s", data)
+	}
+}
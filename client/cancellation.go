@@ -1,6 +1,8 @@
 package client
 
 import (
+	"context"
+
 	"github.com/jmcarbo/fullmcp/mcp"
 )
 
@@ -13,3 +15,41 @@ func (c *Client) CancelRequest(requestID interface{}, reason string) error {
 
 	return c.notify("notifications/cancelled", notification)
 }
+
+// serverRequestContext returns the context for handling a server request.
+// It is cancelled when the server cancels the request or the client is
+// closed; done releases it once the request is answered.
+func (c *Client) serverRequestContext(id interface{}) (ctx context.Context, done func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	key := mcp.IDKey(id)
+
+	c.mu.Lock()
+	c.serverRequests[key] = cancel
+	c.mu.Unlock()
+
+	go func() {
+		select {
+		case <-c.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		c.mu.Lock()
+		delete(c.serverRequests, key)
+		c.mu.Unlock()
+		cancel()
+	}
+}
+
+// cancelServerRequest cancels the context of an in-flight server request
+func (c *Client) cancelServerRequest(id interface{}) {
+	c.mu.Lock()
+	cancel, ok := c.serverRequests[mcp.IDKey(id)]
+	c.mu.Unlock()
+
+	if ok {
+		cancel()
+	}
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// defaultClientInfo identifies the client when WithClientInfo isn't used
+var defaultClientInfo = mcp.Implementation{Name: "fullmcp-client", Version: "0.1.0"}
+
+// WithClientInfo sets the implementation info sent in the initialize request
+func WithClientInfo(name, version, title string) Option {
+	return func(c *Client) {
+		c.clientInfo = mcp.Implementation{Name: name, Version: version, Title: title}
+	}
+}
+
+// WithCapabilities sets the capabilities advertised during initialization.
+// By default the client advertises roots, sampling and elicitation only when
+// the corresponding provider or handler is configured.
+func WithCapabilities(caps mcp.ClientCapabilities) Option {
+	return func(c *Client) {
+		c.clientCaps = &caps
+	}
+}
+
+// WithExperimental declares an experimental capability, advertised to the
+// server during initialize alongside the other capabilities. settings is the
+// experiment's settings object; nil declares it with no settings.
+func WithExperimental(name string, settings interface{}) Option {
+	return func(c *Client) {
+		if c.experimental == nil {
+			c.experimental = make(mcp.Experiments)
+		}
+		if settings == nil {
+			settings = map[string]interface{}{}
+		}
+		c.experimental[name] = settings
+	}
+}
+
+// ServerExperiments returns the experimental capabilities the server
+// declared during initialize, or nil
+func (c *Client) ServerExperiments() mcp.Experiments {
+	if caps := c.ServerCapabilities(); caps != nil {
+		return caps.Experimental
+	}
+	return nil
+}
+
+// clientCapabilities returns the capabilities to advertise in initialize
+func (c *Client) clientCapabilities() mcp.ClientCapabilities {
+	caps := c.derivedCapabilities()
+	if len(c.experimental) > 0 {
+		merged := make(mcp.Experiments, len(caps.Experimental)+len(c.experimental))
+		for name, settings := range caps.Experimental {
+			merged[name] = settings
+		}
+		for name, settings := range c.experimental {
+			merged[name] = settings
+		}
+		caps.Experimental = merged
+	}
+	return caps
+}
+
+// derivedCapabilities returns the explicit capabilities, or those implied by
+// the configured handlers
+func (c *Client) derivedCapabilities() mcp.ClientCapabilities {
+	if c.clientCaps != nil {
+		return *c.clientCaps
+	}
+
+	var caps mcp.ClientCapabilities
+	if c.currentRootsProvider() != nil {
+		caps.Roots = &mcp.RootsCapability{ListChanged: true}
+	}
+	if c.samplingHandler != nil {
+		caps.Sampling = &mcp.SamplingCapability{}
+	}
+	if c.elicitationHandler != nil {
+		caps.Elicitation = &mcp.ElicitationCapability{}
+	}
+	return caps
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+type initializeParams struct {
+	Capabilities mcp.ClientCapabilities `json:"capabilities"`
+	ClientInfo   mcp.Implementation     `json:"clientInfo"`
+}
+
+// captureInitialize records the params of the initialize request
+func captureInitialize(params *initializeParams) func(*mcp.Message) *mcp.Message {
+	return func(msg *mcp.Message) *mcp.Message {
+		if msg.Method == "initialize" {
+			_ = json.Unmarshal(msg.Params, params)
+		}
+		return nil
+	}
+}
+
+func TestClient_DefaultClientInfo(t *testing.T) {
+	var params initializeParams
+	connectWithResponder(t, captureInitialize(&params))
+
+	if params.ClientInfo != defaultClientInfo {
+		t.Errorf("unexpected client info: %+v", params.ClientInfo)
+	}
+	if params.Capabilities.Roots != nil || params.Capabilities.Sampling != nil || params.Capabilities.Elicitation != nil {
+		t.Errorf("expected no capabilities without handlers, got %+v", params.Capabilities)
+	}
+}
+
+func TestClient_WithClientInfo(t *testing.T) {
+	var params initializeParams
+	connectWithResponder(t, captureInitialize(&params), WithClientInfo("my-app", "3.2.1", "My App"))
+
+	want := mcp.Implementation{Name: "my-app", Version: "3.2.1", Title: "My App"}
+	if params.ClientInfo != want {
+		t.Errorf("expected %+v, got %+v", want, params.ClientInfo)
+	}
+}
+
+func TestClient_CapabilitiesFromHandlers(t *testing.T) {
+	var params initializeParams
+	connectWithResponder(t, captureInitialize(&params),
+		WithSamplingHandler(func(context.Context, *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+			return nil, nil
+		}))
+
+	if params.Capabilities.Sampling == nil {
+		t.Error("expected sampling capability when a handler is configured")
+	}
+	if params.Capabilities.Roots != nil {
+		t.Error("expected no roots capability without a provider")
+	}
+}
+
+func TestClient_WithCapabilities(t *testing.T) {
+	var params initializeParams
+	connectWithResponder(t, captureInitialize(&params),
+		WithSamplingHandler(func(context.Context, *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+			return nil, nil
+		}),
+		WithCapabilities(mcp.ClientCapabilities{Elicitation: &mcp.ElicitationCapability{}}))
+
+	if params.Capabilities.Elicitation == nil {
+		t.Error("expected elicitation capability")
+	}
+	if params.Capabilities.Sampling != nil {
+		t.Error("expected explicit capabilities to override handler defaults")
+	}
+}
+
+func TestClient_Experimental(t *testing.T) {
+	var params initializeParams
+	respond := func(msg *mcp.Message) *mcp.Message {
+		if msg.Method != "initialize" {
+			return nil
+		}
+		_ = json.Unmarshal(msg.Params, &params)
+		return &mcp.Message{JSONRPC: "2.0", ID: msg.ID, Result: json.RawMessage(
+			`{"protocolVersion":"2025-06-18","capabilities":{"experimental":{"example.com/streaming":{"maxChunk":1024}}},"serverInfo":{"name":"fake","version":"1.0"}}`)}
+	}
+
+	c, _ := connectWithResponder(t, respond,
+		WithCapabilities(mcp.ClientCapabilities{Experimental: mcp.Experiments{"example.com/base": map[string]interface{}{}}}),
+		WithExperimental("example.com/diff", map[string]interface{}{"format": "unified"}),
+		WithExperimental("example.com/flag", nil),
+	)
+
+	sent := params.Capabilities.Experimental
+	if !sent.Has("example.com/base") || !sent.Has("example.com/diff") || !sent.Has("example.com/flag") {
+		t.Errorf("expected all experiments to be declared, got %v", sent)
+	}
+
+	var settings struct {
+		MaxChunk int `json:"maxChunk"`
+	}
+	found, err := c.ServerExperiments().Decode("example.com/streaming", &settings)
+	if err != nil || !found || settings.MaxChunk != 1024 {
+		t.Errorf("unexpected server experiment: found=%v settings=%+v err=%v", found, settings, err)
+	}
+	if c.ServerExperiments().Has("example.com/other") {
+		t.Error("expected undeclared experiment to be absent")
+	}
+}
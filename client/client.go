@@ -4,6 +4,7 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"sync"
@@ -15,19 +16,68 @@ import (
 
 // Client is an MCP client
 type Client struct {
+	connMu    sync.RWMutex
 	transport io.ReadWriteCloser
 	reader    *jsonrpc.MessageReader
 	writer    *jsonrpc.MessageWriter
 
 	mu      sync.Mutex
 	nextID  atomic.Int64
-	pending map[int64]chan *mcp.Message
+	pending map[int64]*pendingCall
+
+	serverRequests map[string]context.CancelFunc // In-flight server requests keyed by mcp.IDKey, guarded by mu
 
 	capabilities    *mcp.ServerCapabilities
-	samplingHandler SamplingHandler // Handler for server-initiated sampling requests
-	rootsProvider   RootsProvider   // Provider for client roots
-	logHandler      LogHandler      // Handler for log message notifications
-	progressHandler ProgressHandler // Handler for progress notifications
+	serverInfo      mcp.Implementation // Negotiated during initialize
+	protocolVersion string
+	instructions    string
+
+	clientInfo   mcp.Implementation      // Sent in the initialize request
+	clientCaps   *mcp.ClientCapabilities // Explicit capabilities (nil derives them from handlers)
+	experimental mcp.Experiments         // Added to the advertised capabilities
+
+	samplingHandler        SamplingHandler        // Handler for server-initiated sampling requests
+	elicitationHandler     ElicitationHandler     // Handler for server-initiated elicitation requests
+	rootsProvider          RootsProvider          // Provider for client roots, guarded by rootsMu
+	logHandler             LogHandler             // Handler for log message notifications
+	progressHandler        ProgressHandler        // Handler for progress notifications
+	resourceUpdatedHandler ResourceUpdatedHandler // Handler for subscribed resource changes
+
+	progressCallbacks map[string]ProgressHandler // Per-call progress callbacks keyed by token
+	nextProgress      atomic.Int64
+
+	notificationHandlers map[string]NotificationHandler // Application handlers keyed by method
+
+	rootsMu sync.RWMutex
+	roots   []mcp.Root // Roots set with SetRoots and AddRoot
+
+	reconnect     *ReconnectPolicy       // Reconnection policy (nil disables reconnection)
+	stateHandler  ConnectionStateHandler // Handler for connection state changes
+	reconnecting  bool                   // Guarded by connMu
+	closed        atomic.Bool
+	done          chan struct{}
+	logLevel      mcp.LogLevel        // Last log level requested, re-sent on reconnect
+	subscriptions map[string]struct{} // Subscribed resource URIs, re-sent on reconnect
+
+	retry     *RetryPolicy         // Default retry policy (nil disables retries)
+	toolHints map[string]*mcp.Tool // Tool annotations from the last ListTools
+	cache     *listCache           // List result cache (nil disables caching)
+
+	validateArgs bool // Set by WithArgumentValidation
+
+	keepAlive     *keepAlive // Keepalive ping schedule (nil disables keepalive)
+	keepAliveOnce sync.Once
+	latency       atomic.Int64 // Last keepalive round trip in nanoseconds
+}
+
+// ErrConnectionLost is returned for requests that were in flight when the
+// connection dropped and could not be resumed
+var ErrConnectionLost = errors.New("connection lost before response was received")
+
+// pendingCall tracks an in-flight request awaiting its response
+type pendingCall struct {
+	msg *mcp.Message
+	ch  chan *mcp.Message
 }
 
 // Option configures a Client
@@ -39,7 +89,18 @@ func New(transport io.ReadWriteCloser, opts ...Option) *Client {
 		transport: transport,
 		reader:    jsonrpc.NewMessageReader(transport),
 		writer:    jsonrpc.NewMessageWriter(transport),
-		pending:   make(map[int64]chan *mcp.Message),
+		pending:   make(map[int64]*pendingCall),
+		done:      make(chan struct{}),
+
+		serverRequests: make(map[string]context.CancelFunc),
+
+		clientInfo: defaultClientInfo,
+
+		subscriptions: make(map[string]struct{}),
+		toolHints:     make(map[string]*mcp.Tool),
+
+		progressCallbacks:    make(map[string]ProgressHandler),
+		notificationHandlers: make(map[string]NotificationHandler),
 	}
 
 	for _, opt := range opts {
@@ -54,36 +115,37 @@ func (c *Client) Connect(ctx context.Context) error {
 	// Start message handler
 	go c.handleMessages()
 
-	// Initialize
+	if err := c.initialize(ctx); err != nil {
+		return err
+	}
+
+	c.emitState(StateConnected, nil)
+	c.startKeepAlive()
+	return nil
+}
+
+// initialize performs the initialize handshake on the current transport
+func (c *Client) initialize(ctx context.Context) error {
 	var initResult struct {
 		ProtocolVersion string                 `json:"protocolVersion"`
 		Capabilities    mcp.ServerCapabilities `json:"capabilities"`
-		ServerInfo      struct {
-			Name    string `json:"name"`
-			Version string `json:"version"`
-		} `json:"serverInfo"`
-	}
-
-	capabilities := map[string]interface{}{}
-	if c.rootsProvider != nil {
-		capabilities["roots"] = map[string]bool{
-			"listChanged": true,
-		}
+		ServerInfo      mcp.Implementation     `json:"serverInfo"`
+		Instructions    string                 `json:"instructions,omitempty"`
 	}
 
 	if err := c.call(ctx, "initialize", map[string]interface{}{
 		"protocolVersion": "2025-06-18",
-		"capabilities":    capabilities,
-		"clientInfo": map[string]string{
-			"name":    "fullmcp-client",
-			"version": "0.1.0",
-		},
+		"capabilities":    c.clientCapabilities(),
+		"clientInfo":      c.clientInfo,
 	}, &initResult); err != nil {
 		return err
 	}
 
 	c.mu.Lock()
 	c.capabilities = &initResult.Capabilities
+	c.serverInfo = initResult.ServerInfo
+	c.protocolVersion = initResult.ProtocolVersion
+	c.instructions = initResult.Instructions
 	c.mu.Unlock()
 
 	// Send initialized notification
@@ -92,40 +154,90 @@ func (c *Client) Connect(ctx context.Context) error {
 
 // Close closes the connection
 func (c *Client) Close() error {
-	if c.transport != nil {
-		return c.transport.Close()
+	if c.closed.Swap(true) {
+		return nil
+	}
+	close(c.done)
+
+	c.connMu.RLock()
+	transport := c.transport
+	c.connMu.RUnlock()
+
+	if transport != nil {
+		return transport.Close()
 	}
 	return nil
 }
 
 // ListTools lists available tools
-func (c *Client) ListTools(ctx context.Context) ([]*mcp.Tool, error) {
+func (c *Client) ListTools(ctx context.Context, opts ...CallOption) ([]*mcp.Tool, error) {
+	tools, generation, cached := c.cache.toolList().get()
+	if cached {
+		return tools, nil
+	}
+
 	var result struct {
 		Tools []*mcp.Tool `json:"tools"`
 	}
 
-	if err := c.call(ctx, "tools/list", nil, &result); err != nil {
+	if err := c.callWithRetry(ctx, "tools/list", nil, &result, true, opts); err != nil {
 		return nil, err
 	}
 
+	c.rememberTools(result.Tools)
+	c.cache.toolList().set(result.Tools, generation)
 	return result.Tools, nil
 }
 
-// CallTool calls a tool
-func (c *Client) CallTool(ctx context.Context, name string, args interface{}) (interface{}, error) {
+// CallTool calls a tool. Retries apply only to tools annotated with IdempotentHint
+// in the most recent ListTools response.
+func (c *Client) CallTool(ctx context.Context, name string, args interface{}, opts ...CallOption) (interface{}, error) {
+	if c.validateArgs {
+		if err := c.ValidateArguments(name, args); err != nil {
+			return nil, err
+		}
+	}
+
 	params := map[string]interface{}{
 		"name":      name,
 		"arguments": args,
 	}
 
-	var result struct {
-		Content []json.RawMessage `json:"content"`
+	return c.callTool(ctx, name, params, opts)
+}
+
+// CallToolResult calls a tool and returns its full result, including
+// isError and structured content. A tool failure is not an error here; it is
+// reported through the result's IsError.
+func (c *Client) CallToolResult(ctx context.Context, name string, args interface{}, opts ...CallOption) (*mcp.CallToolResult, error) {
+	if c.validateArgs {
+		if err := c.ValidateArguments(name, args); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := c.call(ctx, "tools/call", params, &result); err != nil {
+	params := map[string]interface{}{
+		"name":      name,
+		"arguments": args,
+	}
+
+	var result mcp.CallToolResult
+	if err := c.callWithRetry(ctx, "tools/call", params, &result, c.isIdempotentTool(name), opts); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) callTool(ctx context.Context, name string, params map[string]interface{}, opts []CallOption) (interface{}, error) {
+	var result toolCallResult
+	if err := c.callWithRetry(ctx, "tools/call", params, &result, c.isIdempotentTool(name), opts); err != nil {
 		return nil, err
 	}
 
+	if result.IsError {
+		return nil, result.toolError()
+	}
+
 	if len(result.Content) > 0 {
 		var textContent mcp.TextContent
 		if err := json.Unmarshal(result.Content[0], &textContent); err == nil {
@@ -137,59 +249,123 @@ func (c *Client) CallTool(ctx context.Context, name string, args interface{}) (i
 }
 
 // ListResources lists available resources
-func (c *Client) ListResources(ctx context.Context) ([]*mcp.Resource, error) {
+func (c *Client) ListResources(ctx context.Context, opts ...CallOption) ([]*mcp.Resource, error) {
+	resources, generation, cached := c.cache.resourceList().get()
+	if cached {
+		return resources, nil
+	}
+
 	var result struct {
 		Resources []*mcp.Resource `json:"resources"`
 	}
 
-	if err := c.call(ctx, "resources/list", nil, &result); err != nil {
+	if err := c.callWithRetry(ctx, "resources/list", nil, &result, true, opts); err != nil {
 		return nil, err
 	}
 
+	c.cache.resourceList().set(result.Resources, generation)
 	return result.Resources, nil
 }
 
-// ReadResource reads a resource
-func (c *Client) ReadResource(ctx context.Context, uri string) ([]byte, error) {
-	params := map[string]interface{}{
-		"uri": uri,
+// ListResourcesFiltered lists the resources matching filter. The server does
+// the filtering, so large resource sets are not transferred; scheme and
+// prefix are also applied locally for servers that ignore the filter.
+// Results are not cached.
+func (c *Client) ListResourcesFiltered(ctx context.Context, filter mcp.ResourceFilter, opts ...CallOption) ([]*mcp.Resource, error) {
+	var result struct {
+		Resources []*mcp.Resource `json:"resources"`
+	}
+
+	params := map[string]interface{}{"filter": filter}
+	if err := c.callWithRetry(ctx, "resources/list", params, &result, true, opts); err != nil {
+		return nil, err
 	}
 
+	local := filter
+	local.Tags = nil
+	resources := make([]*mcp.Resource, 0, len(result.Resources))
+	for _, r := range result.Resources {
+		if local.Matches(r.URI, nil) {
+			resources = append(resources, r)
+		}
+	}
+	return resources, nil
+}
+
+// ListResourceTemplates lists the server's parameterized resources
+func (c *Client) ListResourceTemplates(ctx context.Context, opts ...CallOption) ([]*mcp.ResourceTemplate, error) {
 	var result struct {
-		Contents []struct {
-			URI      string `json:"uri"`
-			MimeType string `json:"mimeType"`
-			Text     string `json:"text,omitempty"`
-			Blob     string `json:"blob,omitempty"`
-		} `json:"contents"`
+		ResourceTemplates []*mcp.ResourceTemplate `json:"resourceTemplates"`
 	}
 
-	if err := c.call(ctx, "resources/read", params, &result); err != nil {
+	if err := c.callWithRetry(ctx, "resources/templates/list", nil, &result, true, opts); err != nil {
+		return nil, err
+	}
+	return result.ResourceTemplates, nil
+}
+
+// ReadResource reads a resource and returns the data of its first contents
+// item. Blob contents are decoded from base64.
+func (c *Client) ReadResource(ctx context.Context, uri string, opts ...CallOption) ([]byte, error) {
+	contents, err := c.ReadResourceContents(ctx, uri, opts...)
+	if err != nil {
 		return nil, err
 	}
 
-	if len(result.Contents) == 0 {
+	if len(contents) == 0 {
 		return nil, &mcp.NotFoundError{Type: "resource", Name: uri}
 	}
 
-	return []byte(result.Contents[0].Text), nil
+	return contents[0].Bytes()
+}
+
+// ReadResourceContents reads a resource and returns all its contents items,
+// each either mcp.TextResourceContents or mcp.BlobResourceContents
+func (c *Client) ReadResourceContents(ctx context.Context, uri string, opts ...CallOption) ([]mcp.ResourceContents, error) {
+	params := map[string]interface{}{
+		"uri": uri,
+	}
+
+	var result struct {
+		Contents []json.RawMessage `json:"contents"`
+	}
+
+	if err := c.callWithRetry(ctx, "resources/read", params, &result, true, opts); err != nil {
+		return nil, err
+	}
+
+	contents := make([]mcp.ResourceContents, 0, len(result.Contents))
+	for _, raw := range result.Contents {
+		content, err := mcp.UnmarshalResourceContents(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid resource contents: %w", err)
+		}
+		contents = append(contents, content)
+	}
+	return contents, nil
 }
 
 // ListPrompts lists available prompts
-func (c *Client) ListPrompts(ctx context.Context) ([]*mcp.Prompt, error) {
+func (c *Client) ListPrompts(ctx context.Context, opts ...CallOption) ([]*mcp.Prompt, error) {
+	prompts, generation, cached := c.cache.promptList().get()
+	if cached {
+		return prompts, nil
+	}
+
 	var result struct {
 		Prompts []*mcp.Prompt `json:"prompts"`
 	}
 
-	if err := c.call(ctx, "prompts/list", nil, &result); err != nil {
+	if err := c.callWithRetry(ctx, "prompts/list", nil, &result, true, opts); err != nil {
 		return nil, err
 	}
 
+	c.cache.promptList().set(result.Prompts, generation)
 	return result.Prompts, nil
 }
 
 // GetPrompt gets a prompt
-func (c *Client) GetPrompt(ctx context.Context, name string, args map[string]interface{}) ([]*mcp.PromptMessage, error) {
+func (c *Client) GetPrompt(ctx context.Context, name string, args map[string]interface{}, opts ...CallOption) ([]*mcp.PromptMessage, error) {
 	params := map[string]interface{}{
 		"name":      name,
 		"arguments": args,
@@ -199,7 +375,7 @@ func (c *Client) GetPrompt(ctx context.Context, name string, args map[string]int
 		Messages []*mcp.PromptMessage `json:"messages"`
 	}
 
-	if err := c.call(ctx, "prompts/get", params, &result); err != nil {
+	if err := c.callWithRetry(ctx, "prompts/get", params, &result, true, opts); err != nil {
 		return nil, err
 	}
 
@@ -231,7 +407,7 @@ func (c *Client) call(ctx context.Context, method string, params, result interfa
 	respChan := make(chan *mcp.Message, 1)
 
 	c.mu.Lock()
-	c.pending[id] = respChan
+	c.pending[id] = &pendingCall{msg: msg, ch: respChan}
 	c.mu.Unlock()
 
 	defer func() {
@@ -240,16 +416,21 @@ func (c *Client) call(ctx context.Context, method string, params, result interfa
 		c.mu.Unlock()
 	}()
 
-	if err := c.writer.Write(msg); err != nil {
+	if err := c.write(msg); err != nil {
 		return err
 	}
 
 	select {
 	case <-ctx.Done():
+		c.cancelCall(msg, ctx.Err())
 		return ctx.Err()
 	case resp := <-respChan:
+		if resp == nil {
+			return ErrConnectionLost
+		}
+
 		if resp.Error != nil {
-			return fmt.Errorf("RPC error %d: %s", resp.Error.Code, resp.Error.Message)
+			return resp.Error
 		}
 
 		if result != nil && resp.Result != nil {
@@ -274,42 +455,60 @@ func (c *Client) notify(method string, params interface{}) error {
 		msg.Params = paramsJSON
 	}
 
-	return c.writer.Write(msg)
+	return c.write(msg)
+}
+
+// write sends a message on the current transport
+func (c *Client) write(msg *mcp.Message) error {
+	c.connMu.RLock()
+	writer := c.writer
+	c.connMu.RUnlock()
+	return writer.Write(msg)
 }
 
 func (c *Client) handleMessages() {
+	c.connMu.RLock()
+	reader := c.reader
+	c.connMu.RUnlock()
+
 	for {
-		msg, err := c.reader.Read()
+		msg, err := reader.Read()
 		if err != nil {
+			c.connectionLost(reader, err)
 			return
 		}
 
-		// Handle notifications from server (no ID)
-		if msg.Method != "" && msg.ID == nil {
-			c.handleServerNotification(msg)
-			continue
-		}
+		c.dispatch(msg)
+	}
+}
 
-		// Handle requests from server (like roots/list)
-		if msg.Method != "" && msg.ID != nil {
-			c.handleServerRequest(msg)
-			continue
-		}
+// dispatch routes an incoming message to the appropriate handler
+func (c *Client) dispatch(msg *mcp.Message) {
+	// Handle notifications from server (no ID)
+	if msg.Method != "" && msg.ID == nil {
+		c.handleServerNotification(msg)
+		return
+	}
 
-		// Handle responses to client requests
-		if msg.ID != nil {
-			id, ok := msg.ID.(float64)
-			if !ok {
-				continue
-			}
+	// Handle requests from server (like roots/list)
+	if msg.Method != "" && msg.ID != nil {
+		c.handleServerRequest(msg)
+		return
+	}
 
-			c.mu.Lock()
-			ch, exists := c.pending[int64(id)]
-			c.mu.Unlock()
+	// Handle responses to client requests
+	if msg.ID != nil {
+		id, ok := mcp.IDInt64(msg.ID)
+		if !ok {
+			return
+		}
 
-			if exists {
-				ch <- msg
-			}
+		c.mu.Lock()
+		call, exists := c.pending[id]
+		c.mu.Unlock()
+
+		if exists {
+			call.ch <- msg
 		}
 	}
 }
@@ -326,12 +525,35 @@ func (c *Client) handleServerNotification(msg *mcp.Message) {
 		}
 	case "notifications/progress":
 		// Handle progress notification
-		if c.progressHandler != nil {
-			var progressNotif mcp.ProgressNotification
-			if err := json.Unmarshal(msg.Params, &progressNotif); err == nil {
+		var progressNotif mcp.ProgressNotification
+		if err := json.Unmarshal(msg.Params, &progressNotif); err == nil {
+			if callback := c.progressCallback(progressNotif.ProgressToken); callback != nil {
+				go callback(context.Background(), &progressNotif)
+			}
+			if c.progressHandler != nil {
 				go c.progressHandler(context.Background(), &progressNotif)
 			}
 		}
+	case "notifications/cancelled":
+		var cancelled mcp.CancelledNotification
+		if err := json.Unmarshal(msg.Params, &cancelled); err == nil {
+			c.cancelServerRequest(cancelled.RequestID)
+		}
+	case "notifications/resources/updated":
+		if c.resourceUpdatedHandler != nil {
+			var updated mcp.ResourceUpdatedNotification
+			if err := json.Unmarshal(msg.Params, &updated); err == nil {
+				go c.resourceUpdatedHandler(context.Background(), updated.URI)
+			}
+		}
+	case "notifications/tools/list_changed",
+		"notifications/resources/list_changed",
+		"notifications/prompts/list_changed":
+		c.handleListChanged(msg.Method)
+	}
+
+	if handler := c.notificationHandler(msg.Method); handler != nil {
+		go handler(context.Background(), msg.Params)
 	}
 }
 
@@ -339,19 +561,48 @@ func (c *Client) handleServerRequest(msg *mcp.Message) {
 	var response *mcp.Message
 
 	switch msg.Method {
+	case "sampling/createMessage":
+		// Sampling may call back into the server to gather context, so it
+		// must not block the message loop
+		ctx, done := c.serverRequestContext(msg.ID)
+		go func() {
+			defer done()
+			result, err := c.handleSamplingRequest(ctx, msg.Params)
+			if err != nil {
+				_ = c.write(c.errorResponseFrom(msg.ID, err))
+				return
+			}
+			_ = c.write(c.successResponse(msg.ID, result))
+		}()
+		return
+	case "elicitation/create":
+		// Elicitation waits for user input, so it runs outside the message loop
+		ctx, done := c.serverRequestContext(msg.ID)
+		go func() {
+			defer done()
+			result, err := c.handleElicitationRequest(ctx, msg.Params)
+			if err != nil {
+				_ = c.write(c.errorResponseFrom(msg.ID, err))
+				return
+			}
+			_ = c.write(c.successResponse(msg.ID, result))
+		}()
+		return
+	case "ping":
+		response = c.successResponse(msg.ID, map[string]interface{}{})
 	case "roots/list":
 		result, err := c.handleRootsList(context.Background())
 		if err != nil {
-			response = c.errorResponse(msg.ID, mcp.InternalError, err.Error())
+			response = c.errorResponseFrom(msg.ID, err)
 		} else {
 			response = c.successResponse(msg.ID, result)
 		}
 	default:
-		response = c.errorResponse(msg.ID, mcp.MethodNotFound, "method not found")
+		response = c.errorResponseFrom(msg.ID, mcp.NewMethodNotFound(msg.Method))
 	}
 
 	if response != nil {
-		_ = c.writer.Write(response)
+		_ = c.write(response)
 	}
 }
 
@@ -364,13 +615,12 @@ func (c *Client) successResponse(id interface{}, result interface{}) *mcp.Messag
 	}
 }
 
-func (c *Client) errorResponse(id interface{}, code mcp.ErrorCode, message string) *mcp.Message {
+// errorResponseFrom builds an error response with the protocol error code
+// matching err
+func (c *Client) errorResponseFrom(id interface{}, err error) *mcp.Message {
 	return &mcp.Message{
 		JSONRPC: "2.0",
 		ID:      id,
-		Error: &mcp.RPCError{
-			Code:    int(code),
-			Message: message,
-		},
+		Error:   mcp.ToError(err).RPCError(),
 	}
 }
@@ -182,3 +182,22 @@ func TestClient_CallWithoutParams(t *testing.T) {
 func TestClient_CallWithNilResult(t *testing.T) {
 	t.Skip("Requires proper async mock setup")
 }
+
+func TestClient_AnswersServerPing(t *testing.T) {
+	transport := testutil.NewMockTransport()
+	c := New(transport)
+
+	c.handleServerRequest(&mcp.Message{JSONRPC: "2.0", ID: "srv-1", Method: "ping"})
+
+	msg, err := transport.ReadMessage()
+	if err != nil {
+		t.Fatalf("failed to read message: %v", err)
+	}
+
+	if msg.ID != "srv-1" {
+		t.Errorf("expected response to srv-1, got %v", msg.ID)
+	}
+	if msg.Error != nil {
+		t.Errorf("unexpected error: %v", msg.Error)
+	}
+}
@@ -0,0 +1,41 @@
+package client
+
+import (
+	"context"
+
+	"github.com/jmcarbo/fullmcp/transport"
+	_ "github.com/jmcarbo/fullmcp/transport/http"       // Registers http and https
+	_ "github.com/jmcarbo/fullmcp/transport/sse"        // Registers http+sse and https+sse
+	_ "github.com/jmcarbo/fullmcp/transport/stdio"      // Registers stdio
+	_ "github.com/jmcarbo/fullmcp/transport/streamhttp" // Registers http+stream and https+stream
+	_ "github.com/jmcarbo/fullmcp/transport/tcp"        // Registers tcp
+	_ "github.com/jmcarbo/fullmcp/transport/unix"       // Registers unix
+	_ "github.com/jmcarbo/fullmcp/transport/websocket"  // Registers ws and wss
+)
+
+// Dial connects to the server at rawURL, choosing the transport from the
+// URL scheme, and performs the initialize handshake:
+//
+//	stdio:./server --flag        launch the server as a subprocess
+//	http://host/mcp              HTTP (also https)
+//	http+stream://host/mcp       Streamable HTTP (also https+stream)
+//	http+sse://host/events       SSE (also https+sse)
+//	ws://host/mcp                WebSocket (also wss)
+//	unix:///path/to/server.sock  Unix domain socket
+//	tcp://host:9000              TCP, for servers using ServeListener
+//
+// Third-party transports registered with transport.Register are available
+// under their own schemes.
+func Dial(ctx context.Context, rawURL string, opts ...Option) (*Client, error) {
+	return DialWithConfig(ctx, rawURL, transport.Config{}, opts...)
+}
+
+// DialWithConfig is like Dial, passing cfg (such as an API key or headers)
+// to the transport
+func DialWithConfig(ctx context.Context, rawURL string, cfg transport.Config, opts ...Option) (*Client, error) {
+	t, err := transport.Open(rawURL, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return ConnectTransport(ctx, t, opts...)
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/internal/jsonrpc"
+)
+
+// stdioConn serves the fake server over the process's stdin and stdout
+type stdioConn struct {
+	io.Reader
+	io.Writer
+}
+
+func (stdioConn) Close() error { return nil }
+
+// TestDialHelperProcess is run as a subprocess by TestDial_Stdio
+func TestDialHelperProcess(t *testing.T) {
+	if os.Args[len(os.Args)-1] != "mcp-helper" {
+		t.Skip("helper process")
+	}
+
+	// Serve until the client closes stdin, then exit before the test
+	// framework writes to stdout
+	conn := stdioConn{Reader: os.Stdin, Writer: os.Stdout}
+	fs := &fakeServer{transport: conn, writer: jsonrpc.NewMessageWriter(conn)}
+	fs.serve()
+	os.Exit(0)
+}
+
+func TestDial_Stdio(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := Dial(ctx, "stdio:"+os.Args[0]+" -test.run=^TestDialHelperProcess$ -- mcp-helper")
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	tools, err := c.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("list tools failed: %v", err)
+	}
+	if len(tools) == 0 {
+		t.Error("expected tools from the subprocess server")
+	}
+}
+
+func TestDial_Unix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp.sock")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		startFakeServer(conn)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	c, err := Dial(ctx, "unix://"+path)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	if _, err := c.ListTools(ctx); err != nil {
+		t.Fatalf("list tools failed: %v", err)
+	}
+}
+
+func TestDial_UnknownScheme(t *testing.T) {
+	if _, err := Dial(context.Background(), "gopher://example.com"); err == nil {
+		t.Error("expected error for unregistered scheme")
+	}
+}
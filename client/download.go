@@ -0,0 +1,153 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// preferredExtensions overrides mime.ExtensionsByType for types with several
+// registered extensions
+var preferredExtensions = map[string]string{
+	"text/plain":       ".txt",
+	"text/markdown":    ".md",
+	"text/html":        ".html",
+	"text/csv":         ".csv",
+	"application/json": ".json",
+	"application/xml":  ".xml",
+	"image/jpeg":       ".jpg",
+	"audio/mpeg":       ".mp3",
+}
+
+// downloadChunkSize is how much is written between progress reports
+const downloadChunkSize = 32 * 1024
+
+// DownloadResource reads a resource and writes its contents to path,
+// returning the path written. The contents are streamed from OpenResource, so
+// blobs are decoded as they are written. If path is an existing directory the
+// file is named after the last segment of the URI, and a file name without
+// an extension gets one inferred from the resource's MIME type. The file is
+// written to a temporary name first, so a failed download never leaves a
+// partial file at path.
+//
+// A WithProgress callback receives the server's progress notifications for
+// the read and then the number of bytes written to disk.
+func (c *Client) DownloadResource(ctx context.Context, uri, path string, opts ...CallOption) (string, error) {
+	src, info, err := c.OpenResource(ctx, uri, opts...)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = src.Close() }()
+
+	path = downloadPath(uri, path, info.MimeType)
+	onProgress := c.resolveCallOptions(opts).onProgress
+	if err := writeDownload(ctx, path, src, info.Size, onProgress); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// downloadPath resolves where a resource is written
+func downloadPath(uri, dest, mimeType string) string {
+	if info, err := os.Stat(dest); err == nil && info.IsDir() {
+		dest = filepath.Join(dest, uriFileName(uri))
+	}
+	if filepath.Ext(dest) == "" {
+		dest += extensionForMIME(mimeType)
+	}
+	return dest
+}
+
+// uriFileName returns the last path segment of uri, or "resource"
+func uriFileName(uri string) string {
+	name := ""
+	if u, err := url.Parse(uri); err == nil {
+		name = path.Base(u.Path)
+		if name == "/" || name == "." {
+			name = path.Base(u.Opaque)
+		}
+		if name == "/" || name == "." || name == "" {
+			name = u.Host
+		}
+	}
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "resource"
+	}
+	return name
+}
+
+// extensionForMIME returns the file extension for a MIME type, or "" when
+// it has none
+func extensionForMIME(mimeType string) string {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return ""
+	}
+	if ext, ok := preferredExtensions[mediaType]; ok {
+		return ext
+	}
+	if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+		return exts[0]
+	}
+	return ""
+}
+
+// writeDownload copies src to path through a temporary file, reporting the
+// bytes written to onProgress
+func writeDownload(ctx context.Context, path string, src io.Reader, size int64, onProgress ProgressHandler) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	total := float64(size)
+	var written int64
+	buf := make([]byte, downloadChunkSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			_ = tmp.Close()
+			return err
+		}
+		n, readErr := src.Read(buf)
+		if n > 0 {
+			if _, err := tmp.Write(buf[:n]); err != nil {
+				_ = tmp.Close()
+				return err
+			}
+			written += int64(n)
+			if onProgress != nil {
+				onProgress(ctx, &mcp.ProgressNotification{
+					Progress: float64(written),
+					Total:    &total,
+					Message:  "writing " + filepath.Base(path),
+				})
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			_ = tmp.Close()
+			return fmt.Errorf("failed to decode resource contents: %w", readErr)
+		}
+	}
+
+	// Temporary files are private; give the download the usual permissions
+	if err := tmp.Chmod(0o644); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func TestClient_DownloadResource(t *testing.T) {
+	report := []byte("%PDF-1.7 quarterly report")
+	respond := func(msg *mcp.Message) *mcp.Message {
+		if msg.Method != "resources/read" {
+			return nil
+		}
+		var params struct {
+			URI string `json:"uri"`
+		}
+		_ = json.Unmarshal(msg.Params, &params)
+
+		var content interface{} = mcp.TextResourceContents{URI: params.URI, MimeType: "text/markdown", Text: "# Notes"}
+		if params.URI == "reports://2024/q3" {
+			content = mcp.NewBlobResourceContents(params.URI, "application/pdf", report)
+		}
+		result, _ := json.Marshal(map[string]interface{}{"contents": []interface{}{content}})
+		return &mcp.Message{JSONRPC: "2.0", ID: msg.ID, Result: result}
+	}
+	c, _ := connectWithResponder(t, respond)
+	dir := t.TempDir()
+
+	var progress []float64
+	path, err := c.DownloadResource(context.Background(), "reports://2024/q3", filepath.Join(dir, "report"),
+		WithProgress(func(_ context.Context, n *mcp.ProgressNotification) {
+			progress = append(progress, n.Progress)
+			if n.Total == nil || *n.Total != float64(len(report)) {
+				t.Errorf("expected total %d, got %v", len(report), n.Total)
+			}
+		}))
+	if err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	if path != filepath.Join(dir, "report.pdf") {
+		t.Errorf("expected the extension to be inferred, got %s", path)
+	}
+	if data, _ := os.ReadFile(path); string(data) != string(report) {
+		t.Errorf("expected the decoded blob, got %q", data)
+	}
+	if len(progress) == 0 || progress[len(progress)-1] != float64(len(report)) {
+		t.Errorf("expected progress up to %d bytes, got %v", len(report), progress)
+	}
+
+	// A directory destination is named after the URI
+	path, err = c.DownloadResource(context.Background(), "notes://team/standup", dir)
+	if err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	if path != filepath.Join(dir, "standup.md") {
+		t.Errorf("expected the file to be named after the URI, got %s", path)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "# Notes" {
+		t.Errorf("expected the text contents, got %q", data)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("expected no temporary files to remain, got %d entries", len(entries))
+	}
+}
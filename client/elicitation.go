@@ -0,0 +1,209 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// maxElicitationAttempts bounds how often the handler is asked to correct
+// invalid data before the request is rejected
+const maxElicitationAttempts = 5
+
+// ElicitationHandler asks the user for the data requested by a server. errs
+// is empty on the first call; when accepted data fails validation against the
+// requested schema, the handler is called again with the field-level errors
+// so the UI can ask the user to correct them.
+type ElicitationHandler func(ctx context.Context, req *mcp.ElicitationRequest, errs []*mcp.ValidationError) (*mcp.ElicitationResponse, error)
+
+// WithElicitationHandler configures a handler for server-initiated
+// elicitation requests
+func WithElicitationHandler(handler ElicitationHandler) Option {
+	return func(c *Client) {
+		c.elicitationHandler = handler
+	}
+}
+
+// handleElicitationRequest processes an elicitation/create request from the
+// server. Accepted data is only returned once it satisfies the schema.
+func (c *Client) handleElicitationRequest(ctx context.Context, params json.RawMessage) (*mcp.ElicitationResponse, error) {
+	if c.elicitationHandler == nil {
+		return nil, &mcp.Error{
+			Code:    mcp.MethodNotFound,
+			Message: "elicitation not supported by this client",
+		}
+	}
+
+	var req mcp.ElicitationRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &mcp.Error{
+			Code:    mcp.InvalidParams,
+			Message: "invalid elicitation request parameters",
+		}
+	}
+
+	var errs []*mcp.ValidationError
+	for attempt := 0; attempt < maxElicitationAttempts; attempt++ {
+		resp, err := c.elicitationHandler(ctx, &req, errs)
+		if err != nil {
+			return nil, err
+		}
+		if resp == nil {
+			return nil, fmt.Errorf("elicitation handler returned no response")
+		}
+		if resp.Action != "accept" {
+			return resp, nil
+		}
+
+		errs = ValidateElicitationData(req.Schema, resp.Data)
+		if len(errs) == 0 {
+			return resp, nil
+		}
+	}
+
+	return nil, &mcp.Error{
+		Code:    mcp.InvalidParams,
+		Message: fmt.Sprintf("elicitation data still invalid after %d attempts", maxElicitationAttempts),
+	}
+}
+
+// ValidateElicitationData checks user-provided data against a flat
+// elicitation schema, reporting required fields, primitive types, enums and
+// length and range constraints. Errors are ordered by field name.
+func ValidateElicitationData(schema map[string]interface{}, data map[string]interface{}) []*mcp.ValidationError {
+	var errs []*mcp.ValidationError
+
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	for _, name := range requiredFields(schema) {
+		if _, ok := data[name]; !ok {
+			errs = append(errs, &mcp.ValidationError{Field: name, Message: "is required"})
+		}
+	}
+
+	for name, value := range data {
+		prop, ok := properties[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if msg := validateElicitationValue(prop, value); msg != "" {
+			errs = append(errs, &mcp.ValidationError{Field: name, Message: msg})
+		}
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Field < errs[j].Field
+	})
+	return errs
+}
+
+// requiredFields returns the schema's required property names
+func requiredFields(schema map[string]interface{}) []string {
+	switch required := schema["required"].(type) {
+	case []string:
+		return required
+	case []interface{}:
+		names := make([]string, 0, len(required))
+		for _, r := range required {
+			if name, ok := r.(string); ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// validateElicitationValue returns a message describing why value violates
+// prop, or "" when it is valid
+func validateElicitationValue(prop map[string]interface{}, value interface{}) string {
+	typ, _ := prop["type"].(string)
+
+	switch typ {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return "must be a string"
+		}
+		if enum := enumValues(prop["enum"]); enum != nil && !contains(enum, s) {
+			return fmt.Sprintf("must be one of %v", enum)
+		}
+		length := float64(len([]rune(s)))
+		if limit, ok := toFloat(prop["minLength"]); ok && length < limit {
+			return fmt.Sprintf("must be at least %v characters", limit)
+		}
+		if limit, ok := toFloat(prop["maxLength"]); ok && length > limit {
+			return fmt.Sprintf("must be at most %v characters", limit)
+		}
+	case "number", "integer":
+		n, ok := toFloat(value)
+		if !ok {
+			return fmt.Sprintf("must be a %s", typ)
+		}
+		if typ == "integer" && n != math.Trunc(n) {
+			return "must be an integer"
+		}
+		if limit, ok := toFloat(prop["minimum"]); ok && n < limit {
+			return fmt.Sprintf("must be at least %v", limit)
+		}
+		if limit, ok := toFloat(prop["maximum"]); ok && n > limit {
+			return fmt.Sprintf("must be at most %v", limit)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return "must be a boolean"
+		}
+	}
+
+	return ""
+}
+
+// enumValues converts a schema enum to strings
+func enumValues(v interface{}) []string {
+	switch enum := v.(type) {
+	case []string:
+		return enum
+	case []interface{}:
+		values := make([]string, 0, len(enum))
+		for _, e := range enum {
+			if s, ok := e.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// toFloat converts JSON and Go numeric values to float64
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
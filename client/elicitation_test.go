@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/internal/jsonrpc"
+	"github.com/jmcarbo/fullmcp/internal/testutil"
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+var contactSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"name":   map[string]interface{}{"type": "string", "minLength": 2},
+		"age":    map[string]interface{}{"type": "integer", "minimum": 0, "maximum": 150},
+		"region": map[string]interface{}{"type": "string", "enum": []interface{}{"us-east", "eu"}},
+		"agree":  map[string]interface{}{"type": "boolean"},
+	},
+	"required": []interface{}{"name", "agree"},
+}
+
+func TestValidateElicitationData(t *testing.T) {
+	valid := map[string]interface{}{"name": "Ada", "age": float64(36), "region": "eu", "agree": true}
+	if errs := ValidateElicitationData(contactSchema, valid); len(errs) != 0 {
+		t.Fatalf("expected valid data, got %v", errs)
+	}
+
+	invalid := map[string]interface{}{"name": "A", "age": 36.5, "region": "mars"}
+	errs := ValidateElicitationData(contactSchema, invalid)
+
+	want := []string{"age", "agree", "name", "region"}
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d errors, got %v", len(want), errs)
+	}
+	for i, field := range want {
+		if errs[i].Field != field {
+			t.Errorf("error %d: expected field %q, got %q (%s)", i, field, errs[i].Field, errs[i].Message)
+		}
+	}
+}
+
+func TestClient_ElicitationRevalidates(t *testing.T) {
+	clientTransport, serverTransport := testutil.NewPipeTransport()
+	defer serverTransport.Close()
+
+	var calls [][]*mcp.ValidationError
+	c := New(clientTransport, WithElicitationHandler(func(_ context.Context, _ *mcp.ElicitationRequest, errs []*mcp.ValidationError) (*mcp.ElicitationResponse, error) {
+		calls = append(calls, errs)
+		if len(calls) == 1 {
+			return &mcp.ElicitationResponse{Action: "accept", Data: map[string]interface{}{"name": "Ada"}}, nil
+		}
+		return &mcp.ElicitationResponse{Action: "accept", Data: map[string]interface{}{"name": "Ada", "agree": true}}, nil
+	}))
+	go c.handleMessages()
+
+	reader := jsonrpc.NewMessageReader(serverTransport)
+	writer := jsonrpc.NewMessageWriter(serverTransport)
+
+	params, _ := json.Marshal(mcp.ElicitationRequest{Schema: contactSchema, Description: "Contact details"})
+	_ = writer.Write(&mcp.Message{JSONRPC: "2.0", ID: "e1", Method: "elicitation/create", Params: params})
+
+	resp, err := reader.Read()
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("unexpected error response: %v", resp.Error.Message)
+	}
+
+	var result mcp.ElicitationResponse
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatalf("failed to unmarshal result: %v", err)
+	}
+	if result.Action != "accept" || result.Data["agree"] != true {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	if len(calls) != 2 {
+		t.Fatalf("expected handler to be called twice, got %d", len(calls))
+	}
+	if len(calls[1]) != 1 || calls[1][0].Field != "agree" {
+		t.Errorf("expected a field error for agree, got %v", calls[1])
+	}
+}
+
+func TestClient_ElicitationRejectsPersistentlyInvalidData(t *testing.T) {
+	c := New(testutil.NewMockTransport(), WithElicitationHandler(func(context.Context, *mcp.ElicitationRequest, []*mcp.ValidationError) (*mcp.ElicitationResponse, error) {
+		return &mcp.ElicitationResponse{Action: "accept", Data: map[string]interface{}{}}, nil
+	}))
+
+	params, _ := json.Marshal(mcp.ElicitationRequest{Schema: contactSchema})
+	_, err := c.handleElicitationRequest(context.Background(), params)
+	mcpErr, ok := err.(*mcp.Error)
+	if !ok || mcpErr.Code != mcp.InvalidParams {
+		t.Fatalf("expected InvalidParams error, got %v", err)
+	}
+}
+
+func TestClient_ElicitationDeclineSkipsValidation(t *testing.T) {
+	c := New(testutil.NewMockTransport(), WithElicitationHandler(func(context.Context, *mcp.ElicitationRequest, []*mcp.ValidationError) (*mcp.ElicitationResponse, error) {
+		return &mcp.ElicitationResponse{Action: "decline"}, nil
+	}))
+
+	params, _ := json.Marshal(mcp.ElicitationRequest{Schema: contactSchema})
+	resp, err := c.handleElicitationRequest(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Action != "decline" {
+		t.Errorf("expected decline, got %q", resp.Action)
+	}
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/internal/testutil"
+)
+
+func TestClient_IDShapes(t *testing.T) {
+	clientSide, serverSide := testutil.NewPipeTransport()
+	defer func() { _ = serverSide.Close() }()
+
+	lines := bufio.NewReader(serverSide)
+	readRaw := func() map[string]json.RawMessage {
+		line, err := lines.ReadBytes('\n')
+		if err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal(line, &raw); err != nil {
+			t.Fatalf("decode failed: %v", err)
+		}
+		return raw
+	}
+	send := func(s string) {
+		if _, err := serverSide.Write([]byte(s + "\n")); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+
+	c := New(clientSide)
+	defer func() { _ = c.Close() }()
+
+	connected := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		connected <- c.Connect(ctx)
+	}()
+
+	// The client matches responses whose numeric ID is written as 1.0
+	init := readRaw()
+	if string(init["id"]) != "1" {
+		t.Fatalf("expected first request ID 1, got %s", init["id"])
+	}
+	send(`{"jsonrpc":"2.0","id":1.0,"result":{"protocolVersion":"2025-06-18","capabilities":{},"serverInfo":{"name":"fake","version":"1"}}}`)
+	readRaw() // notifications/initialized
+	if err := <-connected; err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+
+	// Server-initiated requests are answered with the exact ID
+	for _, id := range []string{`"srv-1"`, `"42"`, `42`, `9007199254740993`} {
+		send(`{"jsonrpc":"2.0","id":` + id + `,"method":"ping"}`)
+		resp := readRaw()
+		if string(resp["id"]) != id {
+			t.Errorf("expected response id %s, got %s", id, resp["id"])
+		}
+	}
+}
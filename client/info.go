@@ -0,0 +1,34 @@
+package client
+
+import (
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// ServerInfo returns the server implementation reported during initialize
+func (c *Client) ServerInfo() mcp.Implementation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.serverInfo
+}
+
+// ServerCapabilities returns the capabilities the server advertised during
+// initialize, or nil before Connect
+func (c *Client) ServerCapabilities() *mcp.ServerCapabilities {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.capabilities
+}
+
+// ProtocolVersion returns the protocol version negotiated with the server
+func (c *Client) ProtocolVersion() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.protocolVersion
+}
+
+// Instructions returns the usage instructions provided by the server
+func (c *Client) Instructions() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.instructions
+}
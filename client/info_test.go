@@ -0,0 +1,52 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/internal/testutil"
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func TestClient_ServerInfo(t *testing.T) {
+	respond := func(msg *mcp.Message) *mcp.Message {
+		if msg.Method != "initialize" {
+			return nil
+		}
+		return &mcp.Message{JSONRPC: "2.0", ID: msg.ID, Result: json.RawMessage(`{
+			"protocolVersion": "2025-03-26",
+			"capabilities": {"tools": {"listChanged": true}, "completions": {}},
+			"serverInfo": {"name": "demo", "title": "Demo Server", "version": "2.1.0"},
+			"instructions": "Call search before fetch."
+		}`)}
+	}
+
+	c := New(testutil.NewMockTransport())
+	if c.ServerCapabilities() != nil {
+		t.Error("expected no capabilities before connect")
+	}
+
+	c, _ = connectWithResponder(t, respond)
+
+	info := c.ServerInfo()
+	if info.Name != "demo" || info.Title != "Demo Server" || info.Version != "2.1.0" {
+		t.Errorf("unexpected server info: %+v", info)
+	}
+	if got := c.ProtocolVersion(); got != "2025-03-26" {
+		t.Errorf("expected protocol version 2025-03-26, got %q", got)
+	}
+	if got := c.Instructions(); got != "Call search before fetch." {
+		t.Errorf("unexpected instructions: %q", got)
+	}
+
+	caps := c.ServerCapabilities()
+	if caps == nil || caps.Tools == nil || !caps.Tools.ListChanged {
+		t.Errorf("expected tools listChanged capability, got %+v", caps)
+	}
+	if caps.Completions == nil {
+		t.Error("expected completions capability")
+	}
+	if caps.Resources != nil {
+		t.Error("expected no resources capability")
+	}
+}
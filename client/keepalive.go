@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"time"
+)
+
+// KeepAliveFailureHandler is called when a keepalive ping fails or times out
+type KeepAliveFailureHandler func(err error)
+
+// keepAlive holds the keepalive ping schedule
+type keepAlive struct {
+	interval  time.Duration
+	timeout   time.Duration
+	onFailure KeepAliveFailureHandler
+}
+
+// WithKeepAlive pings the server every interval once connected. A ping that
+// doesn't complete within timeout (default: interval) is reported to the
+// handler configured with WithKeepAliveFailureHandler.
+func WithKeepAlive(interval, timeout time.Duration) Option {
+	return func(c *Client) {
+		if interval <= 0 {
+			return
+		}
+		if timeout <= 0 {
+			timeout = interval
+		}
+		if c.keepAlive == nil {
+			c.keepAlive = &keepAlive{}
+		}
+		c.keepAlive.interval = interval
+		c.keepAlive.timeout = timeout
+	}
+}
+
+// WithKeepAliveFailureHandler configures a handler for failed keepalive pings
+func WithKeepAliveFailureHandler(handler KeepAliveFailureHandler) Option {
+	return func(c *Client) {
+		if c.keepAlive == nil {
+			c.keepAlive = &keepAlive{}
+		}
+		c.keepAlive.onFailure = handler
+	}
+}
+
+// Latency returns the round-trip time of the last successful keepalive ping
+func (c *Client) Latency() time.Duration {
+	return time.Duration(c.latency.Load())
+}
+
+// startKeepAlive launches the ping loop if keepalive is configured
+func (c *Client) startKeepAlive() {
+	if c.keepAlive == nil || c.keepAlive.interval <= 0 {
+		return
+	}
+	c.keepAliveOnce.Do(func() {
+		go c.keepAliveLoop()
+	})
+}
+
+func (c *Client) keepAliveLoop() {
+	ticker := time.NewTicker(c.keepAlive.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), c.keepAlive.timeout)
+		start := time.Now()
+		err := c.call(ctx, "ping", nil, nil)
+		cancel()
+
+		if err != nil {
+			if c.closed.Load() {
+				return
+			}
+			if c.keepAlive.onFailure != nil {
+				c.keepAlive.onFailure(err)
+			}
+			continue
+		}
+		c.latency.Store(int64(time.Since(start)))
+	}
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func TestClient_KeepAlive(t *testing.T) {
+	c, fs := connectWithResponder(t, nil, WithKeepAlive(5*time.Millisecond, time.Second))
+	defer c.Close()
+
+	deadline := time.Now().Add(time.Second)
+	for fs.count("ping") < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if got := fs.count("ping"); got < 2 {
+		t.Fatalf("expected periodic pings, got %d", got)
+	}
+	if c.Latency() <= 0 {
+		t.Error("expected latency to be recorded")
+	}
+}
+
+func TestClient_KeepAliveFailure(t *testing.T) {
+	failPing := func(msg *mcp.Message) *mcp.Message {
+		if msg.Method == "ping" {
+			return &mcp.Message{JSONRPC: "2.0", ID: msg.ID, Error: &mcp.RPCError{Code: int(mcp.InternalError), Message: "unhealthy"}}
+		}
+		return nil
+	}
+
+	failures := make(chan error, 10)
+	c, _ := connectWithResponder(t, failPing,
+		WithKeepAlive(5*time.Millisecond, 0),
+		WithKeepAliveFailureHandler(func(err error) { failures <- err }),
+	)
+	defer c.Close()
+
+	select {
+	case err := <-failures:
+		if err == nil {
+			t.Error("expected a non-nil error")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("failure handler was not called")
+	}
+}
+
+func TestClient_KeepAliveStopsOnClose(t *testing.T) {
+	c, fs := connectWithResponder(t, nil, WithKeepAlive(5*time.Millisecond, time.Second))
+
+	_ = c.Close()
+	time.Sleep(20 * time.Millisecond)
+	sent := fs.count("ping")
+	time.Sleep(30 * time.Millisecond)
+
+	if got := fs.count("ping"); got != sent {
+		t.Errorf("expected no pings after close, got %d more", got-sent)
+	}
+}
+
+func TestClient_KeepAliveDisabled(t *testing.T) {
+	c, fs := connectWithResponder(t, nil)
+	defer c.Close()
+
+	time.Sleep(20 * time.Millisecond)
+	if got := fs.count("ping"); got != 0 {
+		t.Errorf("expected no pings without keepalive, got %d", got)
+	}
+}
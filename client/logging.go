@@ -22,5 +22,14 @@ func (c *Client) SetLogLevel(ctx context.Context, level mcp.LogLevel) error {
 		Level: level,
 	}
 
-	return c.call(ctx, "logging/setLevel", params, nil)
+	if err := c.call(ctx, "logging/setLevel", params, nil); err != nil {
+		return err
+	}
+
+	// Remember the level so it can be restored after a reconnect
+	c.mu.Lock()
+	c.logLevel = level
+	c.mu.Unlock()
+
+	return nil
 }
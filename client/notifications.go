@@ -0,0 +1,31 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// NotificationHandler is called when the client receives a notification
+type NotificationHandler func(ctx context.Context, params json.RawMessage)
+
+// OnNotification registers a handler for notifications with the given method.
+// It receives notifications the client doesn't model (e.g. vendor
+// extensions) as well as those it handles itself, such as
+// notifications/resources/updated. Passing a nil handler removes it.
+func (c *Client) OnNotification(method string, handler NotificationHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if handler == nil {
+		delete(c.notificationHandlers, method)
+		return
+	}
+	c.notificationHandlers[method] = handler
+}
+
+// notificationHandler returns the handler registered for method
+func (c *Client) notificationHandler(method string) NotificationHandler {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.notificationHandlers[method]
+}
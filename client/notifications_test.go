@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestClient_OnNotification(t *testing.T) {
+	c, fs := connectWithResponder(t, nil)
+
+	received := make(chan json.RawMessage, 2)
+	c.OnNotification("notifications/vendor/event", func(_ context.Context, params json.RawMessage) {
+		received <- params
+	})
+
+	fs.notify("notifications/other", nil)
+	fs.notify("notifications/vendor/event", map[string]string{"key": "value"})
+
+	select {
+	case params := <-received:
+		var payload map[string]string
+		if err := json.Unmarshal(params, &payload); err != nil {
+			t.Fatalf("failed to decode params: %v", err)
+		}
+		if payload["key"] != "value" {
+			t.Errorf("unexpected params: %s", params)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler was not called")
+	}
+
+	c.OnNotification("notifications/vendor/event", nil)
+	fs.notify("notifications/vendor/event", nil)
+
+	// A round trip guarantees the notification has been dispatched
+	if err := c.Ping(context.Background()); err != nil {
+		t.Fatalf("ping failed: %v", err)
+	}
+
+	select {
+	case params := <-received:
+		t.Errorf("expected removed handler not to be called, got %s", params)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestClient_OnNotificationModeledMethod(t *testing.T) {
+	c, fs := connectWithResponder(t, nil, WithListCache())
+
+	called := make(chan struct{}, 1)
+	c.OnNotification("notifications/tools/list_changed", func(context.Context, json.RawMessage) {
+		called <- struct{}{}
+	})
+
+	fs.notify("notifications/tools/list_changed", nil)
+
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		t.Fatal("handler was not called for a modeled notification")
+	}
+}
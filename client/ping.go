@@ -5,6 +5,6 @@ import (
 )
 
 // Ping sends a ping request to the server to verify the connection is alive
-func (c *Client) Ping(ctx context.Context) error {
-	return c.call(ctx, "ping", nil, nil)
+func (c *Client) Ping(ctx context.Context, opts ...CallOption) error {
+	return c.callWithRetry(ctx, "ping", nil, nil, true, opts)
 }
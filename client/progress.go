@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/jmcarbo/fullmcp/mcp"
 )
@@ -15,3 +16,47 @@ func WithProgressHandler(handler ProgressHandler) Option {
 		c.progressHandler = handler
 	}
 }
+
+// WithProgress sends the request with a generated progress token and
+// delivers progress notifications for it to onProgress. The callback is
+// unregistered once the response arrives.
+func WithProgress(onProgress ProgressHandler) CallOption {
+	return func(o *callOptions) {
+		o.onProgress = onProgress
+	}
+}
+
+// CallToolWithProgress calls a tool with a generated progress token and
+// delivers progress notifications for that call to onProgress. The callback
+// is unregistered once the result arrives.
+func (c *Client) CallToolWithProgress(ctx context.Context, name string, args interface{}, onProgress ProgressHandler, opts ...CallOption) (interface{}, error) {
+	return c.CallTool(ctx, name, args, append(opts[:len(opts):len(opts)], WithProgress(onProgress))...)
+}
+
+// registerProgress registers onProgress under a new progress token and
+// returns the token with a function unregistering it
+func (c *Client) registerProgress(onProgress ProgressHandler) (string, func()) {
+	token := fmt.Sprintf("progress-%d", c.nextProgress.Add(1))
+
+	c.mu.Lock()
+	c.progressCallbacks[token] = onProgress
+	c.mu.Unlock()
+
+	return token, func() {
+		c.mu.Lock()
+		delete(c.progressCallbacks, token)
+		c.mu.Unlock()
+	}
+}
+
+// progressCallback returns the per-call callback registered for token
+func (c *Client) progressCallback(token mcp.ProgressToken) ProgressHandler {
+	key, ok := token.(string)
+	if !ok {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.progressCallbacks[key]
+}
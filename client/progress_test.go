@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func TestClient_CallToolWithProgress(t *testing.T) {
+	var fs *fakeServer
+	respond := func(msg *mcp.Message) *mcp.Message {
+		if msg.Method != "tools/call" {
+			return nil
+		}
+
+		var params struct {
+			Meta mcp.RequestMeta `json:"_meta"`
+		}
+		_ = json.Unmarshal(msg.Params, &params)
+
+		for _, p := range []float64{1, 2} {
+			fs.notify("notifications/progress", mcp.ProgressNotification{ProgressToken: params.Meta.ProgressToken, Progress: p})
+		}
+		// Progress for another operation must not reach this call's callback
+		fs.notify("notifications/progress", mcp.ProgressNotification{ProgressToken: "other", Progress: 99})
+
+		return &mcp.Message{JSONRPC: "2.0", ID: msg.ID, Result: json.RawMessage(`{"content":[{"type":"text","text":"done"}]}`)}
+	}
+
+	c, server := connectWithResponder(t, respond)
+	fs = server
+
+	updates := make(chan float64, 10)
+	result, err := c.CallToolWithProgress(context.Background(), "slow", nil, func(_ context.Context, n *mcp.ProgressNotification) {
+		updates <- n.Progress
+	})
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if result != "done" {
+		t.Errorf("unexpected result: %v", result)
+	}
+
+	// Callbacks run concurrently, so only the set of values is checked
+	seen := make(map[float64]bool)
+	for i := 0; i < 2; i++ {
+		select {
+		case got := <-updates:
+			seen[got] = true
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for progress")
+		}
+	}
+	if !seen[1] || !seen[2] {
+		t.Errorf("unexpected progress values: %v", seen)
+	}
+
+	select {
+	case got := <-updates:
+		t.Errorf("unexpected extra progress update %v", got)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	c.mu.Lock()
+	remaining := len(c.progressCallbacks)
+	c.mu.Unlock()
+	if remaining != 0 {
+		t.Errorf("expected progress callback to be removed, %d remain", remaining)
+	}
+}
@@ -0,0 +1,280 @@
+package client
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/internal/jsonrpc"
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// ConnectionState describes the state of the client's connection to the server
+type ConnectionState int
+
+// Connection states reported to a ConnectionStateHandler
+const (
+	StateConnected    ConnectionState = iota // Initialized and ready
+	StateDisconnected                        // Transport failed
+	StateReconnecting                        // Attempting to re-establish the connection
+	StateFailed                              // Reconnection attempts exhausted
+)
+
+// String returns the state name
+func (s ConnectionState) String() string {
+	switch s {
+	case StateConnected:
+		return "connected"
+	case StateDisconnected:
+		return "disconnected"
+	case StateReconnecting:
+		return "reconnecting"
+	case StateFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// ConnectionStateHandler is called when the connection state changes.
+// err carries the cause for StateDisconnected and StateFailed.
+type ConnectionStateHandler func(state ConnectionState, err error)
+
+// DialFunc opens a new transport to the server. Transport Connect methods
+// (e.g. streamhttp.Transport.Connect) satisfy this signature.
+type DialFunc func(ctx context.Context) (io.ReadWriteCloser, error)
+
+// ReconnectPolicy controls automatic reconnection after a transport failure
+type ReconnectPolicy struct {
+	Dial         DialFunc      // Opens a replacement transport (required)
+	MaxAttempts  int           // Maximum attempts per outage (0 = unlimited)
+	InitialDelay time.Duration // Delay before the first attempt (default 100ms)
+	MaxDelay     time.Duration // Upper bound on the delay (default 30s)
+	Multiplier   float64       // Backoff growth factor (default 2)
+}
+
+// retryableMethods are idempotent requests that are transparently re-sent
+// when the connection drops while they are in flight
+var retryableMethods = map[string]bool{
+	"tools/list":               true,
+	"resources/list":           true,
+	"resources/templates/list": true,
+	"prompts/list":             true,
+	"ping":                     true,
+}
+
+// WithReconnect enables automatic reconnection with exponential backoff
+func WithReconnect(policy ReconnectPolicy) Option {
+	return func(c *Client) {
+		if policy.InitialDelay <= 0 {
+			policy.InitialDelay = 100 * time.Millisecond
+		}
+		if policy.MaxDelay <= 0 {
+			policy.MaxDelay = 30 * time.Second
+		}
+		if policy.Multiplier < 1 {
+			policy.Multiplier = 2
+		}
+		c.reconnect = &policy
+	}
+}
+
+// WithConnectionStateHandler configures a handler for connection state changes
+func WithConnectionStateHandler(handler ConnectionStateHandler) Option {
+	return func(c *Client) {
+		c.stateHandler = handler
+	}
+}
+
+// backoff returns the delay before the given attempt (starting at 1)
+func (p *ReconnectPolicy) backoff(attempt int) time.Duration {
+	return backoffDelay(p.InitialDelay, p.MaxDelay, p.Multiplier, attempt)
+}
+
+// backoffDelay computes an exponential backoff delay capped at max
+func backoffDelay(initial, maxDelay time.Duration, multiplier float64, attempt int) time.Duration {
+	delay := float64(initial)
+	for i := 1; i < attempt; i++ {
+		delay *= multiplier
+		if delay >= float64(maxDelay) {
+			return maxDelay
+		}
+	}
+	return time.Duration(delay)
+}
+
+func (c *Client) emitState(state ConnectionState, err error) {
+	if c.stateHandler != nil {
+		c.stateHandler(state, err)
+	}
+}
+
+// connectionLost is called by the message loop when its reader fails
+func (c *Client) connectionLost(reader *jsonrpc.MessageReader, err error) {
+	if c.reconnect == nil || c.closed.Load() {
+		return
+	}
+
+	c.connMu.Lock()
+	// Ignore failures from stale transports or while already reconnecting
+	if c.reader != reader || c.reconnecting {
+		c.connMu.Unlock()
+		return
+	}
+	c.reconnecting = true
+	c.connMu.Unlock()
+
+	c.emitState(StateDisconnected, err)
+	go c.reconnectLoop()
+}
+
+// reconnectLoop re-establishes the connection and restores session state
+func (c *Client) reconnectLoop() {
+	defer func() {
+		c.connMu.Lock()
+		c.reconnecting = false
+		c.connMu.Unlock()
+	}()
+
+	// The failed transport is replaced; close it so it doesn't leak
+	c.connMu.RLock()
+	failed := c.transport
+	c.connMu.RUnlock()
+	if failed != nil {
+		_ = failed.Close()
+	}
+
+	// Requests in flight when the connection dropped
+	c.mu.Lock()
+	inFlight := make([]int64, 0, len(c.pending))
+	for id := range c.pending {
+		inFlight = append(inFlight, id)
+	}
+	c.mu.Unlock()
+
+	var lastErr error
+	for attempt := 1; c.reconnect.MaxAttempts == 0 || attempt <= c.reconnect.MaxAttempts; attempt++ {
+		c.emitState(StateReconnecting, nil)
+
+		select {
+		case <-c.done:
+			return
+		case <-time.After(c.reconnect.backoff(attempt)):
+		}
+
+		if lastErr = c.redial(); lastErr == nil {
+			c.resumeInFlight(inFlight)
+			c.emitState(StateConnected, nil)
+			return
+		}
+		if c.closed.Load() {
+			return
+		}
+	}
+
+	c.failInFlight(inFlight)
+	c.emitState(StateFailed, lastErr)
+}
+
+// redial opens a new transport, re-initializes and restores subscriptions.
+// The new transport is closed again if any step fails.
+func (c *Client) redial() error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.reconnect.MaxDelay)
+	defer cancel()
+
+	conn, err := c.reconnect.Dial(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.connMu.Lock()
+	if c.closed.Load() {
+		c.connMu.Unlock()
+		_ = conn.Close()
+		return io.ErrClosedPipe
+	}
+	c.transport = conn
+	c.reader = jsonrpc.NewMessageReader(conn)
+	c.writer = jsonrpc.NewMessageWriter(conn)
+	c.connMu.Unlock()
+
+	go c.handleMessages()
+
+	// The new session may expose different tools, resources or prompts
+	c.InvalidateCache()
+
+	if err := c.initialize(ctx); err != nil {
+		_ = conn.Close()
+		return err
+	}
+	if err := c.restoreSession(ctx); err != nil {
+		_ = conn.Close()
+		return err
+	}
+	return nil
+}
+
+// restoreSession re-sends the log level and resource subscriptions
+func (c *Client) restoreSession(ctx context.Context) error {
+	c.mu.Lock()
+	level := c.logLevel
+	uris := make([]string, 0, len(c.subscriptions))
+	for uri := range c.subscriptions {
+		uris = append(uris, uri)
+	}
+	c.mu.Unlock()
+
+	if level != "" {
+		if err := c.call(ctx, "logging/setLevel", mcp.SetLevelRequest{Level: level}, nil); err != nil {
+			return err
+		}
+	}
+
+	for _, uri := range uris {
+		if err := c.call(ctx, "resources/subscribe", map[string]string{"uri": uri}, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resumeInFlight re-sends idempotent requests and fails the rest
+func (c *Client) resumeInFlight(ids []int64) {
+	for _, id := range ids {
+		c.mu.Lock()
+		call, exists := c.pending[id]
+		c.mu.Unlock()
+
+		if !exists {
+			continue
+		}
+
+		if retryableMethods[call.msg.Method] {
+			if err := c.write(call.msg); err == nil {
+				continue
+			}
+		}
+		c.failCall(call)
+	}
+}
+
+// failInFlight fails every request that was pending when the connection dropped
+func (c *Client) failInFlight(ids []int64) {
+	for _, id := range ids {
+		c.mu.Lock()
+		call, exists := c.pending[id]
+		c.mu.Unlock()
+
+		if exists {
+			c.failCall(call)
+		}
+	}
+}
+
+func (c *Client) failCall(call *pendingCall) {
+	select {
+	case call.ch <- nil:
+	default:
+	}
+}
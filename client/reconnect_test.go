@@ -0,0 +1,278 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/internal/jsonrpc"
+	"github.com/jmcarbo/fullmcp/internal/testutil"
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// fakeServer answers client requests on one end of a pipe transport
+type fakeServer struct {
+	transport io.ReadWriteCloser
+	respond   func(msg *mcp.Message) *mcp.Message // Optional override; nil falls back to defaults
+	writer    *jsonrpc.MessageWriter
+	writeMu   sync.Mutex
+	mu        sync.Mutex
+	methods   []string
+}
+
+func startFakeServer(transport io.ReadWriteCloser) *fakeServer {
+	return startFakeServerWith(transport, nil)
+}
+
+func startFakeServerWith(transport io.ReadWriteCloser, respond func(msg *mcp.Message) *mcp.Message) *fakeServer {
+	fs := &fakeServer{transport: transport, respond: respond, writer: jsonrpc.NewMessageWriter(transport)}
+	go fs.serve()
+	return fs
+}
+
+func (fs *fakeServer) write(msg *mcp.Message) {
+	fs.writeMu.Lock()
+	defer fs.writeMu.Unlock()
+	_ = fs.writer.Write(msg)
+}
+
+// notify sends a server-to-client notification
+func (fs *fakeServer) notify(method string, params interface{}) {
+	msg := &mcp.Message{JSONRPC: "2.0", Method: method}
+	if params != nil {
+		msg.Params, _ = json.Marshal(params)
+	}
+	fs.write(msg)
+}
+
+func (fs *fakeServer) serve() {
+	reader := jsonrpc.NewMessageReader(fs.transport)
+
+	for {
+		msg, err := reader.Read()
+		if err != nil {
+			return
+		}
+		if msg.Method == "" {
+			continue
+		}
+
+		fs.mu.Lock()
+		fs.methods = append(fs.methods, msg.Method)
+		fs.mu.Unlock()
+
+		if msg.ID == nil {
+			continue
+		}
+
+		if fs.respond != nil {
+			if resp := fs.respond(msg); resp != nil {
+				fs.write(resp)
+				continue
+			}
+		}
+
+		result := json.RawMessage(`{}`)
+		switch msg.Method {
+		case "initialize":
+			result = json.RawMessage(`{"protocolVersion":"2025-06-18","capabilities":{},"serverInfo":{"name":"fake","version":"1.0"}}`)
+		case "tools/list":
+			result = json.RawMessage(`{"tools":[{"name":"echo","inputSchema":{"type":"object"}}]}`)
+		}
+		fs.write(&mcp.Message{JSONRPC: "2.0", ID: msg.ID, Result: result})
+	}
+}
+
+func (fs *fakeServer) count(method string) int {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	n := 0
+	for _, m := range fs.methods {
+		if m == method {
+			n++
+		}
+	}
+	return n
+}
+
+func (fs *fakeServer) received(method string) bool {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	for _, m := range fs.methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func TestReconnectPolicy_Backoff(t *testing.T) {
+	policy := &ReconnectPolicy{
+		InitialDelay: 100 * time.Millisecond,
+		MaxDelay:     time.Second,
+		Multiplier:   2,
+	}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{10, time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := policy.backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestClient_Reconnect(t *testing.T) {
+	firstClient, firstServer := testutil.NewPipeTransport()
+	startFakeServer(firstServer)
+
+	var second *fakeServer
+	dial := func(_ context.Context) (io.ReadWriteCloser, error) {
+		clientSide, serverSide := testutil.NewPipeTransport()
+		second = startFakeServer(serverSide)
+		return clientSide, nil
+	}
+
+	states := make(chan ConnectionState, 10)
+	c := New(firstClient,
+		WithReconnect(ReconnectPolicy{Dial: dial, InitialDelay: 10 * time.Millisecond}),
+		WithConnectionStateHandler(func(state ConnectionState, _ error) {
+			states <- state
+		}),
+	)
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+	if err := c.SetLogLevel(ctx, mcp.LogLevelDebug); err != nil {
+		t.Fatalf("set log level failed: %v", err)
+	}
+	if err := c.SubscribeResource(ctx, "file:///watched.txt"); err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+
+	// Drop the connection from the server side
+	_ = firstServer.Close()
+
+	want := []ConnectionState{StateConnected, StateDisconnected, StateReconnecting, StateConnected}
+	for _, expected := range want {
+		select {
+		case got := <-states:
+			if got != expected {
+				t.Fatalf("expected state %v, got %v", expected, got)
+			}
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for state %v", expected)
+		}
+	}
+
+	tools, err := c.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("list tools after reconnect failed: %v", err)
+	}
+	if len(tools) != 1 {
+		t.Errorf("expected 1 tool, got %d", len(tools))
+	}
+
+	for _, method := range []string{"initialize", "logging/setLevel", "resources/subscribe"} {
+		if !second.received(method) {
+			t.Errorf("expected %s to be re-sent after reconnect", method)
+		}
+	}
+}
+
+// closeRecorder reports when a transport is closed
+type closeRecorder struct {
+	io.ReadWriteCloser
+	closed chan struct{}
+	once   sync.Once
+}
+
+func (cr *closeRecorder) Close() error {
+	cr.once.Do(func() { close(cr.closed) })
+	return cr.ReadWriteCloser.Close()
+}
+
+func TestClient_ReconnectClosesOldTransport(t *testing.T) {
+	firstClient, firstServer := testutil.NewPipeTransport()
+	startFakeServer(firstServer)
+	first := &closeRecorder{ReadWriteCloser: firstClient, closed: make(chan struct{})}
+
+	dial := func(_ context.Context) (io.ReadWriteCloser, error) {
+		clientSide, serverSide := testutil.NewPipeTransport()
+		startFakeServer(serverSide)
+		return clientSide, nil
+	}
+	c := New(first, WithReconnect(ReconnectPolicy{Dial: dial, InitialDelay: time.Millisecond}))
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+
+	_ = firstServer.Close()
+	select {
+	case <-first.closed:
+	case <-ctx.Done():
+		t.Fatal("expected the failed transport to be closed on reconnect")
+	}
+}
+
+func TestClient_ReconnectFails(t *testing.T) {
+	firstClient, firstServer := testutil.NewPipeTransport()
+	startFakeServer(firstServer)
+
+	dialErr := errors.New("server unavailable")
+	failed := make(chan error, 1)
+	c := New(firstClient,
+		WithReconnect(ReconnectPolicy{
+			Dial:         func(context.Context) (io.ReadWriteCloser, error) { return nil, dialErr },
+			MaxAttempts:  2,
+			InitialDelay: time.Millisecond,
+		}),
+		WithConnectionStateHandler(func(state ConnectionState, err error) {
+			if state == StateFailed {
+				failed <- err
+			}
+		}),
+	)
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+
+	_ = firstServer.Close()
+
+	select {
+	case err := <-failed:
+		if !errors.Is(err, dialErr) {
+			t.Errorf("expected dial error, got %v", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("expected reconnection to fail")
+	}
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func TestClient_ListResourcesFiltered(t *testing.T) {
+	filters := make(chan mcp.ResourceFilter, 1)
+	respond := func(msg *mcp.Message) *mcp.Message {
+		if msg.Method != "resources/list" {
+			return nil
+		}
+
+		var params struct {
+			Filter mcp.ResourceFilter `json:"filter"`
+		}
+		_ = json.Unmarshal(msg.Params, &params)
+		filters <- params.Filter
+
+		// Respond as a server that ignores the filter
+		return &mcp.Message{JSONRPC: "2.0", ID: msg.ID, Result: json.RawMessage(
+			`{"resources":[{"uri":"file:///docs/a.md","name":"a"},{"uri":"db://users","name":"users"}]}`)}
+	}
+	c, _ := connectWithResponder(t, respond)
+
+	resources, err := c.ListResourcesFiltered(context.Background(), mcp.ResourceFilter{Scheme: "file", Tags: []string{"docs"}})
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+
+	sent := <-filters
+	if sent.Scheme != "file" || len(sent.Tags) != 1 || sent.Tags[0] != "docs" {
+		t.Errorf("filter not sent to the server: %+v", sent)
+	}
+	if len(resources) != 1 || resources[0].URI != "file:///docs/a.md" {
+		t.Errorf("expected scheme to be applied locally, got %v", resources)
+	}
+}
+
+func TestClient_ListResourceTemplates(t *testing.T) {
+	respond := func(msg *mcp.Message) *mcp.Message {
+		if msg.Method != "resources/templates/list" {
+			return nil
+		}
+		return &mcp.Message{JSONRPC: "2.0", ID: msg.ID, Result: json.RawMessage(
+			`{"resourceTemplates":[{"uriTemplate":"file:///{path}","name":"files","mimeType":"text/plain"}]}`)}
+	}
+	c, _ := connectWithResponder(t, respond)
+
+	templates, err := c.ListResourceTemplates(context.Background())
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(templates) != 1 || templates[0].URITemplate != "file:///{path}" || templates[0].MimeType != "text/plain" {
+		t.Errorf("unexpected templates: %+v", templates)
+	}
+}
+
+func TestClient_ReadResourceBlob(t *testing.T) {
+	respond := func(msg *mcp.Message) *mcp.Message {
+		if msg.Method != "resources/read" {
+			return nil
+		}
+		return &mcp.Message{JSONRPC: "2.0", ID: msg.ID, Result: json.RawMessage(
+			`{"contents":[{"uri":"image://logo","mimeType":"image/png","blob":"iVBORwA="},` +
+				`{"uri":"image://logo","mimeType":"text/plain","text":"alt text"}]}`)}
+	}
+	c, _ := connectWithResponder(t, respond)
+
+	data, err := c.ReadResource(context.Background(), "image://logo")
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if string(data) != "\x89PNG\x00" {
+		t.Errorf("expected decoded blob, got %q", data)
+	}
+
+	contents, err := c.ReadResourceContents(context.Background(), "image://logo")
+	if err != nil {
+		t.Fatalf("read contents failed: %v", err)
+	}
+	if len(contents) != 2 {
+		t.Fatalf("expected 2 contents, got %d", len(contents))
+	}
+	if blob, ok := contents[0].(mcp.BlobResourceContents); !ok || blob.MimeType != "image/png" {
+		t.Errorf("expected blob contents first, got %#v", contents[0])
+	}
+	if text, ok := contents[1].(mcp.TextResourceContents); !ok || text.Text != "alt text" {
+		t.Errorf("expected text contents second, got %#v", contents[1])
+	}
+}
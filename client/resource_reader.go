@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// ResourceInfo describes a resource opened with OpenResource
+type ResourceInfo struct {
+	URI      string
+	MimeType string
+	Size     int64                  // Decoded size in bytes
+	Binary   bool                   // Contents were sent as base64 blobs
+	Chunks   int                    // Number of contents items streamed
+	Meta     map[string]interface{} // _meta of the first contents item
+}
+
+// OpenResource reads a resource and returns its contents as a stream, so it
+// can be piped elsewhere without holding a decoded copy in memory: blobs are
+// decoded from base64 as the stream is read. Servers that split a large
+// resource into several contents items for the same URI are treated as
+// sending chunks, which the stream yields in order; items for other URIs are
+// left out.
+func (c *Client) OpenResource(ctx context.Context, uri string, opts ...CallOption) (io.ReadCloser, *ResourceInfo, error) {
+	contents, err := c.ReadResourceContents(ctx, uri, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(contents) == 0 {
+		return nil, nil, &mcp.NotFoundError{Type: "resource", Name: uri}
+	}
+
+	info := &ResourceInfo{URI: contents[0].ResourceURI()}
+	var readers []io.Reader
+	for i, item := range contents {
+		if item.ResourceURI() != info.URI {
+			continue
+		}
+		var mimeType string
+		var meta map[string]interface{}
+		switch item := item.(type) {
+		case mcp.BlobResourceContents:
+			readers = append(readers, base64.NewDecoder(base64.StdEncoding, strings.NewReader(item.Blob)))
+			info.Size += blobSize(item.Blob)
+			info.Binary = true
+			mimeType, meta = item.MimeType, item.Meta
+		case mcp.TextResourceContents:
+			readers = append(readers, strings.NewReader(item.Text))
+			info.Size += int64(len(item.Text))
+			mimeType, meta = item.MimeType, item.Meta
+		default:
+			return nil, nil, fmt.Errorf("unsupported resource contents %T", item)
+		}
+		if i == 0 {
+			info.MimeType, info.Meta = mimeType, meta
+		}
+		info.Chunks++
+	}
+
+	return &resourceReader{r: io.MultiReader(readers...)}, info, nil
+}
+
+// resourceReader is the stream returned by OpenResource
+type resourceReader struct {
+	r io.Reader
+}
+
+func (rr *resourceReader) Read(p []byte) (int, error) {
+	if rr.r == nil {
+		return 0, os.ErrClosed
+	}
+	return rr.r.Read(p)
+}
+
+// Close releases the contents held by the stream
+func (rr *resourceReader) Close() error {
+	rr.r = nil
+	return nil
+}
+
+// blobSize returns the decoded size of base64 data
+func blobSize(blob string) int64 {
+	padding := strings.Count(blob[max(len(blob)-2, 0):], "=")
+	return int64(base64.StdEncoding.DecodedLen(len(blob)) - padding)
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func TestClient_OpenResource(t *testing.T) {
+	respond := func(msg *mcp.Message) *mcp.Message {
+		if msg.Method != "resources/read" {
+			return nil
+		}
+		// A large log split into chunks, followed by an unrelated item
+		result, _ := json.Marshal(map[string]interface{}{"contents": []interface{}{
+			mcp.NewBlobResourceContents("logs://build", "text/plain", []byte("step 1\n")),
+			mcp.NewBlobResourceContents("logs://build", "text/plain", []byte("step 2\n")),
+			mcp.TextResourceContents{URI: "logs://build", Text: "done\n"},
+			mcp.TextResourceContents{URI: "logs://test", Text: "unrelated"},
+		}})
+		return &mcp.Message{JSONRPC: "2.0", ID: msg.ID, Result: result}
+	}
+	c, _ := connectWithResponder(t, respond)
+
+	stream, info, err := c.OpenResource(context.Background(), "logs://build")
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+
+	data, err := io.ReadAll(stream)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if string(data) != "step 1\nstep 2\ndone\n" {
+		t.Errorf("expected the chunks in order, got %q", data)
+	}
+	if info.URI != "logs://build" || info.MimeType != "text/plain" || !info.Binary || info.Chunks != 3 || info.Size != int64(len(data)) {
+		t.Errorf("unexpected info %+v", info)
+	}
+
+	_ = stream.Close()
+	if _, err := stream.Read(make([]byte, 1)); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("expected reads after Close to fail, got %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// RetryPolicy controls how failed requests are retried
+type RetryPolicy struct {
+	MaxAttempts  int             // Total attempts including the first (<= 1 disables retries)
+	InitialDelay time.Duration   // Delay before the first retry (default 100ms)
+	MaxDelay     time.Duration   // Upper bound on the delay (default 5s)
+	Multiplier   float64         // Backoff growth factor (default 2)
+	RetryOnCodes []mcp.ErrorCode // JSON-RPC error codes that are retried
+}
+
+// CallOption configures a single client request
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	retry          *RetryPolicy
+	timeout        time.Duration
+	meta           map[string]interface{}
+	idempotencyKey string
+	onProgress     ProgressHandler
+}
+
+// WithRetry sets the default retry policy for all requests made by the client.
+// Transient transport errors, such as network failures, a lost connection or
+// a transport timeout, are always retried; JSON-RPC errors are retried
+// only when their code is listed in RetryOnCodes. tools/call requests are
+// retried only for tools annotated with IdempotentHint.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = normalizeRetryPolicy(policy)
+	}
+}
+
+// WithCallRetry overrides the client retry policy for a single request
+func WithCallRetry(policy RetryPolicy) CallOption {
+	return func(o *callOptions) {
+		o.retry = normalizeRetryPolicy(policy)
+	}
+}
+
+func normalizeRetryPolicy(policy RetryPolicy) *RetryPolicy {
+	if policy.InitialDelay <= 0 {
+		policy.InitialDelay = 100 * time.Millisecond
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = 5 * time.Second
+	}
+	if policy.Multiplier < 1 {
+		policy.Multiplier = 2
+	}
+	return &policy
+}
+
+// shouldRetry reports whether err, returned by a request made with ctx, is
+// worth retrying under this policy
+func (p *RetryPolicy) shouldRetry(ctx context.Context, err error) bool {
+	var rpcErr *mcp.RPCError
+	if errors.As(err, &rpcErr) {
+		for _, code := range p.RetryOnCodes {
+			if int(code) == rpcErr.Code {
+				return true
+			}
+		}
+		return false
+	}
+	return isTransient(ctx, err)
+}
+
+// isTransient reports whether err is a transport failure or timeout that
+// may not happen again. Errors such as failing to encode the request or
+// decode the result fail the same way on every attempt.
+func isTransient(ctx context.Context, err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) {
+		// A deadline below the request's own, such as an HTTP client timeout
+		return ctx.Err() == nil
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, ErrConnectionLost) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.ErrClosedPipe) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+// resolveCallOptions merges per-call options over the client defaults
+func (c *Client) resolveCallOptions(opts []CallOption) *callOptions {
+	o := &callOptions{retry: c.retry}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// callWithRetry performs a request, retrying according to the resolved policy.
+// Non-idempotent requests are never retried unless they carry an
+// idempotency key.
+func (c *Client) callWithRetry(ctx context.Context, method string, params, result interface{}, idempotent bool, opts []CallOption) error {
+	options := c.resolveCallOptions(opts)
+	policy := options.retry
+
+	if options.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.timeout)
+		defer cancel()
+	}
+
+	if options.idempotencyKey != "" {
+		if options.meta == nil {
+			options.meta = make(map[string]interface{}, 1)
+		}
+		options.meta[mcp.IdempotencyKeyMeta] = options.idempotencyKey
+		// The server runs the call at most once, so retries are safe
+		idempotent = true
+	}
+
+	if options.onProgress != nil {
+		token, unregister := c.registerProgress(options.onProgress)
+		defer unregister()
+		if options.meta == nil {
+			options.meta = make(map[string]interface{}, 1)
+		}
+		options.meta["progressToken"] = token
+	}
+
+	if len(options.meta) > 0 {
+		var err error
+		if params, err = withMeta(params, options.meta); err != nil {
+			return err
+		}
+	}
+
+	attempts := 1
+	if policy != nil && idempotent && policy.MaxAttempts > 1 {
+		attempts = policy.MaxAttempts
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = c.call(ctx, method, params, result); err == nil {
+			return nil
+		}
+
+		if attempt == attempts || !policy.shouldRetry(ctx, err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoffDelay(policy.InitialDelay, policy.MaxDelay, policy.Multiplier, attempt)):
+		}
+	}
+
+	return err
+}
+
+// isIdempotentTool reports whether a tool was advertised with IdempotentHint
+func (c *Client) isIdempotentTool(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tool, ok := c.toolHints[name]
+	return ok && tool.Annotations.IsIdempotent()
+}
+
+// rememberTools records tool annotations used for retry decisions
+func (c *Client) rememberTools(tools []*mcp.Tool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, tool := range tools {
+		c.toolHints[tool.Name] = tool
+	}
+}
@@ -0,0 +1,193 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/internal/testutil"
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// flakyResponder fails the given method with code until failures are exhausted
+func flakyResponder(method string, code mcp.ErrorCode, failures int32) func(*mcp.Message) *mcp.Message {
+	var remaining atomic.Int32
+	remaining.Store(failures)
+
+	return func(msg *mcp.Message) *mcp.Message {
+		switch msg.Method {
+		case "tools/list":
+			return &mcp.Message{JSONRPC: "2.0", ID: msg.ID, Result: json.RawMessage(
+				`{"tools":[{"name":"safe","inputSchema":{},"annotations":{"idempotentHint":true}},{"name":"unsafe","inputSchema":{}}]}`)}
+		case method:
+			if remaining.Add(-1) >= 0 {
+				return &mcp.Message{JSONRPC: "2.0", ID: msg.ID, Error: &mcp.RPCError{Code: int(code), Message: "busy"}}
+			}
+			if method == "tools/call" {
+				return &mcp.Message{JSONRPC: "2.0", ID: msg.ID, Result: json.RawMessage(`{"content":[{"type":"text","text":"done"}]}`)}
+			}
+		}
+		return nil
+	}
+}
+
+func connectWithResponder(t *testing.T, respond func(*mcp.Message) *mcp.Message, opts ...Option) (*Client, *fakeServer) {
+	t.Helper()
+
+	clientTransport, serverTransport := testutil.NewPipeTransport()
+	fs := startFakeServerWith(serverTransport, respond)
+	t.Cleanup(func() { _ = serverTransport.Close() })
+
+	c := New(clientTransport, opts...)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+	return c, fs
+}
+
+func TestClient_RetryOnCode(t *testing.T) {
+	c, fs := connectWithResponder(t, flakyResponder("resources/list", mcp.InternalError, 2),
+		WithRetry(RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond, RetryOnCodes: []mcp.ErrorCode{mcp.InternalError}}))
+
+	if _, err := c.ListResources(context.Background()); err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+	if got := fs.count("resources/list"); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestClient_RetryIgnoresUnlistedCode(t *testing.T) {
+	c, fs := connectWithResponder(t, flakyResponder("resources/list", mcp.InvalidParams, 1),
+		WithRetry(RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond, RetryOnCodes: []mcp.ErrorCode{mcp.InternalError}}))
+
+	_, err := c.ListResources(context.Background())
+	var rpcErr *mcp.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != int(mcp.InvalidParams) {
+		t.Fatalf("expected InvalidParams error, got %v", err)
+	}
+	if got := fs.count("resources/list"); got != 1 {
+		t.Errorf("expected 1 attempt, got %d", got)
+	}
+}
+
+func TestClient_RetryPerCallOverride(t *testing.T) {
+	c, fs := connectWithResponder(t, flakyResponder("resources/list", mcp.InternalError, 1))
+
+	policy := RetryPolicy{MaxAttempts: 2, InitialDelay: time.Millisecond, RetryOnCodes: []mcp.ErrorCode{mcp.InternalError}}
+	if _, err := c.ListResources(context.Background(), WithCallRetry(policy)); err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+	if got := fs.count("resources/list"); got != 2 {
+		t.Errorf("expected 2 attempts, got %d", got)
+	}
+}
+
+func TestClient_RetryToolsOnlyWhenIdempotent(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond, RetryOnCodes: []mcp.ErrorCode{mcp.InternalError}}
+
+	c, fs := connectWithResponder(t, flakyResponder("tools/call", mcp.InternalError, 1), WithRetry(policy))
+	ctx := context.Background()
+
+	if _, err := c.ListTools(ctx); err != nil {
+		t.Fatalf("list tools failed: %v", err)
+	}
+
+	if _, err := c.CallTool(ctx, "unsafe", map[string]interface{}{}); err == nil {
+		t.Fatal("expected non-idempotent tool failure to surface")
+	}
+	if got := fs.count("tools/call"); got != 1 {
+		t.Fatalf("expected non-idempotent tool to be called once, got %d", got)
+	}
+
+	c2, fs2 := connectWithResponder(t, flakyResponder("tools/call", mcp.InternalError, 1), WithRetry(policy))
+	if _, err := c2.ListTools(ctx); err != nil {
+		t.Fatalf("list tools failed: %v", err)
+	}
+	if _, err := c2.CallTool(ctx, "safe", map[string]interface{}{}); err != nil {
+		t.Fatalf("expected idempotent tool to be retried, got %v", err)
+	}
+	if got := fs2.count("tools/call"); got != 2 {
+		t.Errorf("expected idempotent tool to be called twice, got %d", got)
+	}
+}
+
+func TestClient_RetryWithIdempotencyKey(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond, RetryOnCodes: []mcp.ErrorCode{mcp.InternalError}}
+
+	var keys []string
+	flaky := flakyResponder("tools/call", mcp.InternalError, 1)
+	respond := func(msg *mcp.Message) *mcp.Message {
+		if msg.Method == "tools/call" {
+			var params mcp.CallToolRequest
+			_ = json.Unmarshal(msg.Params, &params)
+			keys = append(keys, params.IdempotencyKey())
+		}
+		return flaky(msg)
+	}
+	c, fs := connectWithResponder(t, respond, WithRetry(policy))
+
+	key := NewIdempotencyKey()
+	if _, err := c.CallTool(context.Background(), "unsafe", map[string]interface{}{}, WithIdempotencyKey(key)); err != nil {
+		t.Fatalf("expected keyed call to be retried, got %v", err)
+	}
+	if got := fs.count("tools/call"); got != 2 {
+		t.Fatalf("expected 2 attempts, got %d", got)
+	}
+	if len(keys) != 2 || keys[0] != key || keys[1] != key {
+		t.Errorf("expected every attempt to carry key %q, got %v", key, keys)
+	}
+}
+
+func TestClient_RetrySkipsPermanentErrors(t *testing.T) {
+	respond := func(msg *mcp.Message) *mcp.Message {
+		if msg.Method == "resources/list" {
+			return &mcp.Message{JSONRPC: "2.0", ID: msg.ID, Result: json.RawMessage(`{"resources":"not a list"}`)}
+		}
+		return nil
+	}
+	c, fs := connectWithResponder(t, respond, WithRetry(RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond}))
+
+	if _, err := c.ListResources(context.Background()); err == nil {
+		t.Fatal("expected a decode error")
+	}
+	if got := fs.count("resources/list"); got != 1 {
+		t.Errorf("expected a decode error not to be retried, got %d attempts", got)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	live := context.Background()
+	expired, cancel := context.WithTimeout(live, 0)
+	defer cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{"connection lost", live, ErrConnectionLost, true},
+		{"eof", live, fmt.Errorf("read: %w", io.EOF), true},
+		{"net error", live, &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, true},
+		{"transport timeout", live, fmt.Errorf("request: %w", context.DeadlineExceeded), true},
+		{"request deadline", expired, context.DeadlineExceeded, false},
+		{"cancelled", live, context.Canceled, false},
+		{"decode error", live, &json.UnmarshalTypeError{Value: "string"}, false},
+		{"other", live, errors.New("invalid parameters"), false},
+	}
+	for _, tt := range tests {
+		if got := isTransient(tt.ctx, tt.err); got != tt.want {
+			t.Errorf("%s: isTransient = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
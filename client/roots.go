@@ -19,14 +19,15 @@ func WithRoots(provider RootsProvider) Option {
 // ListRoots is called by servers to request the list of roots
 // This is typically handled automatically by the message handler
 func (c *Client) handleRootsList(ctx context.Context) (*mcp.RootsListResult, error) {
-	if c.rootsProvider == nil {
+	provider := c.currentRootsProvider()
+	if provider == nil {
 		return nil, &mcp.Error{
 			Code:    mcp.MethodNotFound,
 			Message: "roots not supported by this client",
 		}
 	}
 
-	roots, err := c.rootsProvider(ctx)
+	roots, err := provider(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -38,7 +39,7 @@ func (c *Client) handleRootsList(ctx context.Context) (*mcp.RootsListResult, err
 
 // NotifyRootsChanged sends a notification to the server that the roots list has changed
 func (c *Client) NotifyRootsChanged() error {
-	if c.rootsProvider == nil {
+	if c.currentRootsProvider() == nil {
 		return &mcp.Error{
 			Code:    mcp.InternalError,
 			Message: "roots not configured for this client",
@@ -46,3 +47,90 @@ func (c *Client) NotifyRootsChanged() error {
 	}
 	return c.notify("notifications/roots/list_changed", nil)
 }
+
+// SetRoots replaces the client's roots, taking the place of any provider
+// configured with WithRoots. Once connected the server is sent
+// notifications/roots/list_changed; roots set before Connect are advertised
+// in the initialize request instead.
+func (c *Client) SetRoots(ctx context.Context, roots []mcp.Root) error {
+	c.rootsMu.Lock()
+	c.roots = append([]mcp.Root{}, roots...)
+	c.rootsProvider = c.staticRoots
+	c.rootsMu.Unlock()
+
+	return c.rootsChanged(ctx)
+}
+
+// AddRoot adds a root, replacing any existing root with the same URI, and
+// notifies the server as SetRoots does. The first call starts from the roots
+// returned by the configured provider.
+func (c *Client) AddRoot(ctx context.Context, root mcp.Root) error {
+	roots, err := c.currentRoots(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range roots {
+		if roots[i].URI == root.URI {
+			roots[i] = root
+			return c.SetRoots(ctx, roots)
+		}
+	}
+	return c.SetRoots(ctx, append(roots, root))
+}
+
+// RemoveRoot removes the root with the given URI and notifies the server as
+// SetRoots does. Removing an unknown URI is a no-op.
+func (c *Client) RemoveRoot(ctx context.Context, uri string) error {
+	roots, err := c.currentRoots(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range roots {
+		if roots[i].URI == uri {
+			return c.SetRoots(ctx, append(roots[:i], roots[i+1:]...))
+		}
+	}
+	return nil
+}
+
+// currentRootsProvider returns the provider answering roots/list requests
+func (c *Client) currentRootsProvider() RootsProvider {
+	c.rootsMu.RLock()
+	defer c.rootsMu.RUnlock()
+	return c.rootsProvider
+}
+
+// currentRoots returns a copy of the client's current roots
+func (c *Client) currentRoots(ctx context.Context) ([]mcp.Root, error) {
+	provider := c.currentRootsProvider()
+	if provider == nil {
+		return nil, nil
+	}
+	roots, err := provider(ctx)
+	return append([]mcp.Root{}, roots...), err
+}
+
+// staticRoots is the provider installed by SetRoots
+func (c *Client) staticRoots(_ context.Context) ([]mcp.Root, error) {
+	c.rootsMu.RLock()
+	defer c.rootsMu.RUnlock()
+	return append([]mcp.Root{}, c.roots...), nil
+}
+
+// rootsChanged notifies the server of a roots change once the client has
+// been initialized
+func (c *Client) rootsChanged(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	initialized := c.capabilities != nil
+	c.mu.Unlock()
+	if !initialized {
+		return nil
+	}
+	return c.notify("notifications/roots/list_changed", nil)
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/internal/testutil"
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func TestClient_SetRootsBeforeConnect(t *testing.T) {
+	clientTransport, serverTransport := testutil.NewPipeTransport()
+	var params initializeParams
+	fs := startFakeServerWith(serverTransport, captureInitialize(&params))
+	t.Cleanup(func() { _ = serverTransport.Close() })
+
+	c := New(clientTransport)
+	if err := c.SetRoots(context.Background(), []mcp.Root{{URI: "file:///work", Name: "work"}}); err != nil {
+		t.Fatalf("SetRoots failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+
+	if params.Capabilities.Roots == nil || !params.Capabilities.Roots.ListChanged {
+		t.Error("expected roots capability to be advertised")
+	}
+	if fs.received("notifications/roots/list_changed") {
+		t.Error("expected no list_changed notification before initialization")
+	}
+}
+
+func TestClient_AddRoot(t *testing.T) {
+	c, fs := connectWithResponder(t, nil, WithRoots(func(context.Context) ([]mcp.Root, error) {
+		return []mcp.Root{{URI: "file:///a"}}, nil
+	}))
+	ctx := context.Background()
+
+	if err := c.AddRoot(ctx, mcp.Root{URI: "file:///b", Name: "b"}); err != nil {
+		t.Fatalf("AddRoot failed: %v", err)
+	}
+	if err := c.AddRoot(ctx, mcp.Root{URI: "file:///a", Name: "renamed"}); err != nil {
+		t.Fatalf("AddRoot failed: %v", err)
+	}
+	if err := c.RemoveRoot(ctx, "file:///b"); err != nil {
+		t.Fatalf("RemoveRoot failed: %v", err)
+	}
+
+	result, err := c.handleRootsList(ctx)
+	if err != nil {
+		t.Fatalf("roots/list failed: %v", err)
+	}
+	if len(result.Roots) != 1 || result.Roots[0] != (mcp.Root{URI: "file:///a", Name: "renamed"}) {
+		t.Errorf("unexpected roots: %+v", result.Roots)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for fs.count("notifications/roots/list_changed") < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := fs.count("notifications/roots/list_changed"); got != 3 {
+		t.Errorf("expected 3 list_changed notifications, got %d", got)
+	}
+}
+
+func TestClient_RemoveUnknownRoot(t *testing.T) {
+	c, _ := connectWithResponder(t, nil)
+
+	if err := c.RemoveRoot(context.Background(), "file:///missing"); err != nil {
+		t.Errorf("expected removing an unknown root to succeed, got %v", err)
+	}
+	if c.currentRootsProvider() != nil {
+		t.Error("expected no roots provider to be installed")
+	}
+}
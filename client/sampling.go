@@ -10,6 +10,17 @@ import (
 // SamplingHandler is a function that handles sampling requests from servers
 type SamplingHandler func(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error)
 
+// SamplingResource is a snapshot of a server resource attached to a sampling
+// request when the server asks for context via includeContext
+type SamplingResource struct {
+	URI      string
+	Name     string
+	MimeType string
+	Text     string
+}
+
+type samplingContextKey struct{}
+
 // WithSamplingHandler configures a sampling handler for the client
 func WithSamplingHandler(handler SamplingHandler) Option {
 	return func(c *Client) {
@@ -17,11 +28,16 @@ func WithSamplingHandler(handler SamplingHandler) Option {
 	}
 }
 
-// TODO: Wire up handleSamplingRequest in the client message routing
+// IncludedContext returns the resources gathered for a sampling request whose
+// includeContext is "thisServer" or "allServers". Sampling handlers call this
+// to ground the LLM call in the requesting server's data.
+func IncludedContext(ctx context.Context) []SamplingResource {
+	resources, _ := ctx.Value(samplingContextKey{}).([]SamplingResource)
+	return resources
+}
+
 // handleSamplingRequest processes a sampling/createMessage request from the server
-//
-//nolint:unused // Reserved for future server-initiated sampling requests
-func (c *Client) _handleSamplingRequest(ctx context.Context, params json.RawMessage) (*mcp.CreateMessageResult, error) {
+func (c *Client) handleSamplingRequest(ctx context.Context, params json.RawMessage) (*mcp.CreateMessageResult, error) {
 	if c.samplingHandler == nil {
 		return nil, &mcp.Error{
 			Code:    mcp.MethodNotFound,
@@ -37,5 +53,41 @@ func (c *Client) _handleSamplingRequest(ctx context.Context, params json.RawMess
 		}
 	}
 
+	switch req.IncludeContext {
+	case mcp.IncludeContextThisServer, mcp.IncludeContextAllServers:
+		// A client connection only sees a single server, so both values
+		// resolve to the resources exposed by the requesting server
+		resources, err := c.collectSamplingContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		ctx = context.WithValue(ctx, samplingContextKey{}, resources)
+	}
+
 	return c.samplingHandler(ctx, &req)
 }
+
+// collectSamplingContext reads every resource exposed by the server
+func (c *Client) collectSamplingContext(ctx context.Context) ([]SamplingResource, error) {
+	resources, err := c.ListResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	included := make([]SamplingResource, 0, len(resources))
+	for _, res := range resources {
+		data, err := c.ReadResource(ctx, res.URI)
+		if err != nil {
+			// Skip resources that cannot be read rather than failing the request
+			continue
+		}
+		included = append(included, SamplingResource{
+			URI:      res.URI,
+			Name:     res.Name,
+			MimeType: res.MimeType,
+			Text:     string(data),
+		})
+	}
+
+	return included, nil
+}
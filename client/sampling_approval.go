@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// userRejectedCode is the error code the spec's examples use when the user
+// denies a sampling request
+const userRejectedCode mcp.ErrorCode = -1
+
+// SamplingApprover puts a human in the loop of server-initiated sampling, as
+// the spec recommends. Either review may be nil to skip it.
+type SamplingApprover struct {
+	// ReviewRequest is shown the request before the LLM is called and
+	// returns the request to send, which the user may have edited, or nil
+	// to deny it
+	ReviewRequest func(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageRequest, error)
+
+	// ReviewResult is shown the generated result before it is returned to
+	// the server and returns the result to send, which the user may have
+	// edited, or nil to deny it
+	ReviewResult func(ctx context.Context, req *mcp.CreateMessageRequest, result *mcp.CreateMessageResult) (*mcp.CreateMessageResult, error)
+}
+
+// ApproveSampling wraps handler so that approver reviews each request before
+// the LLM is called and each result before it reaches the server. A denied
+// request or result fails the sampling request with a "user rejected" error.
+func ApproveSampling(handler SamplingHandler, approver SamplingApprover) SamplingHandler {
+	return func(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+		if approver.ReviewRequest != nil {
+			approved, err := approver.ReviewRequest(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			if approved == nil {
+				return nil, &mcp.Error{Code: userRejectedCode, Message: "user rejected sampling request"}
+			}
+			req = approved
+		}
+
+		result, err := handler(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		if approver.ReviewResult != nil {
+			approved, err := approver.ReviewResult(ctx, req, result)
+			if err != nil {
+				return nil, err
+			}
+			if approved == nil {
+				return nil, &mcp.Error{Code: userRejectedCode, Message: "user rejected sampling result"}
+			}
+			result = approved
+		}
+		return result, nil
+	}
+}
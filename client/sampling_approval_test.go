@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/internal/testutil"
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func echoSampling(_ context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	return &mcp.CreateMessageResult{
+		Role:    "assistant",
+		Content: mcp.SamplingContent{Type: "text", Text: "echo: " + req.Messages[0].Content.Text},
+		Model:   "test-model",
+	}, nil
+}
+
+func samplingParams(text string) json.RawMessage {
+	params, _ := json.Marshal(mcp.CreateMessageRequest{
+		Messages: []mcp.SamplingMessage{{Role: "user", Content: mcp.SamplingContent{Type: "text", Text: text}}},
+	})
+	return params
+}
+
+func TestApproveSampling_EditsRequestAndResult(t *testing.T) {
+	var reviewedResult string
+	c := New(testutil.NewMockTransport(), WithSamplingHandler(ApproveSampling(echoSampling, SamplingApprover{
+		ReviewRequest: func(_ context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageRequest, error) {
+			edited := *req
+			edited.Messages = []mcp.SamplingMessage{{Role: "user", Content: mcp.SamplingContent{Type: "text", Text: "redacted"}}}
+			return &edited, nil
+		},
+		ReviewResult: func(_ context.Context, req *mcp.CreateMessageRequest, result *mcp.CreateMessageResult) (*mcp.CreateMessageResult, error) {
+			if req.Messages[0].Content.Text != "redacted" {
+				t.Errorf("expected the edited request, got %q", req.Messages[0].Content.Text)
+			}
+			reviewedResult = result.Content.Text
+			edited := *result
+			edited.Content.Text = "approved"
+			return &edited, nil
+		},
+	})))
+
+	result, err := c.handleSamplingRequest(context.Background(), samplingParams("my password is hunter2"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reviewedResult != "echo: redacted" {
+		t.Errorf("expected the LLM to see the edited request, got %q", reviewedResult)
+	}
+	if result.Content.Text != "approved" {
+		t.Errorf("expected the edited result, got %q", result.Content.Text)
+	}
+}
+
+func TestApproveSampling_Denied(t *testing.T) {
+	called := false
+	handler := func(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+		called = true
+		return echoSampling(ctx, req)
+	}
+	deny := SamplingApprover{
+		ReviewRequest: func(context.Context, *mcp.CreateMessageRequest) (*mcp.CreateMessageRequest, error) {
+			return nil, nil
+		},
+	}
+
+	_, err := ApproveSampling(handler, deny)(context.Background(), &mcp.CreateMessageRequest{})
+	var mcpErr *mcp.Error
+	if !errors.As(err, &mcpErr) || mcpErr.Code != userRejectedCode {
+		t.Fatalf("expected user rejected error, got %v", err)
+	}
+	if called {
+		t.Error("expected the LLM not to be called for a denied request")
+	}
+
+	denyResult := SamplingApprover{
+		ReviewResult: func(context.Context, *mcp.CreateMessageRequest, *mcp.CreateMessageResult) (*mcp.CreateMessageResult, error) {
+			return nil, nil
+		},
+	}
+	req := &mcp.CreateMessageRequest{Messages: []mcp.SamplingMessage{{Role: "user", Content: mcp.SamplingContent{Type: "text", Text: "hi"}}}}
+	_, err = ApproveSampling(handler, denyResult)(context.Background(), req)
+	if !errors.As(err, &mcpErr) || mcpErr.Code != userRejectedCode || mcpErr.Message != "user rejected sampling result" {
+		t.Fatalf("expected rejected result error, got %v", err)
+	}
+}
+
+func TestApproveSampling_ReviewError(t *testing.T) {
+	failure := errors.New("approval UI closed")
+	approver := SamplingApprover{
+		ReviewRequest: func(context.Context, *mcp.CreateMessageRequest) (*mcp.CreateMessageRequest, error) {
+			return nil, failure
+		},
+	}
+
+	_, err := ApproveSampling(echoSampling, approver)(context.Background(), &mcp.CreateMessageRequest{})
+	if !errors.Is(err, failure) {
+		t.Fatalf("expected review error, got %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/internal/jsonrpc"
+	"github.com/jmcarbo/fullmcp/internal/testutil"
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func TestClient_SamplingIncludeContext(t *testing.T) {
+	clientTransport, serverTransport := testutil.NewPipeTransport()
+	defer serverTransport.Close()
+
+	received := make(chan []SamplingResource, 1)
+	c := New(clientTransport, WithSamplingHandler(func(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+		received <- IncludedContext(ctx)
+		return &mcp.CreateMessageResult{
+			Role:    "assistant",
+			Content: mcp.SamplingContent{Type: "text", Text: "ok"},
+			Model:   "test-model",
+		}, nil
+	}))
+	go c.handleMessages()
+
+	reader := jsonrpc.NewMessageReader(serverTransport)
+	writer := jsonrpc.NewMessageWriter(serverTransport)
+
+	params, _ := json.Marshal(mcp.CreateMessageRequest{
+		Messages:       []mcp.SamplingMessage{{Role: "user", Content: mcp.SamplingContent{Type: "text", Text: "hi"}}},
+		IncludeContext: mcp.IncludeContextThisServer,
+	})
+	_ = writer.Write(&mcp.Message{JSONRPC: "2.0", ID: "s1", Method: "sampling/createMessage", Params: params})
+
+	// Serve the client's context-gathering requests until the sampling response arrives
+	var resp *mcp.Message
+	for resp == nil {
+		msg, err := reader.Read()
+		if err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+
+		switch msg.Method {
+		case "resources/list":
+			_ = writer.Write(&mcp.Message{JSONRPC: "2.0", ID: msg.ID, Result: json.RawMessage(
+				`{"resources":[{"uri":"file:///notes.txt","name":"notes","mimeType":"text/plain"}]}`)})
+		case "resources/read":
+			_ = writer.Write(&mcp.Message{JSONRPC: "2.0", ID: msg.ID, Result: json.RawMessage(
+				`{"contents":[{"uri":"file:///notes.txt","mimeType":"text/plain","text":"remember the milk"}]}`)})
+		case "":
+			resp = msg
+		}
+	}
+
+	if resp.Error != nil {
+		t.Fatalf("unexpected error response: %v", resp.Error.Message)
+	}
+
+	select {
+	case resources := <-received:
+		if len(resources) != 1 {
+			t.Fatalf("expected 1 included resource, got %d", len(resources))
+		}
+		if resources[0].Text != "remember the milk" {
+			t.Errorf("unexpected resource text: %q", resources[0].Text)
+		}
+		if resources[0].MimeType != "text/plain" {
+			t.Errorf("unexpected mime type: %q", resources[0].MimeType)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("sampling handler was not called")
+	}
+}
+
+func TestClient_SamplingWithoutIncludeContext(t *testing.T) {
+	c := New(testutil.NewMockTransport(), WithSamplingHandler(func(ctx context.Context, _ *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+		if IncludedContext(ctx) != nil {
+			t.Error("expected no included context")
+		}
+		return &mcp.CreateMessageResult{Role: "assistant"}, nil
+	}))
+
+	params, _ := json.Marshal(mcp.CreateMessageRequest{IncludeContext: mcp.IncludeContextNone})
+	if _, err := c.handleSamplingRequest(context.Background(), params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestClient_SamplingNotSupported(t *testing.T) {
+	c := New(testutil.NewMockTransport())
+
+	_, err := c.handleSamplingRequest(context.Background(), json.RawMessage(`{}`))
+	mcpErr, ok := err.(*mcp.Error)
+	if !ok || mcpErr.Code != mcp.MethodNotFound {
+		t.Fatalf("expected MethodNotFound error, got %v", err)
+	}
+}
+
+func TestClient_SamplingCancelled(t *testing.T) {
+	clientTransport, serverTransport := testutil.NewPipeTransport()
+	defer serverTransport.Close()
+
+	started := make(chan struct{})
+	c := New(clientTransport, WithSamplingHandler(func(ctx context.Context, _ *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}))
+	go c.handleMessages()
+
+	reader := jsonrpc.NewMessageReader(serverTransport)
+	writer := jsonrpc.NewMessageWriter(serverTransport)
+
+	params, _ := json.Marshal(mcp.CreateMessageRequest{})
+	_ = writer.Write(&mcp.Message{JSONRPC: "2.0", ID: "s1", Method: "sampling/createMessage", Params: params})
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("sampling handler was not called")
+	}
+
+	cancelled, _ := json.Marshal(mcp.CancelledNotification{RequestID: "s1", Reason: "no longer needed"})
+	_ = writer.Write(&mcp.Message{JSONRPC: "2.0", Method: "notifications/cancelled", Params: cancelled})
+
+	resp, err := reader.Read()
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if resp.Error == nil {
+		t.Fatalf("expected the cancelled request to fail, got %s", resp.Result)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		c.mu.Lock()
+		pending := len(c.serverRequests)
+		c.mu.Unlock()
+		if pending == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected finished requests to be released, got %d", pending)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestClient_SamplingCancelledOnClose(t *testing.T) {
+	c := New(testutil.NewMockTransport())
+
+	ctx, done := c.serverRequestContext(int64(1))
+	defer done()
+
+	_ = c.Close()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected closing the client to cancel server requests")
+	}
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+)
+
+// ResourceUpdatedHandler is called when a subscribed resource changes
+type ResourceUpdatedHandler func(ctx context.Context, uri string)
+
+// WithResourceUpdatedHandler configures a handler for
+// notifications/resources/updated
+func WithResourceUpdatedHandler(handler ResourceUpdatedHandler) Option {
+	return func(c *Client) {
+		c.resourceUpdatedHandler = handler
+	}
+}
+
+// SubscribeResource asks the server to send notifications/resources/updated
+// when the resource at uri changes
+func (c *Client) SubscribeResource(ctx context.Context, uri string) error {
+	if err := c.call(ctx, "resources/subscribe", map[string]string{"uri": uri}, nil); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.subscriptions[uri] = struct{}{}
+	c.mu.Unlock()
+
+	return nil
+}
+
+// UnsubscribeResource cancels a previous resource subscription
+func (c *Client) UnsubscribeResource(ctx context.Context, uri string) error {
+	if err := c.call(ctx, "resources/unsubscribe", map[string]string{"uri": uri}, nil); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	delete(c.subscriptions, uri)
+	c.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func TestClient_ResourceUpdatedHandler(t *testing.T) {
+	updated := make(chan string, 1)
+	c, fs := connectWithResponder(t, nil, WithResourceUpdatedHandler(func(_ context.Context, uri string) {
+		updated <- uri
+	}))
+
+	if err := c.SubscribeResource(context.Background(), "file:///config.json"); err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+
+	fs.notify("notifications/resources/updated", mcp.ResourceUpdatedNotification{URI: "file:///config.json"})
+
+	select {
+	case uri := <-updated:
+		if uri != "file:///config.json" {
+			t.Errorf("unexpected uri: %s", uri)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler was not called")
+	}
+}
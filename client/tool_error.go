@@ -0,0 +1,14 @@
+package client
+
+import "github.com/jmcarbo/fullmcp/mcp"
+
+// ToolError is returned when a tool reports a failure through a result with
+// isError set. The server answered normally; the tool itself failed.
+type ToolError struct {
+	Message string              // Text of the first text content item
+	Result  *mcp.CallToolResult // The full result, when it could be decoded
+}
+
+func (e *ToolError) Error() string {
+	return "tool returned error: " + e.Message
+}
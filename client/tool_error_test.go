@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func isErrorResponder(msg *mcp.Message) *mcp.Message {
+	if msg.Method != "tools/call" {
+		return nil
+	}
+	return &mcp.Message{
+		JSONRPC: "2.0",
+		ID:      msg.ID,
+		Result:  json.RawMessage(`{"content":[{"type":"text","text":"quota exceeded"}],"structuredContent":{"remaining":0},"isError":true}`),
+	}
+}
+
+func TestClient_CallToolIsError(t *testing.T) {
+	c, _ := connectWithResponder(t, isErrorResponder)
+
+	_, err := c.CallTool(context.Background(), "quota", nil)
+	var toolErr *ToolError
+	if !errors.As(err, &toolErr) {
+		t.Fatalf("expected ToolError, got %v", err)
+	}
+	if toolErr.Message != "quota exceeded" {
+		t.Errorf("unexpected message %q", toolErr.Message)
+	}
+	if toolErr.Result == nil || !toolErr.Result.IsError {
+		t.Errorf("expected the full result, got %+v", toolErr.Result)
+	}
+}
+
+func TestClient_CallToolResult(t *testing.T) {
+	c, _ := connectWithResponder(t, isErrorResponder)
+
+	result, err := c.CallToolResult(context.Background(), "quota", nil)
+	if err != nil {
+		t.Fatalf("expected tool failure in the result, got error %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected IsError to be set")
+	}
+	if len(result.Content) != 1 || result.Content[0].(mcp.TextContent).Text != "quota exceeded" {
+		t.Errorf("unexpected content: %+v", result.Content)
+	}
+	if structured, _ := result.StructuredContent.(map[string]interface{}); structured["remaining"] != float64(0) {
+		t.Errorf("unexpected structured content: %v", result.StructuredContent)
+	}
+}
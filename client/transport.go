@@ -0,0 +1,36 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/jmcarbo/fullmcp/transport"
+)
+
+// ConnectTransport opens a connection with t and performs the initialize
+// handshake. Closing the client also closes the transport.
+func ConnectTransport(ctx context.Context, t transport.Transport, opts ...Option) (*Client, error) {
+	conn, err := t.Connect(ctx)
+	if err != nil {
+		_ = t.Close()
+		return nil, err
+	}
+
+	c := New(&transportConn{ReadWriteCloser: conn, transport: t}, opts...)
+	if err := c.Connect(ctx); err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// transportConn closes the transport along with its connection
+type transportConn struct {
+	io.ReadWriteCloser
+	transport transport.Transport
+}
+
+func (tc *transportConn) Close() error {
+	return errors.Join(tc.ReadWriteCloser.Close(), tc.transport.Close())
+}
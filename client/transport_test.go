@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/internal/testutil"
+)
+
+// pipeTransport hands out one end of an in-memory pipe
+type pipeTransport struct {
+	conn   io.ReadWriteCloser
+	closed atomic.Bool
+}
+
+func (p *pipeTransport) Connect(context.Context) (io.ReadWriteCloser, error) { return p.conn, nil }
+
+func (p *pipeTransport) Close() error {
+	p.closed.Store(true)
+	return nil
+}
+
+func TestConnectTransport(t *testing.T) {
+	clientSide, serverSide := testutil.NewPipeTransport()
+	startFakeServer(serverSide)
+	defer func() { _ = serverSide.Close() }()
+
+	tr := &pipeTransport{conn: clientSide}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	c, err := ConnectTransport(ctx, tr)
+	if err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+	if _, err := c.ListTools(ctx); err != nil {
+		t.Fatalf("list tools failed: %v", err)
+	}
+
+	_ = c.Close()
+	if !tr.closed.Load() {
+		t.Error("expected closing the client to close the transport")
+	}
+}
@@ -0,0 +1,180 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// toolCallResult is the wire format of a tools/call result
+type toolCallResult struct {
+	Content           []json.RawMessage `json:"content"`
+	StructuredContent json.RawMessage   `json:"structuredContent,omitempty"`
+	IsError           bool              `json:"isError,omitempty"`
+}
+
+// CallToolAs calls a tool with a typed input and decodes its result into Out.
+// The input is validated against the tool's InputSchema when it is known from
+// a previous ListTools. The result is decoded from structuredContent when
+// present, otherwise from the JSON text of the first text content item.
+func CallToolAs[In, Out any](ctx context.Context, c *Client, name string, input In, opts ...CallOption) (Out, error) {
+	var out Out
+
+	args, err := json.Marshal(input)
+	if err != nil {
+		return out, fmt.Errorf("failed to marshal tool input: %w", err)
+	}
+
+	if schema := c.toolSchema(name); schema != nil {
+		if err := validateToolInput(name, args, schema); err != nil {
+			return out, err
+		}
+	}
+
+	params := map[string]interface{}{
+		"name":      name,
+		"arguments": json.RawMessage(args),
+	}
+
+	var result toolCallResult
+	if err := c.callWithRetry(ctx, "tools/call", params, &result, c.isIdempotentTool(name), opts); err != nil {
+		return out, err
+	}
+
+	if err := decodeToolResult(&result, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// toolSchema returns the cached input schema for a tool
+func (c *Client) toolSchema(name string) map[string]interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if tool, ok := c.toolHints[name]; ok {
+		return tool.InputSchema
+	}
+	return nil
+}
+
+// validateToolInput validates JSON arguments against a tool input schema,
+// naming the tool and the first field that violates a constraint
+func validateToolInput(name string, args json.RawMessage, schema map[string]interface{}) error {
+	schemaJSON, err := json.Marshal(schema)
+	if err != nil {
+		return &mcp.ValidationError{Field: "inputSchema", Message: fmt.Sprintf("invalid schema of tool %s: %v", name, err)}
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schemaJSON), gojsonschema.NewBytesLoader(args))
+	if err != nil {
+		return &mcp.ValidationError{Field: "arguments", Message: fmt.Sprintf("validation error: %v", err)}
+	}
+
+	if !result.Valid() {
+		errs := result.Errors()
+		errMsg := "invalid arguments for tool " + name + ": "
+		for i, desc := range errs {
+			if i > 0 {
+				errMsg += "; "
+			}
+			errMsg += desc.String()
+		}
+
+		field := "arguments"
+		if f := errs[0].Field(); f != gojsonschema.STRING_ROOT_SCHEMA_PROPERTY {
+			field = f
+		}
+		return &mcp.ValidationError{Field: field, Message: errMsg}
+	}
+
+	return nil
+}
+
+// ValidateToolOutput checks a result's structuredContent against a tool's
+// output schema, returning one error per violation. Missing structured
+// content is a violation, since tools that declare an output schema must
+// return it.
+func ValidateToolOutput(result *mcp.CallToolResult, schema map[string]interface{}) []*mcp.ValidationError {
+	if result.StructuredContent == nil {
+		return []*mcp.ValidationError{{Field: "structuredContent", Message: "is required by the output schema"}}
+	}
+
+	schemaJSON, err := json.Marshal(schema)
+	if err != nil {
+		return []*mcp.ValidationError{{Field: "outputSchema", Message: fmt.Sprintf("invalid schema: %v", err)}}
+	}
+
+	validation, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schemaJSON), gojsonschema.NewGoLoader(result.StructuredContent))
+	if err != nil {
+		return []*mcp.ValidationError{{Field: "outputSchema", Message: fmt.Sprintf("validation error: %v", err)}}
+	}
+
+	var errs []*mcp.ValidationError
+	for _, desc := range validation.Errors() {
+		field := "structuredContent"
+		if f := desc.Field(); f != gojsonschema.STRING_ROOT_SCHEMA_PROPERTY {
+			field += "." + f
+		}
+		errs = append(errs, &mcp.ValidationError{Field: field, Message: desc.Description()})
+	}
+	return errs
+}
+
+// decodeToolResult decodes structured or text tool output into out
+func decodeToolResult(result *toolCallResult, out interface{}) error {
+	text, hasText := firstText(result.Content)
+
+	if result.IsError {
+		return result.toolError()
+	}
+
+	if len(result.StructuredContent) > 0 && string(result.StructuredContent) != "null" {
+		if err := json.Unmarshal(result.StructuredContent, out); err != nil {
+			return fmt.Errorf("failed to decode structured content: %w", err)
+		}
+		return nil
+	}
+
+	if !hasText {
+		return fmt.Errorf("tool result has no structured or text content")
+	}
+
+	if err := json.Unmarshal([]byte(text), out); err != nil {
+		// Plain text results decode directly into string outputs
+		if s, ok := out.(*string); ok {
+			*s = text
+			return nil
+		}
+		return fmt.Errorf("failed to decode text content: %w", err)
+	}
+	return nil
+}
+
+// toolError describes a result with isError set
+func (r *toolCallResult) toolError() *ToolError {
+	text, _ := firstText(r.Content)
+	toolErr := &ToolError{Message: text}
+
+	if data, err := json.Marshal(r); err == nil {
+		var full mcp.CallToolResult
+		if json.Unmarshal(data, &full) == nil {
+			toolErr.Result = &full
+		}
+	}
+	return toolErr
+}
+
+// firstText returns the text of the first text content item
+func firstText(content []json.RawMessage) (string, bool) {
+	for _, raw := range content {
+		var item mcp.TextContent
+		if err := json.Unmarshal(raw, &item); err == nil && item.Type == "text" {
+			return item.Text, true
+		}
+	}
+	return "", false
+}
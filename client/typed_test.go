@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+type addInput struct {
+	A int `json:"a"`
+	B int `json:"b"`
+}
+
+type addOutput struct {
+	Sum int `json:"sum"`
+}
+
+// typedResponder serves an "add" tool whose result is returned as structured
+// content and a "text_add" tool whose result is JSON text
+func typedResponder(msg *mcp.Message) *mcp.Message {
+	switch msg.Method {
+	case "tools/list":
+		return &mcp.Message{JSONRPC: "2.0", ID: msg.ID, Result: json.RawMessage(
+			`{"tools":[{"name":"add","inputSchema":{"type":"object","properties":{"a":{"type":"integer"},"b":{"type":"integer"}},"required":["a","b"]}}]}`)}
+	case "tools/call":
+		var params struct {
+			Name      string   `json:"name"`
+			Arguments addInput `json:"arguments"`
+		}
+		_ = json.Unmarshal(msg.Params, &params)
+		sum := params.Arguments.A + params.Arguments.B
+
+		var result json.RawMessage
+		switch params.Name {
+		case "add":
+			result, _ = json.Marshal(map[string]interface{}{
+				"content":           []map[string]string{{"type": "text", "text": "ignored"}},
+				"structuredContent": addOutput{Sum: sum},
+			})
+		case "text_add":
+			text, _ := json.Marshal(addOutput{Sum: sum})
+			result, _ = json.Marshal(map[string]interface{}{
+				"content": []map[string]string{{"type": "text", "text": string(text)}},
+			})
+		case "greet":
+			result = json.RawMessage(`{"content":[{"type":"text","text":"hello"}]}`)
+		case "fail":
+			result = json.RawMessage(`{"content":[{"type":"text","text":"boom"}],"isError":true}`)
+		}
+		return &mcp.Message{JSONRPC: "2.0", ID: msg.ID, Result: result}
+	}
+	return nil
+}
+
+func TestCallToolAs_StructuredContent(t *testing.T) {
+	c, _ := connectWithResponder(t, typedResponder)
+
+	out, err := CallToolAs[addInput, addOutput](context.Background(), c, "add", addInput{A: 2, B: 3})
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if out.Sum != 5 {
+		t.Errorf("expected sum 5, got %d", out.Sum)
+	}
+}
+
+func TestCallToolAs_TextContent(t *testing.T) {
+	c, _ := connectWithResponder(t, typedResponder)
+	ctx := context.Background()
+
+	out, err := CallToolAs[addInput, addOutput](ctx, c, "text_add", addInput{A: 4, B: 1})
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if out.Sum != 5 {
+		t.Errorf("expected sum 5, got %d", out.Sum)
+	}
+
+	greeting, err := CallToolAs[struct{}, string](ctx, c, "greet", struct{}{})
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if greeting != "hello" {
+		t.Errorf("expected plain text result, got %q", greeting)
+	}
+}
+
+func TestCallToolAs_ValidatesCachedSchema(t *testing.T) {
+	c, fs := connectWithResponder(t, typedResponder)
+	ctx := context.Background()
+
+	if _, err := c.ListTools(ctx); err != nil {
+		t.Fatalf("list tools failed: %v", err)
+	}
+
+	_, err := CallToolAs[map[string]string, addOutput](ctx, c, "add", map[string]string{"a": "two"})
+	if _, ok := err.(*mcp.ValidationError); !ok {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if got := fs.count("tools/call"); got != 0 {
+		t.Errorf("expected invalid input not to be sent, got %d calls", got)
+	}
+}
+
+func TestCallToolAs_ToolError(t *testing.T) {
+	c, _ := connectWithResponder(t, typedResponder)
+
+	if _, err := CallToolAs[struct{}, addOutput](context.Background(), c, "fail", struct{}{}); err == nil {
+		t.Fatal("expected tool error")
+	}
+}
+
+func TestValidateToolOutput(t *testing.T) {
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"sum": map[string]interface{}{"type": "integer"}},
+		"required":   []string{"sum"},
+	}
+
+	valid := &mcp.CallToolResult{StructuredContent: map[string]interface{}{"sum": 3}}
+	if errs := ValidateToolOutput(valid, schema); len(errs) != 0 {
+		t.Errorf("expected valid output, got %v", errs)
+	}
+
+	wrongType := &mcp.CallToolResult{StructuredContent: map[string]interface{}{"sum": "three"}}
+	errs := ValidateToolOutput(wrongType, schema)
+	if len(errs) != 1 || errs[0].Field != "structuredContent.sum" {
+		t.Errorf("expected one error on structuredContent.sum, got %v", errs)
+	}
+
+	missing := &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Type: "text", Text: "3"}}}
+	errs = ValidateToolOutput(missing, schema)
+	if len(errs) != 1 || errs[0].Field != "structuredContent" {
+		t.Errorf("expected missing structured content to be reported, got %v", errs)
+	}
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// WithArgumentValidation checks the arguments of CallTool, CallToolResult
+// and CallToolWithProgress against the tool's InputSchema from the most
+// recent ListTools before sending the call. Invalid arguments fail with an
+// *mcp.ValidationError naming the offending field, without a round trip.
+// Tools not seen in a ListTools are sent unchecked.
+func WithArgumentValidation() Option {
+	return func(c *Client) {
+		c.validateArgs = true
+	}
+}
+
+// ValidateArguments checks args against the InputSchema of the named tool
+// from the most recent ListTools. Tools not seen in a ListTools are not
+// checked.
+func (c *Client) ValidateArguments(name string, args interface{}) error {
+	schema := c.toolSchema(name)
+	if schema == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tool arguments: %w", err)
+	}
+	if string(data) == "null" {
+		data = []byte("{}")
+	}
+	return validateToolInput(name, data, schema)
+}
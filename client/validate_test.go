@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// addResponder lists an "add" tool requiring integer a and b and answers
+// every call to it
+func addResponder(msg *mcp.Message) *mcp.Message {
+	switch msg.Method {
+	case "tools/list":
+		return &mcp.Message{JSONRPC: "2.0", ID: msg.ID, Result: json.RawMessage(`{"tools":[{"name":"add","inputSchema":{
+			"type":"object",
+			"properties":{"a":{"type":"integer"},"b":{"type":"integer"}},
+			"required":["a","b"]}}]}`)}
+	case "tools/call":
+		return &mcp.Message{JSONRPC: "2.0", ID: msg.ID, Result: json.RawMessage(`{"content":[{"type":"text","text":"3"}]}`)}
+	}
+	return nil
+}
+
+func TestClient_ArgumentValidation(t *testing.T) {
+	c, fs := connectWithResponder(t, addResponder, WithArgumentValidation())
+	ctx := context.Background()
+
+	// Without a ListTools the schema is unknown, so the call is sent
+	if _, err := c.CallTool(ctx, "add", map[string]interface{}{"a": 1}); err != nil {
+		t.Fatalf("expected an unchecked call to be sent, got %v", err)
+	}
+
+	if _, err := c.ListTools(ctx); err != nil {
+		t.Fatal(err)
+	}
+	_, err := c.CallTool(ctx, "add", map[string]interface{}{"a": 1, "b": "two"})
+	var validationErr *mcp.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if validationErr.Field != "b" || !strings.Contains(validationErr.Message, "tool add") {
+		t.Errorf("expected the error to name the tool and field, got %v", validationErr)
+	}
+
+	if _, err := c.CallToolResult(ctx, "add", json.RawMessage(`{}`)); err == nil {
+		t.Error("expected missing arguments to fail")
+	}
+	if got := fs.count("tools/call"); got != 1 {
+		t.Errorf("expected invalid calls not to be sent, got %d calls", got)
+	}
+
+	if _, err := c.CallTool(ctx, "add", map[string]interface{}{"a": 1, "b": 2}); err != nil {
+		t.Errorf("expected valid arguments to be sent, got %v", err)
+	}
+}
+
+func TestClient_ValidateArgumentsWithoutOption(t *testing.T) {
+	c, fs := connectWithResponder(t, addResponder)
+	ctx := context.Background()
+	if _, err := c.ListTools(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.ValidateArguments("add", nil); err == nil {
+		t.Error("expected nil arguments to miss the required fields")
+	}
+	if err := c.ValidateArguments("unknown", nil); err != nil {
+		t.Errorf("expected an unknown tool to be unchecked, got %v", err)
+	}
+
+	// CallTool leaves validation to the server unless the option is set
+	if _, err := c.CallTool(ctx, "add", map[string]interface{}{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := fs.count("tools/call"); got != 1 {
+		t.Errorf("expected the call to be sent, got %d calls", got)
+	}
+}
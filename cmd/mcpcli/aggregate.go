@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/client"
+	"github.com/jmcarbo/fullmcp/mcp"
+	"github.com/jmcarbo/fullmcp/server"
+	"github.com/jmcarbo/fullmcp/server/proxy"
+	"github.com/jmcarbo/fullmcp/transport/streamhttp"
+	"github.com/spf13/cobra"
+)
+
+func aggregateCmd() *cobra.Command {
+	var profiles []string
+	var listen string
+	var name string
+
+	cmd := &cobra.Command{
+		Use:   "aggregate --profile <name> --profile <name> [--listen <addr>]",
+		Short: "Serve several profiles as one namespaced MCP server",
+		Long: `Connects to each profile and serves their tools, prompts and resources as a
+single MCP server, for clients that can only configure one. Tools and prompts
+are exposed as "<profile>_<name>" and resources as "<profile>/<uri>".
+
+With --listen the server accepts Streamable HTTP requests on that address;
+otherwise it serves over stdio, so it can itself be configured as a command.
+Status messages go to stderr.`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if len(profiles) == 0 {
+				return fmt.Errorf("at least one --profile is required")
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			var upstreams []proxy.Upstream
+			defer func() {
+				for _, upstream := range upstreams {
+					_ = upstream.Client.Close()
+				}
+			}()
+			for _, profile := range profiles {
+				c, err := connectProfile(ctx, profile)
+				if err != nil {
+					return fmt.Errorf("profile %s: %w", profile, err)
+				}
+				upstreams = append(upstreams, proxy.Upstream{Namespace: profile, Client: c})
+				fmt.Fprintf(os.Stderr, "✓ Connected to %s\n", profile)
+			}
+
+			srv, err := proxy.Aggregate(name, upstreams)
+			if err != nil {
+				return err
+			}
+
+			if listen == "" {
+				return srv.Run(ctx)
+			}
+			return serveHTTP(ctx, srv, listen)
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&profiles, "profile", "p", nil, "Profile to include; repeat for each server")
+	cmd.Flags().StringVar(&listen, "listen", "", "Address to serve Streamable HTTP on (e.g. :9000); stdio when empty")
+	cmd.Flags().StringVar(&name, "name", "mcpcli-aggregate", "Server name reported to clients")
+	return cmd
+}
+
+// connectProfile connects to a named profile within the request timeout
+func connectProfile(ctx context.Context, name string) (*client.Client, error) {
+	t, err := openProfile(name)
+	if err != nil {
+		return nil, err
+	}
+	connectCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+	return client.ConnectTransport(connectCtx, t)
+}
+
+// serveHTTP serves srv over Streamable HTTP on addr until ctx is cancelled
+func serveHTTP(ctx context.Context, srv *server.Server, addr string) error {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "failed to read request", http.StatusBadRequest)
+			return
+		}
+
+		var msg mcp.Message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			http.Error(w, "invalid JSON-RPC message", http.StatusBadRequest)
+			return
+		}
+
+		response := srv.HandleMessage(r.Context(), &msg)
+		if response == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	})
+
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           streamhttp.NewServer(addr, handler),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(os.Stderr, "Serving on %s\n", addr)
+	if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
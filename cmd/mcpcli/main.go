@@ -7,13 +7,23 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jmcarbo/fullmcp/client"
 	"github.com/jmcarbo/fullmcp/mcp"
-	"github.com/jmcarbo/fullmcp/transport/http"
+	"github.com/jmcarbo/fullmcp/transport"
+	_ "github.com/jmcarbo/fullmcp/transport/http" // Registers http and https
+	_ "github.com/jmcarbo/fullmcp/transport/sse"  // Registers http+sse and https+sse
 	"github.com/jmcarbo/fullmcp/transport/stdio"
-	"github.com/jmcarbo/fullmcp/transport/streamhttp"
+	_ "github.com/jmcarbo/fullmcp/transport/streamhttp" // Registers http+stream and https+stream
+	_ "github.com/jmcarbo/fullmcp/transport/tcp"        // Registers tcp
+	_ "github.com/jmcarbo/fullmcp/transport/unix"       // Registers unix
+	_ "github.com/jmcarbo/fullmcp/transport/websocket"  // Registers ws and wss
 	"github.com/spf13/cobra"
 )
 
@@ -24,30 +34,41 @@ var (
 	url           string
 	useStreamHTTP bool
 	apiKey        string
+	profileName   string
+	profilesPath  string
 )
 
-// createTransport creates the appropriate transport based on the URL flag
+// createTransport creates the transport for the selected profile or the
+// transport registered for the URL's scheme, falling back to stdio when
+// neither is given
 func createTransport() (io.ReadWriteCloser, error) {
-	if url != "" {
-		if useStreamHTTP {
-			// Use streamhttp transport (HTTP+SSE)
-			opts := []streamhttp.Option{}
-			if apiKey != "" {
-				opts = append(opts, streamhttp.WithAPIKey(apiKey))
-			}
-			transport := streamhttp.New(url, opts...)
-			return transport.Connect(context.Background())
+	if profileName != "" {
+		if url != "" {
+			return nil, fmt.Errorf("--profile and --url cannot be combined")
 		}
-		// Use basic HTTP transport
-		opts := []http.Option{}
-		if apiKey != "" {
-			opts = append(opts, http.WithAPIKey(apiKey))
+		t, err := openProfile(profileName)
+		if err != nil {
+			return nil, err
 		}
-		transport := http.New(url, opts...)
-		return transport.Connect(context.Background())
+		return t.Connect(context.Background())
 	}
-	// Use stdio transport
-	return stdio.New(), nil
+	if url == "" {
+		return stdio.New(), nil
+	}
+
+	target := url
+	if useStreamHTTP {
+		// --stream selects the streamhttp transport (HTTP+SSE) for http URLs
+		if scheme, rest, ok := strings.Cut(target, "://"); ok && (scheme == "http" || scheme == "https") {
+			target = scheme + "+stream://" + rest
+		}
+	}
+
+	t, err := transport.Open(target, transport.Config{APIKey: apiKey})
+	if err != nil {
+		return nil, err
+	}
+	return t.Connect(context.Background())
 }
 
 func main() {
@@ -61,19 +82,25 @@ It supports testing connections, listing capabilities, and invoking tools.`,
 
 	rootCmd.PersistentFlags().IntVarP(&timeout, "timeout", "t", 30, "Request timeout in seconds")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output")
-	rootCmd.PersistentFlags().StringVarP(&url, "url", "u", "", "MCP server URL (use HTTP transport instead of stdio)")
+	rootCmd.PersistentFlags().StringVarP(&url, "url", "u", "", "MCP server URL; the scheme selects the transport (http, https, ws, wss, http+stream, http+sse, unix, stdio)")
 	rootCmd.PersistentFlags().BoolVar(&useStreamHTTP, "stream", false, "Use streamhttp transport (HTTP+SSE) instead of basic HTTP")
 	rootCmd.PersistentFlags().StringVarP(&apiKey, "api-key", "k", "", "API key for authentication (sent as X-API-Key header)")
+	rootCmd.PersistentFlags().StringVarP(&profileName, "profile", "p", "", "Connect using a saved profile (see import)")
+	rootCmd.PersistentFlags().StringVar(&profilesPath, "profiles-file", defaultProfilesPath(), "Profiles file")
 
 	// Add commands
 	rootCmd.AddCommand(pingCmd())
 	rootCmd.AddCommand(listToolsCmd())
 	rootCmd.AddCommand(listResourcesCmd())
+	rootCmd.AddCommand(listTemplatesCmd())
 	rootCmd.AddCommand(listPromptsCmd())
 	rootCmd.AddCommand(callToolCmd())
 	rootCmd.AddCommand(readResourceCmd())
 	rootCmd.AddCommand(getPromptCmd())
 	rootCmd.AddCommand(infoCmd())
+	rootCmd.AddCommand(setLogLevelCmd())
+	rootCmd.AddCommand(importCmd())
+	rootCmd.AddCommand(aggregateCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -82,10 +109,15 @@ It supports testing connections, listing capabilities, and invoking tools.`,
 }
 
 func pingCmd() *cobra.Command {
-	return &cobra.Command{
+	var count int
+	var interval time.Duration
+
+	cmd := &cobra.Command{
 		Use:   "ping",
 		Short: "Test connection to an MCP server",
-		Long:  `Establishes a connection to an MCP server and verifies it responds.`,
+		Long: `Establishes a connection to an MCP server and sends ping requests,
+reporting the round-trip time of each and a latency summary. A count of 0
+pings until interrupted.`,
 		RunE: func(_ *cobra.Command, _ []string) error {
 			transport, err := createTransport()
 			if err != nil {
@@ -93,18 +125,133 @@ func pingCmd() *cobra.Command {
 			}
 			c := client.New(transport)
 
-			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			connectCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
 			defer cancel()
 
-			if err := c.Connect(ctx); err != nil {
+			if err := c.Connect(connectCtx); err != nil {
 				return fmt.Errorf("failed to connect: %w", err)
 			}
 			defer func() { _ = c.Close() }()
 
 			fmt.Println("✓ Successfully connected to MCP server")
+
+			var stats latencyStats
+			for seq := 1; count == 0 || seq <= count; seq++ {
+				if seq > 1 {
+					select {
+					case <-ctx.Done():
+					case <-time.After(interval):
+					}
+				}
+				if ctx.Err() != nil {
+					break
+				}
+
+				pingCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+				start := time.Now()
+				err := c.Ping(pingCtx)
+				rtt := time.Since(start)
+				cancel()
+
+				if err != nil {
+					if ctx.Err() != nil {
+						break
+					}
+					stats.fail()
+					fmt.Printf("ping %d: error: %v\n", seq, err)
+					continue
+				}
+				stats.add(rtt)
+				fmt.Printf("ping %d: time=%s\n", seq, formatLatency(rtt))
+			}
+
+			stats.printPingSummary()
+			if stats.failed > 0 && len(stats.rtts) == 0 {
+				return fmt.Errorf("no ping responses received")
+			}
 			return nil
 		},
 	}
+
+	cmd.Flags().IntVarP(&count, "count", "c", 1, "Number of pings to send (0 pings until interrupted)")
+	cmd.Flags().DurationVarP(&interval, "interval", "i", time.Second, "Time between pings")
+	return cmd
+}
+
+// latencyStats collects the round-trip times and failures of repeated
+// requests. It is safe for concurrent use.
+type latencyStats struct {
+	mu     sync.Mutex
+	rtts   []time.Duration
+	failed int
+}
+
+func (s *latencyStats) add(rtt time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rtts = append(s.rtts, rtt)
+}
+
+func (s *latencyStats) fail() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failed++
+}
+
+// printPingSummary writes the loss and latency summary of a ping run
+func (s *latencyStats) printPingSummary() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sent := len(s.rtts) + s.failed
+	if sent == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("%d sent, %d received, %.1f%% loss\n", sent, len(s.rtts), float64(s.failed)*100/float64(sent))
+	s.printLatency()
+}
+
+// printCallSummary writes the success and latency summary of a load run
+func (s *latencyStats) printCallSummary(elapsed time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	total := len(s.rtts) + s.failed
+	fmt.Println()
+	fmt.Printf("%d calls, %d succeeded, %d failed in %s (%.1f calls/s)\n",
+		total, len(s.rtts), s.failed, elapsed.Round(time.Millisecond), float64(total)/elapsed.Seconds())
+	s.printLatency()
+}
+
+// printLatency writes the latency distribution of successful requests;
+// callers hold mu
+func (s *latencyStats) printLatency() {
+	if len(s.rtts) == 0 {
+		return
+	}
+
+	sorted := append([]time.Duration{}, s.rtts...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, rtt := range sorted {
+		total += rtt
+	}
+	avg := total / time.Duration(len(sorted))
+	p95 := sorted[(len(sorted)*95+99)/100-1]
+
+	fmt.Printf("min/avg/p95/max = %s/%s/%s/%s\n",
+		formatLatency(sorted[0]), formatLatency(avg), formatLatency(p95), formatLatency(sorted[len(sorted)-1]))
+}
+
+// formatLatency formats a duration in milliseconds
+func formatLatency(d time.Duration) string {
+	return fmt.Sprintf("%.3fms", float64(d)/float64(time.Millisecond))
 }
 
 func listToolsCmd() *cobra.Command {
@@ -148,6 +295,10 @@ func listToolsCmd() *cobra.Command {
 						schema, _ := json.MarshalIndent(tool.InputSchema, "    ", "  ")
 						fmt.Printf("    Schema: %s\n", string(schema))
 					}
+					if verbose && tool.OutputSchema != nil {
+						schema, _ := json.MarshalIndent(tool.OutputSchema, "    ", "  ")
+						fmt.Printf("    Output Schema: %s\n", string(schema))
+					}
 					fmt.Println()
 				}
 			}
@@ -215,6 +366,61 @@ func listResourcesCmd() *cobra.Command {
 	return cmd
 }
 
+func listTemplatesCmd() *cobra.Command {
+	var outputJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "list-templates",
+		Short: "List available resource templates",
+		Long:  `Retrieves and displays the URI templates of parameterized resources on the MCP server.`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			transport, err := createTransport()
+			if err != nil {
+				return fmt.Errorf("failed to create transport: %w", err)
+			}
+			c := client.New(transport)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+			defer cancel()
+
+			if err := c.Connect(ctx); err != nil {
+				return fmt.Errorf("failed to connect: %w", err)
+			}
+			defer func() { _ = c.Close() }()
+
+			templates, err := c.ListResourceTemplates(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to list resource templates: %w", err)
+			}
+
+			if outputJSON {
+				data, _ := json.MarshalIndent(templates, "", "  ")
+				fmt.Println(string(data))
+			} else {
+				fmt.Printf("Available Resource Templates (%d):\n\n", len(templates))
+				for _, template := range templates {
+					fmt.Printf("  • %s\n", template.URITemplate)
+					if template.Name != "" {
+						fmt.Printf("    Name: %s\n", template.Name)
+					}
+					if template.Description != "" {
+						fmt.Printf("    Description: %s\n", template.Description)
+					}
+					if template.MimeType != "" {
+						fmt.Printf("    MIME Type: %s\n", template.MimeType)
+					}
+					fmt.Println()
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+	return cmd
+}
+
 func displayPromptArguments(args []mcp.PromptArgument) {
 	if !verbose || len(args) == 0 {
 		return
@@ -287,6 +493,8 @@ func listPromptsCmd() *cobra.Command {
 func callToolCmd() *cobra.Command {
 	var argsJSON string
 	var outputJSON bool
+	var validateArgs, validateOutput bool
+	var parallel, repeat int
 
 	cmd := &cobra.Command{
 		Use:   "call-tool <tool-name>",
@@ -317,6 +525,18 @@ func callToolCmd() *cobra.Command {
 				toolArgs = json.RawMessage("{}")
 			}
 
+			if validateArgs {
+				if err := validateToolArgs(ctx, c, toolName, toolArgs); err != nil {
+					return err
+				}
+			}
+			if validateOutput {
+				return callToolValidated(ctx, c, toolName, toolArgs, outputJSON)
+			}
+			if parallel > 1 || repeat > 1 {
+				return callToolLoad(c, toolName, toolArgs, parallel, repeat)
+			}
+
 			result, err := c.CallTool(ctx, toolName, toolArgs)
 			if err != nil {
 				return fmt.Errorf("failed to call tool: %w", err)
@@ -335,17 +555,150 @@ func callToolCmd() *cobra.Command {
 
 	cmd.Flags().StringVar(&argsJSON, "args", "", "Tool arguments as JSON")
 	cmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+	cmd.Flags().BoolVar(&validateArgs, "validate", false, "Check the arguments against the tool's input schema before calling it")
+	cmd.Flags().BoolVar(&validateOutput, "validate-output", false, "Check structuredContent against the tool's output schema")
+	cmd.Flags().IntVar(&parallel, "parallel", 1, "Number of concurrent callers sharing the connection")
+	cmd.Flags().IntVar(&repeat, "repeat", 1, "Number of calls made by each caller")
 	return cmd
 }
 
+// validateToolArgs checks args against the input schema declared in
+// tools/list, failing without calling the tool when they do not conform
+func validateToolArgs(ctx context.Context, c *client.Client, name string, args json.RawMessage) error {
+	tools, err := c.ListTools(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list tools: %w", err)
+	}
+	listed := false
+	for _, tool := range tools {
+		listed = listed || tool.Name == name
+	}
+	if !listed {
+		return fmt.Errorf("tool %q is not listed by the server", name)
+	}
+
+	if err := c.ValidateArguments(name, args); err != nil {
+		fmt.Fprintf(os.Stderr, "✗ Arguments violate the tool's input schema:\n  %v\n", err)
+		return fmt.Errorf("argument validation failed")
+	}
+	return nil
+}
+
+// callToolLoad calls a tool repeat times from each of parallel goroutines
+// over the client's single connection, reporting every call and a summary.
+// Each call gets its own timeout; the run fails if any call fails.
+func callToolLoad(c *client.Client, name string, args json.RawMessage, parallel, repeat int) error {
+	if parallel < 1 || repeat < 1 {
+		return fmt.Errorf("--parallel and --repeat must be at least 1")
+	}
+
+	var (
+		stats latencyStats
+		seq   atomic.Int64
+		wg    sync.WaitGroup
+		outMu sync.Mutex
+	)
+
+	start := time.Now()
+	for worker := 1; worker <= parallel; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < repeat; i++ {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+				callStart := time.Now()
+				_, err := c.CallTool(ctx, name, args)
+				rtt := time.Since(callStart)
+				cancel()
+
+				n := seq.Add(1)
+				outMu.Lock()
+				if err != nil {
+					stats.fail()
+					fmt.Printf("call %d (worker %d): error after %s: %v\n", n, worker, formatLatency(rtt), err)
+				} else {
+					stats.add(rtt)
+					fmt.Printf("call %d (worker %d): ok time=%s\n", n, worker, formatLatency(rtt))
+				}
+				outMu.Unlock()
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	stats.printCallSummary(time.Since(start))
+	if stats.failed > 0 {
+		return fmt.Errorf("%d of %d calls failed", stats.failed, parallel*repeat)
+	}
+	return nil
+}
+
+// callToolValidated calls a tool and checks its structured content against
+// the output schema declared in tools/list, failing when it does not conform
+func callToolValidated(ctx context.Context, c *client.Client, name string, args json.RawMessage, outputJSON bool) error {
+	tools, err := c.ListTools(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list tools: %w", err)
+	}
+	var schema map[string]interface{}
+	for _, tool := range tools {
+		if tool.Name == name {
+			schema = tool.OutputSchema
+		}
+	}
+
+	result, err := c.CallToolResult(ctx, name, args)
+	if err != nil {
+		return fmt.Errorf("failed to call tool: %w", err)
+	}
+
+	if outputJSON {
+		data, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		fmt.Println("Tool Result:")
+		for _, content := range result.Content {
+			if text, ok := content.(mcp.TextContent); ok {
+				fmt.Println(text.Text)
+			}
+		}
+		if result.StructuredContent != nil {
+			data, _ := json.MarshalIndent(result.StructuredContent, "", "  ")
+			fmt.Printf("Structured Content:\n%s\n", string(data))
+		}
+	}
+
+	switch {
+	case result.IsError:
+		return fmt.Errorf("tool returned an error result")
+	case schema == nil:
+		fmt.Fprintf(os.Stderr, "Tool %q declares no output schema; nothing to validate\n", name)
+		return nil
+	}
+
+	errs := client.ValidateToolOutput(result, schema)
+	if len(errs) == 0 {
+		fmt.Fprintln(os.Stderr, "✓ Output conforms to the tool's output schema")
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "✗ Output violates the tool's output schema:\n")
+	for _, e := range errs {
+		fmt.Fprintf(os.Stderr, "  - %s: %s\n", e.Field, e.Message)
+	}
+	return fmt.Errorf("output schema validation failed with %d violation(s)", len(errs))
+}
+
 func readResourceCmd() *cobra.Command {
 	var outputJSON bool
+	var outputFile string
 
 	cmd := &cobra.Command{
 		Use:   "read-resource <uri>",
 		Short: "Read a resource from the MCP server",
-		Long:  `Retrieves and displays the content of a resource.`,
-		Args:  cobra.ExactArgs(1),
+		Long: `Retrieves and displays the content of a resource. Binary (blob) contents are
+summarized instead of printed; use --output to save them to a file.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(_ *cobra.Command, args []string) error {
 			uri := args[0]
 
@@ -363,16 +716,47 @@ func readResourceCmd() *cobra.Command {
 			}
 			defer func() { _ = c.Close() }()
 
-			content, err := c.ReadResource(ctx, uri)
+			contents, err := c.ReadResourceContents(ctx, uri)
 			if err != nil {
 				return fmt.Errorf("failed to read resource: %w", err)
 			}
+			if len(contents) == 0 {
+				return fmt.Errorf("resource %s has no contents", uri)
+			}
+
+			if outputFile != "" {
+				data, err := contents[0].Bytes()
+				if err != nil {
+					return err
+				}
+				if err := os.WriteFile(outputFile, data, 0o644); err != nil {
+					return fmt.Errorf("failed to write output: %w", err)
+				}
+				fmt.Printf("Wrote %d bytes to %s\n", len(data), outputFile)
+				return nil
+			}
 
 			if outputJSON {
-				data, _ := json.MarshalIndent(string(content), "", "  ")
+				data, _ := json.MarshalIndent(contents, "", "  ")
 				fmt.Println(string(data))
-			} else {
-				fmt.Printf("Resource Content:\n%s\n", string(content))
+				return nil
+			}
+
+			for _, content := range contents {
+				switch content := content.(type) {
+				case mcp.TextResourceContents:
+					fmt.Printf("Resource Content:\n%s\n", content.Text)
+				case mcp.BlobResourceContents:
+					data, err := content.Bytes()
+					if err != nil {
+						return err
+					}
+					mimeType := content.MimeType
+					if mimeType == "" {
+						mimeType = "unknown type"
+					}
+					fmt.Printf("Binary Content: %s (%s, %d bytes)\n", content.URI, mimeType, len(data))
+				}
 			}
 
 			return nil
@@ -380,6 +764,7 @@ func readResourceCmd() *cobra.Command {
 	}
 
 	cmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "Write the first contents item's raw data to a file")
 	return cmd
 }
 
@@ -472,16 +857,43 @@ func infoCmd() *cobra.Command {
 			}
 			defer func() { _ = c.Close() }()
 
-			// Get counts
-			tools, _ := c.ListTools(ctx)
-			resources, _ := c.ListResources(ctx)
-			prompts, _ := c.ListPrompts(ctx)
+			serverInfo := c.ServerInfo()
+			caps := c.ServerCapabilities()
+			if caps == nil {
+				caps = &mcp.ServerCapabilities{}
+			}
+
+			// Only list what the server declared
+			var tools []*mcp.Tool
+			var resources []*mcp.Resource
+			var prompts []*mcp.Prompt
+			if caps.Tools != nil {
+				tools, _ = c.ListTools(ctx)
+			}
+			if caps.Resources != nil {
+				resources, _ = c.ListResources(ctx)
+			}
+			if caps.Prompts != nil {
+				prompts, _ = c.ListPrompts(ctx)
+			}
 
 			if outputJSON {
 				info := map[string]interface{}{
-					"tools_count":     len(tools),
-					"resources_count": len(resources),
-					"prompts_count":   len(prompts),
+					"server":           serverInfo,
+					"protocol_version": c.ProtocolVersion(),
+					"capabilities":     caps,
+				}
+				if caps.Tools != nil {
+					info["tools_count"] = len(tools)
+				}
+				if caps.Resources != nil {
+					info["resources_count"] = len(resources)
+				}
+				if caps.Prompts != nil {
+					info["prompts_count"] = len(prompts)
+				}
+				if instructions := c.Instructions(); instructions != "" {
+					info["instructions"] = instructions
 				}
 				data, _ := json.MarshalIndent(info, "", "  ")
 				fmt.Println(string(data))
@@ -489,9 +901,29 @@ func infoCmd() *cobra.Command {
 				fmt.Println("MCP Server Information")
 				fmt.Println("======================")
 				fmt.Println()
-				fmt.Printf("Tools:     %d\n", len(tools))
-				fmt.Printf("Resources: %d\n", len(resources))
-				fmt.Printf("Prompts:   %d\n", len(prompts))
+				fmt.Printf("Name:      %s\n", serverInfo.Name)
+				if serverInfo.Title != "" {
+					fmt.Printf("Title:     %s\n", serverInfo.Title)
+				}
+				fmt.Printf("Version:   %s\n", serverInfo.Version)
+				fmt.Printf("Protocol:  %s\n", c.ProtocolVersion())
+				fmt.Println()
+				displayCapabilities(caps)
+				fmt.Println()
+				if caps.Tools != nil {
+					fmt.Printf("Tools:     %d\n", len(tools))
+				}
+				if caps.Resources != nil {
+					fmt.Printf("Resources: %d\n", len(resources))
+				}
+				if caps.Prompts != nil {
+					fmt.Printf("Prompts:   %d\n", len(prompts))
+				}
+				if instructions := c.Instructions(); instructions != "" {
+					fmt.Println()
+					fmt.Println("Instructions:")
+					fmt.Println(instructions)
+				}
 				fmt.Println()
 				fmt.Println("Use --verbose for detailed listings")
 			}
@@ -503,3 +935,123 @@ func infoCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&outputJSON, "json", false, "Output as JSON")
 	return cmd
 }
+
+// displayCapabilities prints the capability flags from the initialize result
+func displayCapabilities(caps *mcp.ServerCapabilities) {
+	fmt.Println("Capabilities:")
+	if caps.Tools != nil {
+		fmt.Printf("  tools         (listChanged: %v)\n", caps.Tools.ListChanged)
+	}
+	if caps.Resources != nil {
+		fmt.Printf("  resources     (subscribe: %v, listChanged: %v)\n", caps.Resources.Subscribe, caps.Resources.ListChanged)
+	}
+	if caps.Prompts != nil {
+		fmt.Printf("  prompts       (listChanged: %v)\n", caps.Prompts.ListChanged)
+	}
+	if caps.Completions != nil {
+		fmt.Println("  completions")
+	}
+	if caps.Logging != nil {
+		fmt.Println("  logging")
+	}
+
+	names := make([]string, 0, len(caps.Experimental))
+	for name := range caps.Experimental {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("  experimental  %s\n", name)
+	}
+
+	if caps.Tools == nil && caps.Resources == nil && caps.Prompts == nil &&
+		caps.Completions == nil && caps.Logging == nil && len(names) == 0 {
+		fmt.Println("  (none)")
+	}
+}
+
+// logLevels lists the accepted levels, from least to most severe
+var logLevels = []mcp.LogLevel{
+	mcp.LogLevelDebug, mcp.LogLevelInfo, mcp.LogLevelNotice, mcp.LogLevelWarning,
+	mcp.LogLevelError, mcp.LogLevelCritical, mcp.LogLevelAlert, mcp.LogLevelEmergency,
+}
+
+func setLogLevelCmd() *cobra.Command {
+	var follow bool
+	var outputJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "set-log-level <level>",
+		Short: "Set the server's minimum log level",
+		Long: `Sends logging/setLevel to the MCP server. With --follow the connection is kept
+open and log notifications are printed until interrupted.
+
+Levels: debug, info, notice, warning, error, critical, alert, emergency`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			level := mcp.LogLevel(strings.ToLower(args[0]))
+			valid := false
+			for _, l := range logLevels {
+				valid = valid || l == level
+			}
+			if !valid {
+				return fmt.Errorf("unknown log level %q", args[0])
+			}
+
+			transport, err := createTransport()
+			if err != nil {
+				return fmt.Errorf("failed to create transport: %w", err)
+			}
+
+			var opts []client.Option
+			if follow {
+				opts = append(opts, client.WithLogHandler(func(_ context.Context, msg *mcp.LogMessage) {
+					printLogMessage(msg, outputJSON)
+				}))
+			}
+			c := client.New(transport, opts...)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+			defer cancel()
+
+			if err := c.Connect(ctx); err != nil {
+				return fmt.Errorf("failed to connect: %w", err)
+			}
+			defer func() { _ = c.Close() }()
+
+			if err := c.SetLogLevel(ctx, level); err != nil {
+				return fmt.Errorf("failed to set log level: %w", err)
+			}
+			if !follow {
+				fmt.Printf("✓ Log level set to %s\n", level)
+				return nil
+			}
+
+			fmt.Fprintf(os.Stderr, "Log level set to %s, following log messages (Ctrl+C to stop)\n", level)
+			interrupted, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			<-interrupted.Done()
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Stream log notifications until interrupted")
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "Print followed log messages as JSON lines")
+	return cmd
+}
+
+// printLogMessage prints a log notification received with --follow
+func printLogMessage(msg *mcp.LogMessage, outputJSON bool) {
+	if outputJSON {
+		data, _ := json.Marshal(msg)
+		fmt.Println(string(data))
+		return
+	}
+
+	data, _ := json.Marshal(msg.Data)
+	if msg.Logger != "" {
+		fmt.Printf("%s [%s] %s: %s\n", time.Now().Format(time.TimeOnly), msg.Level, msg.Logger, data)
+	} else {
+		fmt.Printf("%s [%s] %s\n", time.Now().Format(time.TimeOnly), msg.Level, data)
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jmcarbo/fullmcp/transport"
+	"github.com/jmcarbo/fullmcp/transport/stdio"
+	"github.com/spf13/cobra"
+)
+
+// profile describes how to reach a named server: either a command launched
+// over stdio or a URL opened with the registered transports
+type profile struct {
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// profilesFile is the on-disk format of the profiles file
+type profilesFile struct {
+	Profiles map[string]profile `json:"profiles"`
+}
+
+// defaultProfilesPath returns the profiles file under the user's config
+// directory
+func defaultProfilesPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "mcpcli-profiles.json"
+	}
+	return filepath.Join(dir, "mcpcli", "profiles.json")
+}
+
+// loadProfiles reads the profiles file; a missing file has no profiles
+func loadProfiles(path string) (map[string]profile, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]profile{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file profilesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid profiles file %s: %w", path, err)
+	}
+	if file.Profiles == nil {
+		file.Profiles = map[string]profile{}
+	}
+	return file.Profiles, nil
+}
+
+// saveProfiles writes the profiles file, creating its directory. It may hold
+// credentials from imported env and headers, so only the owner can read it.
+func saveProfiles(path string, profiles map[string]profile) error {
+	data, err := json.MarshalIndent(profilesFile{Profiles: profiles}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// openProfile creates the transport for a named profile
+func openProfile(name string) (transport.Transport, error) {
+	profiles, err := loadProfiles(profilesPath)
+	if err != nil {
+		return nil, err
+	}
+	p, ok := profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown profile %q in %s", name, profilesPath)
+	}
+
+	if p.URL != "" {
+		return transport.Open(p.URL, transport.Config{APIKey: apiKey, Headers: p.Headers})
+	}
+	if p.Command == "" {
+		return nil, fmt.Errorf("profile %q has neither a command nor a url", name)
+	}
+
+	env := make([]string, 0, len(p.Env))
+	for key, value := range p.Env {
+		env = append(env, key+"="+value)
+	}
+	sort.Strings(env)
+	return stdio.NewCommand(p.Command, p.Args, stdio.WithEnv(env...)), nil
+}
+
+// desktopServer is a server entry in the mcpServers config format shared by
+// Claude Desktop, Cursor and similar clients
+type desktopServer struct {
+	Command string            `json:"command"`
+	Args    []string          `json:"args"`
+	Env     map[string]string `json:"env"`
+	URL     string            `json:"url"`
+	Type    string            `json:"type"` // "stdio", "sse", "http" or "streamable-http"
+	Headers map[string]string `json:"headers"`
+}
+
+// convertDesktopServer converts a config entry into a profile, selecting the
+// transport scheme from the entry's type
+func convertDesktopServer(server desktopServer) (profile, error) {
+	if server.URL == "" {
+		if server.Command == "" {
+			return profile{}, fmt.Errorf("entry has neither a command nor a url")
+		}
+		return profile{Command: server.Command, Args: server.Args, Env: server.Env}, nil
+	}
+
+	target := server.URL
+	scheme, rest, ok := strings.Cut(target, "://")
+	if ok && (scheme == "http" || scheme == "https") {
+		switch server.Type {
+		case "sse":
+			target = scheme + "+sse://" + rest
+		case "", "http", "streamable-http":
+			target = scheme + "+stream://" + rest
+		}
+	}
+	return profile{URL: target, Headers: server.Headers}, nil
+}
+
+func importCmd() *cobra.Command {
+	var from string
+	var overwrite bool
+
+	cmd := &cobra.Command{
+		Use:   "import --from <config.json>",
+		Short: "Import server definitions as profiles",
+		Long: `Converts the mcpServers entries of a Claude Desktop, Cursor or similar config
+file into mcpcli profiles, usable with --profile. Command entries run the
+server over stdio; url entries use Streamable HTTP, or HTTP+SSE when their
+type is "sse". Existing profiles are kept unless --overwrite is given.`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if from == "" {
+				return fmt.Errorf("--from is required")
+			}
+
+			data, err := os.ReadFile(from)
+			if err != nil {
+				return fmt.Errorf("failed to read config: %w", err)
+			}
+			var config struct {
+				MCPServers map[string]desktopServer `json:"mcpServers"`
+			}
+			if err := json.Unmarshal(data, &config); err != nil {
+				return fmt.Errorf("invalid config %s: %w", from, err)
+			}
+			if len(config.MCPServers) == 0 {
+				return fmt.Errorf("no mcpServers entries in %s", from)
+			}
+
+			profiles, err := loadProfiles(profilesPath)
+			if err != nil {
+				return err
+			}
+
+			names := make([]string, 0, len(config.MCPServers))
+			for name := range config.MCPServers {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			imported := 0
+			for _, name := range names {
+				p, err := convertDesktopServer(config.MCPServers[name])
+				if err != nil {
+					fmt.Printf("  ✗ %s: %v\n", name, err)
+					continue
+				}
+				if _, exists := profiles[name]; exists && !overwrite {
+					fmt.Printf("  - %s: already exists (use --overwrite to replace)\n", name)
+					continue
+				}
+				profiles[name] = p
+				imported++
+				if p.URL != "" {
+					fmt.Printf("  ✓ %s: %s\n", name, p.URL)
+				} else {
+					fmt.Printf("  ✓ %s: %s\n", name, strings.Join(append([]string{p.Command}, p.Args...), " "))
+				}
+			}
+
+			if imported == 0 {
+				fmt.Println("No profiles imported")
+				return nil
+			}
+			if err := saveProfiles(profilesPath, profiles); err != nil {
+				return fmt.Errorf("failed to save profiles: %w", err)
+			}
+			fmt.Printf("Imported %d profile(s) into %s\n", imported, profilesPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Config file with an mcpServers section (e.g. claude_desktop_config.json)")
+	cmd.Flags().BoolVar(&overwrite, "overwrite", false, "Replace existing profiles with the same name")
+	return cmd
+}
@@ -0,0 +1,495 @@
+// Package conformance checks that an MCP server follows the JSON-RPC 2.0 and
+// MCP specifications. The checks speak the protocol directly over a
+// connection from a TransportFactory, so they work with any server reachable
+// over a fullmcp transport, not just servers built with fullmcp:
+//
+//	func TestConformance(t *testing.T) {
+//		conformance.RunAll(t, func() (io.ReadWriteCloser, error) {
+//			return stdio.NewCommand("./my-server", nil).Connect(context.Background())
+//		})
+//	}
+package conformance
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// ProtocolVersion is the protocol version the checks request in initialize
+const ProtocolVersion = "2025-06-18"
+
+// TransportFactory opens a new connection to the server under test. Every
+// check runs on its own connection, which it closes when done.
+type TransportFactory func() (io.ReadWriteCloser, error)
+
+// Option configures RunAll
+type Option func(*config)
+
+type config struct {
+	timeout time.Duration
+	skip    map[string]bool
+}
+
+// WithTimeout sets how long a check waits for each response (default 5s)
+func WithTimeout(d time.Duration) Option {
+	return func(c *config) {
+		if d > 0 {
+			c.timeout = d
+		}
+	}
+}
+
+// WithSkip skips the named checks, such as ones covering behavior a server
+// deliberately leaves out
+func WithSkip(names ...string) Option {
+	return func(c *config) {
+		for _, name := range names {
+			c.skip[name] = true
+		}
+	}
+}
+
+// check is a single conformance check, run as a subtest
+type check struct {
+	name string
+	// handshake runs initialize before the check
+	handshake bool
+	run       func(t *testing.T, c *conn)
+}
+
+var checks = []check{
+	{name: "initialize", run: checkInitialize},
+	{name: "ping", handshake: true, run: checkPing},
+	{name: "request-ids", handshake: true, run: checkRequestIDs},
+	{name: "unknown-method", handshake: true, run: checkUnknownMethod},
+	{name: "notifications", handshake: true, run: checkNotifications},
+	{name: "tools/list", handshake: true, run: checkToolsList},
+	{name: "tools/call-unknown", handshake: true, run: checkUnknownTool},
+	{name: "resources/list", handshake: true, run: checkResourcesList},
+	{name: "prompts/list", handshake: true, run: checkPromptsList},
+}
+
+// Names returns the names of every check, for use with WithSkip
+func Names() []string {
+	names := make([]string, len(checks))
+	for i, c := range checks {
+		names[i] = c.name
+	}
+	return names
+}
+
+// RunAll runs every conformance check against the server as a subtest of t.
+// Checks of tools, resources and prompts are skipped when the server does
+// not advertise the capability.
+func RunAll(t *testing.T, factory TransportFactory, opts ...Option) {
+	t.Helper()
+	cfg := &config{timeout: 5 * time.Second, skip: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	for _, ch := range checks {
+		t.Run(ch.name, func(t *testing.T) {
+			if cfg.skip[ch.name] {
+				t.Skip("skipped by WithSkip")
+			}
+			rw, err := factory()
+			if err != nil {
+				t.Fatalf("failed to connect: %v", err)
+			}
+			c := newConn(t, rw, cfg.timeout)
+			defer c.close()
+
+			if ch.handshake {
+				c.initialize()
+			}
+			ch.run(t, c)
+		})
+	}
+}
+
+var protocolVersionFormat = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+
+func checkInitialize(t *testing.T, c *conn) {
+	result := c.initialize()
+
+	var init struct {
+		ProtocolVersion *string         `json:"protocolVersion"`
+		Capabilities    json.RawMessage `json:"capabilities"`
+		ServerInfo      *struct {
+			Name    *string `json:"name"`
+			Version *string `json:"version"`
+		} `json:"serverInfo"`
+	}
+	if err := json.Unmarshal(result, &init); err != nil {
+		t.Fatalf("initialize result is not a valid object: %v", err)
+	}
+
+	switch {
+	case init.ProtocolVersion == nil:
+		t.Error("initialize result has no protocolVersion")
+	case !protocolVersionFormat.MatchString(*init.ProtocolVersion):
+		t.Errorf("protocolVersion %q is not a YYYY-MM-DD date", *init.ProtocolVersion)
+	}
+	if !isObject(init.Capabilities) {
+		t.Errorf("initialize result capabilities must be an object, got %s", init.Capabilities)
+	}
+	switch {
+	case init.ServerInfo == nil:
+		t.Error("initialize result has no serverInfo")
+	case init.ServerInfo.Name == nil || *init.ServerInfo.Name == "":
+		t.Error("serverInfo has no name")
+	case init.ServerInfo.Version == nil:
+		t.Error("serverInfo has no version")
+	}
+}
+
+func checkPing(t *testing.T, c *conn) {
+	resp := c.request("ping", nil)
+	if resp.Error != nil {
+		t.Fatalf("ping failed: %s", resp.Error)
+	}
+	if !isObject(resp.Result) {
+		t.Errorf("ping result must be an object, got %s", resp.Result)
+	}
+}
+
+func checkRequestIDs(_ *testing.T, c *conn) {
+	for _, id := range []json.RawMessage{
+		json.RawMessage(`"conformance-string-id"`),
+		json.RawMessage(`9007199254740991`),
+		json.RawMessage(`0`),
+	} {
+		c.send(message{JSONRPC: "2.0", ID: id, Method: "ping"})
+		c.await(id)
+	}
+}
+
+func checkUnknownMethod(t *testing.T, c *conn) {
+	resp := c.request("conformance/unknown-method", nil)
+	if resp.Error == nil {
+		t.Fatal("expected an error for an unknown method")
+	}
+	if resp.Error.code() != -32601 {
+		t.Errorf("expected code -32601 (method not found), got %s", resp.Error)
+	}
+}
+
+// checkNotifications sends notifications the server must not answer. The
+// ping sent after them must get the next response.
+func checkNotifications(_ *testing.T, c *conn) {
+	c.send(message{JSONRPC: "2.0", Method: "notifications/conformance/unknown"})
+	c.send(message{JSONRPC: "2.0", Method: "notifications/cancelled", Params: json.RawMessage(`{"requestId":"conformance-none"}`)})
+	c.request("ping", nil)
+}
+
+func checkToolsList(t *testing.T, c *conn) {
+	c.requireCapability("tools")
+	var result struct {
+		Tools []struct {
+			Name        string          `json:"name"`
+			InputSchema json.RawMessage `json:"inputSchema"`
+		} `json:"tools"`
+	}
+	c.listResult("tools/list", "tools", &result)
+
+	for i, tool := range result.Tools {
+		if tool.Name == "" {
+			t.Errorf("tool %d has no name", i)
+		}
+		if !isObject(tool.InputSchema) {
+			t.Errorf("tool %q inputSchema must be an object, got %s", tool.Name, tool.InputSchema)
+			continue
+		}
+		var schema struct {
+			Type string `json:"type"`
+		}
+		_ = json.Unmarshal(tool.InputSchema, &schema)
+		if schema.Type != "object" {
+			t.Errorf("tool %q inputSchema must have type \"object\", got %q", tool.Name, schema.Type)
+		}
+	}
+}
+
+func checkUnknownTool(t *testing.T, c *conn) {
+	c.requireCapability("tools")
+	resp := c.request("tools/call", map[string]interface{}{
+		"name":      "conformance-unknown-tool",
+		"arguments": map[string]interface{}{},
+	})
+	if resp.Error == nil {
+		t.Fatalf("expected an error for an unknown tool, got result %s", resp.Result)
+	}
+	if resp.Error.code() != -32602 {
+		t.Errorf("expected code -32602 (invalid params) for an unknown tool, got %s", resp.Error)
+	}
+}
+
+func checkResourcesList(t *testing.T, c *conn) {
+	c.requireCapability("resources")
+	var result struct {
+		Resources []struct {
+			URI  string `json:"uri"`
+			Name string `json:"name"`
+		} `json:"resources"`
+	}
+	c.listResult("resources/list", "resources", &result)
+
+	for i, resource := range result.Resources {
+		if resource.URI == "" {
+			t.Errorf("resource %d has no uri", i)
+		}
+		if resource.Name == "" {
+			t.Errorf("resource %q has no name", resource.URI)
+		}
+	}
+}
+
+func checkPromptsList(t *testing.T, c *conn) {
+	c.requireCapability("prompts")
+	var result struct {
+		Prompts []struct {
+			Name string `json:"name"`
+		} `json:"prompts"`
+	}
+	c.listResult("prompts/list", "prompts", &result)
+
+	for i, prompt := range result.Prompts {
+		if prompt.Name == "" {
+			t.Errorf("prompt %d has no name", i)
+		}
+	}
+}
+
+// isObject reports whether data is a JSON object
+func isObject(data json.RawMessage) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && data[0] == '{' && json.Valid(data)
+}
+
+// message is a JSON-RPC message. IDs are kept raw so their exact type can
+// be checked.
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is a JSON-RPC error, with fields optional so missing ones can be
+// reported
+type rpcError struct {
+	Code    *int            `json:"code"`
+	Message *string         `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *rpcError) code() int {
+	if e.Code == nil {
+		return 0
+	}
+	return *e.Code
+}
+
+func (e *rpcError) String() string {
+	message := ""
+	if e.Message != nil {
+		message = *e.Message
+	}
+	return fmt.Sprintf("%d %q", e.code(), message)
+}
+
+// conn is a connection to the server under test
+type conn struct {
+	t       *testing.T
+	rw      io.ReadWriteCloser
+	timeout time.Duration
+	msgs    chan *message
+	readErr chan error
+	done    chan struct{}
+	nextID  int
+	caps    map[string]json.RawMessage // Advertised during initialize
+}
+
+func newConn(t *testing.T, rw io.ReadWriteCloser, timeout time.Duration) *conn {
+	c := &conn{
+		t:       t,
+		rw:      rw,
+		timeout: timeout,
+		msgs:    make(chan *message, 16),
+		readErr: make(chan error, 1),
+		done:    make(chan struct{}),
+	}
+	go c.read()
+	return c
+}
+
+func (c *conn) read() {
+	decoder := json.NewDecoder(c.rw)
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			c.readErr <- err
+			close(c.msgs)
+			return
+		}
+		var msg message
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			c.readErr <- fmt.Errorf("server sent a message that is not a JSON object: %s", raw)
+			close(c.msgs)
+			return
+		}
+		select {
+		case c.msgs <- &msg:
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func (c *conn) close() {
+	close(c.done)
+	_ = c.rw.Close()
+}
+
+// send writes msg in a single write, since message-oriented transports
+// treat each write as one message
+func (c *conn) send(msg message) {
+	c.t.Helper()
+	data, err := json.Marshal(msg)
+	if err != nil {
+		c.t.Fatalf("failed to encode %s: %v", msg.Method, err)
+	}
+	if _, err := c.rw.Write(append(data, '\n')); err != nil {
+		c.t.Fatalf("failed to send %s: %v", msg.Method, err)
+	}
+}
+
+// request sends a request and returns its response
+func (c *conn) request(method string, params interface{}) *message {
+	c.t.Helper()
+	c.nextID++
+	msg := message{JSONRPC: "2.0", ID: json.RawMessage(fmt.Sprint(c.nextID)), Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			c.t.Fatalf("failed to encode %s params: %v", method, err)
+		}
+		msg.Params = data
+	}
+	c.send(msg)
+	return c.await(msg.ID)
+}
+
+// await returns the response with id, checking it is well formed. Server
+// requests and notifications received meanwhile are skipped, and any other
+// response fails the check, since the server answered something it should
+// not have.
+func (c *conn) await(id json.RawMessage) *message {
+	c.t.Helper()
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case msg, ok := <-c.msgs:
+			if !ok {
+				c.t.Fatalf("connection closed while waiting for response %s: %v", id, <-c.readErr)
+			}
+			if msg.Method != "" {
+				continue
+			}
+			if !bytes.Equal(msg.ID, id) {
+				c.t.Errorf("unexpected response with id %s while waiting for %s", msg.ID, id)
+				continue
+			}
+			c.checkResponse(msg)
+			return msg
+		case <-timer.C:
+			c.t.Fatalf("no response to request %s within %s", id, c.timeout)
+		}
+	}
+}
+
+// checkResponse checks a response against JSON-RPC 2.0
+func (c *conn) checkResponse(msg *message) {
+	c.t.Helper()
+	if msg.JSONRPC != "2.0" {
+		c.t.Errorf("response %s has jsonrpc %q, want \"2.0\"", msg.ID, msg.JSONRPC)
+	}
+	if (msg.Result == nil) == (msg.Error == nil) {
+		c.t.Errorf("response %s must have exactly one of result and error", msg.ID)
+	}
+	if msg.Error != nil {
+		if msg.Error.Code == nil {
+			c.t.Errorf("error response %s has no integer code", msg.ID)
+		}
+		if msg.Error.Message == nil {
+			c.t.Errorf("error response %s has no message", msg.ID)
+		}
+	}
+}
+
+// initialize runs the initialize handshake, returning the result
+func (c *conn) initialize() json.RawMessage {
+	c.t.Helper()
+	resp := c.request("initialize", map[string]interface{}{
+		"protocolVersion": ProtocolVersion,
+		"capabilities":    map[string]interface{}{},
+		"clientInfo":      map[string]string{"name": "fullmcp-conformance", "version": "1.0.0"},
+	})
+	if resp.Error != nil {
+		c.t.Fatalf("initialize failed: %s", resp.Error)
+	}
+
+	var result struct {
+		Capabilities map[string]json.RawMessage `json:"capabilities"`
+	}
+	_ = json.Unmarshal(resp.Result, &result)
+	c.caps = result.Capabilities
+
+	c.send(message{JSONRPC: "2.0", Method: "notifications/initialized"})
+	return resp.Result
+}
+
+// requireCapability skips the check unless the server advertised name
+func (c *conn) requireCapability(name string) {
+	c.t.Helper()
+	if _, ok := c.caps[name]; !ok {
+		c.t.Skipf("server does not advertise %s", name)
+	}
+}
+
+// listResult sends a list request and decodes its result into v, checking
+// the result has the named array and a valid nextCursor
+func (c *conn) listResult(method, field string, v interface{}) {
+	c.t.Helper()
+	resp := c.request(method, nil)
+	if resp.Error != nil {
+		c.t.Fatalf("%s failed: %s", method, resp.Error)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(resp.Result, &fields); err != nil {
+		c.t.Fatalf("%s result is not an object: %s", method, resp.Result)
+	}
+	if list := bytes.TrimSpace(fields[field]); len(list) == 0 || list[0] != '[' {
+		c.t.Fatalf("%s result must have a %s array, got %s", method, field, resp.Result)
+	}
+	if cursor, ok := fields["nextCursor"]; ok {
+		var s string
+		if err := json.Unmarshal(cursor, &s); err != nil {
+			c.t.Errorf("%s nextCursor must be a string, got %s", method, cursor)
+		}
+	}
+	if err := json.Unmarshal(resp.Result, v); err != nil {
+		c.t.Fatalf("failed to decode %s result: %v", method, err)
+	}
+}
@@ -0,0 +1,35 @@
+package conformance_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/conformance"
+	"github.com/jmcarbo/fullmcp/server"
+)
+
+func TestRunAll(t *testing.T) {
+	srv := server.New("conformance-test", server.WithVersion("1.0.0"))
+	_ = srv.AddTool(&server.ToolHandler{
+		Name:    "echo",
+		Schema:  map[string]interface{}{"type": "object"},
+		Handler: func(_ context.Context, args json.RawMessage) (interface{}, error) { return string(args), nil },
+	})
+	_ = srv.AddResource(&server.ResourceHandler{
+		URI:    "test://data",
+		Name:   "data",
+		Reader: func(context.Context) ([]byte, error) { return []byte("data"), nil },
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	conformance.RunAll(t, func() (io.ReadWriteCloser, error) {
+		clientConn, serverConn := net.Pipe()
+		go func() { _ = srv.Serve(ctx, serverConn) }()
+		return clientConn, nil
+	})
+}
@@ -0,0 +1,140 @@
+// Package discovery publishes and fetches the document an HTTP MCP server
+// serves at /.well-known/mcp.json, describing how to connect to it: the
+// server's name and version, its transport endpoints, the protocol versions
+// it speaks and the authentication it requires. Clients and registries can
+// then connect from the server's address alone.
+//
+// The streamhttp and websocket servers serve a document set with their
+// WithDiscovery options; Handler serves one from any mux.
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Path is where servers publish their discovery document
+const Path = "/.well-known/mcp.json"
+
+// Transport names used in endpoints
+const (
+	TransportStreamableHTTP = "streamable-http"
+	TransportWebSocket      = "websocket"
+	TransportHTTP           = "http"
+	TransportSSE            = "sse"
+)
+
+// Authentication schemes
+const (
+	AuthBearer = "bearer" // OAuth 2.1 or other bearer tokens in the Authorization header
+	AuthAPIKey = "apiKey" // An API key in the X-API-Key header
+)
+
+// Document is the discovery document
+type Document struct {
+	Name             string          `json:"name"`
+	Title            string          `json:"title,omitempty"`
+	Version          string          `json:"version,omitempty"`
+	Description      string          `json:"description,omitempty"`
+	ProtocolVersions []string        `json:"protocolVersions"`
+	Endpoints        []Endpoint      `json:"endpoints"`
+	Authentication   *Authentication `json:"authentication,omitempty"` // Nil when no credentials are needed
+}
+
+// Endpoint is a URL a transport accepts connections on. URLs use the scheme
+// client.Dial expects, such as https+stream://host/mcp or wss://host/mcp.
+// Handler resolves URLs given as a path, such as "/mcp", against the host
+// the document was requested from.
+type Endpoint struct {
+	Transport string `json:"transport"`
+	URL       string `json:"url"`
+}
+
+// Authentication describes the credentials a server requires
+type Authentication struct {
+	Schemes              []string `json:"schemes"`                        // AuthBearer, AuthAPIKey or others
+	AuthorizationServers []string `json:"authorizationServers,omitempty"` // OAuth issuers for bearer tokens
+	Scopes               []string `json:"scopes,omitempty"`               // OAuth scopes to request
+}
+
+// Handler serves doc as JSON to GET requests from any origin
+func Handler(doc *Document) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		resolved := *doc
+		resolved.Endpoints = make([]Endpoint, len(doc.Endpoints))
+		for i, endpoint := range doc.Endpoints {
+			resolved.Endpoints[i] = resolveEndpoint(endpoint, r)
+		}
+		if resolved.ProtocolVersions == nil {
+			resolved.ProtocolVersions = []string{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		_ = json.NewEncoder(w).Encode(&resolved)
+	})
+}
+
+// resolveEndpoint makes a path-only endpoint URL absolute, using the host
+// of r and the scheme of the endpoint's transport
+func resolveEndpoint(endpoint Endpoint, r *http.Request) Endpoint {
+	if !strings.HasPrefix(endpoint.URL, "/") {
+		return endpoint
+	}
+
+	plain, secure := "http", "https"
+	switch endpoint.Transport {
+	case TransportStreamableHTTP:
+		plain, secure = "http+stream", "https+stream"
+	case TransportWebSocket:
+		plain, secure = "ws", "wss"
+	case TransportSSE:
+		plain, secure = "http+sse", "https+sse"
+	}
+	scheme := plain
+	if r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https") {
+		scheme = secure
+	}
+
+	endpoint.URL = scheme + "://" + r.Host + endpoint.URL
+	return endpoint
+}
+
+// Fetch retrieves the discovery document of the server at baseURL, such as
+// https://mcp.example.com, with client (http.DefaultClient when nil)
+func Fetch(ctx context.Context, baseURL string, client *http.Client) (*Document, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(baseURL, "/")+Path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("discovery document request failed: %s", resp.Status)
+	}
+	var doc Document
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid discovery document: %w", err)
+	}
+	return &doc, nil
+}
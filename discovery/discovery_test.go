@@ -0,0 +1,87 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler_ResolvesEndpoints(t *testing.T) {
+	handler := Handler(&Document{
+		Name:             "inventory",
+		ProtocolVersions: []string{"2025-06-18"},
+		Endpoints: []Endpoint{
+			{Transport: TransportStreamableHTTP, URL: "/mcp"},
+			{Transport: TransportWebSocket, URL: "/ws"},
+			{Transport: TransportStreamableHTTP, URL: "https+stream://mcp.example.com/mcp"},
+		},
+		Authentication: &Authentication{Schemes: []string{AuthBearer}, AuthorizationServers: []string{"https://auth.example.com"}},
+	})
+
+	get := func(header http.Header) Document {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "http://inventory.local:8080"+Path, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK || rec.Header().Get("Access-Control-Allow-Origin") != "*" {
+			t.Fatalf("unexpected response: %d %v", rec.Code, rec.Header())
+		}
+		var doc Document
+		if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+			t.Fatalf("invalid document: %v", err)
+		}
+		return doc
+	}
+
+	doc := get(nil)
+	want := []string{"http+stream://inventory.local:8080/mcp", "ws://inventory.local:8080/ws", "https+stream://mcp.example.com/mcp"}
+	for i, endpoint := range doc.Endpoints {
+		if endpoint.URL != want[i] {
+			t.Errorf("endpoint %d: expected %s, got %s", i, want[i], endpoint.URL)
+		}
+	}
+	if doc.Authentication == nil || doc.Authentication.Schemes[0] != AuthBearer {
+		t.Errorf("expected the authentication requirements, got %+v", doc.Authentication)
+	}
+
+	doc = get(http.Header{"X-Forwarded-Proto": {"https"}})
+	if doc.Endpoints[0].URL != "https+stream://inventory.local:8080/mcp" || doc.Endpoints[1].URL != "wss://inventory.local:8080/ws" {
+		t.Errorf("expected secure schemes behind a TLS proxy, got %+v", doc.Endpoints)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, Path, strings.NewReader("{}")))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected POST to be rejected, got %d", rec.Code)
+	}
+}
+
+func TestFetch(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle(Path, Handler(&Document{
+		Name:      "inventory",
+		Version:   "1.0.0",
+		Endpoints: []Endpoint{{Transport: TransportStreamableHTTP, URL: "/mcp"}},
+	}))
+	httpServer := httptest.NewServer(mux)
+	defer httpServer.Close()
+
+	doc, err := Fetch(context.Background(), httpServer.URL+"/", nil)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if doc.Name != "inventory" || doc.Version != "1.0.0" || len(doc.Endpoints) != 1 ||
+		doc.Endpoints[0].URL != "http+stream://"+strings.TrimPrefix(httpServer.URL, "http://")+"/mcp" {
+		t.Errorf("unexpected document: %+v", doc)
+	}
+
+	if _, err := Fetch(context.Background(), httpServer.URL+"/missing", nil); err == nil {
+		t.Error("expected a missing document to fail")
+	}
+}
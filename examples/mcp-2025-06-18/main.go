@@ -140,10 +140,9 @@ func main() {
               Title: "Detailed Analysis Report",
               MimeType: "application/pdf",
           },
-          Annotations: {
-              "generated": "2025-06-18T10:30:00Z",
-              "size": 2048000,
-          },
+          Annotations: NewAnnotations().
+              WithAudience(RoleUser).
+              WithPriority(0.8),
       },
   }
 `)
@@ -255,8 +255,10 @@ func main() {
     "title": "File Deleter",
     "description": "Permanently delete a file",
     "inputSchema": {...},
-    "destructiveHint": true,
-    "idempotentHint": true
+    "annotations": {
+      "destructiveHint": true,
+      "idempotentHint": true
+    }
   }`)
 	fmt.Println()
 
@@ -0,0 +1,48 @@
+// Package cors matches request origins against allowed-origin policies shared
+// by the HTTP-based server transports.
+package cors
+
+import "strings"
+
+// Policy allows origins that match any of Patterns or are approved by Check.
+// A policy with neither allows every origin.
+type Policy struct {
+	Patterns []string                 // Exact origins or wildcard patterns such as "https://*.example.com"
+	Check    func(origin string) bool // Optional callback for custom rules
+}
+
+// Configured reports whether the policy restricts origins
+func (p *Policy) Configured() bool {
+	return len(p.Patterns) > 0 || p.Check != nil
+}
+
+// Allowed reports whether origin may connect
+func (p *Policy) Allowed(origin string) bool {
+	if !p.Configured() {
+		return true
+	}
+	for _, pattern := range p.Patterns {
+		if Match(origin, pattern) {
+			return true
+		}
+	}
+	return p.Check != nil && p.Check(origin)
+}
+
+// Match checks if an origin matches a pattern. "*" matches everything and a
+// single "*" inside a pattern matches any run of characters.
+func Match(origin, pattern string) bool {
+	if pattern == "*" || pattern == origin {
+		return true
+	}
+
+	prefix, suffix, found := strings.Cut(pattern, "*")
+	if !found {
+		return false
+	}
+
+	if len(origin) < len(prefix)+len(suffix) {
+		return false
+	}
+	return strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix)
+}
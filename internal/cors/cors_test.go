@@ -0,0 +1,36 @@
+package cors
+
+import "testing"
+
+func TestPolicy_Allowed(t *testing.T) {
+	policy := &Policy{
+		Patterns: []string{"https://app.example.com", "https://*.staging.example.com"},
+		Check: func(origin string) bool {
+			return origin == "http://localhost:3000"
+		},
+	}
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"https://app.example.com", true},
+		{"https://api.staging.example.com", true},
+		{"http://localhost:3000", true},
+		{"https://evil.com", false},
+		{"https://app.example.com.evil.com", false},
+	}
+
+	for _, tt := range tests {
+		if got := policy.Allowed(tt.origin); got != tt.want {
+			t.Errorf("Allowed(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+}
+
+func TestPolicy_Unconfigured(t *testing.T) {
+	policy := &Policy{}
+	if policy.Configured() || !policy.Allowed("https://anything.com") {
+		t.Error("expected an empty policy to allow every origin")
+	}
+}
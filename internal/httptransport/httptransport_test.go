@@ -0,0 +1,47 @@
+package httptransport
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestDefaultConfig(t *testing.T) {
+	cfg := DefaultConfig()
+
+	if cfg.MaxIdleConnsPerHost == 0 {
+		t.Error("expected per-host idle limit to be set")
+	}
+
+	if cfg.DialTimeout == 0 || cfg.TLSHandshakeTimeout == 0 {
+		t.Error("expected dial and TLS handshake timeouts to be set")
+	}
+}
+
+func TestNew(t *testing.T) {
+	proxyURL, _ := url.Parse("http://proxy.internal:3128")
+
+	cfg := DefaultConfig()
+	cfg.MaxIdleConns = 7
+	cfg.MaxIdleConnsPerHost = 3
+	cfg.MaxConnsPerHost = 5
+	cfg.ResponseHeaderTimeout = 2 * time.Second
+	cfg.Proxy = http.ProxyURL(proxyURL)
+
+	tr := New(cfg)
+
+	if tr.MaxIdleConns != 7 || tr.MaxIdleConnsPerHost != 3 || tr.MaxConnsPerHost != 5 {
+		t.Errorf("pool limits not applied: %d/%d/%d", tr.MaxIdleConns, tr.MaxIdleConnsPerHost, tr.MaxConnsPerHost)
+	}
+
+	if tr.ResponseHeaderTimeout != 2*time.Second {
+		t.Errorf("expected response header timeout 2s, got %v", tr.ResponseHeaderTimeout)
+	}
+
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+	got, err := tr.Proxy(req)
+	if err != nil || got.String() != proxyURL.String() {
+		t.Errorf("expected proxy %s, got %v (%v)", proxyURL, got, err)
+	}
+}
@@ -0,0 +1,42 @@
+package httptransport
+
+import (
+	"fmt"
+	"net/http"
+
+	"golang.org/x/oauth2"
+)
+
+// RequestFunc prepares an outgoing request, for example by adding headers
+type RequestFunc func(req *http.Request) error
+
+// BearerToken returns a RequestFunc that sets the Authorization header from a
+// token source, so expiring tokens are refreshed transparently
+func BearerToken(ts oauth2.TokenSource) RequestFunc {
+	return func(req *http.Request) error {
+		token, err := ts.Token()
+		if err != nil {
+			return fmt.Errorf("failed to get bearer token: %w", err)
+		}
+		token.SetAuthHeader(req)
+		return nil
+	}
+}
+
+// HeaderFunc adapts a function that cannot fail into a RequestFunc
+func HeaderFunc(fn func(req *http.Request)) RequestFunc {
+	return func(req *http.Request) error {
+		fn(req)
+		return nil
+	}
+}
+
+// Apply runs the request functions in order
+func Apply(req *http.Request, funcs []RequestFunc) error {
+	for _, fn := range funcs {
+		if err := fn(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package httptransport
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+type failingTokenSource struct{}
+
+func (failingTokenSource) Token() (*oauth2.Token, error) {
+	return nil, errors.New("expired")
+}
+
+func TestApply(t *testing.T) {
+	req, _ := http.NewRequest("POST", "http://localhost/mcp", nil)
+
+	err := Apply(req, []RequestFunc{
+		BearerToken(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "secret"})),
+		HeaderFunc(func(r *http.Request) { r.Header.Set("X-Trace-Id", "trace-1") }),
+	})
+	if err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+
+	if got := req.Header.Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("expected bearer token, got %q", got)
+	}
+	if got := req.Header.Get("X-Trace-Id"); got != "trace-1" {
+		t.Errorf("expected trace header, got %q", got)
+	}
+}
+
+func TestApply_TokenError(t *testing.T) {
+	req, _ := http.NewRequest("POST", "http://localhost/mcp", nil)
+
+	if err := Apply(req, []RequestFunc{BearerToken(failingTokenSource{})}); err == nil {
+		t.Fatal("expected token error")
+	}
+}
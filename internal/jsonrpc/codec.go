@@ -0,0 +1,53 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Codec encodes and decodes JSON. Implementations must behave like
+// encoding/json, honoring struct tags, json.RawMessage and the
+// json.Marshaler and json.Unmarshaler interfaces, since protocol types rely
+// on them.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+	// NewDecoder returns a decoder reading a stream of JSON values from r
+	NewDecoder(r io.Reader) Decoder
+}
+
+// Decoder reads successive JSON values from a stream
+type Decoder interface {
+	Decode(v interface{}) error
+}
+
+// StdCodec is the encoding/json codec
+type StdCodec struct{}
+
+// Marshal implements Codec
+func (StdCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal implements Codec
+func (StdCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// NewDecoder implements Codec
+func (StdCodec) NewDecoder(r io.Reader) Decoder {
+	return json.NewDecoder(r)
+}
+
+// DefaultCodec returns the codec used when none is configured: encoding/json,
+// or jsoniter when built with the jsoniter tag
+func DefaultCodec() Codec {
+	return defaultCodec
+}
+
+// WithCodec selects the codec used to encode and decode messages
+func WithCodec(codec Codec) Option {
+	return func(o *options) {
+		o.codec = codec
+	}
+}
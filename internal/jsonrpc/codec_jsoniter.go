@@ -0,0 +1,63 @@
+//go:build jsoniter
+
+package jsonrpc
+
+import (
+	"io"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+var defaultCodec Codec = JSONIterCodec{}
+
+// jsoniterAPI is configured to match encoding/json, including sorted map
+// keys and HTML escaping
+var jsoniterAPI = jsoniter.ConfigCompatibleWithStandardLibrary
+
+// JSONIterCodec is the github.com/json-iterator/go codec, the default when
+// built with the jsoniter tag
+type JSONIterCodec struct{}
+
+// Marshal implements Codec
+func (JSONIterCodec) Marshal(v interface{}) ([]byte, error) {
+	return jsoniterAPI.Marshal(v)
+}
+
+// Unmarshal implements Codec
+func (JSONIterCodec) Unmarshal(data []byte, v interface{}) error {
+	return jsoniterAPI.Unmarshal(data, v)
+}
+
+// NewDecoder implements Codec
+func (JSONIterCodec) NewDecoder(r io.Reader) Decoder {
+	src := &eofReader{r: r}
+	return &jsoniterDecoder{dec: jsoniterAPI.NewDecoder(src), src: src}
+}
+
+// jsoniterDecoder reports the end of the stream as io.EOF, as
+// encoding/json does, instead of a syntax error
+type jsoniterDecoder struct {
+	dec *jsoniter.Decoder
+	src *eofReader
+}
+
+func (d *jsoniterDecoder) Decode(v interface{}) error {
+	if !d.dec.More() && d.src.eof {
+		return io.EOF
+	}
+	return d.dec.Decode(v)
+}
+
+// eofReader records whether the underlying reader has reached its end
+type eofReader struct {
+	r   io.Reader
+	eof bool
+}
+
+func (r *eofReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err == io.EOF {
+		r.eof = true
+	}
+	return n, err
+}
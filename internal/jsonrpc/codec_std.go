@@ -0,0 +1,5 @@
+//go:build !jsoniter
+
+package jsonrpc
+
+var defaultCodec Codec = StdCodec{}
@@ -0,0 +1,87 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// countingCodec wraps the standard codec, counting calls
+type countingCodec struct {
+	StdCodec
+	marshals   atomic.Int64
+	unmarshals atomic.Int64
+}
+
+func (c *countingCodec) Marshal(v interface{}) ([]byte, error) {
+	c.marshals.Add(1)
+	return c.StdCodec.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v interface{}) error {
+	c.unmarshals.Add(1)
+	return c.StdCodec.Unmarshal(data, v)
+}
+
+func TestWithCodec(t *testing.T) {
+	codec := &countingCodec{}
+	var buf bytes.Buffer
+
+	writer := NewMessageWriter(&buf, WithCodec(codec))
+	if err := writer.Write(&mcp.Message{JSONRPC: "2.0", ID: 1, Method: "ping"}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if codec.marshals.Load() != 1 {
+		t.Errorf("expected the writer to use the codec, got %d marshals", codec.marshals.Load())
+	}
+
+	reader := NewMessageReader(&buf, WithCodec(codec), WithMaxMessageSize(1024))
+	msg, err := reader.Read()
+	if err != nil || msg.Method != "ping" {
+		t.Fatalf("unexpected read: %+v, %v", msg, err)
+	}
+	if codec.unmarshals.Load() != 1 {
+		t.Errorf("expected the reader to use the codec, got %d unmarshals", codec.unmarshals.Load())
+	}
+}
+
+func TestDefaultCodec_MatchesEncodingJSON(t *testing.T) {
+	msg := &mcp.Message{
+		JSONRPC: "2.0",
+		ID:      "a<b>",
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"echo","arguments":{"z":1,"a":[true,null]}}`),
+	}
+
+	want, _ := json.Marshal(msg)
+	got, err := DefaultCodec().Marshal(msg)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("default codec output differs:\n got %s\nwant %s", got, want)
+	}
+
+	var buf bytes.Buffer
+	writer := NewMessageWriter(&buf)
+	_ = writer.Write(msg)
+	_ = writer.Write(msg)
+
+	reader := NewMessageReader(&buf)
+	for i := 0; i < 2; i++ {
+		read, err := reader.Read()
+		if err != nil {
+			t.Fatalf("read %d failed: %v", i, err)
+		}
+		if string(read.Params) != string(msg.Params) {
+			t.Errorf("unexpected params: %s", read.Params)
+		}
+	}
+	if _, err := reader.Read(); err != io.EOF {
+		t.Errorf("expected EOF at the end of the stream, got %v", err)
+	}
+}
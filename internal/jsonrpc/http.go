@@ -0,0 +1,47 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// ReadBody reads an HTTP request body of at most limit bytes, returning a
+// *MessageTooLargeError for larger bodies. A limit of zero or less reads
+// the whole body.
+func ReadBody(w http.ResponseWriter, r *http.Request, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		return io.ReadAll(r.Body)
+	}
+	if r.ContentLength > limit {
+		return nil, &MessageTooLargeError{Limit: limit}
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, limit))
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return nil, &MessageTooLargeError{Limit: limit}
+	}
+	return body, err
+}
+
+// WriteTooLarge answers a request over the size limit with 413 Content Too
+// Large and a JSON-RPC MessageTooLarge error. The request ID is unknown, so
+// the response carries none.
+func WriteTooLarge(w http.ResponseWriter, err *MessageTooLargeError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusRequestEntityTooLarge)
+	_ = json.NewEncoder(w).Encode(TooLargeResponse(err))
+}
+
+// TooLargeResponse returns the JSON-RPC error response for a message over
+// the size limit
+func TooLargeResponse(err *MessageTooLargeError) *mcp.Message {
+	return &mcp.Message{
+		JSONRPC: "2.0",
+		Error:   mcp.NewMessageTooLarge("message", err.Limit).RPCError(),
+	}
+}
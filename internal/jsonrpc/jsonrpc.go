@@ -2,27 +2,129 @@
 package jsonrpc
 
 import (
-	"encoding/json"
+	"bufio"
+	"bytes"
+	"fmt"
 	"io"
+	"strconv"
+	"strings"
 
 	"github.com/jmcarbo/fullmcp/mcp"
 )
 
+// Framing selects how messages are delimited on the wire
+type Framing int
+
+const (
+	// FramingNewline delimits messages with newlines (the MCP default)
+	FramingNewline Framing = iota
+	// FramingContentLength prefixes each message with LSP-style
+	// Content-Length headers
+	FramingContentLength
+)
+
+// Option configures a MessageReader or MessageWriter
+type Option func(*options)
+
+type options struct {
+	framing Framing
+	maxSize int64
+	codec   Codec
+}
+
+// WithFraming selects the message framing
+func WithFraming(framing Framing) Option {
+	return func(o *options) {
+		o.framing = framing
+	}
+}
+
+// DefaultMaxFrameSize is the largest Content-Length framed message read
+// without WithMaxMessageSize, so a peer can't make the reader allocate an
+// arbitrary amount of memory
+const DefaultMaxFrameSize = 32 << 20
+
+// Limits on the headers of a Content-Length framed message, which are read
+// before the size limit can apply
+const (
+	maxHeaderLineSize = 4096
+	maxHeaderLines    = 32
+)
+
+// WithMaxMessageSize rejects messages larger than n bytes with a
+// *MessageTooLargeError. The oversized message is skipped, so the reader
+// stays usable. With newline framing each message must fit on one line, as
+// the MCP stdio transport requires. Content-Length framed messages are
+// limited to DefaultMaxFrameSize without it.
+func WithMaxMessageSize(n int64) Option {
+	return func(o *options) {
+		o.maxSize = n
+	}
+}
+
+// MessageTooLargeError is returned by MessageReader.Read for a message over
+// the configured size limit
+type MessageTooLargeError struct {
+	Limit int64
+}
+
+func (e *MessageTooLargeError) Error() string {
+	return fmt.Sprintf("message exceeds the maximum size of %d bytes", e.Limit)
+}
+
+func applyOptions(opts []Option) options {
+	o := options{codec: defaultCodec}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
 // MessageReader reads JSON-RPC messages
 type MessageReader struct {
-	decoder *json.Decoder
+	decoder Decoder
+	frames  *bufio.Reader // Set when messages use Content-Length framing
+	lines   *bufio.Reader // Set when newline framed messages are size limited
+	maxSize int64
+	codec   Codec
 }
 
 // NewMessageReader creates a new message reader
-func NewMessageReader(r io.Reader) *MessageReader {
+func NewMessageReader(r io.Reader, opts ...Option) *MessageReader {
+	o := applyOptions(opts)
+	switch {
+	case o.framing == FramingContentLength:
+		return &MessageReader{frames: bufio.NewReader(r), maxSize: o.maxSize, codec: o.codec}
+	case o.maxSize > 0:
+		return &MessageReader{lines: bufio.NewReader(r), maxSize: o.maxSize, codec: o.codec}
+	}
 	return &MessageReader{
-		decoder: json.NewDecoder(r),
+		decoder: o.codec.NewDecoder(r),
+		codec:   o.codec,
 	}
 }
 
 // Read reads a message
 func (mr *MessageReader) Read() (*mcp.Message, error) {
 	var msg mcp.Message
+
+	if mr.frames != nil || mr.lines != nil {
+		var body []byte
+		var err error
+		if mr.frames != nil {
+			body, err = readFrame(mr.frames, mr.maxSize)
+		} else {
+			body, err = readLine(mr.lines, mr.maxSize)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := mr.codec.Unmarshal(body, &msg); err != nil {
+			return nil, err
+		}
+		return &msg, nil
+	}
+
 	if err := mr.decoder.Decode(&msg); err != nil {
 		return nil, err
 	}
@@ -31,17 +133,170 @@ func (mr *MessageReader) Read() (*mcp.Message, error) {
 
 // MessageWriter writes JSON-RPC messages
 type MessageWriter struct {
-	encoder *json.Encoder
+	w       io.Writer
+	framing Framing
+	codec   Codec
 }
 
 // NewMessageWriter creates a new message writer
-func NewMessageWriter(w io.Writer) *MessageWriter {
+func NewMessageWriter(w io.Writer, opts ...Option) *MessageWriter {
+	o := applyOptions(opts)
 	return &MessageWriter{
-		encoder: json.NewEncoder(w),
+		w:       w,
+		framing: o.framing,
+		codec:   o.codec,
 	}
 }
 
 // Write writes a message
 func (mw *MessageWriter) Write(msg *mcp.Message) error {
-	return mw.encoder.Encode(msg)
+	body, err := mw.codec.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if mw.framing == FramingContentLength {
+		return WriteFrame(mw.w, body)
+	}
+	// A single write keeps concurrent writers from interleaving messages
+	_, err = mw.w.Write(append(body, '\n'))
+	return err
+}
+
+// ReadFrame reads the body of one Content-Length framed message of at most
+// DefaultMaxFrameSize bytes. Headers other than Content-Length, such as
+// Content-Type, are ignored.
+func ReadFrame(r *bufio.Reader) ([]byte, error) {
+	return readFrame(r, 0)
+}
+
+// readFrame reads a frame body, skipping bodies over maxSize bytes, or
+// DefaultMaxFrameSize when maxSize isn't positive
+func readFrame(r *bufio.Reader, maxSize int64) ([]byte, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxFrameSize
+	}
+	length := -1
+	sawHeader := false
+
+	lines := 0
+
+	for {
+		line, err := readHeaderLine(r)
+		if err != nil {
+			if err == io.EOF && !sawHeader && line == "" {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("failed to read frame header: %w", err)
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			if !sawHeader {
+				// Tolerate blank lines between frames
+				continue
+			}
+			break
+		}
+		sawHeader = true
+		if lines++; lines > maxHeaderLines {
+			return nil, fmt.Errorf("frame has more than %d header lines", maxHeaderLines)
+		}
+
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("malformed frame header %q", line)
+		}
+		if strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid Content-Length %q", value)
+			}
+			length = n
+		}
+	}
+
+	if length < 0 {
+		return nil, fmt.Errorf("frame is missing Content-Length header")
+	}
+
+	if int64(length) > maxSize {
+		// A failure to skip the body surfaces on the next read
+		_, _ = io.CopyN(io.Discard, r, int64(length))
+		return nil, &MessageTooLargeError{Limit: maxSize}
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, fmt.Errorf("failed to read frame body: %w", err)
+	}
+	return body, nil
+}
+
+// readHeaderLine reads one frame header line of at most maxHeaderLineSize
+// bytes, returning what was read before an error like ReadString
+func readHeaderLine(r *bufio.Reader) (string, error) {
+	var line []byte
+	for {
+		chunk, err := r.ReadSlice('\n')
+		line = append(line, chunk...)
+		if len(line) > maxHeaderLineSize {
+			return "", fmt.Errorf("frame header line exceeds %d bytes", maxHeaderLineSize)
+		}
+		if err != bufio.ErrBufferFull {
+			return string(line), err
+		}
+	}
+}
+
+// readLine reads one newline delimited message of at most maxSize bytes,
+// skipping blank lines. The rest of an oversized line is discarded.
+func readLine(r *bufio.Reader, maxSize int64) ([]byte, error) {
+	for {
+		var line []byte
+		tooLarge := false
+		for {
+			chunk, err := r.ReadSlice('\n')
+			// Allow for the "\r\n" line ending
+			if !tooLarge && int64(len(line)+len(chunk)) > maxSize+2 {
+				tooLarge = true
+				line = nil
+			}
+			if !tooLarge {
+				line = append(line, chunk...)
+			}
+			if err == bufio.ErrBufferFull {
+				continue
+			}
+			if err == io.EOF && (len(line) > 0 || tooLarge) {
+				// A final message without a newline
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+
+		if tooLarge {
+			return nil, &MessageTooLargeError{Limit: maxSize}
+		}
+		line = bytes.TrimSpace(line)
+		if int64(len(line)) > maxSize {
+			return nil, &MessageTooLargeError{Limit: maxSize}
+		}
+		if len(line) > 0 {
+			return line, nil
+		}
+	}
+}
+
+// WriteFrame writes body preceded by a Content-Length header in a single write
+func WriteFrame(w io.Writer, body []byte) error {
+	frame := make([]byte, 0, len(body)+32)
+	frame = append(frame, "Content-Length: "...)
+	frame = strconv.AppendInt(frame, int64(len(body)), 10)
+	frame = append(frame, "\r\n\r\n"...)
+	frame = append(frame, body...)
+
+	_, err := w.Write(frame)
+	return err
 }
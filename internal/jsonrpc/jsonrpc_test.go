@@ -3,7 +3,10 @@ package jsonrpc
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/jmcarbo/fullmcp/mcp"
@@ -84,13 +87,13 @@ func TestMessageWriter_Write(t *testing.T) {
 		t.Errorf("expected method '%s', got '%s'", msg.Method, readMsg.Method)
 	}
 
-	idFloat, ok := readMsg.ID.(float64)
+	id, ok := mcp.IDInt64(readMsg.ID)
 	if !ok {
-		t.Fatalf("expected ID to be float64, got %T", readMsg.ID)
+		t.Fatalf("expected numeric ID, got %T", readMsg.ID)
 	}
 
-	if int(idFloat) != 456 {
-		t.Errorf("expected ID 456, got %d", int(idFloat))
+	if id != 456 {
+		t.Errorf("expected ID 456, got %d", id)
 	}
 }
 
@@ -173,3 +176,125 @@ func TestMessageReader_Write_RoundTrip(t *testing.T) {
 		}
 	}
 }
+
+func TestContentLengthFraming_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewMessageWriter(&buf, WithFraming(FramingContentLength))
+
+	messages := []*mcp.Message{
+		{JSONRPC: "2.0", ID: 1, Method: "initialize"},
+		{JSONRPC: "2.0", Method: "notifications/message", Params: json.RawMessage(`{"data":"line one\nline two"}`)},
+	}
+	for _, msg := range messages {
+		if err := writer.Write(msg); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+
+	if !bytes.HasPrefix(buf.Bytes(), []byte("Content-Length: ")) {
+		t.Fatalf("expected Content-Length header, got %q", buf.String())
+	}
+
+	reader := NewMessageReader(&buf, WithFraming(FramingContentLength))
+	for _, want := range messages {
+		got, err := reader.Read()
+		if err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		if got.Method != want.Method {
+			t.Errorf("expected method %q, got %q", want.Method, got.Method)
+		}
+	}
+
+	if _, err := reader.Read(); err != io.EOF {
+		t.Errorf("expected EOF after last frame, got %v", err)
+	}
+}
+
+func TestReadFrame_Headers(t *testing.T) {
+	body := `{"jsonrpc":"2.0",` + "\n" + `"method":"ping"}`
+	input := "Content-Type: application/vscode-jsonrpc; charset=utf-8\r\n" +
+		"content-length: " + strconv.Itoa(len(body)) + "\r\n\r\n" + body
+
+	reader := NewMessageReader(strings.NewReader(input), WithFraming(FramingContentLength))
+	msg, err := reader.Read()
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if msg.Method != "ping" {
+		t.Errorf("expected method ping, got %q", msg.Method)
+	}
+}
+
+func TestReadFrame_MissingLength(t *testing.T) {
+	reader := NewMessageReader(strings.NewReader("Content-Type: application/json\r\n\r\n{}"), WithFraming(FramingContentLength))
+	if _, err := reader.Read(); err == nil {
+		t.Error("expected error for frame without Content-Length")
+	}
+}
+
+func TestReadFrame_HeaderLimits(t *testing.T) {
+	for name, input := range map[string]string{
+		"long line":  "X-Padding: " + strings.Repeat("x", 1<<20),
+		"many lines": strings.Repeat("X-Padding: x\r\n", 1000) + "Content-Length: 2\r\n\r\n{}",
+	} {
+		reader := NewMessageReader(strings.NewReader(input), WithFraming(FramingContentLength))
+		if _, err := reader.Read(); err == nil || err == io.EOF {
+			t.Errorf("%s: expected header limit error, got %v", name, err)
+		}
+	}
+}
+
+func TestMessageReader_MaxMessageSize(t *testing.T) {
+	small := `{"jsonrpc":"2.0","id":1,"method":"ping"}`
+	large := `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"data":"` + strings.Repeat("x", 10000) + `"}}`
+	input := small + "\n\n" + large + "\r\n" + small
+
+	reader := NewMessageReader(strings.NewReader(input), WithMaxMessageSize(100))
+
+	if msg, err := reader.Read(); err != nil || msg.Method != "ping" {
+		t.Fatalf("expected first message, got %v, %v", msg, err)
+	}
+
+	_, err := reader.Read()
+	var tooLarge *MessageTooLargeError
+	if !errors.As(err, &tooLarge) || tooLarge.Limit != 100 {
+		t.Fatalf("expected MessageTooLargeError, got %v", err)
+	}
+
+	// The oversized message is skipped and the final one needs no newline
+	if msg, err := reader.Read(); err != nil || msg.Method != "ping" {
+		t.Fatalf("expected reader to recover, got %v, %v", msg, err)
+	}
+	if _, err := reader.Read(); err != io.EOF {
+		t.Errorf("expected EOF, got %v", err)
+	}
+}
+
+func TestMessageReader_MaxMessageSize_ContentLength(t *testing.T) {
+	var buf bytes.Buffer
+	_ = WriteFrame(&buf, []byte(`{"jsonrpc":"2.0","id":1,"method":"`+strings.Repeat("x", 200)+`"}`))
+	_ = WriteFrame(&buf, []byte(`{"jsonrpc":"2.0","id":2,"method":"ping"}`))
+
+	reader := NewMessageReader(&buf, WithFraming(FramingContentLength), WithMaxMessageSize(100))
+
+	var tooLarge *MessageTooLargeError
+	if _, err := reader.Read(); !errors.As(err, &tooLarge) {
+		t.Fatalf("expected MessageTooLargeError, got %v", err)
+	}
+	if msg, err := reader.Read(); err != nil || msg.Method != "ping" {
+		t.Fatalf("expected reader to recover, got %v, %v", msg, err)
+	}
+}
+
+func TestReadFrame_DefaultMaxSize(t *testing.T) {
+	reader := NewMessageReader(strings.NewReader("Content-Length: 99999999999\r\n\r\n{}"), WithFraming(FramingContentLength))
+
+	var tooLarge *MessageTooLargeError
+	if _, err := reader.Read(); !errors.As(err, &tooLarge) || tooLarge.Limit != DefaultMaxFrameSize {
+		t.Fatalf("expected MessageTooLargeError at the default limit, got %v", err)
+	}
+	if _, err := reader.Read(); err != io.EOF {
+		t.Errorf("expected EOF after the skipped frame, got %v", err)
+	}
+}
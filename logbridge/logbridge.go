@@ -0,0 +1,54 @@
+// Package logbridge connects MCP logging to Go logging libraries. Its
+// subpackages adapt slog, zap and zerolog loggers to the server's Logger
+// interface and mirror server log notifications into them on the client.
+package logbridge
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// messageKeys are the data keys checked, in order, for the log message text
+var messageKeys = []string{"message", "msg"}
+
+// Field is a structured key/value pair from a log notification
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Split extracts the message text and the remaining structured fields from a
+// log notification. Fields are sorted by key; the logger name, when set, is
+// included as the "logger" field.
+func Split(msg *mcp.LogMessage) (string, []Field) {
+	var text string
+	used := ""
+	for _, key := range messageKeys {
+		if v, ok := msg.Data[key]; ok {
+			text = fmt.Sprint(v)
+			used = key
+			break
+		}
+	}
+
+	fields := make([]Field, 0, len(msg.Data)+1)
+	if msg.Logger != "" {
+		fields = append(fields, Field{Key: "logger", Value: msg.Logger})
+	}
+
+	keys := make([]string, 0, len(msg.Data))
+	for key := range msg.Data {
+		if key != used {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fields = append(fields, Field{Key: key, Value: msg.Data[key]})
+	}
+
+	return text, fields
+}
@@ -0,0 +1,44 @@
+package logbridge
+
+import (
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func TestSplit(t *testing.T) {
+	text, fields := Split(&mcp.LogMessage{
+		Level:  mcp.LogLevelInfo,
+		Logger: "db",
+		Data:   map[string]interface{}{"message": "query done", "rows": 3, "elapsed": "5ms"},
+	})
+
+	if text != "query done" {
+		t.Errorf("unexpected text: %q", text)
+	}
+
+	want := []Field{{"logger", "db"}, {"elapsed", "5ms"}, {"rows", 3}}
+	if len(fields) != len(want) {
+		t.Fatalf("expected %d fields, got %v", len(want), fields)
+	}
+	for i := range want {
+		if fields[i] != want[i] {
+			t.Errorf("field %d: expected %v, got %v", i, want[i], fields[i])
+		}
+	}
+}
+
+func TestSplit_NoMessage(t *testing.T) {
+	text, fields := Split(&mcp.LogMessage{Data: map[string]interface{}{"msg": "hi", "message2": 1}})
+	if text != "hi" {
+		t.Errorf("expected msg key to be used, got %q", text)
+	}
+	if len(fields) != 1 || fields[0].Key != "message2" {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+
+	text, _ = Split(&mcp.LogMessage{Data: map[string]interface{}{"count": 1}})
+	if text != "" {
+		t.Errorf("expected empty text, got %q", text)
+	}
+}
@@ -0,0 +1,61 @@
+// Package slog adapts log/slog loggers for MCP servers and clients.
+package slog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/jmcarbo/fullmcp/client"
+	"github.com/jmcarbo/fullmcp/logbridge"
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// Logger implements server.Logger on top of a slog.Logger
+type Logger struct {
+	logger *slog.Logger
+}
+
+// New creates a server logger that writes to logger
+func New(logger *slog.Logger) *Logger {
+	return &Logger{logger: logger}
+}
+
+// Infof logs a formatted message at info level
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.logger.Info(fmt.Sprintf(format, args...))
+}
+
+// Errorf logs a formatted message at error level
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.logger.Error(fmt.Sprintf(format, args...))
+}
+
+// LogHandler returns a client log handler that mirrors server log
+// notifications into logger
+func LogHandler(logger *slog.Logger) client.LogHandler {
+	return func(ctx context.Context, msg *mcp.LogMessage) {
+		text, fields := logbridge.Split(msg)
+
+		attrs := make([]slog.Attr, 0, len(fields))
+		for _, f := range fields {
+			attrs = append(attrs, slog.Any(f.Key, f.Value))
+		}
+
+		logger.LogAttrs(ctx, Level(msg.Level), text, attrs...)
+	}
+}
+
+// Level maps an MCP log level to a slog level
+func Level(level mcp.LogLevel) slog.Level {
+	switch level {
+	case mcp.LogLevelDebug:
+		return slog.LevelDebug
+	case mcp.LogLevelInfo, mcp.LogLevelNotice:
+		return slog.LevelInfo
+	case mcp.LogLevelWarning:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
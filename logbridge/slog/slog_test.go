@@ -0,0 +1,62 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+	"github.com/jmcarbo/fullmcp/server"
+)
+
+var _ server.Logger = (*Logger)(nil)
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	logger.Infof("Request: %s", "tools/list")
+	logger.Errorf("Error: %v", "boom")
+
+	out := buf.String()
+	if !strings.Contains(out, `level=INFO msg="Request: tools/list"`) {
+		t.Errorf("missing info line: %s", out)
+	}
+	if !strings.Contains(out, `level=ERROR msg="Error: boom"`) {
+		t.Errorf("missing error line: %s", out)
+	}
+}
+
+func TestLogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	handler := LogHandler(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	handler(context.Background(), &mcp.LogMessage{
+		Level:  mcp.LogLevelWarning,
+		Logger: "db",
+		Data:   map[string]interface{}{"message": "slow query", "ms": 250},
+	})
+
+	out := buf.String()
+	for _, want := range []string{"level=WARN", `msg="slow query"`, "logger=db", "ms=250"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in %s", want, out)
+		}
+	}
+}
+
+func TestLevel(t *testing.T) {
+	tests := map[mcp.LogLevel]slog.Level{
+		mcp.LogLevelDebug:     slog.LevelDebug,
+		mcp.LogLevelNotice:    slog.LevelInfo,
+		mcp.LogLevelWarning:   slog.LevelWarn,
+		mcp.LogLevelEmergency: slog.LevelError,
+	}
+	for level, want := range tests {
+		if got := Level(level); got != want {
+			t.Errorf("Level(%s) = %v, want %v", level, got, want)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Package zap adapts zap loggers for MCP servers and clients.
+package zap
+
+import (
+	"context"
+
+	"github.com/jmcarbo/fullmcp/client"
+	"github.com/jmcarbo/fullmcp/logbridge"
+	"github.com/jmcarbo/fullmcp/mcp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Logger implements server.Logger on top of a zap.Logger
+type Logger struct {
+	logger *zap.SugaredLogger
+}
+
+// New creates a server logger that writes to logger
+func New(logger *zap.Logger) *Logger {
+	return &Logger{logger: logger.Sugar()}
+}
+
+// Infof logs a formatted message at info level
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.logger.Infof(format, args...)
+}
+
+// Errorf logs a formatted message at error level
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.logger.Errorf(format, args...)
+}
+
+// LogHandler returns a client log handler that mirrors server log
+// notifications into logger
+func LogHandler(logger *zap.Logger) client.LogHandler {
+	return func(_ context.Context, msg *mcp.LogMessage) {
+		text, fields := logbridge.Split(msg)
+
+		entry := logger.Check(Level(msg.Level), text)
+		if entry == nil {
+			return
+		}
+
+		zapFields := make([]zap.Field, 0, len(fields))
+		for _, f := range fields {
+			zapFields = append(zapFields, zap.Any(f.Key, f.Value))
+		}
+		entry.Write(zapFields...)
+	}
+}
+
+// Level maps an MCP log level to a zap level. Levels above error map to
+// error so that remote messages never panic or exit the process.
+func Level(level mcp.LogLevel) zapcore.Level {
+	switch level {
+	case mcp.LogLevelDebug:
+		return zapcore.DebugLevel
+	case mcp.LogLevelInfo, mcp.LogLevelNotice:
+		return zapcore.InfoLevel
+	case mcp.LogLevelWarning:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
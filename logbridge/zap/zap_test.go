@@ -0,0 +1,56 @@
+package zap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+	"github.com/jmcarbo/fullmcp/server"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var _ server.Logger = (*Logger)(nil)
+
+func TestLogger(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := New(zap.New(core))
+
+	logger.Infof("Request: %s", "tools/list")
+	logger.Errorf("Error: %v", "boom")
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Level != zapcore.InfoLevel || entries[0].Message != "Request: tools/list" {
+		t.Errorf("unexpected info entry: %+v", entries[0].Entry)
+	}
+	if entries[1].Level != zapcore.ErrorLevel || entries[1].Message != "Error: boom" {
+		t.Errorf("unexpected error entry: %+v", entries[1].Entry)
+	}
+}
+
+func TestLogHandler(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	handler := LogHandler(zap.New(core))
+
+	handler(context.Background(), &mcp.LogMessage{Level: mcp.LogLevelDebug, Data: map[string]interface{}{"message": "hidden"}})
+	handler(context.Background(), &mcp.LogMessage{
+		Level:  mcp.LogLevelCritical,
+		Logger: "db",
+		Data:   map[string]interface{}{"message": "disk full"},
+	})
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected debug message to be filtered, got %d entries", len(entries))
+	}
+	if entries[0].Level != zapcore.ErrorLevel || entries[0].Message != "disk full" {
+		t.Errorf("unexpected entry: %+v", entries[0].Entry)
+	}
+	if entries[0].ContextMap()["logger"] != "db" {
+		t.Errorf("expected logger field, got %v", entries[0].ContextMap())
+	}
+}
@@ -0,0 +1,60 @@
+// Package zerolog adapts zerolog loggers for MCP servers and clients.
+package zerolog
+
+import (
+	"context"
+
+	"github.com/jmcarbo/fullmcp/client"
+	"github.com/jmcarbo/fullmcp/logbridge"
+	"github.com/jmcarbo/fullmcp/mcp"
+	"github.com/rs/zerolog"
+)
+
+// Logger implements server.Logger on top of a zerolog.Logger
+type Logger struct {
+	logger zerolog.Logger
+}
+
+// New creates a server logger that writes to logger
+func New(logger zerolog.Logger) *Logger {
+	return &Logger{logger: logger}
+}
+
+// Infof logs a formatted message at info level
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.logger.Info().Msgf(format, args...)
+}
+
+// Errorf logs a formatted message at error level
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.logger.Error().Msgf(format, args...)
+}
+
+// LogHandler returns a client log handler that mirrors server log
+// notifications into logger
+func LogHandler(logger zerolog.Logger) client.LogHandler {
+	return func(_ context.Context, msg *mcp.LogMessage) {
+		text, fields := logbridge.Split(msg)
+
+		event := logger.WithLevel(Level(msg.Level))
+		for _, f := range fields {
+			event = event.Interface(f.Key, f.Value)
+		}
+		event.Msg(text)
+	}
+}
+
+// Level maps an MCP log level to a zerolog level. Levels above error map to
+// error so that remote messages never panic or exit the process.
+func Level(level mcp.LogLevel) zerolog.Level {
+	switch level {
+	case mcp.LogLevelDebug:
+		return zerolog.DebugLevel
+	case mcp.LogLevelInfo, mcp.LogLevelNotice:
+		return zerolog.InfoLevel
+	case mcp.LogLevelWarning:
+		return zerolog.WarnLevel
+	default:
+		return zerolog.ErrorLevel
+	}
+}
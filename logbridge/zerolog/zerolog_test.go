@@ -0,0 +1,49 @@
+package zerolog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+	"github.com/jmcarbo/fullmcp/server"
+	"github.com/rs/zerolog"
+)
+
+var _ server.Logger = (*Logger)(nil)
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(zerolog.New(&buf))
+
+	logger.Infof("Request: %s", "tools/list")
+	logger.Errorf("Error: %v", "boom")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", buf.String())
+	}
+	if lines[0] != `{"level":"info","message":"Request: tools/list"}` {
+		t.Errorf("unexpected info line: %s", lines[0])
+	}
+	if lines[1] != `{"level":"error","message":"Error: boom"}` {
+		t.Errorf("unexpected error line: %s", lines[1])
+	}
+}
+
+func TestLogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	handler := LogHandler(zerolog.New(&buf))
+
+	handler(context.Background(), &mcp.LogMessage{
+		Level:  mcp.LogLevelNotice,
+		Logger: "db",
+		Data:   map[string]interface{}{"message": "connected", "pool": 4},
+	})
+
+	want := `{"level":"info","logger":"db","pool":4,"message":"connected"}`
+	if got := strings.TrimSpace(buf.String()); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,224 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Role identifies a participant in the conversation
+type Role string
+
+// Audience roles
+const (
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+)
+
+// Annotations tell clients how to use or display resources and content
+// (2025-06-18). Unset fields are nil, so a priority of 0 can be told apart
+// from no priority.
+type Annotations struct {
+	Audience     []Role     // Who the content is intended for
+	Priority     *float64   // Importance from 0 (optional) to 1 (required)
+	LastModified *time.Time // When the content was last modified
+}
+
+// annotationsJSON is the wire format of Annotations
+type annotationsJSON struct {
+	Audience     []Role   `json:"audience,omitempty"`
+	Priority     *float64 `json:"priority,omitempty"`
+	LastModified string   `json:"lastModified,omitempty"` // ISO 8601
+}
+
+// lastModifiedLayouts are the ISO 8601 forms accepted for lastModified,
+// tried in order. Times without a zone are taken as UTC.
+var lastModifiedLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// parseLastModified parses an ISO 8601 timestamp
+func parseLastModified(value string) (time.Time, error) {
+	for _, layout := range lastModifiedLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid lastModified %q: expected an ISO 8601 timestamp", value)
+}
+
+// NewAnnotations creates empty annotations
+func NewAnnotations() *Annotations {
+	return &Annotations{}
+}
+
+// WithAudience sets the intended audience
+func (a *Annotations) WithAudience(roles ...Role) *Annotations {
+	a.Audience = roles
+	return a
+}
+
+// WithPriority sets the priority, from 0 to 1
+func (a *Annotations) WithPriority(priority float64) *Annotations {
+	a.Priority = &priority
+	return a
+}
+
+// WithLastModified sets the last modification time
+func (a *Annotations) WithLastModified(t time.Time) *Annotations {
+	a.LastModified = &t
+	return a
+}
+
+// PriorityOr returns the priority, or def when a is nil or has none
+func (a *Annotations) PriorityOr(def float64) float64 {
+	if a == nil || a.Priority == nil {
+		return def
+	}
+	return *a.Priority
+}
+
+// IsFor reports whether the content is intended for role. Content without
+// an audience is intended for everyone.
+func (a *Annotations) IsFor(role Role) bool {
+	if a == nil || len(a.Audience) == 0 {
+		return true
+	}
+	for _, r := range a.Audience {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON implements json.Marshaler, omitting unset fields
+func (a Annotations) MarshalJSON() ([]byte, error) {
+	wire := annotationsJSON{
+		Audience: a.Audience,
+		Priority: a.Priority,
+	}
+	if a.LastModified != nil {
+		wire.LastModified = a.LastModified.UTC().Format(time.RFC3339Nano)
+	}
+	return json.Marshal(wire)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (a *Annotations) UnmarshalJSON(data []byte) error {
+	var wire annotationsJSON
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+
+	a.Audience = wire.Audience
+	a.Priority = wire.Priority
+	a.LastModified = nil
+	if wire.LastModified != "" {
+		t, err := parseLastModified(wire.LastModified)
+		if err != nil {
+			return err
+		}
+		a.LastModified = &t
+	}
+	return nil
+}
+
+// ToolAnnotations describe how a tool behaves (2025-03-26). Unset hints are
+// nil. Custom holds extra keys, such as cost tier or owner, serialized
+// alongside the hints in the same object.
+type ToolAnnotations struct {
+	ReadOnlyHint    *bool                  // Tool doesn't modify environment
+	DestructiveHint *bool                  // Tool may perform destructive updates
+	IdempotentHint  *bool                  // Repeated calls have no additional effect
+	OpenWorldHint   *bool                  // Tool may interact with external entities
+	Custom          map[string]interface{} // Additional annotations
+}
+
+// Tool annotation hint keys
+const (
+	readOnlyHintKey    = "readOnlyHint"
+	destructiveHintKey = "destructiveHint"
+	idempotentHintKey  = "idempotentHint"
+	openWorldHintKey   = "openWorldHint"
+)
+
+// IsEmpty reports whether a is nil or sets no hints and no custom annotations
+func (a *ToolAnnotations) IsEmpty() bool {
+	return a == nil || (a.ReadOnlyHint == nil && a.DestructiveHint == nil &&
+		a.IdempotentHint == nil && a.OpenWorldHint == nil && len(a.Custom) == 0)
+}
+
+// IsReadOnly reports whether ReadOnlyHint is set to true
+func (a *ToolAnnotations) IsReadOnly() bool {
+	return a != nil && a.ReadOnlyHint != nil && *a.ReadOnlyHint
+}
+
+// IsIdempotent reports whether IdempotentHint is set to true
+func (a *ToolAnnotations) IsIdempotent() bool {
+	return a != nil && a.IdempotentHint != nil && *a.IdempotentHint
+}
+
+// hints returns the hint fields keyed by their wire names
+func (a *ToolAnnotations) hints() map[string]**bool {
+	return map[string]**bool{
+		readOnlyHintKey:    &a.ReadOnlyHint,
+		destructiveHintKey: &a.DestructiveHint,
+		idempotentHintKey:  &a.IdempotentHint,
+		openWorldHintKey:   &a.OpenWorldHint,
+	}
+}
+
+// MarshalJSON implements json.Marshaler. Hints take precedence over custom
+// annotations with the same key.
+func (a ToolAnnotations) MarshalJSON() ([]byte, error) {
+	wire := make(map[string]interface{}, len(a.Custom)+4)
+	for key, value := range a.Custom {
+		wire[key] = value
+	}
+	for key, hint := range a.hints() {
+		if *hint != nil {
+			wire[key] = **hint
+		} else {
+			delete(wire, key)
+		}
+	}
+	return json.Marshal(wire)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, collecting unknown keys in
+// Custom
+func (a *ToolAnnotations) UnmarshalJSON(data []byte) error {
+	var wire map[string]json.RawMessage
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+
+	*a = ToolAnnotations{}
+	for key, hint := range a.hints() {
+		raw, ok := wire[key]
+		if !ok {
+			continue
+		}
+		delete(wire, key)
+		if err := json.Unmarshal(raw, hint); err != nil {
+			return err
+		}
+	}
+	for key, raw := range wire {
+		var value interface{}
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return err
+		}
+		if a.Custom == nil {
+			a.Custom = make(map[string]interface{})
+		}
+		a.Custom[key] = value
+	}
+	return nil
+}
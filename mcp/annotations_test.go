@@ -0,0 +1,255 @@
+package mcp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnnotations_JSONRoundTrip(t *testing.T) {
+	modified := time.Date(2025, 6, 18, 10, 30, 0, 0, time.UTC)
+	content := TextContent{
+		Type: "text",
+		Text: "report",
+		Annotations: NewAnnotations().
+			WithAudience(RoleUser, RoleAssistant).
+			WithPriority(0.9).
+			WithLastModified(modified),
+	}
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	want := `{"type":"text","text":"report","annotations":{"audience":["user","assistant"],"priority":0.9,"lastModified":"2025-06-18T10:30:00Z"}}`
+	if string(data) != want {
+		t.Errorf("unexpected JSON:\n got %s\nwant %s", data, want)
+	}
+
+	var decoded TextContent
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	a := decoded.Annotations
+	if a == nil {
+		t.Fatal("expected annotations to be decoded")
+	}
+	if len(a.Audience) != 2 || a.Audience[0] != RoleUser || a.Audience[1] != RoleAssistant {
+		t.Errorf("unexpected audience: %v", a.Audience)
+	}
+	if a.Priority == nil || *a.Priority != 0.9 {
+		t.Errorf("expected priority 0.9, got %v", a.Priority)
+	}
+	if a.LastModified == nil || !a.LastModified.Equal(modified) {
+		t.Errorf("expected lastModified %v, got %v", modified, a.LastModified)
+	}
+}
+
+func TestAnnotations_OmitsUnsetFields(t *testing.T) {
+	data, err := json.Marshal(NewAnnotations().WithPriority(0.2))
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	if string(data) != `{"priority":0.2}` {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+}
+
+func TestAnnotations_InvalidLastModified(t *testing.T) {
+	var a Annotations
+	if err := json.Unmarshal([]byte(`{"lastModified":"yesterday"}`), &a); err == nil {
+		t.Error("expected error for invalid lastModified")
+	}
+}
+
+func TestAnnotations_LastModifiedPrecision(t *testing.T) {
+	modified := time.Date(2025, 6, 18, 10, 30, 0, 123456789, time.UTC)
+	data, err := json.Marshal(NewAnnotations().WithLastModified(modified))
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	if string(data) != `{"lastModified":"2025-06-18T10:30:00.123456789Z"}` {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+
+	var a Annotations
+	if err := json.Unmarshal(data, &a); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if !a.LastModified.Equal(modified) {
+		t.Errorf("expected %v, got %v", modified, a.LastModified)
+	}
+}
+
+func TestAnnotations_LastModifiedISO8601(t *testing.T) {
+	for value, want := range map[string]time.Time{
+		"2025-06-18T10:30:00.5+02:00": time.Date(2025, 6, 18, 8, 30, 0, 5e8, time.UTC),
+		"2025-06-18T10:30:00+0200":    time.Date(2025, 6, 18, 8, 30, 0, 0, time.UTC),
+		"2025-06-18T10:30:00":         time.Date(2025, 6, 18, 10, 30, 0, 0, time.UTC),
+		"2025-06-18T10:30Z":           time.Date(2025, 6, 18, 10, 30, 0, 0, time.UTC),
+		"2025-06-18":                  time.Date(2025, 6, 18, 0, 0, 0, 0, time.UTC),
+	} {
+		var a Annotations
+		if err := json.Unmarshal([]byte(`{"lastModified":"`+value+`"}`), &a); err != nil {
+			t.Errorf("%s: unexpected error: %v", value, err)
+			continue
+		}
+		if !a.LastModified.Equal(want) {
+			t.Errorf("%s: expected %v, got %v", value, want, a.LastModified)
+		}
+	}
+}
+
+func TestAnnotations_ZeroPriority(t *testing.T) {
+	data, err := json.Marshal(NewAnnotations().WithPriority(0))
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	if string(data) != `{"priority":0}` {
+		t.Errorf("expected explicit zero priority, got %s", data)
+	}
+
+	var a Annotations
+	if err := json.Unmarshal([]byte(`{}`), &a); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if a.Priority != nil || a.LastModified != nil {
+		t.Errorf("expected unset fields to stay nil, got %+v", a)
+	}
+}
+
+func TestAnnotations_Accessors(t *testing.T) {
+	var unset *Annotations
+	if unset.PriorityOr(0.5) != 0.5 || !unset.IsFor(RoleAssistant) {
+		t.Error("expected nil annotations to use defaults")
+	}
+
+	a := NewAnnotations().WithAudience(RoleUser).WithPriority(1)
+	if a.PriorityOr(0.5) != 1 {
+		t.Errorf("expected priority 1, got %v", a.PriorityOr(0.5))
+	}
+	if !a.IsFor(RoleUser) || a.IsFor(RoleAssistant) {
+		t.Error("expected content to be intended only for the user")
+	}
+}
+
+func TestResource_Annotations(t *testing.T) {
+	resource := Resource{
+		URI:         "file:///README.md",
+		Name:        "readme",
+		Annotations: NewAnnotations().WithAudience(RoleAssistant).WithPriority(0.7),
+	}
+
+	data, err := json.Marshal(resource)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	var decoded Resource
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if decoded.Annotations.PriorityOr(0) != 0.7 || !decoded.Annotations.IsFor(RoleAssistant) {
+		t.Errorf("unexpected annotations: %+v", decoded.Annotations)
+	}
+}
+
+func TestToolAnnotations_JSONRoundTrip(t *testing.T) {
+	readOnly := true
+	destructive := false
+	original := Tool{
+		Name: "search",
+		Annotations: &ToolAnnotations{
+			ReadOnlyHint:    &readOnly,
+			DestructiveHint: &destructive,
+			Custom:          map[string]interface{}{"owner": "search", "readOnlyHint": false},
+		},
+	}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	var wire struct {
+		Annotations map[string]interface{} `json:"annotations"`
+	}
+	if err := json.Unmarshal(data, &wire); err != nil {
+		t.Fatalf("failed to unmarshal wire form: %v", err)
+	}
+	want := map[string]interface{}{"readOnlyHint": true, "destructiveHint": false, "owner": "search"}
+	if len(wire.Annotations) != len(want) {
+		t.Errorf("expected annotations %v, got %v", want, wire.Annotations)
+	}
+	for key, value := range want {
+		if wire.Annotations[key] != value {
+			t.Errorf("expected %s=%v, got %v", key, value, wire.Annotations[key])
+		}
+	}
+
+	var decoded Tool
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	a := decoded.Annotations
+	if !a.IsReadOnly() || a.DestructiveHint == nil || *a.DestructiveHint || a.IdempotentHint != nil {
+		t.Errorf("unexpected hints: %+v", a)
+	}
+	if len(a.Custom) != 1 || a.Custom["owner"] != "search" {
+		t.Errorf("expected only custom annotations in Custom, got %v", a.Custom)
+	}
+}
+
+func TestToolAnnotations_Empty(t *testing.T) {
+	var unset *ToolAnnotations
+	if !unset.IsEmpty() || unset.IsReadOnly() || unset.IsIdempotent() {
+		t.Error("expected nil tool annotations to be empty")
+	}
+	if (&ToolAnnotations{Custom: map[string]interface{}{"owner": "x"}}).IsEmpty() {
+		t.Error("expected custom annotations to make them non-empty")
+	}
+
+	data, err := json.Marshal(Tool{Name: "plain"})
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	if strings.Contains(string(data), "annotations") {
+		t.Errorf("expected annotations to be omitted, got %s", data)
+	}
+}
+
+func TestTool_DeprecatedHintFields(t *testing.T) {
+	readOnly := true
+	destructive := true
+	tool := Tool{
+		Name:            "legacy",
+		ReadOnlyHint:    &readOnly,
+		DestructiveHint: &destructive,
+		Annotations:     &ToolAnnotations{DestructiveHint: new(bool), Custom: map[string]interface{}{"owner": "x"}},
+	}
+
+	data, err := json.Marshal(tool)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	var wire map[string]interface{}
+	_ = json.Unmarshal(data, &wire)
+	if _, ok := wire["readOnlyHint"]; ok || wire["annotations"] == nil {
+		t.Errorf("expected hints only under annotations, got %s", data)
+	}
+
+	var decoded Tool
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	a := decoded.Annotations
+	if !a.IsReadOnly() || a.DestructiveHint == nil || *a.DestructiveHint || a.Custom["owner"] != "x" {
+		t.Errorf("expected deprecated hints merged under annotations, got %+v", a)
+	}
+	if tool.Annotations.ReadOnlyHint != nil {
+		t.Error("expected marshaling not to modify the tool's annotations")
+	}
+}
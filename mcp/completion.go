@@ -2,8 +2,9 @@ package mcp
 
 // CompletionRef represents a reference to what is being completed
 type CompletionRef struct {
-	Type string `json:"type"` // "ref/prompt" or "ref/resource"
-	Name string `json:"name"` // Name of the prompt or resource
+	Type string `json:"type"`          // "ref/prompt" or "ref/resource"
+	Name string `json:"name"`          // Name of the prompt or resource
+	URI  string `json:"uri,omitempty"` // Resource URI or URI template, as sent by spec clients for ref/resource
 }
 
 // CompletionArgument represents the argument being completed
@@ -14,8 +15,9 @@ type CompletionArgument struct {
 
 // CompleteRequest represents a request for completion suggestions
 type CompleteRequest struct {
-	Ref      CompletionRef      `json:"ref"`      // What is being completed
-	Argument CompletionArgument `json:"argument"` // Argument being completed
+	Ref      CompletionRef          `json:"ref"`             // What is being completed
+	Argument CompletionArgument     `json:"argument"`        // Argument being completed
+	Meta     map[string]interface{} `json:"_meta,omitempty"` // Metadata (2025-06-18)
 }
 
 // CompletionValue represents a single completion suggestion
@@ -34,4 +36,5 @@ type CompleteResult struct {
 		HasMore     *bool             `json:"hasMore,omitempty"`     // More results available
 		Completions []CompletionValue `json:"completions,omitempty"` // Rich completions
 	} `json:"completion"`
+	Meta map[string]interface{} `json:"_meta,omitempty"` // Metadata (2025-06-18)
 }
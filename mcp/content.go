@@ -0,0 +1,59 @@
+package mcp
+
+import (
+	"encoding/base64"
+	"strings"
+	"unicode/utf8"
+)
+
+// textMimeTypes lists non-text/* MIME types whose data is text
+var textMimeTypes = map[string]bool{
+	"application/json":       true,
+	"application/xml":        true,
+	"application/javascript": true,
+	"application/yaml":       true,
+	"application/x-yaml":     true,
+	"application/toml":       true,
+	"application/sql":        true,
+	"image/svg+xml":          true,
+}
+
+// NewEmbeddedResource creates content embedding a resource's data in a tool
+// result. Data with a textual MIME type is embedded as text, anything else
+// as a base64 blob.
+func NewEmbeddedResource(uri, mimeType string, data []byte) ResourceContent {
+	rc := ResourceContent{Type: "resource", URI: uri, MimeType: mimeType}
+	if isTextMimeType(mimeType, data) {
+		rc.Text = string(data)
+	} else {
+		rc.Blob = base64.StdEncoding.EncodeToString(data)
+	}
+	return rc
+}
+
+// NewResourceLink creates content linking to a resource without embedding
+// its data. annotations may be nil.
+func NewResourceLink(resource Resource, annotations *Annotations) ResourceLinkContent {
+	return ResourceLinkContent{
+		Type:        "resource",
+		Resource:    resource,
+		Annotations: annotations,
+	}
+}
+
+// isTextMimeType reports whether data of the MIME type is text. Without a
+// MIME type, valid UTF-8 is treated as text.
+func isTextMimeType(mimeType string, data []byte) bool {
+	mimeType, _, _ = strings.Cut(mimeType, ";")
+	mimeType = strings.TrimSpace(strings.ToLower(mimeType))
+
+	switch {
+	case mimeType == "":
+		return utf8.Valid(data)
+	case strings.HasPrefix(mimeType, "text/"), textMimeTypes[mimeType]:
+		return true
+	case strings.HasSuffix(mimeType, "+json"), strings.HasSuffix(mimeType, "+xml"):
+		return true
+	}
+	return false
+}
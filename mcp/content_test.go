@@ -0,0 +1,65 @@
+package mcp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+)
+
+func TestNewEmbeddedResource(t *testing.T) {
+	tests := []struct {
+		name     string
+		mimeType string
+		data     []byte
+		wantText bool
+	}{
+		{"text", "text/csv", []byte("a,b\n1,2\n"), true},
+		{"json with charset", "application/json; charset=utf-8", []byte(`{"a":1}`), true},
+		{"structured suffix", "application/ld+json", []byte(`{}`), true},
+		{"binary", "image/png", []byte{0x89, 'P', 'N', 'G'}, false},
+		{"unknown utf8", "", []byte("plain"), true},
+		{"unknown binary", "", []byte{0xff, 0xfe}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := NewEmbeddedResource("file:///out", tt.mimeType, tt.data)
+			if rc.Type != "resource" || rc.URI != "file:///out" || rc.MimeType != tt.mimeType {
+				t.Errorf("unexpected resource content: %+v", rc)
+			}
+
+			if tt.wantText {
+				if rc.Text != string(tt.data) || rc.Blob != "" {
+					t.Errorf("expected text embedding, got %+v", rc)
+				}
+				return
+			}
+			decoded, err := base64.StdEncoding.DecodeString(rc.Blob)
+			if err != nil || string(decoded) != string(tt.data) || rc.Text != "" {
+				t.Errorf("expected blob embedding, got %+v", rc)
+			}
+		})
+	}
+}
+
+func TestNewResourceLink(t *testing.T) {
+	resource := Resource{URI: "file:///report.pdf", Name: "report", MimeType: "application/pdf"}
+	link := NewResourceLink(resource, NewAnnotations().WithAudience(RoleUser))
+
+	data, err := json.Marshal(link)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+
+	var decoded CallToolResult
+	if err := json.Unmarshal([]byte(`{"content":[`+string(data)+`]}`), &decoded); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	got, ok := decoded.Content[0].(ResourceLinkContent)
+	if !ok {
+		t.Fatalf("expected ResourceLinkContent, got %T", decoded.Content[0])
+	}
+	if got.Resource.URI != resource.URI || got.Annotations == nil || got.Annotations.Audience[0] != RoleUser {
+		t.Errorf("unexpected link: %+v", got)
+	}
+}
@@ -1,7 +1,10 @@
 // Package mcp defines core types and interfaces for the Model Context Protocol.
 package mcp
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 // ErrorCode represents JSON-RPC error codes
 type ErrorCode int
@@ -15,6 +18,41 @@ const (
 	InternalError  ErrorCode = -32603
 )
 
+// MCP-specific error codes
+const (
+	ResourceNotFound ErrorCode = -32002
+	// MessageTooLarge reports a message or result over the receiver's size
+	// limit, the counterpart of HTTP 413 Content Too Large
+	MessageTooLarge ErrorCode = -32003
+	// Unauthenticated reports a request without valid credentials, the
+	// counterpart of HTTP 401 Unauthorized
+	Unauthenticated ErrorCode = -32004
+)
+
+// String returns the name of the error code
+func (c ErrorCode) String() string {
+	switch c {
+	case ParseError:
+		return "ParseError"
+	case InvalidRequest:
+		return "InvalidRequest"
+	case MethodNotFound:
+		return "MethodNotFound"
+	case InvalidParams:
+		return "InvalidParams"
+	case InternalError:
+		return "InternalError"
+	case ResourceNotFound:
+		return "ResourceNotFound"
+	case MessageTooLarge:
+		return "MessageTooLarge"
+	case Unauthenticated:
+		return "Unauthenticated"
+	default:
+		return fmt.Sprintf("ErrorCode(%d)", int(c))
+	}
+}
+
 // Error represents an MCP protocol error
 type Error struct {
 	Code    ErrorCode
@@ -26,6 +64,87 @@ func (e *Error) Error() string {
 	return fmt.Sprintf("MCP error %d: %s", e.Code, e.Message)
 }
 
+// RPCError converts the error to its JSON-RPC wire form
+func (e *Error) RPCError() *RPCError {
+	return &RPCError{Code: int(e.Code), Message: e.Message, Data: e.Data}
+}
+
+// NewParseError creates an error for a message that is not valid JSON
+func NewParseError(message string) *Error {
+	return &Error{Code: ParseError, Message: message}
+}
+
+// NewInvalidRequest creates an error for a message that is not a valid request
+func NewInvalidRequest(message string) *Error {
+	return &Error{Code: InvalidRequest, Message: message}
+}
+
+// NewMethodNotFound creates an error for an unknown or unsupported method
+func NewMethodNotFound(method string) *Error {
+	return &Error{Code: MethodNotFound, Message: "method not found: " + method}
+}
+
+// NewInvalidParams creates an error for invalid request parameters
+func NewInvalidParams(message string) *Error {
+	return &Error{Code: InvalidParams, Message: message}
+}
+
+// NewInternalError creates an error for a failure while handling a request
+func NewInternalError(message string) *Error {
+	return &Error{Code: InternalError, Message: message}
+}
+
+// NewResourceNotFound creates an error for a resource that does not exist,
+// carrying its URI in the error data as the spec requires
+func NewResourceNotFound(uri string) *Error {
+	return &Error{
+		Code:    ResourceNotFound,
+		Message: "resource not found: " + uri,
+		Data:    map[string]interface{}{"uri": uri},
+	}
+}
+
+// NewMessageTooLarge creates an error for a message or result over a size
+// limit of limit bytes, carrying the limit in the error data
+func NewMessageTooLarge(what string, limit int64) *Error {
+	return &Error{
+		Code:    MessageTooLarge,
+		Message: fmt.Sprintf("%s exceeds the maximum size of %d bytes", what, limit),
+		Data:    map[string]interface{}{"limit": limit},
+	}
+}
+
+// NewUnauthenticated creates an error for a request that needs credentials
+func NewUnauthenticated(message string) *Error {
+	return &Error{Code: Unauthenticated, Message: message}
+}
+
+// ToError converts err to a protocol error. An *Error in the chain is
+// returned as is; missing resources get ResourceNotFound, unknown tools and
+// prompts and validation failures InvalidParams, and anything else
+// InternalError.
+func ToError(err error) *Error {
+	var mcpErr *Error
+	if errors.As(err, &mcpErr) {
+		return mcpErr
+	}
+
+	var notFound *NotFoundError
+	if errors.As(err, &notFound) {
+		if notFound.Type == "resource" {
+			return NewResourceNotFound(notFound.Name)
+		}
+		return NewInvalidParams(notFound.Error())
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return NewInvalidParams(err.Error())
+	}
+
+	return NewInternalError(err.Error())
+}
+
 // NotFoundError represents a not found error
 type NotFoundError struct {
 	Type string // "tool", "resource", "prompt"
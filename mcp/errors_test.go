@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -109,3 +110,52 @@ func TestValidationError_AsError(t *testing.T) {
 		t.Errorf("expected field 'age', got '%s'", validationErr.Field)
 	}
 }
+
+func TestToError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    ErrorCode
+		message string
+	}{
+		{"mcp error kept", NewInvalidParams("bad region"), InvalidParams, "bad region"},
+		{"wrapped mcp error", fmt.Errorf("call: %w", NewMethodNotFound("x")), MethodNotFound, "method not found: x"},
+		{"missing resource", &NotFoundError{Type: "resource", Name: "config://app"}, ResourceNotFound, "resource not found: config://app"},
+		{"unknown tool", &NotFoundError{Type: "tool", Name: "add"}, InvalidParams, "tool not found: add"},
+		{"validation", &ValidationError{Field: "a", Message: "is required"}, InvalidParams, "validation error on a: is required"},
+		{"other", errors.New("boom"), InternalError, "boom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ToError(tt.err)
+			if got.Code != tt.code || got.Message != tt.message {
+				t.Errorf("ToError() = %d %q, want %d %q", got.Code, got.Message, tt.code, tt.message)
+			}
+		})
+	}
+}
+
+func TestNewResourceNotFound(t *testing.T) {
+	rpcErr := NewResourceNotFound("file:///missing").RPCError()
+
+	if rpcErr.Code != -32002 {
+		t.Errorf("expected code -32002, got %d", rpcErr.Code)
+	}
+	data, ok := rpcErr.Data.(map[string]interface{})
+	if !ok || data["uri"] != "file:///missing" {
+		t.Errorf("expected uri in error data, got %v", rpcErr.Data)
+	}
+}
+
+func TestErrorCode_String(t *testing.T) {
+	if InvalidParams.String() != "InvalidParams" || ResourceNotFound.String() != "ResourceNotFound" {
+		t.Errorf("unexpected names: %s, %s", InvalidParams, ResourceNotFound)
+	}
+	if MessageTooLarge.String() != "MessageTooLarge" || Unauthenticated.String() != "Unauthenticated" {
+		t.Errorf("unexpected names: %s, %s", MessageTooLarge, Unauthenticated)
+	}
+	if ErrorCode(-1).String() != "ErrorCode(-1)" {
+		t.Errorf("unexpected name for unknown code: %s", ErrorCode(-1))
+	}
+}
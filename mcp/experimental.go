@@ -0,0 +1,29 @@
+package mcp
+
+import "encoding/json"
+
+// Experiments declares non-standard capabilities by name. Each value is the
+// experiment's settings object, so extensions can be negotiated without
+// changes to the capability types.
+type Experiments map[string]interface{}
+
+// Has reports whether the named experiment is declared
+func (e Experiments) Has(name string) bool {
+	_, ok := e[name]
+	return ok
+}
+
+// Decode decodes the named experiment's settings into v, reporting false if
+// the experiment is not declared
+func (e Experiments) Decode(name string, v interface{}) (bool, error) {
+	settings, ok := e[name]
+	if !ok {
+		return false, nil
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return true, err
+	}
+	return true, json.Unmarshal(data, v)
+}
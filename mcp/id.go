@@ -0,0 +1,154 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// Request IDs are strings or numbers. Decoded messages hold numeric IDs as
+// json.Number so IDs beyond 2^53 keep their precision, and responses echo
+// them back unchanged.
+
+// IDKey returns a canonical key for a request ID, so IDs decoded from JSON
+// match the Go values they were created from: 7, int64(7), float64(7) and
+// json.Number("7") share a key, while the string "7" does not.
+func IDKey(id interface{}) string {
+	switch v := id.(type) {
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(v)
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return strconv.FormatInt(n, 10)
+		}
+		if f, err := v.Float64(); err == nil {
+			return formatFloatID(f)
+		}
+		return v.String()
+	case int:
+		return strconv.FormatInt(int64(v), 10)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		return formatFloatID(v)
+	case float32:
+		return formatFloatID(float64(v))
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// formatFloatID formats whole floats like integers, so float64(7) and 7 match
+func formatFloatID(f float64) string {
+	if f == float64(int64(f)) {
+		return strconv.FormatInt(int64(f), 10)
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// IDInt64 returns a numeric request ID as an int64
+func IDInt64(id interface{}) (int64, bool) {
+	switch v := id.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n, true
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return 0, false
+		}
+		return IDInt64(f)
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	case float64:
+		if v != float64(int64(v)) {
+			return 0, false
+		}
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// decodeID decodes a raw JSON ID, keeping numbers as json.Number
+func decodeID(raw json.RawMessage) (interface{}, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var id interface{}
+	if err := decoder.Decode(&id); err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+// messageJSON is the wire format of Message with a raw ID
+type messageJSON struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. Responses always carry an ID, which
+// is null when the request's ID could not be determined.
+func (m Message) MarshalJSON() ([]byte, error) {
+	wire := messageJSON{
+		JSONRPC: m.JSONRPC,
+		Method:  m.Method,
+		Params:  m.Params,
+		Result:  m.Result,
+		Error:   m.Error,
+	}
+
+	switch {
+	case m.ID != nil:
+		id, err := json.Marshal(m.ID)
+		if err != nil {
+			return nil, err
+		}
+		wire.ID = id
+	case m.Method == "" && (m.Result != nil || m.Error != nil):
+		wire.ID = json.RawMessage("null")
+	}
+
+	return json.Marshal(wire)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding numeric IDs as
+// json.Number
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var wire messageJSON
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+
+	id, err := decodeID(wire.ID)
+	if err != nil {
+		return err
+	}
+
+	*m = Message{
+		JSONRPC: wire.JSONRPC,
+		ID:      id,
+		Method:  wire.Method,
+		Params:  wire.Params,
+		Result:  wire.Result,
+		Error:   wire.Error,
+	}
+	return nil
+}
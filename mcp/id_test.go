@@ -0,0 +1,109 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMessage_IDRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		wantID string // Raw JSON of the re-encoded ID, "" when absent
+	}{
+		{"string", `{"jsonrpc":"2.0","id":"req-1","method":"ping"}`, `"req-1"`},
+		{"numeric string", `{"jsonrpc":"2.0","id":"7","method":"ping"}`, `"7"`},
+		{"small number", `{"jsonrpc":"2.0","id":7,"method":"ping"}`, `7`},
+		{"zero", `{"jsonrpc":"2.0","id":0,"method":"ping"}`, `0`},
+		{"negative", `{"jsonrpc":"2.0","id":-3,"method":"ping"}`, `-3`},
+		{"beyond float64 precision", `{"jsonrpc":"2.0","id":9007199254740993,"method":"ping"}`, `9007199254740993`},
+		{"null error response", `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"invalid"}}`, `null`},
+		{"notification", `{"jsonrpc":"2.0","method":"notifications/initialized"}`, ``},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var msg Message
+			if err := json.Unmarshal([]byte(tt.input), &msg); err != nil {
+				t.Fatalf("unmarshal failed: %v", err)
+			}
+
+			data, err := json.Marshal(&msg)
+			if err != nil {
+				t.Fatalf("marshal failed: %v", err)
+			}
+
+			var raw map[string]json.RawMessage
+			if err := json.Unmarshal(data, &raw); err != nil {
+				t.Fatalf("re-decode failed: %v", err)
+			}
+			id, present := raw["id"]
+			if tt.wantID == "" {
+				if present {
+					t.Errorf("expected no id, got %s", id)
+				}
+				return
+			}
+			if string(id) != tt.wantID {
+				t.Errorf("expected id %s, got %s", tt.wantID, id)
+			}
+		})
+	}
+}
+
+func TestMessage_NullIDErrorResponse(t *testing.T) {
+	msg := &Message{JSONRPC: "2.0", Error: &RPCError{Code: int(ParseError), Message: "parse error"}}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	want := `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"parse error"}}`
+	if string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+}
+
+func TestIDKey(t *testing.T) {
+	tests := []struct {
+		a, b interface{}
+		same bool
+	}{
+		{7, json.Number("7"), true},
+		{int64(7), float64(7), true},
+		{json.Number("7.0"), 7, true},
+		{"7", 7, false},
+		{"abc", "abc", true},
+		{json.Number("9007199254740993"), json.Number("9007199254740992"), false},
+		{nil, "null", false},
+	}
+
+	for _, tt := range tests {
+		if got := IDKey(tt.a) == IDKey(tt.b); got != tt.same {
+			t.Errorf("IDKey(%#v) == IDKey(%#v): expected %v, got %v", tt.a, tt.b, tt.same, got)
+		}
+	}
+}
+
+func TestIDInt64(t *testing.T) {
+	tests := []struct {
+		id   interface{}
+		want int64
+		ok   bool
+	}{
+		{json.Number("42"), 42, true},
+		{json.Number("42.0"), 42, true},
+		{float64(42), 42, true},
+		{42, 42, true},
+		{json.Number("4.5"), 0, false},
+		{"42", 0, false},
+		{nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := IDInt64(tt.id)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("IDInt64(%#v) = %d, %v; expected %d, %v", tt.id, got, ok, tt.want, tt.ok)
+		}
+	}
+}
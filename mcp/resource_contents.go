@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// ResourceContents is one item of a resources/read result: either
+// TextResourceContents or BlobResourceContents
+type ResourceContents interface {
+	ResourceURI() string
+	// Bytes returns the contents' data, decoding blobs from base64
+	Bytes() ([]byte, error)
+}
+
+// TextResourceContents holds the contents of a text resource
+type TextResourceContents struct {
+	URI      string                 `json:"uri"`
+	MimeType string                 `json:"mimeType,omitempty"`
+	Text     string                 `json:"text"`
+	Meta     map[string]interface{} `json:"_meta,omitempty"`
+}
+
+// ResourceURI returns the URI of the resource
+func (t TextResourceContents) ResourceURI() string {
+	return t.URI
+}
+
+// Bytes returns the text as bytes
+func (t TextResourceContents) Bytes() ([]byte, error) {
+	return []byte(t.Text), nil
+}
+
+// BlobResourceContents holds the contents of a binary resource
+type BlobResourceContents struct {
+	URI      string                 `json:"uri"`
+	MimeType string                 `json:"mimeType,omitempty"`
+	Blob     string                 `json:"blob"` // Base64-encoded binary data
+	Meta     map[string]interface{} `json:"_meta,omitempty"`
+}
+
+// NewBlobResourceContents creates blob contents, base64-encoding data
+func NewBlobResourceContents(uri, mimeType string, data []byte) BlobResourceContents {
+	return BlobResourceContents{
+		URI:      uri,
+		MimeType: mimeType,
+		Blob:     base64.StdEncoding.EncodeToString(data),
+	}
+}
+
+// ResourceURI returns the URI of the resource
+func (b BlobResourceContents) ResourceURI() string {
+	return b.URI
+}
+
+// Bytes decodes the base64 blob
+func (b BlobResourceContents) Bytes() ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(b.Blob)
+	if err != nil {
+		return nil, fmt.Errorf("invalid blob for %s: %w", b.URI, err)
+	}
+	return data, nil
+}
+
+// UnmarshalResourceContents decodes a resources/read contents item, which is
+// a blob when it has a blob field and text otherwise
+func UnmarshalResourceContents(data json.RawMessage) (ResourceContents, error) {
+	var probe struct {
+		Blob *string `json:"blob"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+
+	if probe.Blob != nil {
+		var blob BlobResourceContents
+		if err := json.Unmarshal(data, &blob); err != nil {
+			return nil, err
+		}
+		return blob, nil
+	}
+
+	var text TextResourceContents
+	if err := json.Unmarshal(data, &text); err != nil {
+		return nil, err
+	}
+	return text, nil
+}
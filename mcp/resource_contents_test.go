@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBlobResourceContents_RoundTrip(t *testing.T) {
+	data := []byte{0x00, 0x01, 0xfe, 0xff}
+	blob := NewBlobResourceContents("file:///data.bin", "application/octet-stream", data)
+
+	encoded, err := json.Marshal(blob)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+
+	decoded, err := UnmarshalResourceContents(encoded)
+	if err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	got, ok := decoded.(BlobResourceContents)
+	if !ok {
+		t.Fatalf("expected BlobResourceContents, got %T", decoded)
+	}
+	if got.ResourceURI() != "file:///data.bin" || got.MimeType != "application/octet-stream" {
+		t.Errorf("unexpected blob contents: %+v", got)
+	}
+
+	bytes, err := got.Bytes()
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if string(bytes) != string(data) {
+		t.Errorf("Bytes() = %v, want %v", bytes, data)
+	}
+}
+
+func TestUnmarshalResourceContents_Text(t *testing.T) {
+	decoded, err := UnmarshalResourceContents(json.RawMessage(`{"uri":"config://app","text":""}`))
+	if err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if _, ok := decoded.(TextResourceContents); !ok {
+		t.Errorf("expected TextResourceContents, got %T", decoded)
+	}
+}
+
+func TestBlobResourceContents_InvalidBlob(t *testing.T) {
+	blob := BlobResourceContents{URI: "file:///x", Blob: "not base64!"}
+	if _, err := blob.Bytes(); err == nil {
+		t.Error("expected an error for an invalid blob")
+	}
+}
@@ -0,0 +1,44 @@
+package mcp
+
+import "strings"
+
+// ResourceFilter narrows a resources/list request. Empty fields match every
+// resource.
+type ResourceFilter struct {
+	Scheme string   `json:"scheme,omitempty"` // URI scheme, such as "file"
+	Prefix string   `json:"prefix,omitempty"` // URI prefix
+	Tags   []string `json:"tags,omitempty"`   // Tags the resource must all carry
+}
+
+// IsZero reports whether the filter matches everything
+func (f ResourceFilter) IsZero() bool {
+	return f.Scheme == "" && f.Prefix == "" && len(f.Tags) == 0
+}
+
+// Matches reports whether a resource with the given URI and tags passes the
+// filter
+func (f ResourceFilter) Matches(uri string, tags []string) bool {
+	if f.Scheme != "" {
+		scheme, _, ok := strings.Cut(uri, ":")
+		if !ok || !strings.EqualFold(scheme, f.Scheme) {
+			return false
+		}
+	}
+	if f.Prefix != "" && !strings.HasPrefix(uri, f.Prefix) {
+		return false
+	}
+
+	for _, want := range f.Tags {
+		found := false
+		for _, tag := range tags {
+			if tag == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
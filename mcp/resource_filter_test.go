@@ -0,0 +1,31 @@
+package mcp
+
+import "testing"
+
+func TestResourceFilter_Matches(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter ResourceFilter
+		uri    string
+		tags   []string
+		want   bool
+	}{
+		{"zero", ResourceFilter{}, "file:///a", nil, true},
+		{"scheme", ResourceFilter{Scheme: "file"}, "file:///a", nil, true},
+		{"scheme case", ResourceFilter{Scheme: "FILE"}, "file:///a", nil, true},
+		{"other scheme", ResourceFilter{Scheme: "db"}, "file:///a", nil, false},
+		{"prefix", ResourceFilter{Prefix: "file:///docs/"}, "file:///docs/a.md", nil, true},
+		{"other prefix", ResourceFilter{Prefix: "file:///docs/"}, "file:///src/a.go", nil, false},
+		{"tags", ResourceFilter{Tags: []string{"public", "docs"}}, "file:///a", []string{"docs", "public", "v2"}, true},
+		{"missing tag", ResourceFilter{Tags: []string{"public", "docs"}}, "file:///a", []string{"docs"}, false},
+		{"combined", ResourceFilter{Scheme: "file", Tags: []string{"docs"}}, "db://a", []string{"docs"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(tt.uri, tt.tags); got != tt.want {
+				t.Errorf("Matches(%q, %v) = %v, want %v", tt.uri, tt.tags, got, tt.want)
+			}
+		})
+	}
+}
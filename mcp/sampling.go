@@ -9,14 +9,16 @@ type SamplingMessage struct {
 
 // SamplingContent represents the content of a sampling message
 type SamplingContent struct {
-	Type string `json:"type"`           // "text" or "image"
-	Text string `json:"text,omitempty"` // For text content
-	Data string `json:"data,omitempty"` // For image content (base64)
+	Type     string `json:"type"`               // "text", "image", or "audio"
+	Text     string `json:"text,omitempty"`     // For text content
+	Data     string `json:"data,omitempty"`     // For image and audio content (base64)
+	MimeType string `json:"mimeType,omitempty"` // For image and audio content (e.g., "image/png")
 }
 
 // ModelPreferences specifies preferences for model selection
 type ModelPreferences struct {
 	Hints                []ModelHint `json:"hints,omitempty"`                // Suggested models
+	CostPriority         *float64    `json:"costPriority,omitempty"`         // 0-1, higher = prefer cheaper models
 	IntelligencePriority *float64    `json:"intelligencePriority,omitempty"` // 0-1, higher = prefer more capable models
 	SpeedPriority        *float64    `json:"speedPriority,omitempty"`        // 0-1, higher = prefer faster models
 }
@@ -28,23 +30,33 @@ type ModelHint struct {
 
 // CreateMessageRequest represents a request to create a message via sampling
 type CreateMessageRequest struct {
-	Messages         []SamplingMessage `json:"messages"`                   // Conversation messages
-	ModelPreferences *ModelPreferences `json:"modelPreferences,omitempty"` // Model selection preferences
-	SystemPrompt     string            `json:"systemPrompt,omitempty"`     // System prompt to guide model behavior
-	MaxTokens        *int              `json:"maxTokens,omitempty"`        // Maximum tokens in response
-	Temperature      *float64          `json:"temperature,omitempty"`      // Sampling temperature
-	StopSequences    []string          `json:"stopSequences,omitempty"`    // Stop generation at these sequences
-	Metadata         map[string]string `json:"metadata,omitempty"`         // Additional metadata
+	Messages         []SamplingMessage      `json:"messages"`                   // Conversation messages
+	ModelPreferences *ModelPreferences      `json:"modelPreferences,omitempty"` // Model selection preferences
+	SystemPrompt     string                 `json:"systemPrompt,omitempty"`     // System prompt to guide model behavior
+	IncludeContext   string                 `json:"includeContext,omitempty"`   // "none", "thisServer", or "allServers"
+	MaxTokens        *int                   `json:"maxTokens,omitempty"`        // Maximum tokens in response
+	Temperature      *float64               `json:"temperature,omitempty"`      // Sampling temperature
+	StopSequences    []string               `json:"stopSequences,omitempty"`    // Stop generation at these sequences
+	Metadata         map[string]interface{} `json:"metadata,omitempty"`         // Provider-specific metadata passed to the LLM
+	Meta             map[string]interface{} `json:"_meta,omitempty"`            // Protocol metadata (2025-06-18)
 }
 
 // CreateMessageResult represents the result of a sampling request
 type CreateMessageResult struct {
-	Role       string          `json:"role"`                 // "assistant"
-	Content    SamplingContent `json:"content"`              // Generated content
-	Model      string          `json:"model"`                // Actual model used
-	StopReason string          `json:"stopReason,omitempty"` // Reason for stopping (e.g., "endTurn", "stopSequence", "maxTokens")
+	Role       string                 `json:"role"`                 // "assistant"
+	Content    SamplingContent        `json:"content"`              // Generated content
+	Model      string                 `json:"model"`                // Actual model used
+	StopReason string                 `json:"stopReason,omitempty"` // Reason for stopping (e.g., "endTurn", "stopSequence", "maxTokens")
+	Meta       map[string]interface{} `json:"_meta,omitempty"`      // Metadata (2025-06-18)
 }
 
+// Context inclusion values for CreateMessageRequest.IncludeContext
+const (
+	IncludeContextNone       = "none"       // Do not include any MCP context
+	IncludeContextThisServer = "thisServer" // Include context from the requesting server
+	IncludeContextAllServers = "allServers" // Include context from all connected servers
+)
+
 // Common stop reasons
 const (
 	StopReasonEndTurn      = "endTurn"      // Natural end of model's turn
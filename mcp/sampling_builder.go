@@ -4,16 +4,49 @@ package mcp
 
 // AddMessage adds a message to the request
 func (r *CreateMessageRequest) AddMessage(role, text string) *CreateMessageRequest {
+	return r.AddContent(role, SamplingContent{
+		Type: "text",
+		Text: text,
+	})
+}
+
+// AddContent adds a message with arbitrary content to the request
+func (r *CreateMessageRequest) AddContent(role string, content SamplingContent) *CreateMessageRequest {
 	r.Messages = append(r.Messages, SamplingMessage{
-		Role: role,
-		Content: SamplingContent{
-			Type: "text",
-			Text: text,
-		},
+		Role:    role,
+		Content: content,
 	})
 	return r
 }
 
+// AddImage adds an image message (base64 data) to the request
+func (r *CreateMessageRequest) AddImage(role, data, mimeType string) *CreateMessageRequest {
+	return r.AddContent(role, SamplingContent{
+		Type:     "image",
+		Data:     data,
+		MimeType: mimeType,
+	})
+}
+
+// AddAudio adds an audio message (base64 data) to the request
+func (r *CreateMessageRequest) AddAudio(role, data, mimeType string) *CreateMessageRequest {
+	return r.AddContent(role, SamplingContent{
+		Type:     "audio",
+		Data:     data,
+		MimeType: mimeType,
+	})
+}
+
+// AddUserImage adds a user image message
+func (r *CreateMessageRequest) AddUserImage(data, mimeType string) *CreateMessageRequest {
+	return r.AddImage("user", data, mimeType)
+}
+
+// AddUserAudio adds a user audio message
+func (r *CreateMessageRequest) AddUserAudio(data, mimeType string) *CreateMessageRequest {
+	return r.AddAudio("user", data, mimeType)
+}
+
 // AddUserMessage adds a user message
 func (r *CreateMessageRequest) AddUserMessage(text string) *CreateMessageRequest {
 	return r.AddMessage("user", text)
@@ -30,6 +63,12 @@ func (r *CreateMessageRequest) WithSystemPrompt(prompt string) *CreateMessageReq
 	return r
 }
 
+// WithIncludeContext sets which MCP context the client should attach to the request
+func (r *CreateMessageRequest) WithIncludeContext(include string) *CreateMessageRequest {
+	r.IncludeContext = include
+	return r
+}
+
 // WithMaxTokens sets the maximum tokens for the response
 func (r *CreateMessageRequest) WithMaxTokens(tokens int) *CreateMessageRequest {
 	r.MaxTokens = &tokens
@@ -42,12 +81,33 @@ func (r *CreateMessageRequest) WithTemperature(temp float64) *CreateMessageReque
 	return r
 }
 
+// WithStopSequences sets sequences that stop generation
+func (r *CreateMessageRequest) WithStopSequences(sequences ...string) *CreateMessageRequest {
+	r.StopSequences = sequences
+	return r
+}
+
+// WithMetadata adds a provider-specific metadata entry
+func (r *CreateMessageRequest) WithMetadata(key string, value interface{}) *CreateMessageRequest {
+	if r.Metadata == nil {
+		r.Metadata = make(map[string]interface{})
+	}
+	r.Metadata[key] = value
+	return r
+}
+
 // WithModelPreferences sets model selection preferences
 func (r *CreateMessageRequest) WithModelPreferences(prefs *ModelPreferences) *CreateMessageRequest {
 	r.ModelPreferences = prefs
 	return r
 }
 
+// WithCostPriority sets cost priority (0-1)
+func (p *ModelPreferences) WithCostPriority(priority float64) *ModelPreferences {
+	p.CostPriority = &priority
+	return p
+}
+
 // WithIntelligencePriority sets intelligence priority (0-1)
 func (p *ModelPreferences) WithIntelligencePriority(priority float64) *ModelPreferences {
 	p.IntelligencePriority = &priority
@@ -127,3 +127,97 @@ func TestStopReasons(t *testing.T) {
 		}
 	}
 }
+
+func TestCreateMessageRequest_IncludeContext(t *testing.T) {
+	req := (&CreateMessageRequest{}).
+		AddUserMessage("Summarize the project").
+		WithIncludeContext(IncludeContextThisServer)
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("failed to marshal request: %v", err)
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("failed to unmarshal request: %v", err)
+	}
+
+	if raw["includeContext"] != "thisServer" {
+		t.Errorf("includeContext mismatch: got %v", raw["includeContext"])
+	}
+}
+
+func TestCreateMessageRequest_SpecFields(t *testing.T) {
+	req := (&CreateMessageRequest{}).
+		AddUserMessage("Write a haiku").
+		WithStopSequences("\n\n", "END").
+		WithMetadata("provider", map[string]interface{}{"topK": 40}).
+		WithModelPreferences((&ModelPreferences{}).WithCostPriority(0.8).WithSpeedPriority(0.2))
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("failed to marshal request: %v", err)
+	}
+
+	var decoded CreateMessageRequest
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal request: %v", err)
+	}
+
+	if len(decoded.StopSequences) != 2 || decoded.StopSequences[1] != "END" {
+		t.Errorf("stopSequences mismatch: %v", decoded.StopSequences)
+	}
+	provider, _ := decoded.Metadata["provider"].(map[string]interface{})
+	if provider["topK"] != float64(40) {
+		t.Errorf("metadata mismatch: %v", decoded.Metadata)
+	}
+	prefs := decoded.ModelPreferences
+	if prefs == nil || prefs.CostPriority == nil || *prefs.CostPriority != 0.8 {
+		t.Errorf("costPriority mismatch: %+v", prefs)
+	}
+}
+
+func TestCreateMessageRequest_MultimodalContent(t *testing.T) {
+	req := (&CreateMessageRequest{}).
+		AddUserMessage("What is in this picture?").
+		AddUserImage("iVBORw0KGgo=", "image/png").
+		AddUserAudio("UklGRg==", "audio/wav")
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("failed to marshal request: %v", err)
+	}
+
+	var decoded CreateMessageRequest
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal request: %v", err)
+	}
+
+	if len(decoded.Messages) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(decoded.Messages))
+	}
+
+	image := decoded.Messages[1].Content
+	if image.Type != "image" || image.Data != "iVBORw0KGgo=" || image.MimeType != "image/png" {
+		t.Errorf("image content mismatch: %+v", image)
+	}
+
+	audio := decoded.Messages[2].Content
+	if audio.Type != "audio" || audio.MimeType != "audio/wav" {
+		t.Errorf("audio content mismatch: %+v", audio)
+	}
+}
+
+func TestCreateMessageResult_ImageContent(t *testing.T) {
+	data := []byte(`{"role":"assistant","content":{"type":"image","data":"R0lGOD=","mimeType":"image/gif"},"model":"m"}`)
+
+	var result CreateMessageResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("failed to unmarshal result: %v", err)
+	}
+
+	if result.Content.Type != "image" || result.Content.MimeType != "image/gif" {
+		t.Errorf("result content mismatch: %+v", result.Content)
+	}
+}
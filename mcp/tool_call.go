@@ -0,0 +1,65 @@
+package mcp
+
+import "encoding/json"
+
+// CallToolRequest is the params of a tools/call request
+type CallToolRequest struct {
+	Name      string                 `json:"name"`
+	Arguments json.RawMessage        `json:"arguments,omitempty"`
+	Meta      map[string]interface{} `json:"_meta,omitempty"` // Progress token and client-supplied metadata
+}
+
+// ProgressToken returns the token the client supplied for progress
+// notifications, or nil
+func (r *CallToolRequest) ProgressToken() ProgressToken {
+	if r == nil {
+		return nil
+	}
+	return r.Meta["progressToken"]
+}
+
+// IdempotencyKeyMeta is the _meta field carrying a tools/call request's
+// idempotency key
+const IdempotencyKeyMeta = "idempotencyKey"
+
+// IdempotencyKey returns the key the client supplied so retries of the
+// request are executed at most once, or ""
+func (r *CallToolRequest) IdempotencyKey() string {
+	if r == nil {
+		return ""
+	}
+	key, _ := r.Meta[IdempotencyKeyMeta].(string)
+	return key
+}
+
+// CallToolResult is the result of a tools/call request
+type CallToolResult struct {
+	Content           []Content              `json:"content"`
+	StructuredContent interface{}            `json:"structuredContent,omitempty"` // 2025-06-18
+	IsError           bool                   `json:"isError,omitempty"`
+	Meta              map[string]interface{} `json:"_meta,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding content blocks by type
+func (r *CallToolResult) UnmarshalJSON(data []byte) error {
+	var temp struct {
+		Content           []json.RawMessage      `json:"content"`
+		StructuredContent interface{}            `json:"structuredContent,omitempty"`
+		IsError           bool                   `json:"isError,omitempty"`
+		Meta              map[string]interface{} `json:"_meta,omitempty"`
+	}
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return err
+	}
+
+	content, err := unmarshalContents(temp.Content)
+	if err != nil {
+		return err
+	}
+
+	r.Content = content
+	r.StructuredContent = temp.StructuredContent
+	r.IsError = temp.IsError
+	r.Meta = temp.Meta
+	return nil
+}
@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCallToolResult_RoundTrip(t *testing.T) {
+	original := CallToolResult{
+		Content: []Content{
+			TextContent{Type: "text", Text: "hello"},
+			ImageContent{Type: "image", Data: "aGk=", MimeType: "image/png"},
+		},
+		StructuredContent: map[string]interface{}{"ok": true},
+		IsError:           true,
+	}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+
+	var decoded CallToolResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	if !decoded.IsError {
+		t.Error("expected isError to survive the round trip")
+	}
+	if len(decoded.Content) != 2 {
+		t.Fatalf("expected 2 content blocks, got %d", len(decoded.Content))
+	}
+	if _, ok := decoded.Content[1].(ImageContent); !ok {
+		t.Errorf("expected image content, got %T", decoded.Content[1])
+	}
+}
+
+func TestCallToolRequest_ProgressToken(t *testing.T) {
+	var req CallToolRequest
+	if err := json.Unmarshal([]byte(`{"name":"t","_meta":{"progressToken":"abc"}}`), &req); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if req.ProgressToken() != "abc" {
+		t.Errorf("expected progress token abc, got %v", req.ProgressToken())
+	}
+
+	var none *CallToolRequest
+	if none.ProgressToken() != nil {
+		t.Error("expected nil token from a nil request")
+	}
+}
+
+func TestCallToolRequest_IdempotencyKey(t *testing.T) {
+	var req CallToolRequest
+	if err := json.Unmarshal([]byte(`{"name":"t","_meta":{"idempotencyKey":"k1"}}`), &req); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if req.IdempotencyKey() != "k1" {
+		t.Errorf("expected key k1, got %q", req.IdempotencyKey())
+	}
+
+	var none *CallToolRequest
+	if none.IdempotencyKey() != "" {
+		t.Error("expected no key from a nil request")
+	}
+}
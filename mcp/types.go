@@ -1,6 +1,9 @@
 package mcp
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 // Content represents MCP content blocks
 type Content interface {
@@ -9,8 +12,10 @@ type Content interface {
 
 // TextContent represents text content
 type TextContent struct {
-	Type string `json:"type"`
-	Text string `json:"text"`
+	Type        string                 `json:"type"`
+	Text        string                 `json:"text"`
+	Annotations *Annotations           `json:"annotations,omitempty"`
+	Meta        map[string]interface{} `json:"_meta,omitempty"` // Metadata (2025-06-18)
 }
 
 // ContentType returns the content type
@@ -20,9 +25,11 @@ func (t TextContent) ContentType() string {
 
 // ImageContent represents image content
 type ImageContent struct {
-	Type     string `json:"type"`
-	Data     string `json:"data"`
-	MimeType string `json:"mimeType"`
+	Type        string                 `json:"type"`
+	Data        string                 `json:"data"`
+	MimeType    string                 `json:"mimeType"`
+	Annotations *Annotations           `json:"annotations,omitempty"`
+	Meta        map[string]interface{} `json:"_meta,omitempty"` // Metadata (2025-06-18)
 }
 
 // ContentType returns the content type
@@ -32,9 +39,11 @@ func (i ImageContent) ContentType() string {
 
 // AudioContent represents audio content (2025-03-26)
 type AudioContent struct {
-	Type     string `json:"type"`
-	Data     string `json:"data"`
-	MimeType string `json:"mimeType"`
+	Type        string                 `json:"type"`
+	Data        string                 `json:"data"`
+	MimeType    string                 `json:"mimeType"`
+	Annotations *Annotations           `json:"annotations,omitempty"`
+	Meta        map[string]interface{} `json:"_meta,omitempty"` // Metadata (2025-06-18)
 }
 
 // ContentType returns the content type
@@ -44,10 +53,13 @@ func (a AudioContent) ContentType() string {
 
 // ResourceContent represents resource content
 type ResourceContent struct {
-	Type     string `json:"type"`
-	URI      string `json:"uri"`
-	MimeType string `json:"mimeType,omitempty"`
-	Text     string `json:"text,omitempty"`
+	Type        string                 `json:"type"`
+	URI         string                 `json:"uri"`
+	MimeType    string                 `json:"mimeType,omitempty"`
+	Text        string                 `json:"text,omitempty"`
+	Blob        string                 `json:"blob,omitempty"` // Base64-encoded binary data
+	Annotations *Annotations           `json:"annotations,omitempty"`
+	Meta        map[string]interface{} `json:"_meta,omitempty"` // Metadata (2025-06-18)
 }
 
 // ContentType returns the content type
@@ -59,7 +71,8 @@ func (r ResourceContent) ContentType() string {
 type ResourceLinkContent struct {
 	Type        string                 `json:"type"` // "resource"
 	Resource    Resource               `json:"resource"`
-	Annotations map[string]interface{} `json:"annotations,omitempty"`
+	Annotations *Annotations           `json:"annotations,omitempty"`
+	Meta        map[string]interface{} `json:"_meta,omitempty"` // Metadata (2025-06-18)
 }
 
 // ContentType returns the content type
@@ -74,12 +87,69 @@ type Tool struct {
 	InputSchema map[string]interface{} `json:"inputSchema"`
 	// 2025-06-18: Tool output schemas
 	OutputSchema map[string]interface{} `json:"outputSchema,omitempty"` // JSON Schema for tool output
-	// 2025-03-26 annotations
-	Title           string `json:"title,omitempty"`           // Human-readable title
-	ReadOnlyHint    *bool  `json:"readOnlyHint,omitempty"`    // Tool doesn't modify environment
-	DestructiveHint *bool  `json:"destructiveHint,omitempty"` // Tool may perform destructive updates
-	IdempotentHint  *bool  `json:"idempotentHint,omitempty"`  // Repeated calls have no additional effect
-	OpenWorldHint   *bool  `json:"openWorldHint,omitempty"`   // Tool may interact with external entities
+	Title        string                 `json:"title,omitempty"`        // Human-readable title
+	// 2025-03-26 behavior hints and custom annotations
+	Annotations *ToolAnnotations       `json:"annotations,omitempty"`
+	Meta        map[string]interface{} `json:"_meta,omitempty"` // Metadata (2025-06-18)
+
+	// Deprecated: use Annotations.ReadOnlyHint. Hints set here are encoded
+	// in Annotations unless it sets them too; decoded tools leave them nil.
+	ReadOnlyHint *bool `json:"-"`
+	// Deprecated: use Annotations.DestructiveHint.
+	DestructiveHint *bool `json:"-"`
+	// Deprecated: use Annotations.IdempotentHint.
+	IdempotentHint *bool `json:"-"`
+	// Deprecated: use Annotations.OpenWorldHint.
+	OpenWorldHint *bool `json:"-"`
+}
+
+// toolJSON is the wire format of Tool, without its MarshalJSON method
+type toolJSON Tool
+
+// MarshalJSON implements json.Marshaler, moving the deprecated hint fields
+// into the annotations
+func (t Tool) MarshalJSON() ([]byte, error) {
+	wire := toolJSON(t)
+	wire.Annotations = t.mergedAnnotations()
+	return json.Marshal(wire)
+}
+
+// mergedAnnotations returns the annotations with any deprecated hint fields
+// they don't set
+func (t *Tool) mergedAnnotations() *ToolAnnotations {
+	if t.ReadOnlyHint == nil && t.DestructiveHint == nil && t.IdempotentHint == nil && t.OpenWorldHint == nil {
+		return t.Annotations
+	}
+
+	var merged ToolAnnotations
+	if t.Annotations != nil {
+		merged = *t.Annotations
+	}
+	if merged.ReadOnlyHint == nil {
+		merged.ReadOnlyHint = t.ReadOnlyHint
+	}
+	if merged.DestructiveHint == nil {
+		merged.DestructiveHint = t.DestructiveHint
+	}
+	if merged.IdempotentHint == nil {
+		merged.IdempotentHint = t.IdempotentHint
+	}
+	if merged.OpenWorldHint == nil {
+		merged.OpenWorldHint = t.OpenWorldHint
+	}
+	return &merged
+}
+
+// DeprecatedMeta is the _meta field marking a deprecated tool or prompt, in
+// listings and in the results of using it. Its value says what to use
+// instead.
+const DeprecatedMeta = "deprecated"
+
+// Deprecation returns the tool's deprecation message, or "" if it is not
+// deprecated
+func (t *Tool) Deprecation() string {
+	message, _ := t.Meta[DeprecatedMeta].(string)
+	return message
 }
 
 // Resource represents an MCP resource
@@ -89,9 +159,15 @@ type Resource struct {
 	Title       string                 `json:"title,omitempty"` // Human-readable title (2025-06-18)
 	Description string                 `json:"description,omitempty"`
 	MimeType    string                 `json:"mimeType,omitempty"`
+	Annotations *Annotations           `json:"annotations,omitempty"`
 	Meta        map[string]interface{} `json:"_meta,omitempty"` // Metadata (2025-06-18)
 }
 
+// ResourceUpdatedNotification is sent when a subscribed resource changes
+type ResourceUpdatedNotification struct {
+	URI string `json:"uri"`
+}
+
 // ResourceTemplate for parameterized resources
 type ResourceTemplate struct {
 	URITemplate string                 `json:"uriTemplate"`
@@ -99,6 +175,7 @@ type ResourceTemplate struct {
 	Title       string                 `json:"title,omitempty"` // Human-readable title (2025-06-18)
 	Description string                 `json:"description,omitempty"`
 	MimeType    string                 `json:"mimeType,omitempty"`
+	Annotations *Annotations           `json:"annotations,omitempty"`
 	Meta        map[string]interface{} `json:"_meta,omitempty"` // Metadata (2025-06-18)
 }
 
@@ -111,6 +188,13 @@ type Prompt struct {
 	Meta        map[string]interface{} `json:"_meta,omitempty"` // Metadata (2025-06-18)
 }
 
+// Deprecation returns the prompt's deprecation message, or "" if it is not
+// deprecated
+func (p *Prompt) Deprecation() string {
+	message, _ := p.Meta[DeprecatedMeta].(string)
+	return message
+}
+
 // PromptArgument represents a prompt argument
 type PromptArgument struct {
 	Name        string `json:"name"`
@@ -189,33 +273,53 @@ func (pm *PromptMessage) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	content, err := unmarshalContents(temp.Content)
+	if err != nil {
+		return err
+	}
+
 	pm.Role = temp.Role
-	pm.Content = make([]Content, 0, len(temp.Content))
+	pm.Content = content
+	return nil
+}
+
+// unmarshalContents decodes content blocks by their type field
+func unmarshalContents(raw []json.RawMessage) ([]Content, error) {
+	contents := make([]Content, 0, len(raw))
 
-	for _, rawContent := range temp.Content {
+	for _, rawContent := range raw {
 		var typeCheck struct {
 			Type string `json:"type"`
 		}
 		if err := json.Unmarshal(rawContent, &typeCheck); err != nil {
-			return err
+			return nil, err
 		}
 
 		content, err := unmarshalContentByType(rawContent, typeCheck.Type)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		pm.Content = append(pm.Content, content)
+		contents = append(contents, content)
 	}
 
-	return nil
+	return contents, nil
+}
+
+// Implementation describes the name and version of an MCP client or server
+type Implementation struct {
+	Name    string `json:"name"`
+	Title   string `json:"title,omitempty"` // Human-readable name (2025-06-18)
+	Version string `json:"version"`
 }
 
 // ServerCapabilities represents server capabilities
 type ServerCapabilities struct {
-	Tools       *ToolsCapability       `json:"tools,omitempty"`
-	Resources   *ResourcesCapability   `json:"resources,omitempty"`
-	Prompts     *PromptsCapability     `json:"prompts,omitempty"`
-	Completions *CompletionsCapability `json:"completions,omitempty"` // 2025-03-26
+	Tools        *ToolsCapability       `json:"tools,omitempty"`
+	Resources    *ResourcesCapability   `json:"resources,omitempty"`
+	Prompts      *PromptsCapability     `json:"prompts,omitempty"`
+	Completions  *CompletionsCapability `json:"completions,omitempty"` // 2025-03-26
+	Logging      *LoggingCapability     `json:"logging,omitempty"`
+	Experimental Experiments            `json:"experimental,omitempty"`
 }
 
 // ToolsCapability represents tools capability
@@ -241,9 +345,10 @@ type CompletionsCapability struct {
 
 // ClientCapabilities represents client capabilities
 type ClientCapabilities struct {
-	Roots       *RootsCapability       `json:"roots,omitempty"` // 2025-06-18
-	Sampling    *SamplingCapability    `json:"sampling,omitempty"`
-	Elicitation *ElicitationCapability `json:"elicitation,omitempty"` // 2025-06-18
+	Roots        *RootsCapability       `json:"roots,omitempty"` // 2025-06-18
+	Sampling     *SamplingCapability    `json:"sampling,omitempty"`
+	Elicitation  *ElicitationCapability `json:"elicitation,omitempty"` // 2025-06-18
+	Experimental Experiments            `json:"experimental,omitempty"`
 }
 
 // SamplingCapability represents sampling capability
@@ -260,12 +365,14 @@ type ElicitationCapability struct {
 type ElicitationRequest struct {
 	Schema      map[string]interface{} `json:"schema"`                // JSON Schema for requested data
 	Description string                 `json:"description,omitempty"` // What the data is for
+	Meta        map[string]interface{} `json:"_meta,omitempty"`       // Metadata (2025-06-18)
 }
 
 // ElicitationResponse represents user's response to elicitation (2025-06-18)
 type ElicitationResponse struct {
-	Action string                 `json:"action"`         // "accept", "decline", or "cancel"
-	Data   map[string]interface{} `json:"data,omitempty"` // User-provided data (if accepted)
+	Action string                 `json:"action"`          // "accept", "decline", or "cancel"
+	Data   map[string]interface{} `json:"data,omitempty"`  // User-provided data (if accepted)
+	Meta   map[string]interface{} `json:"_meta,omitempty"` // Metadata (2025-06-18)
 }
 
 // Message represents a JSON-RPC 2.0 message envelope
@@ -284,3 +391,7 @@ type RPCError struct {
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
 }
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("RPC error %d: %s", e.Code, e.Message)
+}
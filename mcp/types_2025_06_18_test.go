@@ -156,9 +156,7 @@ func TestResourceLinkContent(t *testing.T) {
 			URI:  "file:///report.pdf",
 			Name: "report",
 		},
-		Annotations: map[string]interface{}{
-			"size": 1024,
-		},
+		Annotations: NewAnnotations().WithPriority(0.5),
 	}
 
 	if link.ContentType() != "resource" {
@@ -181,7 +179,7 @@ func TestResourceLinkContent(t *testing.T) {
 		t.Errorf("expected URI 'file:///report.pdf', got '%s'", link2.Resource.URI)
 	}
 
-	if link2.Annotations == nil {
+	if link2.Annotations == nil || link2.Annotations.PriorityOr(0) != 0.5 {
 		t.Fatal("expected Annotations to be preserved")
 	}
 }
@@ -346,3 +344,40 @@ func TestNewFields_JSONRoundTrip(t *testing.T) {
 		t.Errorf("prompt marshal failed: %v", err)
 	}
 }
+
+// Test _meta serialization on tools, content blocks and request/result types (2025-06-18)
+func TestMeta_JSONRoundTrip(t *testing.T) {
+	meta := map[string]interface{}{"example.com/trace": "abc"}
+
+	values := []interface{}{
+		&Tool{Name: "t", InputSchema: map[string]interface{}{"type": "object"}, Meta: meta},
+		&TextContent{Type: "text", Text: "hi", Meta: meta},
+		&ImageContent{Type: "image", Data: "AA==", MimeType: "image/png", Meta: meta},
+		&AudioContent{Type: "audio", Data: "AA==", MimeType: "audio/wav", Meta: meta},
+		&ResourceContent{Type: "resource", URI: "file:///a", Meta: meta},
+		&CreateMessageRequest{Meta: meta},
+		&CreateMessageResult{Role: "assistant", Meta: meta},
+		&CompleteRequest{Meta: meta},
+		&CompleteResult{Meta: meta},
+	}
+
+	for _, v := range values {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("failed to marshal %T: %v", v, err)
+		}
+
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal(data, &raw); err != nil {
+			t.Fatalf("failed to unmarshal %T: %v", v, err)
+		}
+		if string(raw["_meta"]) != `{"example.com/trace":"abc"}` {
+			t.Errorf("%T: expected _meta to be serialized, got %s", v, data)
+		}
+	}
+
+	data, _ := json.Marshal(TextContent{Type: "text", Text: "hi"})
+	if string(data) != `{"type":"text","text":"hi"}` {
+		t.Errorf("expected _meta to be omitted when empty, got %s", data)
+	}
+}
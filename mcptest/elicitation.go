@@ -0,0 +1,94 @@
+// Package mcptest provides helpers for testing MCP servers and clients
+// without a human or an LLM in the loop.
+package mcptest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// Accept returns an elicitation response accepting the request with data
+func Accept(data map[string]interface{}) *mcp.ElicitationResponse {
+	return &mcp.ElicitationResponse{Action: "accept", Data: data}
+}
+
+// Decline returns an elicitation response declining the request
+func Decline() *mcp.ElicitationResponse {
+	return &mcp.ElicitationResponse{Action: "decline"}
+}
+
+// Cancel returns an elicitation response dismissing the request
+func Cancel() *mcp.ElicitationResponse {
+	return &mcp.ElicitationResponse{Action: "cancel"}
+}
+
+// ElicitationResponder answers elicitation requests on behalf of a user, so
+// code that elicits can be tested headlessly. Its Handle method is a
+// client.ElicitationHandler:
+//
+//	responder := mcptest.ScriptedElicitation(mcptest.Accept(map[string]interface{}{"name": "Ada"}))
+//	c := client.New(conn, client.WithElicitationHandler(responder.Handle))
+type ElicitationResponder struct {
+	mu       sync.Mutex
+	decide   func(*mcp.ElicitationRequest) *mcp.ElicitationResponse
+	script   []*mcp.ElicitationResponse
+	requests []*mcp.ElicitationRequest
+}
+
+// NewElicitationResponder creates a responder that answers each request with
+// decide, which can inspect the request's description and schema
+func NewElicitationResponder(decide func(req *mcp.ElicitationRequest) *mcp.ElicitationResponse) *ElicitationResponder {
+	return &ElicitationResponder{decide: decide}
+}
+
+// ScriptedElicitation creates a responder that answers requests with
+// responses in order. Requests beyond the script fail.
+func ScriptedElicitation(responses ...*mcp.ElicitationResponse) *ElicitationResponder {
+	return &ElicitationResponder{script: responses}
+}
+
+// Handle answers an elicitation request. Accepted data that fails the
+// client's schema validation fails the request rather than being retried,
+// since a script can't correct it.
+func (r *ElicitationResponder) Handle(_ context.Context, req *mcp.ElicitationRequest, errs []*mcp.ValidationError) (*mcp.ElicitationResponse, error) {
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("mcptest: elicitation data failed validation: %v", errs)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests = append(r.requests, req)
+	if r.decide != nil {
+		resp := r.decide(req)
+		if resp == nil {
+			return nil, fmt.Errorf("mcptest: no elicitation response for %q", req.Description)
+		}
+		return resp, nil
+	}
+
+	if len(r.script) == 0 {
+		return nil, fmt.Errorf("mcptest: unexpected elicitation request %d for %q", len(r.requests), req.Description)
+	}
+	resp := r.script[0]
+	r.script = r.script[1:]
+	return resp, nil
+}
+
+// Requests returns the elicitation requests received so far
+func (r *ElicitationResponder) Requests() []*mcp.ElicitationRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*mcp.ElicitationRequest(nil), r.requests...)
+}
+
+// Remaining returns the number of scripted responses not yet used, so tests
+// can check every expected request was made
+func (r *ElicitationResponder) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.script)
+}
@@ -0,0 +1,114 @@
+package mcptest
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/builder/elicit"
+	"github.com/jmcarbo/fullmcp/client"
+	"github.com/jmcarbo/fullmcp/internal/jsonrpc"
+	"github.com/jmcarbo/fullmcp/internal/testutil"
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func TestScriptedElicitation(t *testing.T) {
+	r := ScriptedElicitation(Accept(map[string]interface{}{"name": "Ada"}), Decline())
+	ctx := context.Background()
+
+	resp, err := r.Handle(ctx, &mcp.ElicitationRequest{Description: "first"}, nil)
+	if err != nil || resp.Action != "accept" || resp.Data["name"] != "Ada" {
+		t.Fatalf("unexpected first response: %+v, %v", resp, err)
+	}
+	resp, err = r.Handle(ctx, &mcp.ElicitationRequest{Description: "second"}, nil)
+	if err != nil || resp.Action != "decline" {
+		t.Fatalf("unexpected second response: %+v, %v", resp, err)
+	}
+	if r.Remaining() != 0 {
+		t.Errorf("expected script to be used up, %d left", r.Remaining())
+	}
+
+	if _, err := r.Handle(ctx, &mcp.ElicitationRequest{Description: "third"}, nil); err == nil {
+		t.Error("expected requests beyond the script to fail")
+	}
+	if got := r.Requests(); len(got) != 3 || got[1].Description != "second" {
+		t.Errorf("unexpected recorded requests: %+v", got)
+	}
+}
+
+func TestElicitationResponder_RejectsInvalidData(t *testing.T) {
+	r := ScriptedElicitation(Accept(nil))
+
+	errs := []*mcp.ValidationError{{Field: "name", Message: "is required"}}
+	if _, err := r.Handle(context.Background(), &mcp.ElicitationRequest{}, errs); err == nil || !strings.Contains(err.Error(), "name") {
+		t.Fatalf("expected validation failure, got %v", err)
+	}
+}
+
+func TestElicitationResponder_ThroughClient(t *testing.T) {
+	r := NewElicitationResponder(func(req *mcp.ElicitationRequest) *mcp.ElicitationResponse {
+		properties, _ := req.Schema["properties"].(map[string]interface{})
+		if _, ok := properties["confirm"]; ok {
+			return Cancel()
+		}
+		return Accept(map[string]interface{}{"email": "ada@example.com"})
+	})
+
+	clientConn, serverConn := testutil.NewPipeTransport()
+	defer func() { _ = serverConn.Close() }()
+	c := client.New(clientConn, client.WithElicitationHandler(r.Handle))
+
+	reader := jsonrpc.NewMessageReader(serverConn)
+	writer := jsonrpc.NewMessageWriter(serverConn)
+
+	// Play the server's side of the handshake
+	connected := make(chan error, 1)
+	go func() { connected <- c.Connect(context.Background()) }()
+	initReq, err := reader.Read()
+	if err != nil {
+		t.Fatalf("read initialize failed: %v", err)
+	}
+	_ = writer.Write(&mcp.Message{JSONRPC: "2.0", ID: initReq.ID, Result: json.RawMessage(
+		`{"protocolVersion":"2025-06-18","capabilities":{},"serverInfo":{"name":"test","version":"1.0"}}`)})
+	if _, err := reader.Read(); err != nil {
+		t.Fatalf("read initialized failed: %v", err)
+	}
+	if err := <-connected; err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	elicitAction := func(id string, req *mcp.ElicitationRequest) map[string]interface{} {
+		params, _ := json.Marshal(req)
+		_ = writer.Write(&mcp.Message{JSONRPC: "2.0", ID: id, Method: "elicitation/create", Params: params})
+		resp, err := reader.Read()
+		if err != nil {
+			t.Fatalf("read response failed: %v", err)
+		}
+		if resp.Error != nil {
+			t.Fatalf("unexpected error: %s", resp.Error.Message)
+		}
+		var result map[string]interface{}
+		_ = json.Unmarshal(resp.Result, &result)
+		return result
+	}
+
+	emailReq, _ := elicit.NewSchema().String("email", elicit.Format("email"), elicit.Required()).Request("Contact email")
+	result := elicitAction("e1", emailReq)
+	if result["action"] != "accept" {
+		t.Fatalf("unexpected accept result: %v", result)
+	}
+	if data, _ := result["data"].(map[string]interface{}); data["email"] != "ada@example.com" {
+		t.Errorf("unexpected data: %v", result)
+	}
+
+	confirmReq, _ := elicit.NewSchema().Boolean("confirm").Request("Delete everything?")
+	if result := elicitAction("e2", confirmReq); result["action"] != "cancel" {
+		t.Errorf("expected cancel, got %v", result)
+	}
+
+	if got := r.Requests(); len(got) != 2 || got[0].Description != "Contact email" {
+		t.Errorf("unexpected recorded requests: %+v", got)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build jsoniter
+
+package server
+
+import "github.com/jmcarbo/fullmcp/internal/jsonrpc"
+
+func init() {
+	benchCodecs["jsoniter"] = jsonrpc.JSONIterCodec{}
+}
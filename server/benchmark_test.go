@@ -1,13 +1,22 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"testing"
 
+	"github.com/jmcarbo/fullmcp/internal/jsonrpc"
 	"github.com/jmcarbo/fullmcp/mcp"
 )
 
+// benchCodecs lists the codecs compared by codec benchmarks; building the
+// tests with the jsoniter tag adds jsoniter
+var benchCodecs = map[string]Codec{
+	"encoding-json": jsonrpc.StdCodec{},
+}
+
 // BenchmarkToolRegistration measures the performance of registering tools
 func BenchmarkToolRegistration(b *testing.B) {
 	srv := New("benchmark-server")
@@ -227,3 +236,111 @@ func BenchmarkMiddlewareChain(b *testing.B) {
 		_ = srv.HandleMessage(ctx, msg)
 	}
 }
+
+// largeSchema returns an input schema with n documented properties
+func largeSchema(n int) map[string]interface{} {
+	properties := make(map[string]interface{}, n)
+	required := make([]string, 0, n/2)
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("field_%d", i)
+		properties[name] = map[string]interface{}{
+			"type":        "string",
+			"description": "A moderately long description of the field, as generated from struct tags",
+			"enum":        []string{"alpha", "beta", "gamma", "delta"},
+			"minLength":   1,
+			"maxLength":   256,
+		}
+		if i%2 == 0 {
+			required = append(required, name)
+		}
+	}
+	return map[string]interface{}{"type": "object", "properties": properties, "required": required}
+}
+
+// BenchmarkToolsListLargeSchemas measures encoding a tools/list response for
+// 100 tools with 40-property schemas with each codec. tools/list reuses
+// encoded listings, so this encodes them as a first request does. Run with
+// -tags jsoniter to compare jsoniter against encoding/json.
+func BenchmarkToolsListLargeSchemas(b *testing.B) {
+	for name, codec := range benchCodecs {
+		b.Run(name, func(b *testing.B) {
+			srv := New("benchmark-server", WithCodec(codec))
+			for i := 0; i < 100; i++ {
+				_ = srv.AddTool(&ToolHandler{
+					Name:        fmt.Sprintf("tool-%d", i),
+					Description: "Test tool with a large input schema",
+					Schema:      largeSchema(40),
+					Handler: func(_ context.Context, _ json.RawMessage) (interface{}, error) {
+						return nil, nil
+					},
+				})
+			}
+
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				tools, _ := srv.tools.List(ctx)
+				_ = srv.successResponse(1, map[string]interface{}{"tools": tools})
+			}
+		})
+	}
+}
+
+// BenchmarkReadMessage measures decoding tools/call requests with each codec
+func BenchmarkReadMessage(b *testing.B) {
+	line, _ := json.Marshal(&mcp.Message{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"search","arguments":{"query":"model context protocol","limit":25,"filters":{"lang":["go","rust"],"stars":100}}}`),
+	})
+	line = append(line, '\n')
+
+	for name, codec := range benchCodecs {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(line)))
+			for i := 0; i < b.N; i++ {
+				reader := jsonrpc.NewMessageReader(bytes.NewReader(line), jsonrpc.WithCodec(codec), jsonrpc.WithMaxMessageSize(1<<20))
+				if _, err := reader.Read(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkToolsList500 compares tools/list for 500 tools with 20-property
+// schemas when every schema is marshaled on each request (uncached) against
+// reusing each tool's encoded listing (cached)
+func BenchmarkToolsList500(b *testing.B) {
+	srv := New("benchmark-server")
+	for i := 0; i < 500; i++ {
+		_ = srv.AddTool(&ToolHandler{
+			Name:        fmt.Sprintf("tool-%d", i),
+			Description: "Test tool",
+			Schema:      largeSchema(20),
+			Handler: func(_ context.Context, _ json.RawMessage) (interface{}, error) {
+				return nil, nil
+			},
+		})
+	}
+	ctx := context.Background()
+
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			tools, _ := srv.tools.List(ctx)
+			_ = srv.successResponse(1, map[string]interface{}{"tools": tools})
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		msg := &mcp.Message{JSONRPC: "2.0", ID: 1, Method: "tools/list"}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = srv.HandleMessage(ctx, msg)
+		}
+	})
+}
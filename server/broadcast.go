@@ -0,0 +1,118 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// BroadcastTarget is a transport whose sessions are served outside Serve,
+// such as the WebSocket and Streamable HTTP servers. Notifications sent with
+// NotifyAll, including list_changed notifications, are delivered to each of
+// its sessions.
+type BroadcastTarget interface {
+	// SessionIDs returns the IDs of the sessions that can receive messages
+	SessionIDs() []string
+	// Send writes an encoded JSON-RPC message to a session
+	Send(sessionID string, msg []byte) error
+}
+
+// BroadcastFilter reports whether a notification sent with NotifyAll is
+// delivered to a session, such as to keep tenant-specific notifications
+// within the tenant's sessions
+type BroadcastFilter func(sessionID, method string, params interface{}) bool
+
+// WithBroadcastFilter adds a filter applied to every session reached by
+// NotifyAll. A session receives a notification only if every filter allows
+// it. The sender installed with SetNotificationSender is not filtered, since
+// it has no session.
+func WithBroadcastFilter(filter BroadcastFilter) Option {
+	return func(s *Server) {
+		s.broadcastFilters = append(s.broadcastFilters, filter)
+	}
+}
+
+// AddBroadcastTarget adds a transport whose sessions receive notifications
+// sent with NotifyAll, alongside the sessions started by Serve. It returns a
+// function removing the target again.
+func (s *Server) AddBroadcastTarget(target BroadcastTarget) (remove func()) {
+	s.notifyMu.Lock()
+	defer s.notifyMu.Unlock()
+
+	// Copied on write so NotifyAll can use a snapshot without the lock
+	targets := make([]BroadcastTarget, 0, len(s.broadcastTargets)+1)
+	s.broadcastTargets = append(append(targets, s.broadcastTargets...), target)
+
+	return func() {
+		s.notifyMu.Lock()
+		defer s.notifyMu.Unlock()
+
+		targets := make([]BroadcastTarget, 0, len(s.broadcastTargets))
+		removed := false
+		for _, t := range s.broadcastTargets {
+			if !removed && t == target {
+				removed = true
+				continue
+			}
+			targets = append(targets, t)
+		}
+		s.broadcastTargets = targets
+	}
+}
+
+// broadcastAllowed reports whether the broadcast filters let a notification
+// through to a session
+func (s *Server) broadcastAllowed(sessionID, method string, params interface{}) bool {
+	for _, filter := range s.broadcastFilters {
+		if !filter(sessionID, method, params) {
+			return false
+		}
+	}
+	return true
+}
+
+// encodeNotification encodes a notification message for BroadcastTarget.Send
+func encodeNotification(method string, params interface{}) ([]byte, error) {
+	msg := &mcp.Message{JSONRPC: "2.0", Method: method}
+	if params != nil {
+		paramsJSON, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		msg.Params = paramsJSON
+	}
+	return json.Marshal(msg)
+}
+
+// sendToTarget sends a notification to one session of target
+func (s *Server) sendToTarget(target BroadcastTarget, sessionID, method string, params interface{}) error {
+	data, err := encodeNotification(method, params)
+	if err != nil {
+		return err
+	}
+	return target.Send(sessionID, data)
+}
+
+// broadcastToTargets encodes a notification once and sends it to every
+// allowed session of targets
+func (s *Server) broadcastToTargets(targets []BroadcastTarget, method string, params interface{}) error {
+	data, err := encodeNotification(method, params)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, target := range targets {
+		for _, id := range target.SessionIDs() {
+			if !s.broadcastAllowed(id, method, params) {
+				continue
+			}
+			if err := target.Send(id, data); err != nil {
+				errs = append(errs, fmt.Errorf("session %s: %w", id, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
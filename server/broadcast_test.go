@@ -0,0 +1,86 @@
+package server
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingTarget is a broadcast target recording the messages it is sent
+type recordingTarget struct {
+	ids []string
+
+	mu   sync.Mutex
+	sent map[string][]string
+}
+
+func (rt *recordingTarget) SessionIDs() []string {
+	return rt.ids
+}
+
+func (rt *recordingTarget) Send(sessionID string, msg []byte) error {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if rt.sent == nil {
+		rt.sent = make(map[string][]string)
+	}
+	rt.sent[sessionID] = append(rt.sent[sessionID], string(msg))
+	return nil
+}
+
+func (rt *recordingTarget) messages(sessionID string) []string {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return rt.sent[sessionID]
+}
+
+func TestServer_BroadcastTargets(t *testing.T) {
+	srv := New("test")
+	reader, _ := servePipe(t, srv)
+	ws := &recordingTarget{ids: []string{"ws-1", "ws-2"}}
+	remove := srv.AddBroadcastTarget(ws)
+
+	// The pipe is unbuffered, so the served session is read concurrently
+	done := make(chan error, 1)
+	go func() { done <- srv.NotifyResourceListChanged() }()
+
+	if msg := readMessage(t, reader); msg.Method != "notifications/resources/list_changed" {
+		t.Errorf("expected the served session to be notified, got %+v", msg)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("broadcast failed: %v", err)
+	}
+	for _, id := range ws.ids {
+		sent := ws.messages(id)
+		if len(sent) != 1 || sent[0] != `{"jsonrpc":"2.0","method":"notifications/resources/list_changed"}` {
+			t.Errorf("expected %s to be notified, got %v", id, sent)
+		}
+	}
+
+	remove()
+	go func() { done <- srv.NotifyAll("notifications/vendor/hello", nil) }()
+	readMessage(t, reader)
+	<-done
+	if len(ws.messages("ws-1")) != 1 {
+		t.Error("expected a removed target to receive nothing")
+	}
+}
+
+func TestServer_BroadcastFilter(t *testing.T) {
+	srv := New("test", WithBroadcastFilter(func(sessionID, method string, _ interface{}) bool {
+		// Vendor notifications only go to the acme tenant
+		return !strings.HasPrefix(method, "notifications/acme/") || strings.HasPrefix(sessionID, "acme-")
+	}))
+	target := &recordingTarget{ids: []string{"acme-1", "other-1"}}
+	srv.AddBroadcastTarget(target)
+
+	_ = srv.NotifyAll("notifications/acme/report_ready", map[string]string{"id": "r1"})
+	_ = srv.NotifyAll("notifications/tools/list_changed", nil)
+
+	if sent := target.messages("acme-1"); len(sent) != 2 {
+		t.Errorf("expected acme-1 to get both notifications, got %v", sent)
+	}
+	if sent := target.messages("other-1"); len(sent) != 1 || !strings.Contains(sent[0], "tools/list_changed") {
+		t.Errorf("expected other-1 to get only list_changed, got %v", sent)
+	}
+}
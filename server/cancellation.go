@@ -10,14 +10,14 @@ import (
 // CancellationManager manages request cancellations
 type CancellationManager struct {
 	mu             sync.RWMutex
-	cancelFuncs    map[interface{}]context.CancelFunc
+	cancelFuncs    map[string]context.CancelFunc // Keyed by mcp.IDKey
 	cancellationCh chan *mcp.CancelledNotification
 }
 
 // NewCancellationManager creates a new cancellation manager
 func NewCancellationManager() *CancellationManager {
 	return &CancellationManager{
-		cancelFuncs:    make(map[interface{}]context.CancelFunc),
+		cancelFuncs:    make(map[string]context.CancelFunc),
 		cancellationCh: make(chan *mcp.CancelledNotification, 10),
 	}
 }
@@ -26,14 +26,14 @@ func NewCancellationManager() *CancellationManager {
 func (cm *CancellationManager) Register(requestID interface{}, cancel context.CancelFunc) {
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
-	cm.cancelFuncs[requestID] = cancel
+	cm.cancelFuncs[mcp.IDKey(requestID)] = cancel
 }
 
 // Unregister removes a request from cancellation tracking
 func (cm *CancellationManager) Unregister(requestID interface{}) {
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
-	delete(cm.cancelFuncs, requestID)
+	delete(cm.cancelFuncs, mcp.IDKey(requestID))
 }
 
 // Cancel cancels a request by ID
@@ -41,9 +41,10 @@ func (cm *CancellationManager) Cancel(requestID interface{}, _ string) bool {
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
 
-	if cancel, exists := cm.cancelFuncs[requestID]; exists {
+	key := mcp.IDKey(requestID)
+	if cancel, exists := cm.cancelFuncs[key]; exists {
 		cancel()
-		delete(cm.cancelFuncs, requestID)
+		delete(cm.cancelFuncs, key)
 		return true
 	}
 
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// Capability names a capability the server advertises during initialize
+type Capability string
+
+// Server capabilities
+const (
+	CapabilityTools       Capability = "tools"
+	CapabilityResources   Capability = "resources"
+	CapabilityPrompts     Capability = "prompts"
+	CapabilityCompletions Capability = "completions"
+	CapabilityLogging     Capability = "logging"
+)
+
+// WithoutCapabilities hides capabilities from clients: they are not
+// advertised during initialize and their methods fail with MethodNotFound,
+// as if the server did not implement them
+func WithoutCapabilities(caps ...Capability) Option {
+	return func(s *Server) {
+		if s.hiddenCaps == nil {
+			s.hiddenCaps = make(map[Capability]bool, len(caps))
+		}
+		for _, c := range caps {
+			s.hiddenCaps[c] = true
+		}
+	}
+}
+
+// WithOmitEmptyCapabilities stops the tools, resources and prompts
+// capabilities being advertised when nothing of that kind is registered at
+// initialize time. Clients don't ask for capabilities that weren't
+// advertised, so register everything before clients connect.
+func WithOmitEmptyCapabilities() Option {
+	return func(s *Server) {
+		s.omitEmptyCaps = true
+	}
+}
+
+// capabilities returns the capabilities advertised during initialize
+func (s *Server) capabilities(ctx context.Context) mcp.ServerCapabilities {
+	var caps mcp.ServerCapabilities
+	tools, _ := s.tools.List(ctx)
+	if s.advertises(CapabilityTools, len(tools) > 0) {
+		caps.Tools = &mcp.ToolsCapability{}
+	}
+	if s.advertises(CapabilityResources, len(s.resources.List()) > 0 || len(s.resources.ListTemplates()) > 0) {
+		caps.Resources = &mcp.ResourcesCapability{Subscribe: true, ListChanged: true}
+		// Stateless transports can't deliver the notifications these promise
+		if info, ok := ConnInfoFromContext(ctx); ok && info.Stateless {
+			caps.Resources = &mcp.ResourcesCapability{}
+		}
+	}
+	if s.advertises(CapabilityPrompts, len(s.prompts.List()) > 0) {
+		caps.Prompts = &mcp.PromptsCapability{}
+	}
+
+	// Add completions capability if enabled (2025-03-26). Template variables
+	// are completed from the registered resources without a handler.
+	if (s.completion != nil || len(s.resources.ListTemplates()) > 0) && !s.hiddenCaps[CapabilityCompletions] {
+		caps.Completions = &mcp.CompletionsCapability{}
+	}
+	if s.logging != nil && !s.hiddenCaps[CapabilityLogging] {
+		caps.Logging = &mcp.LoggingCapability{}
+	}
+	if len(s.experimental) > 0 {
+		caps.Experimental = s.experimental
+	}
+	return caps
+}
+
+// advertises reports whether a capability with registrations is advertised
+func (s *Server) advertises(c Capability, registered bool) bool {
+	if s.hiddenCaps[c] {
+		return false
+	}
+	return registered || !s.omitEmptyCaps
+}
+
+// methodHidden reports whether method belongs to a hidden capability
+func (s *Server) methodHidden(method string) bool {
+	if len(s.hiddenCaps) == 0 {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(method, "tools/"):
+		return s.hiddenCaps[CapabilityTools]
+	case strings.HasPrefix(method, "resources/"):
+		return s.hiddenCaps[CapabilityResources]
+	case strings.HasPrefix(method, "prompts/"):
+		return s.hiddenCaps[CapabilityPrompts]
+	case method == "completion/complete":
+		return s.hiddenCaps[CapabilityCompletions]
+	case method == "logging/setLevel":
+		return s.hiddenCaps[CapabilityLogging]
+	default:
+		return false
+	}
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func initializeCapabilities(t *testing.T, srv *Server) map[string]json.RawMessage {
+	t.Helper()
+	response := srv.HandleMessage(context.Background(), &mcp.Message{JSONRPC: "2.0", ID: 1, Method: "initialize"})
+	var result struct {
+		Capabilities map[string]json.RawMessage `json:"capabilities"`
+	}
+	if err := json.Unmarshal(response.Result, &result); err != nil {
+		t.Fatalf("failed to unmarshal result: %v", err)
+	}
+	return result.Capabilities
+}
+
+func TestCapabilities_DefaultAdvertisesEverything(t *testing.T) {
+	caps := initializeCapabilities(t, New("test"))
+	for _, name := range []string{"tools", "resources", "prompts"} {
+		if _, ok := caps[name]; !ok {
+			t.Errorf("expected %s to be advertised, got %v", name, caps)
+		}
+	}
+}
+
+func TestCapabilities_OmitEmpty(t *testing.T) {
+	srv := New("test", WithOmitEmptyCapabilities())
+	_ = srv.AddTool(&ToolHandler{
+		Name:    "echo",
+		Handler: func(_ context.Context, _ json.RawMessage) (interface{}, error) { return "echo", nil },
+	})
+
+	caps := initializeCapabilities(t, srv)
+	if _, ok := caps["tools"]; !ok {
+		t.Error("expected tools to be advertised")
+	}
+	if _, ok := caps["resources"]; ok {
+		t.Error("expected no resources capability without resources")
+	}
+	if _, ok := caps["prompts"]; ok {
+		t.Error("expected no prompts capability without prompts")
+	}
+
+	_ = srv.AddResourceTemplate(&ResourceTemplateHandler{
+		URITemplate: "file:///{path}",
+		Name:        "files",
+		Reader:      func(_ context.Context, _ map[string]string) ([]byte, error) { return nil, nil },
+	})
+	if _, ok := initializeCapabilities(t, srv)["resources"]; !ok {
+		t.Error("expected a resource template to advertise resources")
+	}
+}
+
+func TestCapabilities_Hidden(t *testing.T) {
+	srv := New("test", WithoutCapabilities(CapabilityTools, CapabilityLogging), EnableLogging())
+	_ = srv.AddTool(&ToolHandler{
+		Name:    "echo",
+		Handler: func(_ context.Context, _ json.RawMessage) (interface{}, error) { return "echo", nil },
+	})
+
+	caps := initializeCapabilities(t, srv)
+	if _, ok := caps["tools"]; ok {
+		t.Error("expected tools to be hidden")
+	}
+	if _, ok := caps["logging"]; ok {
+		t.Error("expected logging to be hidden")
+	}
+	if _, ok := caps["prompts"]; !ok {
+		t.Error("expected prompts to still be advertised")
+	}
+
+	resp := srv.HandleMessage(context.Background(), &mcp.Message{
+		JSONRPC: "2.0",
+		ID:      2,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"echo"}`),
+	})
+	if resp.Error == nil || resp.Error.Code != int(mcp.MethodNotFound) {
+		t.Errorf("expected MethodNotFound for a hidden capability, got %+v", resp)
+	}
+}
@@ -0,0 +1,283 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// CircuitState is the state of a tool's circuit breaker
+type CircuitState int
+
+// Circuit breaker states
+const (
+	CircuitClosed   CircuitState = iota // Calls run normally
+	CircuitOpen                         // Calls fail fast with the fallback
+	CircuitHalfOpen                     // A trial call is running to decide whether to close
+)
+
+// String returns the state's name
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerOption configures CircuitBreakerMiddleware
+type CircuitBreakerOption func(*circuitBreaker)
+
+// WithFailureThreshold sets the number of consecutive failed calls that
+// opens a tool's circuit (default 5)
+func WithFailureThreshold(n int) CircuitBreakerOption {
+	return func(cb *circuitBreaker) {
+		if n > 0 {
+			cb.threshold = n
+		}
+	}
+}
+
+// WithOpenDuration sets how long a circuit stays open before a trial call
+// is let through (default 30s)
+func WithOpenDuration(d time.Duration) CircuitBreakerOption {
+	return func(cb *circuitBreaker) {
+		if d > 0 {
+			cb.openDuration = d
+		}
+	}
+}
+
+// WithCircuitTools limits the circuit breaker to the named tools, such as
+// those with OpenWorldHint set. By default every tool has a circuit.
+func WithCircuitTools(names ...string) CircuitBreakerOption {
+	return func(cb *circuitBreaker) {
+		cb.tools = make(map[string]bool, len(names))
+		for _, name := range names {
+			cb.tools[name] = true
+		}
+	}
+}
+
+// WithCircuitFallback sets the result returned for calls to a tool whose
+// circuit is open. By default such calls get a result with isError set
+// saying the tool is temporarily unavailable.
+func WithCircuitFallback(fallback func(ctx context.Context, tool string) *mcp.CallToolResult) CircuitBreakerOption {
+	return func(cb *circuitBreaker) {
+		cb.fallback = fallback
+	}
+}
+
+// WithCircuitStateChange sets a function called whenever a tool's circuit
+// changes state, such as a logger or metrics hook
+func WithCircuitStateChange(fn func(tool string, from, to CircuitState)) CircuitBreakerOption {
+	return func(cb *circuitBreaker) {
+		cb.onStateChange = fn
+	}
+}
+
+// CircuitBreakerMiddleware keeps a circuit breaker per tool, so tools
+// wrapping unreliable external APIs fail fast instead of tying up the
+// server. A call fails if it returns a protocol error or a result with
+// isError set; after the threshold of consecutive failures the tool's
+// circuit opens and calls get the fallback without running the tool. Once
+// the open duration passes a single trial call runs: if it succeeds the
+// circuit closes, otherwise it opens again.
+func CircuitBreakerMiddleware(opts ...CircuitBreakerOption) Middleware {
+	return newCircuitBreaker(opts...).middleware
+}
+
+// circuitBreaker holds the circuits of every tool
+type circuitBreaker struct {
+	threshold     int
+	openDuration  time.Duration
+	tools         map[string]bool // Nil for every tool
+	fallback      func(context.Context, string) *mcp.CallToolResult
+	onStateChange func(tool string, from, to CircuitState)
+	now           func() time.Time
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+// circuit is the breaker state of a single tool
+type circuit struct {
+	state      CircuitState
+	generation uint64    // Incremented on every state change
+	failures   int       // Consecutive failures while closed
+	openedAt   time.Time // When the circuit last opened
+}
+
+func newCircuitBreaker(opts ...CircuitBreakerOption) *circuitBreaker {
+	cb := &circuitBreaker{
+		threshold:    5,
+		openDuration: 30 * time.Second,
+		now:          time.Now,
+		circuits:     make(map[string]*circuit),
+	}
+	for _, opt := range opts {
+		opt(cb)
+	}
+	return cb
+}
+
+func (cb *circuitBreaker) middleware(next Handler) Handler {
+	return func(ctx context.Context, req *Request) (*Response, error) {
+		if req.Method != "tools/call" {
+			return next(ctx, req)
+		}
+		tool := toolName(req.Params)
+		if tool == "" || (cb.tools != nil && !cb.tools[tool]) {
+			return next(ctx, req)
+		}
+
+		generation, ok := cb.allow(tool)
+		if !ok {
+			return &Response{Result: cb.fallbackResult(ctx, tool)}, nil
+		}
+
+		// Deferred so a panicking trial call doesn't leave the circuit
+		// half-open forever
+		success := false
+		defer func() { cb.record(tool, generation, success) }()
+		resp, err := next(ctx, req)
+		success = err == nil && !callFailed(resp)
+		return resp, err
+	}
+}
+
+// allow reports whether a call to tool may run, moving an open circuit to
+// half-open once its open duration has passed. It returns the circuit's
+// generation, which the call passes back to record.
+func (cb *circuitBreaker) allow(tool string) (uint64, bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	c, ok := cb.circuits[tool]
+	if !ok {
+		return 0, true
+	}
+	switch c.state {
+	case CircuitOpen:
+		if cb.now().Sub(c.openedAt) < cb.openDuration {
+			return 0, false
+		}
+		cb.setState(tool, c, CircuitHalfOpen)
+		return c.generation, true
+	case CircuitHalfOpen:
+		// Only the trial call runs until it decides the state
+		return 0, false
+	default:
+		return c.generation, true
+	}
+}
+
+// record updates tool's circuit with the outcome of a call allowed in the
+// given generation. Calls allowed before the circuit last changed state are
+// ignored, so only the trial call decides a half-open circuit.
+func (cb *circuitBreaker) record(tool string, generation uint64, success bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	c, ok := cb.circuits[tool]
+	if ok && c.generation != generation {
+		return
+	}
+	if !ok {
+		if success {
+			return
+		}
+		c = &circuit{}
+		cb.circuits[tool] = c
+	}
+
+	if c.state == CircuitHalfOpen {
+		if success {
+			c.failures = 0
+			cb.setState(tool, c, CircuitClosed)
+		} else {
+			c.openedAt = cb.now()
+			cb.setState(tool, c, CircuitOpen)
+		}
+		return
+	}
+
+	if success {
+		c.failures = 0
+		return
+	}
+	c.failures++
+	if c.state == CircuitClosed && c.failures >= cb.threshold {
+		c.openedAt = cb.now()
+		cb.setState(tool, c, CircuitOpen)
+	}
+}
+
+// setState moves c to state, reporting the change. The caller holds cb.mu.
+func (cb *circuitBreaker) setState(tool string, c *circuit, state CircuitState) {
+	from := c.state
+	if from == state {
+		return
+	}
+	c.state = state
+	c.generation++
+	if cb.onStateChange != nil {
+		cb.onStateChange(tool, from, state)
+	}
+}
+
+func (cb *circuitBreaker) fallbackResult(ctx context.Context, tool string) *mcp.CallToolResult {
+	if cb.fallback != nil {
+		if result := cb.fallback(ctx, tool); result != nil {
+			return result
+		}
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "tool " + tool + " is temporarily unavailable"}},
+		IsError: true,
+	}
+}
+
+// toolName returns the tool named in tools/call params
+func toolName(params interface{}) string {
+	raw, ok := params.(json.RawMessage)
+	if !ok {
+		return ""
+	}
+	var call struct {
+		Name string `json:"name"`
+	}
+	_ = json.Unmarshal(raw, &call)
+	return call.Name
+}
+
+// callFailed reports whether a tools/call response is a protocol error or a
+// result with isError set
+func callFailed(resp *Response) bool {
+	if resp == nil {
+		return false
+	}
+	if resp.Error != nil {
+		return true
+	}
+	switch result := resp.Result.(type) {
+	case *mcp.CallToolResult:
+		return result != nil && result.IsError
+	case json.RawMessage:
+		var r struct {
+			IsError bool `json:"isError"`
+		}
+		_ = json.Unmarshal(result, &r)
+		return r.IsError
+	default:
+		return false
+	}
+}
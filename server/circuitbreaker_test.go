@@ -0,0 +1,200 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// newFlakyServer registers a "weather" tool failing while failing is set and
+// an "echo" tool that always succeeds
+func newFlakyServer(cb *circuitBreaker, failing *atomic.Bool) (*Server, *atomic.Int32) {
+	var executions atomic.Int32
+	srv := New("test", WithMiddleware(cb.middleware))
+	_ = srv.AddTool(&ToolHandler{
+		Name: "weather",
+		Handler: func(_ context.Context, _ json.RawMessage) (interface{}, error) {
+			executions.Add(1)
+			if failing.Load() {
+				return nil, errors.New("upstream timeout")
+			}
+			return "sunny", nil
+		},
+	})
+	_ = srv.AddTool(&ToolHandler{
+		Name: "echo",
+		Handler: func(_ context.Context, _ json.RawMessage) (interface{}, error) {
+			return "echo", nil
+		},
+	})
+	return srv, &executions
+}
+
+func callToolResult(t *testing.T, srv *Server, name string) *mcp.CallToolResult {
+	t.Helper()
+	resp := srv.HandleMessage(context.Background(), &mcp.Message{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"` + name + `"}`),
+	})
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %s", resp.Error.Message)
+	}
+	var result mcp.CallToolResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatalf("failed to decode result: %v", err)
+	}
+	return &result
+}
+
+func TestCircuitBreaker_OpensAfterThreshold(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	cb := newCircuitBreaker(WithFailureThreshold(3))
+	srv, executions := newFlakyServer(cb, &failing)
+
+	for i := 0; i < 3; i++ {
+		if !callToolResult(t, srv, "weather").IsError {
+			t.Fatal("expected the tool to fail")
+		}
+	}
+
+	result := callToolResult(t, srv, "weather")
+	if !result.IsError || result.Content[0].(mcp.TextContent).Text != "tool weather is temporarily unavailable" {
+		t.Errorf("expected the fallback, got %+v", result)
+	}
+	if executions.Load() != 3 {
+		t.Errorf("expected the open circuit to skip the tool, ran %d times", executions.Load())
+	}
+
+	// Other tools have their own circuits
+	if callToolResult(t, srv, "echo").IsError {
+		t.Error("expected echo to be unaffected")
+	}
+}
+
+func TestCircuitBreaker_SuccessResetsFailures(t *testing.T) {
+	var failing atomic.Bool
+	cb := newCircuitBreaker(WithFailureThreshold(2))
+	srv, executions := newFlakyServer(cb, &failing)
+
+	failing.Store(true)
+	callToolResult(t, srv, "weather")
+	failing.Store(false)
+	callToolResult(t, srv, "weather")
+	failing.Store(true)
+	callToolResult(t, srv, "weather")
+	callToolResult(t, srv, "weather")
+
+	if executions.Load() != 4 {
+		t.Errorf("expected non-consecutive failures to keep the circuit closed, ran %d times", executions.Load())
+	}
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	var transitions []string
+	cb := newCircuitBreaker(
+		WithFailureThreshold(1),
+		WithOpenDuration(time.Minute),
+		WithCircuitStateChange(func(tool string, from, to CircuitState) {
+			transitions = append(transitions, tool+": "+from.String()+" -> "+to.String())
+		}),
+	)
+	now := time.Now()
+	cb.now = func() time.Time { return now }
+	srv, executions := newFlakyServer(cb, &failing)
+
+	callToolResult(t, srv, "weather")
+	now = now.Add(time.Minute)
+
+	// The trial call fails, so the circuit opens again
+	callToolResult(t, srv, "weather")
+	callToolResult(t, srv, "weather")
+	if executions.Load() != 2 {
+		t.Fatalf("expected a single trial call, ran %d times", executions.Load())
+	}
+
+	now = now.Add(time.Minute)
+	failing.Store(false)
+	if callToolResult(t, srv, "weather").IsError {
+		t.Fatal("expected the trial call to succeed")
+	}
+	callToolResult(t, srv, "weather")
+	if executions.Load() != 4 {
+		t.Errorf("expected the closed circuit to run the tool, ran %d times", executions.Load())
+	}
+
+	want := []string{
+		"weather: closed -> open",
+		"weather: open -> half-open",
+		"weather: half-open -> open",
+		"weather: open -> half-open",
+		"weather: half-open -> closed",
+	}
+	if len(transitions) != len(want) {
+		t.Fatalf("expected transitions %v, got %v", want, transitions)
+	}
+	for i := range want {
+		if transitions[i] != want[i] {
+			t.Errorf("transition %d: expected %q, got %q", i, want[i], transitions[i])
+		}
+	}
+}
+
+func TestCircuitBreaker_StaleCallsIgnored(t *testing.T) {
+	cb := newCircuitBreaker(WithFailureThreshold(1), WithOpenDuration(time.Minute))
+	now := time.Now()
+	cb.now = func() time.Time { return now }
+
+	stale, _ := cb.allow("weather")
+	failed, _ := cb.allow("weather")
+	cb.record("weather", failed, false)
+
+	now = now.Add(time.Minute)
+	trial, ok := cb.allow("weather")
+	if !ok {
+		t.Fatal("expected the trial call to be allowed")
+	}
+
+	// A call started before the circuit opened finishes during the trial
+	cb.record("weather", stale, true)
+	if state := cb.circuits["weather"].state; state != CircuitHalfOpen {
+		t.Fatalf("expected a stale call not to decide the circuit, got %s", state)
+	}
+
+	cb.record("weather", trial, false)
+	if state := cb.circuits["weather"].state; state != CircuitOpen {
+		t.Errorf("expected the failed trial call to open the circuit, got %s", state)
+	}
+}
+
+func TestCircuitBreaker_ToolsAndFallback(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	cb := newCircuitBreaker(
+		WithFailureThreshold(1),
+		WithCircuitTools("weather"),
+		WithCircuitFallback(func(_ context.Context, tool string) *mcp.CallToolResult {
+			return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "cached forecast"}}}
+		}),
+	)
+	srv, _ := newFlakyServer(cb, &failing)
+
+	callToolResult(t, srv, "weather")
+	result := callToolResult(t, srv, "weather")
+	if result.IsError || result.Content[0].(mcp.TextContent).Text != "cached forecast" {
+		t.Errorf("expected the custom fallback, got %+v", result)
+	}
+
+	if _, ok := cb.circuits["echo"]; ok {
+		t.Error("expected tools outside the list to have no circuit")
+	}
+}
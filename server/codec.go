@@ -0,0 +1,20 @@
+package server
+
+import "github.com/jmcarbo/fullmcp/internal/jsonrpc"
+
+// Codec encodes and decodes JSON messages and results. It must behave like
+// encoding/json, which is the default unless the binary is built with the
+// jsoniter tag.
+type Codec = jsonrpc.Codec
+
+// Decoder reads successive JSON values from a stream for a Codec
+type Decoder = jsonrpc.Decoder
+
+// WithCodec replaces the JSON codec used for messages read and written by
+// Serve and for encoding results, such as an adapter for
+// github.com/bytedance/sonic
+func WithCodec(codec Codec) Option {
+	return func(s *Server) {
+		s.codec = codec
+	}
+}
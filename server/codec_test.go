@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/internal/jsonrpc"
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+type countingCodec struct {
+	jsonrpc.StdCodec
+	marshals   atomic.Int64
+	unmarshals atomic.Int64
+}
+
+func (c *countingCodec) Marshal(v interface{}) ([]byte, error) {
+	c.marshals.Add(1)
+	return c.StdCodec.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v interface{}) error {
+	c.unmarshals.Add(1)
+	return c.StdCodec.Unmarshal(data, v)
+}
+
+func TestWithCodec(t *testing.T) {
+	codec := &countingCodec{}
+	srv := New("test", WithCodec(codec))
+	_ = srv.AddTool(&ToolHandler{
+		Name: "echo",
+		Handler: func(_ context.Context, args json.RawMessage) (interface{}, error) {
+			return string(args), nil
+		},
+	})
+
+	reader, writer := servePipe(t, srv)
+	_ = writer.Write(&mcp.Message{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: json.RawMessage(`{"name":"echo","arguments":{"x":1}}`)})
+	resp := readMessage(t, reader)
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+
+	// The request and its params are decoded, and the result and response
+	// encoded, with the codec
+	if codec.unmarshals.Load() < 1 || codec.marshals.Load() < 2 {
+		t.Errorf("expected the server to use the codec, got %d unmarshals and %d marshals",
+			codec.unmarshals.Load(), codec.marshals.Load())
+	}
+}
@@ -2,6 +2,9 @@ package server
 
 import (
 	"context"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/jmcarbo/fullmcp/mcp"
 )
@@ -35,25 +38,90 @@ func (cm *CompletionManager) RegisterResourceCompletion(uri string, handler Comp
 
 // GetCompletion returns completion suggestions
 func (cm *CompletionManager) GetCompletion(ctx context.Context, ref mcp.CompletionRef, arg mcp.CompletionArgument) ([]string, error) {
+	handler, exists, err := cm.handler(ref)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		// No handler registered, return empty completions
+		return []string{}, nil
+	}
+
+	return handler(ctx, ref, arg)
+}
+
+// handler returns the handler registered for ref. A nil manager, on servers
+// without WithCompletion, has none.
+func (cm *CompletionManager) handler(ref mcp.CompletionRef) (CompletionHandler, bool, error) {
+	if cm == nil {
+		return nil, false, nil
+	}
+
 	var key string
 	if ref.Type == "ref/prompt" {
 		key = "prompt:" + ref.Name
 	} else if ref.Type == "ref/resource" {
-		key = "resource:" + ref.Name
+		key = "resource:" + resourceRefURI(ref)
 	} else {
-		return nil, &mcp.Error{
-			Code:    mcp.InvalidParams,
-			Message: "invalid reference type",
-		}
+		return nil, false, mcp.NewInvalidParams("invalid reference type")
 	}
 
 	handler, exists := cm.handlers[key]
-	if !exists {
-		// No handler registered, return empty completions
-		return []string{}, nil
+	return handler, exists, nil
+}
+
+// resourceRefURI returns the URI a ref/resource reference points at, which
+// spec clients send as uri and older clients as name
+func resourceRefURI(ref mcp.CompletionRef) string {
+	if ref.URI != "" {
+		return ref.URI
 	}
+	return ref.Name
+}
 
-	return handler(ctx, ref, arg)
+// maxCompletionValues is the most values a completion response may hold
+const maxCompletionValues = 100
+
+// completeURI suggests values for a ref/resource completion without a
+// registered handler. When ref names a template and the argument is one of
+// its variables, the suggestions are the values that variable takes in the
+// URIs of matching concrete resources. Otherwise the argument is taken as a
+// partial URI and completed with concrete resource URIs and the literal
+// prefixes of templates.
+func (rm *ResourceManager) completeURI(refURI string, arg mcp.CompletionArgument) []string {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	values := []string{}
+	add := func(value string) {
+		if value != "" && strings.HasPrefix(value, arg.Value) && !seen[value] {
+			seen[value] = true
+			values = append(values, value)
+		}
+	}
+
+	if tmpl, ok := rm.templates[refURI]; ok && slices.Contains(tmpl.pattern.SubexpNames(), arg.Name) {
+		for uri := range rm.resources {
+			if params, ok := tmpl.Match(uri); ok {
+				add(params[arg.Name])
+			}
+		}
+	} else {
+		for uri := range rm.resources {
+			add(uri)
+		}
+		for uriTemplate := range rm.templates {
+			prefix, _, _ := strings.Cut(uriTemplate, "{")
+			add(prefix)
+		}
+	}
+
+	sort.Strings(values)
+	if len(values) > maxCompletionValues {
+		values = values[:maxCompletionValues]
+	}
+	return values
 }
 
 // WithCompletion enables completion support
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func newCompletionServer(t *testing.T) *Server {
+	t.Helper()
+	srv := New("test", WithCompletion())
+	reader := func(_ context.Context) ([]byte, error) { return nil, nil }
+	for _, uri := range []string{"users://alice/profile", "users://bob/profile", "users://bob/settings", "config://app"} {
+		_ = srv.AddResource(&ResourceHandler{URI: uri, Reader: reader})
+	}
+	_ = srv.AddResourceTemplate(&ResourceTemplateHandler{
+		URITemplate: "users://{user}/profile",
+		Reader: func(_ context.Context, _ map[string]string) ([]byte, error) {
+			return nil, nil
+		},
+	})
+	return srv
+}
+
+func complete(t *testing.T, srv *Server, params string) []string {
+	t.Helper()
+	resp := srv.HandleMessage(context.Background(), &mcp.Message{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "completion/complete",
+		Params:  json.RawMessage(params),
+	})
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %s", resp.Error.Message)
+	}
+	var result mcp.CompleteResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatalf("failed to decode result: %v", err)
+	}
+	return result.Completion.Values
+}
+
+func TestCompletion_ResourceTemplateVariable(t *testing.T) {
+	srv := newCompletionServer(t)
+
+	values := complete(t, srv, `{"ref":{"type":"ref/resource","uri":"users://{user}/profile"},"argument":{"name":"user","value":""}}`)
+	if want := []string{"alice", "bob"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("expected %v, got %v", want, values)
+	}
+
+	values = complete(t, srv, `{"ref":{"type":"ref/resource","uri":"users://{user}/profile"},"argument":{"name":"user","value":"b"}}`)
+	if want := []string{"bob"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("expected %v, got %v", want, values)
+	}
+}
+
+func TestCompletion_ResourcePartialURI(t *testing.T) {
+	srv := newCompletionServer(t)
+
+	values := complete(t, srv, `{"ref":{"type":"ref/resource","name":"users://"},"argument":{"name":"uri","value":"users://b"}}`)
+	if want := []string{"users://bob/profile", "users://bob/settings"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("expected %v, got %v", want, values)
+	}
+
+	values = complete(t, srv, `{"ref":{"type":"ref/resource","name":"users://"},"argument":{"name":"uri","value":"u"}}`)
+	want := []string{"users://", "users://alice/profile", "users://bob/profile", "users://bob/settings"}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("expected the template prefix and resources %v, got %v", want, values)
+	}
+}
+
+func TestCompletion_RegisteredHandlerTakesPrecedence(t *testing.T) {
+	srv := newCompletionServer(t)
+	srv.RegisterResourceCompletion("users://{user}/profile", func(_ context.Context, _ mcp.CompletionRef, _ mcp.CompletionArgument) ([]string, error) {
+		return []string{"carol"}, nil
+	})
+
+	values := complete(t, srv, `{"ref":{"type":"ref/resource","uri":"users://{user}/profile"},"argument":{"name":"user","value":""}}`)
+	if want := []string{"carol"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("expected %v, got %v", want, values)
+	}
+}
+
+func TestCompletion_WithoutCompletionOption(t *testing.T) {
+	srv := New("x")
+	_ = srv.AddResource(&ResourceHandler{URI: "users://alice/profile", Reader: func(_ context.Context) ([]byte, error) {
+		return nil, nil
+	}})
+	_ = srv.AddResourceTemplate(&ResourceTemplateHandler{
+		URITemplate: "users://{user}/profile",
+		Reader: func(_ context.Context, _ map[string]string) ([]byte, error) {
+			return nil, nil
+		},
+	})
+
+	values := complete(t, srv, `{"ref":{"type":"ref/resource","uri":"users://{user}/profile"},"argument":{"name":"user","value":""}}`)
+	if want := []string{"alice"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("expected %v, got %v", want, values)
+	}
+	if caps := initializeCapabilities(t, srv); caps["completions"] == nil {
+		t.Error("expected the completions capability with resource templates registered")
+	}
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+)
+
+// ConnInfo describes the connection a request arrived on, so handlers can
+// make transport-aware decisions
+type ConnInfo struct {
+	SessionID       string               // Server session, or the Mcp-Session-Id header over HTTP
+	RemoteAddr      string               // Peer address, when the transport has one
+	TLS             *tls.ConnectionState // Set for TLS connections
+	Header          http.Header          // Request headers (HTTP transports only)
+	ProtocolVersion string               // Negotiated during initialize
+	Stateless       bool                 // The transport can't send server-initiated messages
+}
+
+// ContextWithConnInfo returns a context carrying info. Serve adds it for its
+// connections; embedders that call HandleMessage directly can use it or
+// ConnInfoMiddleware.
+func ContextWithConnInfo(ctx context.Context, info *ConnInfo) context.Context {
+	return context.WithValue(ctx, connInfoContextKey, info)
+}
+
+// ConnInfoFromContext returns the connection metadata of the request being
+// handled
+func ConnInfoFromContext(ctx context.Context) (*ConnInfo, bool) {
+	info, ok := ctx.Value(connInfoContextKey).(*ConnInfo)
+	if !ok {
+		return nil, false
+	}
+
+	result := *info
+	if ss := sessionFromContext(ctx); ss != nil && result.ProtocolVersion == "" {
+		result.ProtocolVersion = ss.protocolVersion()
+	}
+	return &result, true
+}
+
+// ConnInfoFromRequest builds connection metadata from an HTTP request
+func ConnInfoFromRequest(r *http.Request) *ConnInfo {
+	return &ConnInfo{
+		SessionID:       r.Header.Get("Mcp-Session-Id"),
+		RemoteAddr:      r.RemoteAddr,
+		TLS:             r.TLS,
+		Header:          r.Header.Clone(),
+		ProtocolVersion: r.Header.Get("MCP-Protocol-Version"),
+	}
+}
+
+// ConnInfoMiddleware adds the request's connection metadata to its context,
+// for HTTP handlers that pass r.Context() to HandleMessage
+func ConnInfoMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := ContextWithConnInfo(r.Context(), ConnInfoFromRequest(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// connInfoFor describes a connection served by Serve. Network connections
+// report their remote address and TLS state.
+func connInfoFor(conn io.ReadWriteCloser, sessionID string) *ConnInfo {
+	info := &ConnInfo{SessionID: sessionID}
+
+	if c, ok := conn.(interface{ RemoteAddr() net.Addr }); ok && c.RemoteAddr() != nil {
+		info.RemoteAddr = c.RemoteAddr().String()
+	}
+	if c, ok := conn.(interface{ ConnectionState() tls.ConnectionState }); ok {
+		state := c.ConnectionState()
+		info.TLS = &state
+	}
+	return info
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func TestServer_ConnInfoFromContext(t *testing.T) {
+	srv := New("test")
+	infos := make(chan *ConnInfo, 2)
+	_ = srv.AddTool(&ToolHandler{
+		Name: "whoami",
+		Handler: func(ctx context.Context, _ json.RawMessage) (interface{}, error) {
+			info, ok := ConnInfoFromContext(ctx)
+			if !ok {
+				t.Error("expected connection info")
+			}
+			infos <- info
+			return "ok", nil
+		},
+	})
+
+	reader, writer := servePipe(t, srv)
+	call := &mcp.Message{JSONRPC: "2.0", ID: 2, Method: "tools/call", Params: json.RawMessage(`{"name":"whoami"}`)}
+
+	_ = writer.Write(call)
+	readMessage(t, reader)
+	before := <-infos
+	if before.SessionID == "" {
+		t.Error("expected a session ID")
+	}
+	if before.ProtocolVersion != "" {
+		t.Errorf("expected no protocol version before initialize, got %q", before.ProtocolVersion)
+	}
+
+	_ = writer.Write(&mcp.Message{JSONRPC: "2.0", ID: 3, Method: "initialize", Params: json.RawMessage(`{}`)})
+	readMessage(t, reader)
+	_ = writer.Write(call)
+	readMessage(t, reader)
+
+	after := <-infos
+	if after.ProtocolVersion != protocolVersion {
+		t.Errorf("expected protocol version %q, got %q", protocolVersion, after.ProtocolVersion)
+	}
+	if after.SessionID != before.SessionID {
+		t.Errorf("session ID changed from %q to %q", before.SessionID, after.SessionID)
+	}
+}
+
+func TestConnInfoFromContext_Missing(t *testing.T) {
+	if _, ok := ConnInfoFromContext(context.Background()); ok {
+		t.Error("expected no connection info")
+	}
+}
+
+type fakeNetConn struct {
+	net.Conn
+	addr net.Addr
+}
+
+func (c *fakeNetConn) RemoteAddr() net.Addr { return c.addr }
+
+func (c *fakeNetConn) ConnectionState() tls.ConnectionState {
+	return tls.ConnectionState{ServerName: "example.com"}
+}
+
+func TestConnInfoFor_NetConn(t *testing.T) {
+	addr := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 4242}
+	info := connInfoFor(&fakeNetConn{addr: addr}, "abc")
+
+	if info.SessionID != "abc" {
+		t.Errorf("unexpected session ID %q", info.SessionID)
+	}
+	if info.RemoteAddr != "10.0.0.1:4242" {
+		t.Errorf("unexpected remote address %q", info.RemoteAddr)
+	}
+	if info.TLS == nil || info.TLS.ServerName != "example.com" {
+		t.Errorf("unexpected TLS state %+v", info.TLS)
+	}
+}
+
+func TestConnInfoMiddleware(t *testing.T) {
+	var got *ConnInfo
+	handler := ConnInfoMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got, _ = ConnInfoFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	req.Header.Set("Mcp-Session-Id", "session-1")
+	req.Header.Set("MCP-Protocol-Version", "2025-06-18")
+	req.Header.Set("X-Tenant", "acme")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got == nil {
+		t.Fatal("expected connection info")
+	}
+	if got.SessionID != "session-1" || got.ProtocolVersion != "2025-06-18" {
+		t.Errorf("unexpected session or version: %+v", got)
+	}
+	if got.RemoteAddr != "192.0.2.1:1234" {
+		t.Errorf("unexpected remote address %q", got.RemoteAddr)
+	}
+	if got.Header.Get("X-Tenant") != "acme" {
+		t.Errorf("expected request headers, got %v", got.Header)
+	}
+}
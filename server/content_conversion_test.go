@@ -2,6 +2,7 @@ package server
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
 
 	"github.com/jmcarbo/fullmcp/mcp"
@@ -235,3 +236,31 @@ func TestConvertToContent_PreservesContentTypes(t *testing.T) {
 		t.Error("third item should be AudioContent")
 	}
 }
+
+func TestConvertToContent_ResourcePassThrough(t *testing.T) {
+	embedded := mcp.NewEmbeddedResource("file:///out.bin", "application/octet-stream", []byte{1, 2, 3})
+	link := mcp.NewResourceLink(mcp.Resource{URI: "file:///report.pdf", Name: "report"}, nil)
+
+	tests := []struct {
+		name  string
+		input interface{}
+		want  []mcp.Content
+	}{
+		{"embedded", embedded, []mcp.Content{embedded}},
+		{"link", link, []mcp.Content{link}},
+		{"mixed", []mcp.Content{embedded, link}, []mcp.Content{embedded, link}},
+		{"typed slice", []mcp.ResourceContent{embedded, embedded}, []mcp.Content{embedded, embedded}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := convertToContent(tt.input)
+			if err != nil {
+				t.Fatalf("convertToContent failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("content modified:\ngot  %#v\nwant %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -6,8 +6,10 @@ import "context"
 type contextKey string
 
 const (
-	serverContextKey  contextKey = "mcp.server"
-	sessionContextKey contextKey = "mcp.session"
+	serverContextKey   contextKey = "mcp.server"
+	sessionContextKey  contextKey = "mcp.session"
+	connInfoContextKey contextKey = "mcp.conninfo"
+	toolCallContextKey contextKey = "mcp.toolcall"
 )
 
 // Context provides access to server capabilities from within handlers
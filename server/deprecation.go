@@ -0,0 +1,32 @@
+package server
+
+import "github.com/jmcarbo/fullmcp/mcp"
+
+// deprecationLogger is the logger name of deprecation warnings
+const deprecationLogger = "deprecation"
+
+// withDeprecation returns meta with the deprecation message added under
+// mcp.DeprecatedMeta, copying it so handler metadata isn't modified. Meta is
+// returned unchanged when message is empty.
+func withDeprecation(meta map[string]interface{}, message string) map[string]interface{} {
+	if message == "" {
+		return meta
+	}
+	merged := make(map[string]interface{}, len(meta)+1)
+	for k, v := range meta {
+		merged[k] = v
+	}
+	merged[mcp.DeprecatedMeta] = message
+	return merged
+}
+
+// warnDeprecated counts a use of a deprecated tool or prompt and warns the
+// client with a logging notification, so operators can find agents still
+// using it
+func (s *Server) warnDeprecated(kind, name, message string) {
+	s.stats.recordDeprecated(kind + ":" + name)
+	_ = s.LogWarning(deprecationLogger, map[string]interface{}{
+		kind:      name,
+		"message": kind + " " + name + " is deprecated: " + message,
+	})
+}
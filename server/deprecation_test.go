@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func newDeprecationServer(t *testing.T) (*Server, *[]*mcp.LogMessage) {
+	t.Helper()
+	srv := New("test", EnableLogging())
+	var warnings []*mcp.LogMessage
+	srv.logging.SetSender(func(msg *mcp.LogMessage) error {
+		warnings = append(warnings, msg)
+		return nil
+	})
+	srv.logging.SetLevel(mcp.LogLevelInfo)
+
+	_ = srv.AddTool(&ToolHandler{
+		Name:       "search_v1",
+		Meta:       map[string]interface{}{"owner": "search-team"},
+		Deprecated: "use search_v2",
+		Handler: func(context.Context, json.RawMessage) (interface{}, error) {
+			return "results", nil
+		},
+	})
+	_ = srv.AddPrompt(&PromptHandler{
+		Name:       "summarize_old",
+		Deprecated: "use summarize",
+		Renderer: func(context.Context, map[string]interface{}) ([]*mcp.PromptMessage, error) {
+			return nil, nil
+		},
+	})
+	return srv, &warnings
+}
+
+func TestDeprecatedTool(t *testing.T) {
+	srv, warnings := newDeprecationServer(t)
+	ctx := context.Background()
+
+	tools, _ := srv.tools.List(ctx)
+	if tools[0].Deprecation() != "use search_v2" || tools[0].Meta["owner"] != "search-team" {
+		t.Errorf("expected the listing to carry the deprecation, got %v", tools[0].Meta)
+	}
+
+	for i := 0; i < 2; i++ {
+		resp := srv.HandleMessage(ctx, &mcp.Message{
+			JSONRPC: "2.0", ID: i, Method: "tools/call",
+			Params: json.RawMessage(`{"name":"search_v1","arguments":{}}`),
+		})
+		var result mcp.CallToolResult
+		_ = json.Unmarshal(resp.Result, &result)
+		if result.Meta[mcp.DeprecatedMeta] != "use search_v2" {
+			t.Errorf("expected the result to carry the deprecation, got %s", resp.Result)
+		}
+	}
+
+	if got := srv.Stats().Deprecated["tool:search_v1"]; got != 2 {
+		t.Errorf("expected 2 deprecated calls, got %d", got)
+	}
+	if len(*warnings) != 2 {
+		t.Fatalf("expected a warning per call, got %d", len(*warnings))
+	}
+	warning := (*warnings)[0]
+	if warning.Level != mcp.LogLevelWarning || warning.Logger != "deprecation" || warning.Data["tool"] != "search_v1" {
+		t.Errorf("unexpected warning %+v", warning)
+	}
+}
+
+func TestDeprecatedPrompt(t *testing.T) {
+	srv, warnings := newDeprecationServer(t)
+
+	if prompts := srv.prompts.List(); prompts[0].Deprecation() != "use summarize" {
+		t.Errorf("expected the listing to carry the deprecation, got %v", prompts[0].Meta)
+	}
+
+	resp := srv.HandleMessage(context.Background(), &mcp.Message{
+		JSONRPC: "2.0", ID: 1, Method: "prompts/get",
+		Params: json.RawMessage(`{"name":"summarize_old"}`),
+	})
+	var result struct {
+		Meta map[string]interface{} `json:"_meta"`
+	}
+	_ = json.Unmarshal(resp.Result, &result)
+	if result.Meta[mcp.DeprecatedMeta] != "use summarize" {
+		t.Errorf("expected the result to carry the deprecation, got %s", resp.Result)
+	}
+	if srv.Stats().Deprecated["prompt:summarize_old"] != 1 || len(*warnings) != 1 {
+		t.Errorf("expected the use to be counted and warned about")
+	}
+}
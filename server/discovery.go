@@ -0,0 +1,16 @@
+package server
+
+import "github.com/jmcarbo/fullmcp/discovery"
+
+// Discovery returns a discovery document naming the server and the protocol
+// versions it speaks. Add the endpoints it is served on and the
+// authentication it requires, then publish it with the WithDiscovery option
+// of the streamhttp or websocket server.
+func (s *Server) Discovery() *discovery.Document {
+	return &discovery.Document{
+		Name:             s.name,
+		Title:            s.title,
+		Version:          s.version,
+		ProtocolVersions: []string{protocolVersion},
+	}
+}
@@ -0,0 +1,18 @@
+package server
+
+import "testing"
+
+func TestServer_Discovery(t *testing.T) {
+	srv := New("inventory", WithVersion("1.2.0"), WithTitle("Inventory"))
+
+	doc := srv.Discovery()
+	if doc.Name != "inventory" || doc.Title != "Inventory" || doc.Version != "1.2.0" {
+		t.Errorf("expected the server's identity, got %+v", doc)
+	}
+	if len(doc.ProtocolVersions) != 1 || doc.ProtocolVersions[0] != protocolVersion {
+		t.Errorf("expected protocol versions [%s], got %v", protocolVersion, doc.ProtocolVersions)
+	}
+	if doc.Endpoints != nil || doc.Authentication != nil {
+		t.Errorf("expected endpoints and authentication to be left to the caller, got %+v", doc)
+	}
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+const errorMapperContextKey contextKey = "mcp.errormapper"
+
+// internalErrorMessage replaces the message of errors no rule maps, so
+// internal details such as SQL or file paths don't reach the client
+const internalErrorMessage = "internal error"
+
+// ErrorMapper converts the errors returned by tool, resource, prompt and
+// completion handlers to protocol errors. Rules are tried in the order they
+// were added; *mcp.Error values pass through unchanged, and mcp.NotFoundError
+// and mcp.ValidationError get their usual codes. Any other error becomes an
+// InternalError with a generic message.
+type ErrorMapper struct {
+	rules      []func(error) *mcp.Error
+	onUnmapped func(error)
+}
+
+// NewErrorMapper creates an error mapper without rules
+func NewErrorMapper() *ErrorMapper {
+	return &ErrorMapper{}
+}
+
+// Map converts errors matching target, as reported by errors.Is, to code.
+// An empty message uses target's own message.
+func (m *ErrorMapper) Map(target error, code mcp.ErrorCode, message string) *ErrorMapper {
+	if message == "" {
+		message = target.Error()
+	}
+	return m.MapFunc(func(err error) *mcp.Error {
+		if errors.Is(err, target) {
+			return &mcp.Error{Code: code, Message: message}
+		}
+		return nil
+	})
+}
+
+// MapFunc adds a rule that converts errors it recognizes and returns nil
+// for the rest
+func (m *ErrorMapper) MapFunc(fn func(error) *mcp.Error) *ErrorMapper {
+	m.rules = append(m.rules, fn)
+	return m
+}
+
+// MapErrorType adds a rule to m converting errors of type T, as reported by
+// errors.As, with fn
+func MapErrorType[T error](m *ErrorMapper, fn func(T) *mcp.Error) *ErrorMapper {
+	return m.MapFunc(func(err error) *mcp.Error {
+		var target T
+		if errors.As(err, &target) {
+			return fn(target)
+		}
+		return nil
+	})
+}
+
+// OnUnmapped sets a function called with errors no rule maps before their
+// message is hidden, such as a logger
+func (m *ErrorMapper) OnUnmapped(fn func(error)) *ErrorMapper {
+	m.onUnmapped = fn
+	return m
+}
+
+// Convert returns the protocol error for err
+func (m *ErrorMapper) Convert(err error) *mcp.Error {
+	mcpErr, _ := m.convert(err)
+	return mcpErr
+}
+
+// convert returns the protocol error for err and whether a rule or built-in
+// conversion matched it
+func (m *ErrorMapper) convert(err error) (*mcp.Error, bool) {
+	var mcpErr *mcp.Error
+	if errors.As(err, &mcpErr) {
+		return mcpErr, true
+	}
+
+	for _, rule := range m.rules {
+		if mapped := rule(err); mapped != nil {
+			return mapped, true
+		}
+	}
+
+	var validationErr *mcp.ValidationError
+	if errors.As(err, &validationErr) {
+		if validationErr.Field == "" {
+			return mcp.NewInvalidParams("validation error: " + validationErr.Message), true
+		}
+		invalid := mcp.NewInvalidParams(validationErr.Error())
+		invalid.Data = map[string]interface{}{"field": validationErr.Field}
+		return invalid, true
+	}
+
+	var notFound *mcp.NotFoundError
+	if errors.As(err, &notFound) {
+		return mcp.ToError(notFound), true
+	}
+
+	if m.onUnmapped != nil {
+		m.onUnmapped(err)
+	}
+	return mcp.NewInternalError(internalErrorMessage), false
+}
+
+// ErrorMappingMiddleware converts handler errors with m, so tool authors can
+// return idiomatic Go errors such as sql.ErrNoRows without exposing their
+// messages. Registered tool errors fail the request with their protocol
+// error instead of producing a result with isError set; unregistered ones
+// still produce such a result, with a generic message.
+func ErrorMappingMiddleware(m *ErrorMapper) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			resp, err := next(context.WithValue(ctx, errorMapperContextKey, m), req)
+			if err != nil {
+				return &Response{Error: m.Convert(err).RPCError()}, nil
+			}
+			return resp, nil
+		}
+	}
+}
+
+// mapError converts err with the error mapper in ctx, if any
+func mapError(ctx context.Context, err error) error {
+	if m, ok := ctx.Value(errorMapperContextKey).(*ErrorMapper); ok {
+		return m.Convert(err)
+	}
+	return err
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+var errNoRows = errors.New("no rows in result set")
+
+type quotaError struct {
+	Remaining int
+}
+
+func (e *quotaError) Error() string {
+	return fmt.Sprintf("quota exceeded, %d remaining", e.Remaining)
+}
+
+func TestErrorMapper_Convert(t *testing.T) {
+	var unmapped []error
+	m := NewErrorMapper().
+		Map(errNoRows, mcp.ResourceNotFound, "record not found").
+		OnUnmapped(func(err error) { unmapped = append(unmapped, err) })
+	MapErrorType(m, func(e *quotaError) *mcp.Error {
+		return &mcp.Error{Code: mcp.InvalidRequest, Message: "quota exceeded", Data: map[string]interface{}{"remaining": e.Remaining}}
+	})
+
+	got := m.Convert(fmt.Errorf("loading user 42: %w", errNoRows))
+	if got.Code != mcp.ResourceNotFound || got.Message != "record not found" {
+		t.Errorf("unexpected sentinel mapping: %+v", got)
+	}
+
+	got = m.Convert(fmt.Errorf("wrapped: %w", &quotaError{Remaining: 3}))
+	if got.Code != mcp.InvalidRequest || got.Data.(map[string]interface{})["remaining"] != 3 {
+		t.Errorf("unexpected type mapping: %+v", got)
+	}
+
+	got = m.Convert(&mcp.ValidationError{Field: "email", Message: "must contain @"})
+	if got.Code != mcp.InvalidParams || got.Data.(map[string]interface{})["field"] != "email" {
+		t.Errorf("unexpected validation mapping: %+v", got)
+	}
+
+	direct := mcp.NewInvalidRequest("bad")
+	if got := m.Convert(fmt.Errorf("wrapped: %w", direct)); got != direct {
+		t.Errorf("expected *mcp.Error to pass through, got %+v", got)
+	}
+
+	internal := errors.New("dial tcp 10.0.0.5:5432: connection refused")
+	got = m.Convert(internal)
+	if got.Code != mcp.InternalError || strings.Contains(got.Message, "10.0.0.5") {
+		t.Errorf("expected internal message to be hidden, got %+v", got)
+	}
+	if len(unmapped) != 1 || unmapped[0] != internal {
+		t.Errorf("expected OnUnmapped to see the error, got %v", unmapped)
+	}
+}
+
+func TestErrorMappingMiddleware(t *testing.T) {
+	m := NewErrorMapper().Map(errNoRows, mcp.ResourceNotFound, "record not found")
+	srv := New("test", WithMiddleware(ErrorMappingMiddleware(m)))
+	_ = srv.AddTool(&ToolHandler{
+		Name: "lookup",
+		Handler: func(_ context.Context, args json.RawMessage) (interface{}, error) {
+			if strings.Contains(string(args), "missing") {
+				return nil, fmt.Errorf("query users: %w", errNoRows)
+			}
+			return nil, errors.New("pq: password authentication failed for user \"admin\"")
+		},
+	})
+	_ = srv.AddResource(&ResourceHandler{
+		URI:  "db://users",
+		Name: "users",
+		Reader: func(context.Context) ([]byte, error) {
+			return nil, errors.New("open /var/lib/app/users.db: permission denied")
+		},
+	})
+
+	call := func(method, params string) *mcp.Message {
+		return srv.HandleMessage(context.Background(), &mcp.Message{
+			JSONRPC: "2.0",
+			ID:      1,
+			Method:  method,
+			Params:  json.RawMessage(params),
+		})
+	}
+
+	resp := call("tools/call", `{"name":"lookup","arguments":{"id":"missing"}}`)
+	if resp.Error == nil || resp.Error.Code != int(mcp.ResourceNotFound) || resp.Error.Message != "record not found" {
+		t.Fatalf("expected mapped tool error to fail the request, got %+v", resp)
+	}
+
+	resp = call("tools/call", `{"name":"lookup","arguments":{}}`)
+	if resp.Error != nil {
+		t.Fatalf("expected unmapped tool error as a result, got %v", resp.Error)
+	}
+	var result mcp.CallToolResult
+	_ = json.Unmarshal(resp.Result, &result)
+	if !result.IsError || strings.Contains(string(resp.Result), "password") {
+		t.Errorf("expected hidden tool error, got %s", resp.Result)
+	}
+
+	resp = call("resources/read", `{"uri":"db://users"}`)
+	if resp.Error == nil || resp.Error.Code != int(mcp.InternalError) || strings.Contains(resp.Error.Message, "/var/lib") {
+		t.Errorf("expected hidden resource error, got %+v", resp.Error)
+	}
+
+	resp = call("resources/read", `{"uri":"db://other"}`)
+	if resp.Error == nil || resp.Error.Code != int(mcp.ResourceNotFound) {
+		t.Errorf("expected unknown resource to keep its code, got %+v", resp.Error)
+	}
+}
+
+func TestErrorMappingMiddleware_MiddlewareErrors(t *testing.T) {
+	m := NewErrorMapper().Map(errNoRows, mcp.ResourceNotFound, "")
+	failing := func(Handler) Handler {
+		return func(context.Context, *Request) (*Response, error) {
+			return nil, errNoRows
+		}
+	}
+
+	resp, err := ErrorMappingMiddleware(m)(failing(nil))(context.Background(), &Request{Method: "tools/list"})
+	if err != nil {
+		t.Fatalf("expected error to be converted, got %v", err)
+	}
+	if resp.Error == nil || resp.Error.Code != int(mcp.ResourceNotFound) || resp.Error.Message != errNoRows.Error() {
+		t.Errorf("unexpected response: %+v", resp.Error)
+	}
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"context"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// WithExperimental declares an experimental capability, advertised to
+// clients during initialize. settings is the experiment's settings object;
+// nil declares it with no settings.
+func WithExperimental(name string, settings interface{}) Option {
+	return func(s *Server) {
+		if s.experimental == nil {
+			s.experimental = make(mcp.Experiments)
+		}
+		if settings == nil {
+			settings = map[string]interface{}{}
+		}
+		s.experimental[name] = settings
+	}
+}
+
+// ClientCapabilitiesFromContext returns the capabilities the session's
+// client declared during initialize
+func ClientCapabilitiesFromContext(ctx context.Context) (*mcp.ClientCapabilities, bool) {
+	ss := sessionFromContext(ctx)
+	if ss == nil {
+		return nil, false
+	}
+	caps := ss.clientCaps.Load()
+	return caps, caps != nil
+}
+
+// ClientExperiments returns the experimental capabilities the session's
+// client declared, or nil
+func ClientExperiments(ctx context.Context) mcp.Experiments {
+	if caps, ok := ClientCapabilitiesFromContext(ctx); ok {
+		return caps.Experimental
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func TestServer_Experimental(t *testing.T) {
+	srv := New("test",
+		WithExperimental("example.com/streaming", map[string]interface{}{"maxChunk": 1024}),
+		WithExperimental("example.com/flag", nil),
+	)
+
+	experiments := make(chan mcp.Experiments, 1)
+	_ = srv.AddTool(&ToolHandler{
+		Name: "check",
+		Handler: func(ctx context.Context, _ json.RawMessage) (interface{}, error) {
+			experiments <- ClientExperiments(ctx)
+			return "ok", nil
+		},
+	})
+
+	reader, writer := servePipe(t, srv)
+	_ = writer.Write(&mcp.Message{JSONRPC: "2.0", ID: 2, Method: "initialize", Params: json.RawMessage(
+		`{"protocolVersion":"2025-06-18","capabilities":{"experimental":{"example.com/diff":{"format":"unified"}}},"clientInfo":{"name":"c","version":"1"}}`)})
+	resp := readMessage(t, reader)
+
+	var result struct {
+		Capabilities mcp.ServerCapabilities `json:"capabilities"`
+	}
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatalf("failed to decode initialize result: %v", err)
+	}
+	if !result.Capabilities.Experimental.Has("example.com/streaming") || !result.Capabilities.Experimental.Has("example.com/flag") {
+		t.Errorf("expected experiments to be advertised, got %v", result.Capabilities.Experimental)
+	}
+
+	_ = writer.Write(&mcp.Message{JSONRPC: "2.0", ID: 3, Method: "tools/call", Params: json.RawMessage(`{"name":"check"}`)})
+	readMessage(t, reader)
+
+	got := <-experiments
+	var settings struct {
+		Format string `json:"format"`
+	}
+	if found, err := got.Decode("example.com/diff", &settings); !found || err != nil || settings.Format != "unified" {
+		t.Errorf("unexpected client experiment: found=%v settings=%+v err=%v", found, settings, err)
+	}
+}
+
+func TestClientExperiments_NoSession(t *testing.T) {
+	if got := ClientExperiments(context.Background()); got != nil {
+		t.Errorf("expected nil outside a session, got %v", got)
+	}
+}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/auth"
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// WithIdempotency remembers the result of each tools/call request carrying
+// an idempotency key in its _meta for window, so a client retrying a call
+// after a timeout gets the original result instead of running a
+// non-idempotent tool twice. A duplicate arriving while the original call
+// runs waits for it. Keys are scoped to the authenticated subject or, on
+// servers without authentication, to the session, so one client can't
+// replay another's result by presenting its key. Reusing a key for a
+// different tool or arguments is rejected. Calls that fail with a protocol
+// error are not remembered, so they can be retried.
+func WithIdempotency(window time.Duration) Option {
+	return func(s *Server) {
+		s.idempotency = &idempotencyCache{
+			window:  window,
+			now:     time.Now,
+			entries: make(map[string]*idempotentCall),
+		}
+	}
+}
+
+// idempotencyCache holds tools/call responses by idempotency key
+type idempotencyCache struct {
+	window time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]*idempotentCall
+	nextPrune time.Time
+}
+
+// idempotentCall is a tools/call request that is running or has completed
+type idempotentCall struct {
+	fingerprint [sha256.Size]byte // Of the tool name and arguments
+	done        chan struct{}     // Closed once response is set
+	response    *mcp.Message
+	expires     time.Time // Zero while the call runs
+}
+
+// do runs call for the first request with key and answers duplicates with
+// its response
+func (c *idempotencyCache) do(ctx context.Context, id interface{}, key string, params *mcp.CallToolRequest, call func() *mcp.Message) *mcp.Message {
+	key = idempotencyScope(ctx) + "\x00" + key
+	fingerprint := callFingerprint(params)
+
+	c.mu.Lock()
+	c.prune()
+	if existing, ok := c.lookup(key); ok {
+		c.mu.Unlock()
+		return c.replay(ctx, id, existing, fingerprint)
+	}
+	entry := &idempotentCall{fingerprint: fingerprint, done: make(chan struct{})}
+	c.entries[key] = entry
+	c.mu.Unlock()
+
+	// Deferred so waiters are released even if the tool panics
+	var response *mcp.Message
+	defer func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		entry.response = response
+		if response == nil || response.Error != nil {
+			delete(c.entries, key)
+		} else {
+			entry.expires = c.now().Add(c.window)
+		}
+		close(entry.done)
+	}()
+
+	response = call()
+	return response
+}
+
+// idempotencyScope identifies whose keys a request's key is compared with:
+// the authenticated subject, or else the session
+func idempotencyScope(ctx context.Context) string {
+	if claims, ok := auth.GetClaims(ctx); ok {
+		return "subject:" + claims.Subject
+	}
+	return "session:" + requestSessionID(ctx)
+}
+
+// replay answers a duplicate request with the response of the original
+func (c *idempotencyCache) replay(ctx context.Context, id interface{}, entry *idempotentCall, fingerprint [sha256.Size]byte) *mcp.Message {
+	if entry.fingerprint != fingerprint {
+		return &mcp.Message{JSONRPC: "2.0", ID: id, Error: mcp.NewInvalidParams("idempotency key was already used for a different tool call").RPCError()}
+	}
+
+	select {
+	case <-entry.done:
+	case <-ctx.Done():
+		return &mcp.Message{JSONRPC: "2.0", ID: id, Error: mcp.ToError(ctx.Err()).RPCError()}
+	}
+	if entry.response == nil {
+		return &mcp.Message{JSONRPC: "2.0", ID: id, Error: mcp.NewInternalError("original tool call failed").RPCError()}
+	}
+	return &mcp.Message{JSONRPC: "2.0", ID: id, Result: entry.response.Result, Error: entry.response.Error}
+}
+
+// prune drops expired responses, at most once per window. The caller holds
+// c.mu.
+func (c *idempotencyCache) prune() {
+	now := c.now()
+	if now.Before(c.nextPrune) {
+		return
+	}
+	c.nextPrune = now.Add(c.window)
+
+	for key, entry := range c.entries {
+		if !entry.expires.IsZero() && now.After(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// lookup returns the completed call for key, treating expired calls as
+// missing. The caller holds c.mu.
+func (c *idempotencyCache) lookup(key string) (*idempotentCall, bool) {
+	entry, ok := c.entries[key]
+	if ok && !entry.expires.IsZero() && c.now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry, ok
+}
+
+// callFingerprint hashes the tool name and arguments, ignoring whitespace
+// in the arguments
+func callFingerprint(params *mcp.CallToolRequest) [sha256.Size]byte {
+	var args bytes.Buffer
+	if err := json.Compact(&args, params.Arguments); err != nil {
+		args.Reset()
+		args.Write(params.Arguments)
+	}
+	return sha256.Sum256(append(append([]byte(params.Name), 0), args.Bytes()...))
+}
@@ -0,0 +1,194 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/auth"
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// newChargeServer registers a non-idempotent tool counting its executions
+func newChargeServer(t *testing.T, opts ...Option) (*Server, *atomic.Int32) {
+	t.Helper()
+	var executions atomic.Int32
+	srv := New("test", opts...)
+	_ = srv.AddTool(&ToolHandler{
+		Name: "charge",
+		Handler: func(_ context.Context, _ json.RawMessage) (interface{}, error) {
+			n := executions.Add(1)
+			return "charge " + strconv.Itoa(int(n)), nil
+		},
+	})
+	return srv, &executions
+}
+
+func chargeCall(ctx context.Context, srv *Server, id int, key, args string) *mcp.Message {
+	return srv.HandleMessage(ctx, &mcp.Message{
+		JSONRPC: "2.0",
+		ID:      id,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"charge","arguments":` + args + `,"_meta":{"idempotencyKey":"` + key + `"}}`),
+	})
+}
+
+func TestIdempotency_ReplaysDuplicates(t *testing.T) {
+	srv, executions := newChargeServer(t, WithIdempotency(time.Minute))
+	ctx := context.Background()
+
+	first := chargeCall(ctx, srv, 1, "k1", `{"amount":5}`)
+	retry := chargeCall(ctx, srv, 2, "k1", `{ "amount": 5 }`)
+	if executions.Load() != 1 {
+		t.Fatalf("expected the tool to run once, ran %d times", executions.Load())
+	}
+	if string(retry.Result) != string(first.Result) {
+		t.Errorf("expected the original result, got %s and %s", first.Result, retry.Result)
+	}
+	if retry.ID != 2 {
+		t.Errorf("expected the replay to answer the retry's ID, got %v", retry.ID)
+	}
+
+	chargeCall(ctx, srv, 3, "k2", `{"amount":5}`)
+	if executions.Load() != 2 {
+		t.Errorf("expected a new key to run the tool, ran %d times", executions.Load())
+	}
+
+	// Without a key, every call runs
+	srv.HandleMessage(ctx, &mcp.Message{JSONRPC: "2.0", ID: 4, Method: "tools/call", Params: json.RawMessage(`{"name":"charge"}`)})
+	if executions.Load() != 3 {
+		t.Errorf("expected unkeyed call to run, ran %d times", executions.Load())
+	}
+}
+
+func TestIdempotency_KeyReuseWithDifferentArguments(t *testing.T) {
+	srv, executions := newChargeServer(t, WithIdempotency(time.Minute))
+	ctx := context.Background()
+
+	chargeCall(ctx, srv, 1, "k1", `{"amount":5}`)
+	resp := chargeCall(ctx, srv, 2, "k1", `{"amount":500}`)
+	if resp.Error == nil || resp.Error.Code != int(mcp.InvalidParams) {
+		t.Fatalf("expected InvalidParams for a reused key, got %+v", resp)
+	}
+	if executions.Load() != 1 {
+		t.Errorf("expected the tool to run once, ran %d times", executions.Load())
+	}
+}
+
+func TestIdempotency_ConcurrentDuplicateWaits(t *testing.T) {
+	release := make(chan struct{})
+	var executions atomic.Int32
+	srv := New("test", WithIdempotency(time.Minute))
+	_ = srv.AddTool(&ToolHandler{
+		Name: "charge",
+		Handler: func(_ context.Context, _ json.RawMessage) (interface{}, error) {
+			executions.Add(1)
+			<-release
+			return "charged", nil
+		},
+	})
+
+	ctx := context.Background()
+	responses := make([]*mcp.Message, 2)
+	var wg sync.WaitGroup
+	for i := range responses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i] = chargeCall(ctx, srv, i, "k1", `{}`)
+		}(i)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if executions.Load() != 1 {
+		t.Fatalf("expected the tool to run once, ran %d times", executions.Load())
+	}
+	if string(responses[0].Result) != string(responses[1].Result) {
+		t.Errorf("expected identical results, got %s and %s", responses[0].Result, responses[1].Result)
+	}
+}
+
+func TestIdempotency_Expiry(t *testing.T) {
+	srv, executions := newChargeServer(t, WithIdempotency(time.Minute))
+	now := time.Now()
+	srv.idempotency.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	chargeCall(ctx, srv, 1, "k1", `{}`)
+	now = now.Add(30 * time.Second)
+	chargeCall(ctx, srv, 2, "k1", `{}`)
+	if executions.Load() != 1 {
+		t.Fatalf("expected a replay within the window, ran %d times", executions.Load())
+	}
+
+	now = now.Add(time.Minute)
+	chargeCall(ctx, srv, 3, "k1", `{}`)
+	if executions.Load() != 2 {
+		t.Errorf("expected the tool to run again after the window, ran %d times", executions.Load())
+	}
+}
+
+func TestIdempotency_ProtocolErrorsNotRemembered(t *testing.T) {
+	var executions atomic.Int32
+	srv := New("test", WithIdempotency(time.Minute))
+	_ = srv.AddTool(&ToolHandler{
+		Name: "charge",
+		Handler: func(_ context.Context, _ json.RawMessage) (interface{}, error) {
+			if executions.Add(1) == 1 {
+				return nil, mcp.NewInternalError("payment gateway unavailable")
+			}
+			return "charged", nil
+		},
+	})
+	ctx := context.Background()
+
+	if resp := chargeCall(ctx, srv, 1, "k1", `{}`); resp.Error == nil {
+		t.Fatal("expected the first call to fail")
+	}
+	if resp := chargeCall(ctx, srv, 2, "k1", `{}`); resp.Error != nil {
+		t.Fatalf("expected the retry to run, got %v", resp.Error)
+	}
+	if executions.Load() != 2 {
+		t.Errorf("expected 2 executions, got %d", executions.Load())
+	}
+}
+
+func TestIdempotency_ScopedToSubject(t *testing.T) {
+	srv, executions := newChargeServer(t, WithIdempotency(time.Minute))
+
+	alice := auth.WithClaims(context.Background(), auth.Claims{Subject: "alice"})
+	bob := auth.WithClaims(context.Background(), auth.Claims{Subject: "bob"})
+	chargeCall(alice, srv, 1, "k1", `{}`)
+	chargeCall(bob, srv, 2, "k1", `{}`)
+
+	if executions.Load() != 2 {
+		t.Errorf("expected keys to be scoped to the subject, ran %d times", executions.Load())
+	}
+}
+
+func TestIdempotency_ScopedToSessionWithoutAuth(t *testing.T) {
+	srv, executions := newChargeServer(t, WithIdempotency(time.Minute))
+
+	first := ContextWithConnInfo(context.Background(), &ConnInfo{SessionID: "session-1"})
+	other := ContextWithConnInfo(context.Background(), &ConnInfo{SessionID: "session-2"})
+	original := chargeCall(first, srv, 1, "k1", `{}`)
+	stolen := chargeCall(other, srv, 2, "k1", `{}`)
+
+	if executions.Load() != 2 {
+		t.Errorf("expected keys to be scoped to the session, ran %d times", executions.Load())
+	}
+	if string(stolen.Result) == string(original.Result) {
+		t.Error("expected another session not to get the original result")
+	}
+	chargeCall(first, srv, 3, "k1", `{}`)
+	if executions.Load() != 2 {
+		t.Errorf("expected a retry on the same session to be replayed, ran %d times", executions.Load())
+	}
+}
@@ -0,0 +1,305 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/auth"
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// CallStatus is the state of a journaled tool call
+type CallStatus string
+
+// Journaled tool call states
+const (
+	CallInFlight  CallStatus = "in_flight" // Accepted and running
+	CallSucceeded CallStatus = "succeeded" // Returned a result without isError
+	CallFailed    CallStatus = "failed"    // Returned a protocol error or isError
+	CallOrphaned  CallStatus = "orphaned"  // The server stopped before the call finished
+)
+
+// JournalEntry records a tools/call request and its outcome
+type JournalEntry struct {
+	ID             string          `json:"id"`
+	Tool           string          `json:"tool"`
+	Arguments      json.RawMessage `json:"arguments,omitempty"`
+	IdempotencyKey string          `json:"idempotencyKey,omitempty"`
+	SessionID      string          `json:"sessionId,omitempty"`
+	Subject        string          `json:"subject,omitempty"` // Authenticated subject, if any
+	Status         CallStatus      `json:"status"`
+	Error          string          `json:"error,omitempty"` // Protocol error message of a failed call
+	StartedAt      time.Time       `json:"startedAt"`
+	FinishedAt     *time.Time      `json:"finishedAt,omitempty"`
+}
+
+// JournalStore persists journal entries. Record must not return until the
+// entry is durable, since calls are journaled before they run.
+type JournalStore interface {
+	// Record saves entry, replacing any entry with the same ID
+	Record(entry JournalEntry) error
+	// Entries returns the saved entries in the order they were first recorded
+	Entries() ([]JournalEntry, error)
+}
+
+// WithJournal keeps a write-ahead journal of tools/call requests in store:
+// each call is recorded as in flight before its tool runs and updated with
+// its outcome afterwards. Calls still in flight when the server starts were
+// interrupted by a crash or restart; they are marked orphaned and reported
+// by OrphanedCalls. A client retrying an orphaned call with the same
+// idempotency key gets an error saying its outcome is unknown instead of
+// running the tool again. Calls that cannot be journaled are rejected.
+func WithJournal(store JournalStore) Option {
+	return func(s *Server) {
+		s.journal = newCallJournal(store)
+	}
+}
+
+// OrphanedCalls returns the calls found in flight in the journal when the
+// server started, along with any error reading the journal
+func (s *Server) OrphanedCalls() ([]JournalEntry, error) {
+	if s.journal == nil {
+		return nil, nil
+	}
+	return append([]JournalEntry(nil), s.journal.orphaned...), s.journal.recoverErr
+}
+
+// callJournal records tool calls in a JournalStore
+type callJournal struct {
+	store      JournalStore
+	now        func() time.Time
+	orphaned   []JournalEntry
+	recoverErr error
+}
+
+func newCallJournal(store JournalStore) *callJournal {
+	j := &callJournal{store: store, now: time.Now}
+	j.recover()
+	return j
+}
+
+// recover marks the calls left in flight by a previous run as orphaned
+func (j *callJournal) recover() {
+	entries, err := j.store.Entries()
+	if err != nil {
+		j.recoverErr = err
+		return
+	}
+	for _, entry := range entries {
+		if entry.Status != CallInFlight {
+			if entry.Status == CallOrphaned {
+				j.orphaned = append(j.orphaned, entry)
+			}
+			continue
+		}
+		finished := j.now()
+		entry.Status = CallOrphaned
+		entry.FinishedAt = &finished
+		if err := j.store.Record(entry); err != nil {
+			j.recoverErr = err
+		}
+		j.orphaned = append(j.orphaned, entry)
+	}
+}
+
+// orphanedCall returns the orphaned call with idempotency key made in the
+// idempotency scope of ctx: by the same subject, or without auth on the same
+// session
+func (j *callJournal) orphanedCall(ctx context.Context, key string) (JournalEntry, bool) {
+	scope := idempotencyScope(ctx)
+	for _, entry := range j.orphaned {
+		if entry.IdempotencyKey == key && entry.scope() == scope {
+			return entry, true
+		}
+	}
+	return JournalEntry{}, false
+}
+
+// scope returns the idempotencyScope of the request that made the call
+func (e JournalEntry) scope() string {
+	if e.Subject != "" {
+		return "subject:" + e.Subject
+	}
+	return "session:" + e.SessionID
+}
+
+// run journals call around the tools/call request in params
+func (j *callJournal) run(ctx context.Context, id interface{}, params *mcp.CallToolRequest, call func() *mcp.Message) *mcp.Message {
+	entry := JournalEntry{
+		ID:             newJournalID(),
+		Tool:           params.Name,
+		Arguments:      params.Arguments,
+		IdempotencyKey: params.IdempotencyKey(),
+		Status:         CallInFlight,
+		StartedAt:      j.now(),
+	}
+	entry.SessionID = requestSessionID(ctx)
+	if claims, ok := auth.GetClaims(ctx); ok {
+		entry.Subject = claims.Subject
+	}
+	if err := j.store.Record(entry); err != nil {
+		return &mcp.Message{JSONRPC: "2.0", ID: id, Error: mcp.NewInternalError("failed to journal tool call: " + err.Error()).RPCError()}
+	}
+
+	// Deferred so a panicking tool is still recorded as failed
+	var response *mcp.Message
+	defer func() {
+		finished := j.now()
+		entry.FinishedAt = &finished
+		entry.Status = CallSucceeded
+		switch {
+		case response == nil:
+			entry.Status = CallFailed
+		case response.Error != nil:
+			entry.Status = CallFailed
+			entry.Error = response.Error.Message
+		case callFailed(&Response{Result: response.Result}):
+			entry.Status = CallFailed
+		}
+		_ = j.store.Record(entry)
+	}()
+
+	response = call()
+	return response
+}
+
+// orphanedResponse answers a retry of an orphaned call
+func orphanedResponse(id interface{}, entry JournalEntry) *mcp.Message {
+	err := &mcp.Error{
+		Code:    mcp.InternalError,
+		Message: "tool call with this idempotency key was interrupted by a server restart; its outcome is unknown",
+		Data: map[string]interface{}{
+			"status":    CallOrphaned,
+			"journalId": entry.ID,
+			"tool":      entry.Tool,
+			"startedAt": entry.StartedAt,
+		},
+	}
+	return &mcp.Message{JSONRPC: "2.0", ID: id, Error: err.RPCError()}
+}
+
+func newJournalID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// MemoryJournal is a JournalStore held in memory, for tests and for servers
+// that only need the journal while they run
+type MemoryJournal struct {
+	mu      sync.Mutex
+	entries []JournalEntry
+	index   map[string]int
+}
+
+// NewMemoryJournal creates an empty in-memory journal
+func NewMemoryJournal() *MemoryJournal {
+	return &MemoryJournal{index: make(map[string]int)}
+}
+
+// Record saves entry
+func (m *MemoryJournal) Record(entry JournalEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if i, ok := m.index[entry.ID]; ok {
+		m.entries[i] = entry
+		return nil
+	}
+	m.index[entry.ID] = len(m.entries)
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+// Entries returns the saved entries
+func (m *MemoryJournal) Entries() ([]JournalEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]JournalEntry(nil), m.entries...), nil
+}
+
+// FileJournal is a JournalStore appending entries as JSON lines to a file,
+// synced after every write. The latest line for an ID wins when the file is
+// read back.
+type FileJournal struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenFileJournal opens or creates the journal file at path. A truncated
+// last line, left by a crash during a write, is discarded.
+func OpenFileJournal(path string) (*FileJournal, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if end := bytes.LastIndexByte(data, '\n') + 1; end < len(data) {
+			err = file.Truncate(int64(end))
+		}
+	}
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return &FileJournal{file: file}, nil
+}
+
+// Record appends entry to the file
+func (f *FileJournal) Record(entry JournalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return f.file.Sync()
+}
+
+// Entries reads the entries back from the file
+func (f *FileJournal) Entries() ([]JournalEntry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := os.ReadFile(f.file.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []JournalEntry
+	index := make(map[string]int)
+	lines := bytes.Split(data, []byte("\n"))
+	for i, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var entry JournalEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, fmt.Errorf("journal %s line %d: %w", f.file.Name(), i+1, err)
+		}
+		if j, ok := index[entry.ID]; ok {
+			entries[j] = entry
+			continue
+		}
+		index[entry.ID] = len(entries)
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Close closes the file
+func (f *FileJournal) Close() error {
+	return f.file.Close()
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/auth"
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func TestJournal_RecordsOutcomes(t *testing.T) {
+	journal := NewMemoryJournal()
+	srv, _ := newChargeServer(t, WithJournal(journal))
+	_ = srv.AddTool(&ToolHandler{
+		Name: "decline",
+		Handler: func(_ context.Context, _ json.RawMessage) (interface{}, error) {
+			return nil, errors.New("card declined")
+		},
+	})
+	ctx := auth.WithClaims(context.Background(), auth.Claims{Subject: "alice"})
+
+	chargeCall(ctx, srv, 1, "k1", `{"amount":5}`)
+	srv.HandleMessage(ctx, &mcp.Message{JSONRPC: "2.0", ID: 2, Method: "tools/call", Params: json.RawMessage(`{"name":"decline"}`)})
+
+	entries, _ := journal.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	charge := entries[0]
+	if charge.Tool != "charge" || charge.Status != CallSucceeded || charge.IdempotencyKey != "k1" ||
+		charge.Subject != "alice" || string(charge.Arguments) != `{"amount":5}` || charge.FinishedAt == nil {
+		t.Errorf("unexpected charge entry: %+v", charge)
+	}
+	if entries[1].Tool != "decline" || entries[1].Status != CallFailed {
+		t.Errorf("expected decline to be recorded as failed, got %+v", entries[1])
+	}
+}
+
+func TestJournal_RecordsInFlightBeforeRunning(t *testing.T) {
+	journal := NewMemoryJournal()
+	var seen []JournalEntry
+	srv := New("test", WithJournal(journal))
+	_ = srv.AddTool(&ToolHandler{
+		Name: "charge",
+		Handler: func(_ context.Context, _ json.RawMessage) (interface{}, error) {
+			seen, _ = journal.Entries()
+			return "charged", nil
+		},
+	})
+
+	chargeCall(context.Background(), srv, 1, "k1", `{}`)
+	if len(seen) != 1 || seen[0].Status != CallInFlight {
+		t.Errorf("expected the call to be journaled in flight while running, got %+v", seen)
+	}
+}
+
+// failingJournal is a journal store whose writes fail
+type failingJournal struct{ MemoryJournal }
+
+func (*failingJournal) Record(JournalEntry) error { return errors.New("disk full") }
+
+func TestJournal_RejectsUnjournaledCalls(t *testing.T) {
+	srv, executions := newChargeServer(t, WithJournal(&failingJournal{}))
+
+	resp := chargeCall(context.Background(), srv, 1, "k1", `{}`)
+	if resp.Error == nil || resp.Error.Code != int(mcp.InternalError) {
+		t.Fatalf("expected an internal error, got %+v", resp)
+	}
+	if executions.Load() != 0 {
+		t.Errorf("expected the tool not to run, ran %d times", executions.Load())
+	}
+}
+
+func TestJournal_OrphanedCallsAfterRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calls.jsonl")
+	journal, err := OpenFileJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	started := time.Now().Add(-time.Minute).UTC()
+	_ = journal.Record(JournalEntry{ID: "a", Tool: "charge", IdempotencyKey: "k1", Subject: "alice", Status: CallInFlight, StartedAt: started})
+	_ = journal.Record(JournalEntry{ID: "b", Tool: "charge", IdempotencyKey: "k2", Status: CallInFlight, StartedAt: started})
+	_ = journal.Record(JournalEntry{ID: "b", Tool: "charge", IdempotencyKey: "k2", Status: CallSucceeded, StartedAt: started})
+	_ = journal.Close()
+
+	// Simulate a crash halfway through writing a line
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	_, _ = f.WriteString(`{"id":"c","tool":"cha`)
+	_ = f.Close()
+
+	journal, err = OpenFileJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = journal.Close() }()
+	srv, executions := newChargeServer(t, WithJournal(journal))
+
+	orphaned, err := srv.OrphanedCalls()
+	if err != nil {
+		t.Fatalf("unexpected recovery error: %v", err)
+	}
+	if len(orphaned) != 1 || orphaned[0].ID != "a" || orphaned[0].Status != CallOrphaned {
+		t.Fatalf("expected call a to be orphaned, got %+v", orphaned)
+	}
+
+	alice := auth.WithClaims(context.Background(), auth.Claims{Subject: "alice"})
+	resp := chargeCall(alice, srv, 1, "k1", `{}`)
+	if resp.Error == nil {
+		t.Fatal("expected a retry of the orphaned call to be refused")
+	}
+	data, _ := resp.Error.Data.(map[string]interface{})
+	if data["status"] != CallOrphaned || data["journalId"] != "a" {
+		t.Errorf("unexpected error data: %+v", resp.Error.Data)
+	}
+	if executions.Load() != 0 {
+		t.Errorf("expected the tool not to run, ran %d times", executions.Load())
+	}
+
+	// The key is scoped to the subject that made the orphaned call
+	if resp := chargeCall(context.Background(), srv, 2, "k1", `{}`); resp.Error != nil {
+		t.Errorf("expected another subject's call to run, got %v", resp.Error)
+	}
+
+	entries, err := journal.Entries()
+	if err != nil {
+		t.Fatalf("failed to read the journal: %v", err)
+	}
+	if len(entries) != 3 || entries[0].Status != CallOrphaned || entries[0].FinishedAt == nil {
+		t.Errorf("expected the orphaned status to be persisted, got %+v", entries)
+	}
+}
+
+func TestJournal_OrphanedCallsWithoutAuthAreScopedToTheSession(t *testing.T) {
+	journal := NewMemoryJournal()
+	started := time.Now().Add(-time.Minute).UTC()
+	_ = journal.Record(JournalEntry{ID: "a", Tool: "charge", IdempotencyKey: "k1", SessionID: "s1", Status: CallInFlight, StartedAt: started})
+	srv, executions := newChargeServer(t, WithJournal(journal))
+
+	session := func(id string) context.Context {
+		return ContextWithConnInfo(context.Background(), &ConnInfo{SessionID: id})
+	}
+
+	if resp := chargeCall(session("s2"), srv, 1, "k1", `{}`); resp.Error != nil {
+		t.Errorf("expected another session's call to run, got %v", resp.Error)
+	}
+	if resp := chargeCall(context.Background(), srv, 2, "k1", `{}`); resp.Error != nil {
+		t.Errorf("expected a sessionless call to run, got %v", resp.Error)
+	}
+	if executions.Load() != 2 {
+		t.Errorf("expected 2 executions, got %d", executions.Load())
+	}
+
+	resp := chargeCall(session("s1"), srv, 3, "k1", `{}`)
+	if resp.Error == nil {
+		t.Fatal("expected the session's retry of its orphaned call to be refused")
+	}
+	if data, _ := resp.Error.Data.(map[string]interface{}); data["journalId"] != "a" {
+		t.Errorf("unexpected error data: %+v", resp.Error.Data)
+	}
+}
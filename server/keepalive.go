@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrKeepAliveTimeout is returned by Serve when the connection was closed
+// because the client stopped answering keepalive pings
+var ErrKeepAliveTimeout = errors.New("client failed to respond to keepalive pings")
+
+// PingHook is called after each keepalive ping with its round-trip time, or
+// the error if the ping failed
+type PingHook func(rtt time.Duration, err error)
+
+// keepAlive holds the server keepalive configuration
+type keepAlive struct {
+	interval  time.Duration
+	timeout   time.Duration
+	maxMissed int
+	onPing    PingHook
+	onExpired func()
+}
+
+// KeepAliveOption configures server keepalive pings
+type KeepAliveOption func(*keepAlive)
+
+// WithKeepAlive pings the connected client every interval and closes the
+// connection after consecutive missed pings (default 3)
+func WithKeepAlive(interval time.Duration, opts ...KeepAliveOption) Option {
+	return func(s *Server) {
+		if interval <= 0 {
+			return
+		}
+		ka := &keepAlive{
+			interval:  interval,
+			timeout:   interval,
+			maxMissed: 3,
+		}
+		for _, opt := range opts {
+			opt(ka)
+		}
+		s.keepAlive = ka
+	}
+}
+
+// WithPingTimeout sets how long to wait for each ping response (default:
+// the keepalive interval)
+func WithPingTimeout(timeout time.Duration) KeepAliveOption {
+	return func(ka *keepAlive) {
+		if timeout > 0 {
+			ka.timeout = timeout
+		}
+	}
+}
+
+// WithMaxMissedPings sets how many consecutive failed pings close the
+// connection
+func WithMaxMissedPings(n int) KeepAliveOption {
+	return func(ka *keepAlive) {
+		if n > 0 {
+			ka.maxMissed = n
+		}
+	}
+}
+
+// WithPingHook configures a hook called after every keepalive ping
+func WithPingHook(hook PingHook) KeepAliveOption {
+	return func(ka *keepAlive) {
+		ka.onPing = hook
+	}
+}
+
+// WithSessionExpiredHook configures a hook called when a connection is
+// closed for missing keepalive pings
+func WithSessionExpiredHook(hook func()) KeepAliveOption {
+	return func(ka *keepAlive) {
+		ka.onExpired = hook
+	}
+}
+
+// keepAliveLoop pings the session's client until the session ends
+func (s *Server) keepAliveLoop(ctx context.Context, ss *session) {
+	ka := s.keepAlive
+	ticker := time.NewTicker(ka.interval)
+	defer ticker.Stop()
+
+	missed := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ss.done:
+			return
+		case <-ticker.C:
+		}
+
+		pingCtx, cancel := context.WithTimeout(ctx, ka.timeout)
+		start := time.Now()
+		err := ss.request(pingCtx, "ping", nil, nil)
+		rtt := time.Since(start)
+		cancel()
+
+		if errors.Is(err, ErrSessionClosed) {
+			return
+		}
+
+		if ka.onPing != nil {
+			ka.onPing(rtt, err)
+		}
+
+		if err == nil {
+			missed = 0
+			continue
+		}
+
+		missed++
+		if missed >= ka.maxMissed {
+			_ = ss.expire()
+			if ka.onExpired != nil {
+				ka.onExpired()
+			}
+			return
+		}
+	}
+}
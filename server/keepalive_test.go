@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/internal/jsonrpc"
+	"github.com/jmcarbo/fullmcp/internal/testutil"
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func TestServer_KeepAlive(t *testing.T) {
+	var mu sync.Mutex
+	var rtts []time.Duration
+	srv := New("test", WithKeepAlive(10*time.Millisecond, WithPingHook(func(rtt time.Duration, err error) {
+		if err != nil {
+			t.Errorf("unexpected ping error: %v", err)
+		}
+		mu.Lock()
+		rtts = append(rtts, rtt)
+		mu.Unlock()
+	})))
+
+	clientSide, serverSide := testutil.NewPipeTransport()
+	defer clientSide.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = srv.Serve(ctx, serverSide) }()
+
+	// Answer pings like a client would
+	go func() {
+		reader := jsonrpc.NewMessageReader(clientSide)
+		writer := jsonrpc.NewMessageWriter(clientSide)
+		for {
+			msg, err := reader.Read()
+			if err != nil {
+				return
+			}
+			if msg.Method == "ping" {
+				_ = writer.Write(&mcp.Message{JSONRPC: "2.0", ID: msg.ID, Result: json.RawMessage(`{}`)})
+			}
+		}
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		n := len(rtts)
+		mu.Unlock()
+		if n >= 2 {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("expected at least two successful pings")
+}
+
+func TestServer_KeepAliveReapsUnresponsiveClient(t *testing.T) {
+	expired := make(chan struct{})
+	var failures int
+	srv := New("test", WithKeepAlive(5*time.Millisecond,
+		WithPingTimeout(5*time.Millisecond),
+		WithMaxMissedPings(2),
+		WithPingHook(func(_ time.Duration, err error) {
+			if err != nil {
+				failures++
+			}
+		}),
+		WithSessionExpiredHook(func() { close(expired) }),
+	))
+
+	clientSide, serverSide := testutil.NewPipeTransport()
+	defer clientSide.Close()
+
+	// Read pings but never answer them
+	go func() {
+		reader := jsonrpc.NewMessageReader(clientSide)
+		for {
+			if _, err := reader.Read(); err != nil {
+				return
+			}
+		}
+	}()
+
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(context.Background(), serverSide) }()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrKeepAliveTimeout) {
+			t.Errorf("expected ErrKeepAliveTimeout, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected unresponsive connection to be closed")
+	}
+
+	select {
+	case <-expired:
+	case <-time.After(time.Second):
+		t.Fatal("expired hook was not called")
+	}
+	if failures != 2 {
+		t.Errorf("expected 2 failed pings, got %d", failures)
+	}
+}
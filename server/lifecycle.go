@@ -1,6 +1,12 @@
 package server
 
-import "context"
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
 
 // LifespanFunc is called during server lifecycle
 // It receives the context and server, and returns:
@@ -8,3 +14,99 @@ import "context"
 // - A cleanup function to call on shutdown
 // - An error if initialization failed
 type LifespanFunc func(context.Context, *Server) (context.Context, func(), error)
+
+// ErrParentExited is returned by Run when the process that started the
+// server exited without closing stdin
+var ErrParentExited = errors.New("parent process exited")
+
+// defaultParentCheckInterval is how often Run checks that its parent process
+// is still alive
+const defaultParentCheckInterval = time.Second
+
+// WithParentCheckInterval sets how often Run checks that the parent process
+// is still alive. Zero disables the check, leaving EOF on stdin as the only
+// shutdown signal.
+func WithParentCheckInterval(interval time.Duration) Option {
+	return func(s *Server) {
+		s.parentCheck = interval
+	}
+}
+
+// startLifespan runs the lifespan function, if any, returning the context to
+// serve with and the cleanup to run on shutdown
+func (s *Server) startLifespan(ctx context.Context) (context.Context, func(), error) {
+	if s.lifespan == nil {
+		return ctx, func() {}, nil
+	}
+
+	lifespanCtx, cleanup, err := s.lifespan(ctx, s)
+	if err != nil {
+		return nil, nil, err
+	}
+	if lifespanCtx == nil {
+		lifespanCtx = ctx
+	}
+	if cleanup == nil {
+		cleanup = func() {}
+	}
+	return lifespanCtx, cleanup, nil
+}
+
+// serveUntilOrphaned serves conn within the server lifespan until the client
+// disconnects, ctx is cancelled or parentAlive reports the parent is gone
+func (s *Server) serveUntilOrphaned(ctx context.Context, conn io.ReadWriteCloser, parentAlive func() bool) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	ctx, cleanup, err := s.startLifespan(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	if s.parentCheck > 0 && parentAlive != nil {
+		go watchParent(ctx, s.parentCheck, parentAlive, cancel)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.Serve(ctx, conn)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		// Serve may be blocked reading stdin, which cannot be interrupted;
+		// stop waiting for it so cleanup runs and the process can exit
+		_ = conn.Close()
+		return context.Cause(ctx)
+	}
+}
+
+// watchParent cancels ctx with ErrParentExited once parentAlive reports false
+func watchParent(ctx context.Context, interval time.Duration, parentAlive func() bool, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !parentAlive() {
+				cancel(ErrParentExited)
+				return
+			}
+		}
+	}
+}
+
+// parentWatcher returns a check reporting whether the process that started
+// this one is still alive
+func parentWatcher() func() bool {
+	ppid := os.Getppid()
+	return func() bool {
+		return parentAlive(ppid)
+	}
+}
@@ -2,7 +2,12 @@ package server
 
 import (
 	"context"
+	"errors"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/internal/testutil"
 )
 
 func TestLifespanFunc_Integration(t *testing.T) {
@@ -89,3 +94,78 @@ func TestLifespanFunc_NilCleanup(t *testing.T) {
 		t.Error("expected nil cleanup")
 	}
 }
+
+func TestServeUntilOrphaned_ParentExit(t *testing.T) {
+	var cleaned atomic.Bool
+	srv := New("test",
+		WithParentCheckInterval(5*time.Millisecond),
+		WithLifespan(func(ctx context.Context, _ *Server) (context.Context, func(), error) {
+			return ctx, func() { cleaned.Store(true) }, nil
+		}),
+	)
+
+	clientSide, serverSide := testutil.NewPipeTransport()
+	defer clientSide.Close()
+
+	var alive atomic.Bool
+	alive.Store(true)
+
+	done := make(chan error, 1)
+	go func() { done <- srv.serveUntilOrphaned(context.Background(), serverSide, alive.Load) }()
+
+	time.Sleep(20 * time.Millisecond)
+	if cleaned.Load() {
+		t.Fatal("cleanup ran while parent was alive")
+	}
+	alive.Store(false)
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrParentExited) {
+			t.Errorf("expected ErrParentExited, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("server did not stop after parent exited")
+	}
+	if !cleaned.Load() {
+		t.Error("expected lifespan cleanup to run")
+	}
+}
+
+func TestServeUntilOrphaned_EOF(t *testing.T) {
+	var cleaned atomic.Bool
+	srv := New("test", WithLifespan(func(ctx context.Context, _ *Server) (context.Context, func(), error) {
+		return ctx, func() { cleaned.Store(true) }, nil
+	}))
+
+	clientSide, serverSide := testutil.NewPipeTransport()
+
+	done := make(chan error, 1)
+	go func() { done <- srv.serveUntilOrphaned(context.Background(), serverSide, func() bool { return true }) }()
+
+	_ = clientSide.Close()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("expected clean shutdown, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("server did not stop on EOF")
+	}
+	if !cleaned.Load() {
+		t.Error("expected lifespan cleanup to run")
+	}
+}
+
+func TestServeUntilOrphaned_LifespanError(t *testing.T) {
+	wantErr := errors.New("startup failed")
+	srv := New("test", WithLifespan(func(ctx context.Context, _ *Server) (context.Context, func(), error) {
+		return nil, nil, wantErr
+	}))
+
+	_, serverSide := testutil.NewPipeTransport()
+	if err := srv.serveUntilOrphaned(context.Background(), serverSide, nil); !errors.Is(err, wantErr) {
+		t.Errorf("expected lifespan error, got %v", err)
+	}
+}
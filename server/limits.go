@@ -0,0 +1,59 @@
+package server
+
+import (
+	"errors"
+
+	"github.com/jmcarbo/fullmcp/internal/jsonrpc"
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// WithMaxMessageSize rejects incoming messages larger than n bytes on
+// connections started by Serve or Run, answering them with a
+// MessageTooLarge error. Messages must then be newline delimited, one per
+// line, as the stdio transport requires. HTTP transports have their own
+// body limits.
+func WithMaxMessageSize(n int64) Option {
+	return func(s *Server) {
+		s.maxMessageSize = n
+	}
+}
+
+// WithMaxResultSize fails tools/call and resources/read requests whose
+// result encodes to more than n bytes with a MessageTooLarge error, so a
+// runaway handler can't send a multi-gigabyte response
+func WithMaxResultSize(n int64) Option {
+	return func(s *Server) {
+		s.maxResultSize = n
+	}
+}
+
+// readerOptions configures the message reader of a connection
+func (s *Server) readerOptions() []jsonrpc.Option {
+	opts := []jsonrpc.Option{jsonrpc.WithCodec(s.codec)}
+	if s.maxMessageSize > 0 {
+		opts = append(opts, jsonrpc.WithMaxMessageSize(s.maxMessageSize))
+	}
+	return opts
+}
+
+// tooLargeResponse answers a message rejected by the reader, or returns
+// false for other read errors
+func tooLargeResponse(err error) (*mcp.Message, bool) {
+	var tooLarge *jsonrpc.MessageTooLargeError
+	if !errors.As(err, &tooLarge) {
+		return nil, false
+	}
+	return jsonrpc.TooLargeResponse(tooLarge), true
+}
+
+// limitResult replaces a tools/call or resources/read result over the
+// maximum result size with an error
+func (s *Server) limitResult(method string, response *mcp.Message) *mcp.Message {
+	if s.maxResultSize <= 0 || response == nil || int64(len(response.Result)) <= s.maxResultSize {
+		return response
+	}
+	if method != "tools/call" && method != "resources/read" {
+		return response
+	}
+	return s.errorResponseFrom(response.ID, mcp.NewMessageTooLarge("result", s.maxResultSize))
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func TestServer_MaxMessageSize(t *testing.T) {
+	srv := New("test", WithMaxMessageSize(1024))
+	reader, writer := servePipe(t, srv)
+
+	_ = writer.Write(&mcp.Message{
+		JSONRPC: "2.0",
+		ID:      2,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"echo","arguments":{"text":"` + strings.Repeat("x", 4096) + `"}}`),
+	})
+	resp := readMessage(t, reader)
+	if resp.Error == nil || resp.Error.Code != int(mcp.MessageTooLarge) {
+		t.Fatalf("expected MessageTooLarge error, got %+v", resp)
+	}
+
+	// The session survives the oversized message
+	_ = writer.Write(&mcp.Message{JSONRPC: "2.0", ID: 3, Method: "ping"})
+	if resp := readMessage(t, reader); resp.Error != nil {
+		t.Errorf("expected ping to succeed, got %v", resp.Error.Message)
+	}
+}
+
+func TestServer_MaxResultSize(t *testing.T) {
+	srv := New("test", WithMaxResultSize(512))
+	_ = srv.AddTool(&ToolHandler{
+		Name: "big",
+		Handler: func(_ context.Context, _ json.RawMessage) (interface{}, error) {
+			return strings.Repeat("x", 1024), nil
+		},
+	})
+	_ = srv.AddTool(&ToolHandler{
+		Name: "small",
+		Handler: func(_ context.Context, _ json.RawMessage) (interface{}, error) {
+			return "ok", nil
+		},
+	})
+	_ = srv.AddResource(&ResourceHandler{
+		URI: "file:///big",
+		Reader: func(_ context.Context) ([]byte, error) {
+			return []byte(strings.Repeat("x", 1024)), nil
+		},
+	})
+
+	call := func(method, params string) *mcp.Message {
+		return srv.HandleMessage(context.Background(), &mcp.Message{
+			JSONRPC: "2.0", ID: 1, Method: method, Params: json.RawMessage(params),
+		})
+	}
+
+	resp := call("tools/call", `{"name":"big"}`)
+	if resp.Error == nil || resp.Error.Code != int(mcp.MessageTooLarge) {
+		t.Errorf("expected oversized tool result to fail, got %+v", resp)
+	}
+	if resp := call("tools/call", `{"name":"small"}`); resp.Error != nil {
+		t.Errorf("expected small result to pass, got %v", resp.Error.Message)
+	}
+	resp = call("resources/read", `{"uri":"file:///big"}`)
+	if resp.Error == nil || resp.Error.Code != int(mcp.MessageTooLarge) {
+		t.Errorf("expected oversized resource to fail, got %+v", resp)
+	}
+
+	// Other methods aren't limited
+	if resp := call("tools/list", `{}`); resp.Error != nil {
+		t.Errorf("expected tools/list to pass, got %v", resp.Error.Message)
+	}
+}
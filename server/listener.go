@@ -0,0 +1,185 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/transport/compress"
+)
+
+// defaultDrainTimeout is how long ServeListener waits for connections to
+// finish in-flight requests before closing them
+const defaultDrainTimeout = 5 * time.Second
+
+// listenerConfig holds ServeListener settings
+type listenerConfig struct {
+	maxConns     int
+	drainTimeout time.Duration
+	compression  []string // Algorithms accepted in compression offers
+}
+
+// ListenerOption configures ServeListener
+type ListenerOption func(*listenerConfig)
+
+// WithMaxConns limits the number of connections served at once. Further
+// connections wait in the listener backlog until a slot frees up. Zero means
+// no limit.
+func WithMaxConns(n int) ListenerOption {
+	return func(c *listenerConfig) {
+		c.maxConns = n
+	}
+}
+
+// WithDrainTimeout sets how long shutdown waits for in-flight requests
+// before closing the remaining connections
+func WithDrainTimeout(timeout time.Duration) ListenerOption {
+	return func(c *listenerConfig) {
+		c.drainTimeout = timeout
+	}
+}
+
+// WithConnCompression accepts compression offers for the given algorithms,
+// such as compress.Gzip, from clients that send one (see package
+// transport/compress). Clients that send no offer are served uncompressed.
+func WithConnCompression(algorithms ...string) ListenerOption {
+	return func(c *listenerConfig) {
+		c.compression = algorithms
+	}
+}
+
+// ServeListener accepts connections from lis and serves each one in its own
+// session until ctx is cancelled. The lifespan runs once for the listener.
+//
+// On shutdown the listener is closed and connections stop reading new
+// requests; requests already being handled get the drain timeout to finish
+// before their connections are closed. ServeListener returns ctx's error
+// after a shutdown, or the error that stopped Accept.
+func (s *Server) ServeListener(ctx context.Context, lis net.Listener, opts ...ListenerOption) error {
+	cfg := listenerConfig{drainTimeout: defaultDrainTimeout}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	// Connections outlive ctx while draining, so they are only cancelled
+	// explicitly once the drain timeout passes
+	serveCtx, cleanup, err := s.startLifespan(context.WithoutCancel(ctx))
+	if err != nil {
+		_ = lis.Close()
+		return err
+	}
+	defer cleanup()
+	serveCtx, forceClose := context.WithCancel(serveCtx)
+	defer forceClose()
+
+	stop := context.AfterFunc(ctx, func() { _ = lis.Close() })
+	defer stop()
+
+	var slots chan struct{}
+	if cfg.maxConns > 0 {
+		slots = make(chan struct{}, cfg.maxConns)
+	}
+
+	var (
+		mu    sync.Mutex
+		conns = make(map[net.Conn]struct{})
+		wg    sync.WaitGroup
+	)
+
+	acceptErr := func() error {
+		for {
+			if slots != nil {
+				select {
+				case slots <- struct{}{}:
+				case <-ctx.Done():
+					return nil
+				}
+			}
+
+			conn, err := lis.Accept()
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+
+			mu.Lock()
+			conns[conn] = struct{}{}
+			mu.Unlock()
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.serveConn(serveCtx, conn, cfg.compression)
+				_ = conn.Close()
+
+				mu.Lock()
+				delete(conns, conn)
+				mu.Unlock()
+				if slots != nil {
+					<-slots
+				}
+			}()
+		}
+	}()
+	_ = lis.Close()
+
+	// Fail the next read on every connection, so sessions end once their
+	// current request has been answered
+	mu.Lock()
+	for conn := range conns {
+		_ = conn.SetReadDeadline(time.Now())
+	}
+	mu.Unlock()
+
+	if !waitTimeout(&wg, cfg.drainTimeout) {
+		forceClose()
+		mu.Lock()
+		for conn := range conns {
+			_ = conn.Close()
+		}
+		mu.Unlock()
+		wg.Wait()
+	}
+
+	if acceptErr != nil {
+		return acceptErr
+	}
+	return ctx.Err()
+}
+
+// serveConn serves a single accepted connection, first answering any
+// compression offer
+func (s *Server) serveConn(ctx context.Context, conn net.Conn, compression []string) {
+	var rwc io.ReadWriteCloser = conn
+	if len(compression) > 0 {
+		negotiated, _, err := compress.Accept(conn, compression...)
+		if err != nil {
+			return
+		}
+		rwc = negotiated
+	}
+	_ = s.Serve(ctx, rwc)
+}
+
+// waitTimeout waits for wg, reporting false if timeout passes first
+func waitTimeout(wg *sync.WaitGroup, timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
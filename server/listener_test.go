@@ -0,0 +1,208 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/internal/jsonrpc"
+	"github.com/jmcarbo/fullmcp/mcp"
+	"github.com/jmcarbo/fullmcp/transport/compress"
+)
+
+// startListener serves srv on a local TCP listener until the returned cancel
+// is called; the returned channel receives ServeListener's result
+func startListener(t *testing.T, srv *Server, opts ...ListenerOption) (string, context.CancelFunc, <-chan error) {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.ServeListener(ctx, lis, opts...) }()
+	t.Cleanup(cancel)
+
+	return lis.Addr().String(), cancel, done
+}
+
+func dialServer(t *testing.T, addr string) (net.Conn, *jsonrpc.MessageReader, *jsonrpc.MessageWriter) {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn, jsonrpc.NewMessageReader(conn), jsonrpc.NewMessageWriter(conn)
+}
+
+func waitResult(t *testing.T, done <-chan error) error {
+	t.Helper()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for ServeListener to return")
+		return nil
+	}
+}
+
+func TestServer_ServeListenerSessions(t *testing.T) {
+	srv := New("test")
+	_ = srv.AddTool(&ToolHandler{
+		Name: "session",
+		Handler: func(ctx context.Context, _ json.RawMessage) (interface{}, error) {
+			id, _ := SessionIDFromContext(ctx)
+			return id, nil
+		},
+	})
+	addr, cancel, done := startListener(t, srv)
+
+	ids := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		_, reader, writer := dialServer(t, addr)
+		_ = writer.Write(&mcp.Message{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: json.RawMessage(`{"name":"session"}`)})
+		resp := readMessage(t, reader)
+
+		var result struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		}
+		if err := json.Unmarshal(resp.Result, &result); err != nil || len(result.Content) != 1 {
+			t.Fatalf("unexpected response %s: %v", resp.Result, err)
+		}
+		ids[result.Content[0].Text] = true
+	}
+	if len(ids) != 2 {
+		t.Errorf("expected a session per connection, got %v", ids)
+	}
+
+	cancel()
+	if err := waitResult(t, done); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestServer_ServeListenerMaxConns(t *testing.T) {
+	srv := New("test")
+	addr, _, _ := startListener(t, srv, WithMaxConns(1))
+
+	first, reader1, writer1 := dialServer(t, addr)
+	_ = writer1.Write(&mcp.Message{JSONRPC: "2.0", ID: 1, Method: "ping"})
+	readMessage(t, reader1)
+
+	_, reader2, writer2 := dialServer(t, addr)
+	_ = writer2.Write(&mcp.Message{JSONRPC: "2.0", ID: 1, Method: "ping"})
+
+	answered := make(chan struct{})
+	go func() {
+		_, _ = reader2.Read()
+		close(answered)
+	}()
+
+	select {
+	case <-answered:
+		t.Fatal("second connection served beyond the limit")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	_ = first.Close()
+	select {
+	case <-answered:
+	case <-time.After(time.Second):
+		t.Fatal("second connection not served after the first closed")
+	}
+}
+
+func TestServer_ServeListenerDrain(t *testing.T) {
+	srv := New("test")
+	started := make(chan struct{})
+	_ = srv.AddTool(&ToolHandler{
+		Name: "slow",
+		Handler: func(ctx context.Context, _ json.RawMessage) (interface{}, error) {
+			close(started)
+			select {
+			case <-time.After(50 * time.Millisecond):
+				return "finished", nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		},
+	})
+	addr, cancel, done := startListener(t, srv)
+
+	_, reader, writer := dialServer(t, addr)
+	_ = writer.Write(&mcp.Message{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: json.RawMessage(`{"name":"slow"}`)})
+	<-started
+	cancel()
+
+	resp := readMessage(t, reader)
+	if resp == nil || resp.Error != nil {
+		t.Fatalf("expected in-flight request to complete, got %+v", resp)
+	}
+	waitResult(t, done)
+
+	if _, err := net.Dial("tcp", addr); err == nil {
+		t.Error("expected listener to be closed")
+	}
+}
+
+func TestServer_ServeListenerDrainTimeout(t *testing.T) {
+	srv := New("test")
+	started := make(chan struct{})
+	_ = srv.AddTool(&ToolHandler{
+		Name: "stuck",
+		Handler: func(ctx context.Context, _ json.RawMessage) (interface{}, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	})
+	addr, cancel, done := startListener(t, srv, WithDrainTimeout(20*time.Millisecond))
+
+	_, _, writer := dialServer(t, addr)
+	_ = writer.Write(&mcp.Message{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: json.RawMessage(`{"name":"stuck"}`)})
+	<-started
+
+	cancel()
+	waitResult(t, done)
+}
+
+func TestServer_ServeListenerCompressionConnInfo(t *testing.T) {
+	srv := New("test")
+	_ = srv.AddTool(&ToolHandler{
+		Name: "addr",
+		Handler: func(ctx context.Context, _ json.RawMessage) (interface{}, error) {
+			info, _ := ConnInfoFromContext(ctx)
+			return info.RemoteAddr, nil
+		},
+	})
+	addr, _, _ := startListener(t, srv, WithConnCompression(compress.Gzip))
+
+	for _, offer := range [][]string{nil, {compress.Gzip}} {
+		raw, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Fatalf("dial failed: %v", err)
+		}
+		t.Cleanup(func() { _ = raw.Close() })
+		conn, _, err := compress.OfferConn(context.Background(), raw, offer...)
+		if err != nil {
+			t.Fatalf("offer failed: %v", err)
+		}
+
+		_ = jsonrpc.NewMessageWriter(conn).Write(&mcp.Message{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: json.RawMessage(`{"name":"addr"}`)})
+		resp := readMessage(t, jsonrpc.NewMessageReader(conn))
+		if want := raw.LocalAddr().String(); !strings.Contains(string(resp.Result), want) {
+			t.Errorf("offer %v: expected remote address %s, got %s", offer, want, resp.Result)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"strings"
 	"sync"
 
 	"github.com/jmcarbo/fullmcp/mcp"
@@ -9,27 +10,46 @@ import (
 
 // LoggingManager handles log message notifications
 type LoggingManager struct {
-	mu       sync.RWMutex
-	minLevel mcp.LogLevel
-	enabled  bool
-	sender   LogSender
+	mu           sync.RWMutex
+	minLevel     mcp.LogLevel
+	loggerLevels map[string]mcp.LogLevel // Per-logger overrides of minLevel
+	sessionLevel map[string]mcp.LogLevel // Levels set by each session with logging/setLevel
+	enabled      bool
+	sender       LogSender
+	limiter      *logLimiter // Rate limiting and sampling (nil disables both)
 }
 
+// LoggingOption configures a LoggingManager
+type LoggingOption func(*LoggingManager)
+
 // LogSender sends log notifications to the client
 type LogSender func(msg *mcp.LogMessage) error
 
 // NewLoggingManager creates a new logging manager
 func NewLoggingManager() *LoggingManager {
 	return &LoggingManager{
-		minLevel: mcp.LogLevelInfo, // Default to info level
-		enabled:  false,            // Disabled until client sets level
+		minLevel:     mcp.LogLevelInfo, // Default to info level
+		loggerLevels: make(map[string]mcp.LogLevel),
+		sessionLevel: make(map[string]mcp.LogLevel),
+		enabled:      false, // Disabled until client sets level
 	}
 }
 
 // EnableLogging returns an option that enables logging capability
-func EnableLogging() Option {
+func EnableLogging(opts ...LoggingOption) Option {
 	return func(s *Server) {
 		s.logging = NewLoggingManager()
+		for _, opt := range opts {
+			opt(s.logging)
+		}
+	}
+}
+
+// WithLoggerLevel sets the minimum level for a named logger, overriding the
+// level requested by the client
+func WithLoggerLevel(logger string, level mcp.LogLevel) LoggingOption {
+	return func(lm *LoggingManager) {
+		lm.loggerLevels[logger] = level
 	}
 }
 
@@ -41,6 +61,47 @@ func (lm *LoggingManager) SetLevel(level mcp.LogLevel) {
 	lm.enabled = true
 }
 
+// SetLoggerLevel sets the minimum level for a named logger. Overrides apply
+// to dotted child loggers too, so "db" also covers "db.pool" unless it has
+// its own override.
+func (lm *LoggingManager) SetLoggerLevel(logger string, level mcp.LogLevel) {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+	lm.loggerLevels[logger] = level
+}
+
+// ClearLoggerLevel removes the override for a named logger
+func (lm *LoggingManager) ClearLoggerLevel(logger string) {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+	delete(lm.loggerLevels, logger)
+}
+
+// LevelFor returns the effective minimum level for a named logger
+func (lm *LoggingManager) LevelFor(logger string) mcp.LogLevel {
+	lm.mu.RLock()
+	defer lm.mu.RUnlock()
+	return lm.levelFor(logger)
+}
+
+// levelFor resolves the closest override for logger; callers hold mu
+func (lm *LoggingManager) levelFor(logger string) mcp.LogLevel {
+	for name := logger; ; {
+		if level, ok := lm.loggerLevels[name]; ok {
+			return level
+		}
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	if level, ok := lm.sessionLevel[""]; ok {
+		return level
+	}
+	return lm.minLevel
+}
+
 // SetSender sets the function to send log notifications
 func (lm *LoggingManager) SetSender(sender LogSender) {
 	lm.mu.Lock()
@@ -57,7 +118,7 @@ func (lm *LoggingManager) Log(level mcp.LogLevel, logger string, data map[string
 		return nil // Logging not enabled yet
 	}
 
-	if !level.ShouldLog(lm.minLevel) {
+	if !level.ShouldLog(lm.levelFor(logger)) {
 		return nil // Level below threshold
 	}
 
@@ -65,6 +126,18 @@ func (lm *LoggingManager) Log(level mcp.LogLevel, logger string, data map[string
 		return nil // No sender configured
 	}
 
+	if lm.limiter != nil {
+		ok, suppressed := lm.limiter.allow(level)
+		if !ok {
+			return nil // Sampled out or over the rate limit
+		}
+		if suppressed > 0 {
+			if err := lm.sender(suppressedMessage(suppressed)); err != nil {
+				return err
+			}
+		}
+	}
+
 	msg := &mcp.LogMessage{
 		Level:  level,
 		Logger: logger,
@@ -104,14 +177,61 @@ func (s *Server) LogError(logger string, data map[string]interface{}) error {
 	return s.Log(mcp.LogLevelError, logger, data)
 }
 
-// SetLogLevel handles the logging/setLevel request
-func (s *Server) SetLogLevel(_ context.Context, level mcp.LogLevel) error {
+// SetLoggerLevel overrides the minimum level for a named logger
+func (s *Server) SetLoggerLevel(logger string, level mcp.LogLevel) error {
+	if s.logging == nil {
+		return &mcp.Error{
+			Code:    mcp.MethodNotFound,
+			Message: "logging not enabled on this server",
+		}
+	}
+	s.logging.SetLoggerLevel(logger, level)
+	return nil
+}
+
+// SetLogLevel handles the logging/setLevel request. The level applies to
+// the requesting session only; log messages sent with Log use it when the
+// request has no session, and the server-wide level otherwise.
+func (s *Server) SetLogLevel(ctx context.Context, level mcp.LogLevel) error {
 	if s.logging == nil {
 		return &mcp.Error{
 			Code:    mcp.MethodNotFound,
 			Message: "logging not enabled on this server",
 		}
 	}
-	s.logging.SetLevel(level)
+	s.logging.setSessionLevel(requestSessionID(ctx), level, s.liveSessionIDs())
 	return nil
 }
+
+// setSessionLevel records the level a session requested and enables
+// logging. Only live sessions are recorded, and levels of sessions that
+// ended are dropped, so clients cannot grow the map with made-up session
+// IDs.
+func (lm *LoggingManager) setSessionLevel(sessionID string, level mcp.LogLevel, live map[string]bool) {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	for id := range lm.sessionLevel {
+		if id != "" && !live[id] {
+			delete(lm.sessionLevel, id)
+		}
+	}
+	if sessionID == "" || live[sessionID] {
+		lm.sessionLevel[sessionID] = level
+	}
+	lm.enabled = true
+}
+
+// SessionLogLevel returns the minimum level a session set with
+// logging/setLevel, for relaying log messages to it. Sessionless requests
+// have the session ID "". Levels are kept only for sessions started by
+// Serve or served by a broadcast target.
+func (s *Server) SessionLogLevel(sessionID string) (mcp.LogLevel, bool) {
+	if s.logging == nil {
+		return "", false
+	}
+	s.logging.mu.RLock()
+	defer s.logging.mu.RUnlock()
+	level, ok := s.logging.sessionLevel[sessionID]
+	return level, ok
+}
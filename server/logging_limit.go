@@ -0,0 +1,170 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// suppressedLogger is the logger name used for rate limit summaries
+const suppressedLogger = "logging"
+
+// logLimiter throttles log notifications with a token bucket and samples
+// debug messages, so a chatty tool can't flood the connection
+type logLimiter struct {
+	mu         sync.Mutex
+	rate       float64 // Tokens added per second (0 disables rate limiting)
+	burst      float64
+	tokens     float64
+	last       time.Time
+	suppressed int  // Messages dropped since the last summary
+	flushing   bool // A summary flush is scheduled
+	debugEvery int  // Forward one in every debugEvery debug messages (<= 1 forwards all)
+	debugSeen  int
+	now        func() time.Time
+
+	schedule func(d time.Duration, f func()) // Runs f after d
+	summary  func(count int)                 // Sends a summary flushed by the timer
+}
+
+func (lm *LoggingManager) ensureLimiter() *logLimiter {
+	if lm.limiter == nil {
+		lm.limiter = &logLimiter{
+			now:      time.Now,
+			schedule: func(d time.Duration, f func()) { time.AfterFunc(d, f) },
+			summary:  lm.sendSummary,
+		}
+	}
+	return lm.limiter
+}
+
+// sendSummary reports messages suppressed at the end of a burst
+func (lm *LoggingManager) sendSummary(count int) {
+	lm.mu.RLock()
+	sender := lm.sender
+	lm.mu.RUnlock()
+	if sender != nil {
+		_ = sender(suppressedMessage(count))
+	}
+}
+
+// WithLogRateLimit caps log notifications at perSecond, allowing bursts of up
+// to burst messages. Messages over the limit are dropped, and a single
+// warning reports how many were suppressed: before the next message sent,
+// or once the rate allows another message if none follows.
+func WithLogRateLimit(perSecond, burst int) LoggingOption {
+	return func(lm *LoggingManager) {
+		if perSecond <= 0 {
+			return
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		l := lm.ensureLimiter()
+		l.rate = float64(perSecond)
+		l.burst = float64(burst)
+		l.tokens = float64(burst)
+	}
+}
+
+// WithDebugSampling forwards only one in every n debug messages
+func WithDebugSampling(n int) LoggingOption {
+	return func(lm *LoggingManager) {
+		if n <= 1 {
+			return
+		}
+		lm.ensureLimiter().debugEvery = n
+	}
+}
+
+// allow reports whether a message may be sent, along with the number of
+// messages suppressed since the last allowed one
+func (l *logLimiter) allow(level mcp.LogLevel) (bool, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if level == mcp.LogLevelDebug && l.debugEvery > 1 {
+		l.debugSeen++
+		if (l.debugSeen-1)%l.debugEvery != 0 {
+			return false, 0
+		}
+	}
+
+	if l.rate <= 0 {
+		return true, 0
+	}
+	if !l.take() {
+		l.suppressed++
+		l.scheduleFlush()
+		return false, 0
+	}
+
+	suppressed := l.suppressed
+	l.suppressed = 0
+	return true, suppressed
+}
+
+// take refills the bucket and takes a token if one is available. The caller
+// holds l.mu.
+func (l *logLimiter) take() bool {
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// scheduleFlush arranges for suppressed messages to be summarized once the
+// bucket refills, so the summary is sent even if the burst ends the logging.
+// The caller holds l.mu.
+func (l *logLimiter) scheduleFlush() {
+	if l.flushing || l.schedule == nil {
+		return
+	}
+	l.flushing = true
+	l.schedule(time.Duration((1-l.tokens)/l.rate*float64(time.Second)), l.flush)
+}
+
+// flush sends the summary of suppressed messages unless a later message
+// already carried it
+func (l *logLimiter) flush() {
+	l.mu.Lock()
+	l.flushing = false
+	count := l.suppressed
+	if count == 0 {
+		l.mu.Unlock()
+		return
+	}
+	if !l.take() {
+		l.scheduleFlush()
+		l.mu.Unlock()
+		return
+	}
+	l.suppressed = 0
+	l.mu.Unlock()
+
+	l.summary(count)
+}
+
+// suppressedMessage summarizes messages dropped by the rate limit
+func suppressedMessage(count int) *mcp.LogMessage {
+	return &mcp.LogMessage{
+		Level:  mcp.LogLevelWarning,
+		Logger: suppressedLogger,
+		Data: map[string]interface{}{
+			"message":    fmt.Sprintf("%d log messages suppressed by rate limit", count),
+			"suppressed": count,
+		},
+	}
+}
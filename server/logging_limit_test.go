@@ -0,0 +1,125 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func TestLoggingManager_RateLimit(t *testing.T) {
+	lm := NewLoggingManager()
+	WithLogRateLimit(10, 2)(lm)
+
+	now := time.Unix(0, 0)
+	lm.limiter.now = func() time.Time { return now }
+	lm.limiter.schedule = func(time.Duration, func()) {}
+
+	var sent []*mcp.LogMessage
+	lm.SetSender(func(msg *mcp.LogMessage) error {
+		sent = append(sent, msg)
+		return nil
+	})
+	lm.SetLevel(mcp.LogLevelDebug)
+
+	for i := 0; i < 5; i++ {
+		_ = lm.Log(mcp.LogLevelInfo, "tool", nil)
+	}
+	if len(sent) != 2 {
+		t.Fatalf("expected burst of 2 messages, got %d", len(sent))
+	}
+
+	// 100ms refills one token
+	now = now.Add(100 * time.Millisecond)
+	_ = lm.Log(mcp.LogLevelInfo, "tool", nil)
+
+	if len(sent) != 4 {
+		t.Fatalf("expected summary and message, got %d messages", len(sent))
+	}
+	summary := sent[2]
+	if summary.Logger != suppressedLogger || summary.Level != mcp.LogLevelWarning {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+	if summary.Data["suppressed"] != 3 {
+		t.Errorf("expected 3 suppressed messages, got %v", summary.Data["suppressed"])
+	}
+	if sent[3].Logger != "tool" {
+		t.Errorf("expected original message after summary, got %+v", sent[3])
+	}
+}
+
+func TestLoggingManager_RateLimitFlush(t *testing.T) {
+	lm := NewLoggingManager()
+	WithLogRateLimit(10, 1)(lm)
+
+	now := time.Unix(0, 0)
+	lm.limiter.now = func() time.Time { return now }
+	var delay time.Duration
+	var flush func()
+	lm.limiter.schedule = func(d time.Duration, f func()) {
+		delay, flush = d, f
+	}
+
+	var sent []*mcp.LogMessage
+	lm.SetSender(func(msg *mcp.LogMessage) error {
+		sent = append(sent, msg)
+		return nil
+	})
+	lm.SetLevel(mcp.LogLevelDebug)
+
+	// The burst ends the logging, so no later message carries the summary
+	for i := 0; i < 4; i++ {
+		_ = lm.Log(mcp.LogLevelInfo, "tool", nil)
+	}
+	if flush == nil || delay != 100*time.Millisecond {
+		t.Fatalf("expected a flush once the bucket refills, got %v", delay)
+	}
+
+	now = now.Add(delay)
+	flush()
+	if len(sent) != 2 || sent[1].Logger != suppressedLogger || sent[1].Data["suppressed"] != 3 {
+		t.Fatalf("expected the summary of 3 suppressed messages, got %+v", sent)
+	}
+
+	// A flush after a message already carried the summary sends nothing
+	flush = nil
+	_ = lm.Log(mcp.LogLevelInfo, "tool", nil)
+	now = now.Add(100 * time.Millisecond)
+	_ = lm.Log(mcp.LogLevelInfo, "tool", nil)
+	flush()
+	if len(sent) != 4 || sent[2].Logger != suppressedLogger {
+		t.Errorf("expected the summary to be sent once, got %+v", sent)
+	}
+}
+
+func TestLoggingManager_DebugSampling(t *testing.T) {
+	lm := NewLoggingManager()
+	WithDebugSampling(3)(lm)
+
+	var levels []mcp.LogLevel
+	lm.SetSender(func(msg *mcp.LogMessage) error {
+		levels = append(levels, msg.Level)
+		return nil
+	})
+	lm.SetLevel(mcp.LogLevelDebug)
+
+	for i := 0; i < 7; i++ {
+		_ = lm.Log(mcp.LogLevelDebug, "tool", nil)
+	}
+	_ = lm.Log(mcp.LogLevelInfo, "tool", nil)
+
+	want := []mcp.LogLevel{mcp.LogLevelDebug, mcp.LogLevelDebug, mcp.LogLevelDebug, mcp.LogLevelInfo}
+	if len(levels) != len(want) {
+		t.Fatalf("expected %v, got %v", want, levels)
+	}
+}
+
+func TestLoggingManager_NoLimiter(t *testing.T) {
+	lm := NewLoggingManager()
+	WithLogRateLimit(0, 10)(lm)
+	WithDebugSampling(1)(lm)
+
+	if lm.limiter != nil {
+		t.Error("expected disabled options not to install a limiter")
+	}
+}
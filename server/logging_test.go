@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// recordLogs installs a sender that records the loggers of sent messages
+func recordLogs(lm *LoggingManager) *[]string {
+	var sent []string
+	lm.SetSender(func(msg *mcp.LogMessage) error {
+		sent = append(sent, msg.Logger+":"+string(msg.Level))
+		return nil
+	})
+	return &sent
+}
+
+func TestLoggingManager_LoggerLevels(t *testing.T) {
+	lm := NewLoggingManager()
+	WithLoggerLevel("db", mcp.LogLevelDebug)(lm)
+	sent := recordLogs(lm)
+
+	lm.SetLevel(mcp.LogLevelWarning)
+
+	_ = lm.Log(mcp.LogLevelDebug, "db", nil)
+	_ = lm.Log(mcp.LogLevelDebug, "db.pool", nil)
+	_ = lm.Log(mcp.LogLevelInfo, "http", nil)
+	_ = lm.Log(mcp.LogLevelError, "http", nil)
+	_ = lm.Log(mcp.LogLevelDebug, "dbx", nil)
+
+	want := []string{"db:debug", "db.pool:debug", "http:error"}
+	if len(*sent) != len(want) {
+		t.Fatalf("expected %v, got %v", want, *sent)
+	}
+	for i := range want {
+		if (*sent)[i] != want[i] {
+			t.Errorf("message %d: expected %s, got %s", i, want[i], (*sent)[i])
+		}
+	}
+}
+
+func TestLoggingManager_LevelFor(t *testing.T) {
+	lm := NewLoggingManager()
+	lm.SetLevel(mcp.LogLevelWarning)
+	lm.SetLoggerLevel("db", mcp.LogLevelDebug)
+	lm.SetLoggerLevel("db.pool", mcp.LogLevelError)
+
+	tests := map[string]mcp.LogLevel{
+		"":              mcp.LogLevelWarning,
+		"http":          mcp.LogLevelWarning,
+		"db":            mcp.LogLevelDebug,
+		"db.query":      mcp.LogLevelDebug,
+		"db.pool":       mcp.LogLevelError,
+		"db.pool.stats": mcp.LogLevelError,
+	}
+	for logger, want := range tests {
+		if got := lm.LevelFor(logger); got != want {
+			t.Errorf("LevelFor(%q) = %s, want %s", logger, got, want)
+		}
+	}
+
+	lm.ClearLoggerLevel("db.pool")
+	if got := lm.LevelFor("db.pool"); got != mcp.LogLevelDebug {
+		t.Errorf("expected cleared override to fall back to parent, got %s", got)
+	}
+}
+
+func TestServer_SetLoggerLevel(t *testing.T) {
+	if err := New("test").SetLoggerLevel("db", mcp.LogLevelDebug); err == nil {
+		t.Error("expected error when logging is disabled")
+	}
+
+	srv := New("test", EnableLogging(WithLoggerLevel("db", mcp.LogLevelError)))
+	if err := srv.SetLoggerLevel("db", mcp.LogLevelDebug); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := srv.logging.LevelFor("db"); got != mcp.LogLevelDebug {
+		t.Errorf("expected debug, got %s", got)
+	}
+}
+
+func TestServer_SetLogLevelPerSession(t *testing.T) {
+	srv := New("test", EnableLogging())
+	target := &recordingTarget{ids: []string{"a", "b"}}
+	srv.AddBroadcastTarget(target)
+	sessionCtx := func(id string) context.Context {
+		return ContextWithConnInfo(context.Background(), &ConnInfo{SessionID: id})
+	}
+
+	if err := srv.SetLogLevel(sessionCtx("a"), mcp.LogLevelDebug); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if level, ok := srv.SessionLogLevel("a"); !ok || level != mcp.LogLevelDebug {
+		t.Errorf("expected session a at debug, got %q", level)
+	}
+	if _, ok := srv.SessionLogLevel("b"); ok {
+		t.Error("expected session b to keep no level")
+	}
+	if got := srv.logging.LevelFor("server"); got != mcp.LogLevelInfo {
+		t.Errorf("expected the server-wide level to stay info, got %s", got)
+	}
+
+	// Unknown session IDs are not recorded
+	_ = srv.SetLogLevel(sessionCtx("made-up"), mcp.LogLevelDebug)
+	if _, ok := srv.SessionLogLevel("made-up"); ok {
+		t.Error("expected unknown sessions to be ignored")
+	}
+
+	// Levels of ended sessions are dropped
+	target.ids = []string{"b"}
+	_ = srv.SetLogLevel(sessionCtx("b"), mcp.LogLevelError)
+	if _, ok := srv.SessionLogLevel("a"); ok {
+		t.Error("expected the ended session's level to be dropped")
+	}
+
+	// Sessionless requests set the level of messages sent with Log
+	_ = srv.SetLogLevel(context.Background(), mcp.LogLevelWarning)
+	if got := srv.logging.LevelFor("server"); got != mcp.LogLevelWarning {
+		t.Errorf("expected warning for sessionless logging, got %s", got)
+	}
+}
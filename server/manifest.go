@@ -0,0 +1,115 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// ManifestVersion is the version of the manifest format written by
+// Manifest.JSON. It changes only when the format changes incompatibly.
+const ManifestVersion = 1
+
+// Manifest is a serializable description of a server: what it advertises
+// during initialize and everything it lists. Its JSON form is stable, so
+// manifests can be stored, diffed between releases, exported as docs or
+// published for discovery.
+type Manifest struct {
+	ManifestVersion   int                     `json:"manifestVersion"`
+	ProtocolVersion   string                  `json:"protocolVersion"`
+	ServerInfo        mcp.Implementation      `json:"serverInfo"`
+	Instructions      string                  `json:"instructions,omitempty"`
+	Capabilities      mcp.ServerCapabilities  `json:"capabilities"`
+	Tools             []*mcp.Tool             `json:"tools"`
+	Resources         []*mcp.Resource         `json:"resources"`
+	ResourceTemplates []*mcp.ResourceTemplate `json:"resourceTemplates"`
+	Prompts           []*mcp.Prompt           `json:"prompts"`
+}
+
+// Manifest describes the server as it is now. Tools and prompts are sorted
+// by name and resources by URI, so the same server always yields the same
+// manifest. Instructions set with WithInstructionsFunc are per client and
+// left out.
+func (s *Server) Manifest() *Manifest {
+	tools, _ := s.tools.List(context.Background())
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+
+	resources := s.resources.List()
+	sort.Slice(resources, func(i, j int) bool { return resources[i].URI < resources[j].URI })
+
+	templates := s.resources.ListTemplates()
+	sort.Slice(templates, func(i, j int) bool { return templates[i].URITemplate < templates[j].URITemplate })
+
+	prompts := s.prompts.List()
+	sort.Slice(prompts, func(i, j int) bool { return prompts[i].Name < prompts[j].Name })
+
+	// Empty lists are encoded as [] rather than null
+	if tools == nil {
+		tools = []*mcp.Tool{}
+	}
+	if resources == nil {
+		resources = []*mcp.Resource{}
+	}
+	if templates == nil {
+		templates = []*mcp.ResourceTemplate{}
+	}
+	if prompts == nil {
+		prompts = []*mcp.Prompt{}
+	}
+
+	return &Manifest{
+		ManifestVersion: ManifestVersion,
+		ProtocolVersion: protocolVersion,
+		ServerInfo: mcp.Implementation{
+			Name:    s.name,
+			Title:   s.title,
+			Version: s.version,
+		},
+		Instructions:      s.instructions,
+		Capabilities:      s.capabilities(context.Background()),
+		Tools:             tools,
+		Resources:         resources,
+		ResourceTemplates: templates,
+		Prompts:           prompts,
+	}
+}
+
+// JSON encodes the manifest as indented JSON ending in a newline. Object
+// keys are sorted, so equal manifests encode to identical bytes.
+func (m *Manifest) JSON() ([]byte, error) {
+	// Encoding through a generic value sorts the keys of struct fields too
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+	data, err = json.MarshalIndent(generic, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// ParseManifest decodes a manifest written by Manifest.JSON
+func ParseManifest(data []byte) (*Manifest, error) {
+	// Numbers in schemas are kept as written rather than rounded to float64
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var m Manifest
+	if err := decoder.Decode(&m); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if m.ManifestVersion != ManifestVersion {
+		return nil, fmt.Errorf("unsupported manifest version %d", m.ManifestVersion)
+	}
+	return &m, nil
+}
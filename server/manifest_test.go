@@ -0,0 +1,104 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func newManifestServer(names ...string) *Server {
+	srv := New("inventory", WithVersion("1.2.0"), WithInstructions("Look things up"))
+	for _, name := range names {
+		_ = srv.AddTool(&ToolHandler{
+			Name:    name,
+			Schema:  map[string]interface{}{"type": "object", "properties": map[string]interface{}{"id": map[string]interface{}{"type": "integer", "maximum": 9007199254740993}}},
+			Handler: func(_ context.Context, _ json.RawMessage) (interface{}, error) { return "ok", nil },
+		})
+		_ = srv.AddResource(&ResourceHandler{
+			URI:    "inventory://" + name,
+			Name:   name,
+			Reader: func(context.Context) ([]byte, error) { return nil, nil },
+		})
+	}
+	_ = srv.AddResourceTemplate(&ResourceTemplateHandler{
+		URITemplate: "inventory://items/{id}",
+		Name:        "item",
+		Reader:      func(context.Context, map[string]string) ([]byte, error) { return nil, nil },
+	})
+	_ = srv.AddPrompt(&PromptHandler{
+		Name:     "summarize",
+		Renderer: func(context.Context, map[string]interface{}) ([]*mcp.PromptMessage, error) { return nil, nil },
+	})
+	return srv
+}
+
+func TestManifest_DescribesServer(t *testing.T) {
+	m := newManifestServer("stock", "lookup").Manifest()
+
+	if m.ManifestVersion != ManifestVersion || m.ProtocolVersion != protocolVersion {
+		t.Errorf("unexpected versions: %d %s", m.ManifestVersion, m.ProtocolVersion)
+	}
+	if m.ServerInfo.Name != "inventory" || m.ServerInfo.Version != "1.2.0" || m.Instructions != "Look things up" {
+		t.Errorf("unexpected server info: %+v %q", m.ServerInfo, m.Instructions)
+	}
+	if m.Capabilities.Tools == nil || m.Capabilities.Prompts == nil {
+		t.Errorf("expected the advertised capabilities, got %+v", m.Capabilities)
+	}
+	if len(m.Tools) != 2 || m.Tools[0].Name != "lookup" || m.Tools[1].Name != "stock" {
+		t.Errorf("expected tools sorted by name, got %+v", m.Tools)
+	}
+	if len(m.Resources) != 2 || m.Resources[0].URI != "inventory://lookup" {
+		t.Errorf("expected resources sorted by URI, got %+v", m.Resources)
+	}
+	if len(m.ResourceTemplates) != 1 || len(m.Prompts) != 1 {
+		t.Errorf("expected the template and prompt, got %+v %+v", m.ResourceTemplates, m.Prompts)
+	}
+}
+
+func TestManifest_StableJSON(t *testing.T) {
+	first, err := newManifestServer("stock", "lookup").Manifest().JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := newManifestServer("lookup", "stock").Manifest().JSON()
+	if !bytes.Equal(first, second) {
+		t.Errorf("expected registration order not to matter:\n%s\n%s", first, second)
+	}
+	if !strings.Contains(string(first), "9007199254740993") {
+		t.Error("expected large schema numbers to keep their precision")
+	}
+	if !bytes.HasSuffix(first, []byte("}\n")) {
+		t.Error("expected a trailing newline")
+	}
+
+	parsed, err := ParseManifest(first)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	again, _ := parsed.JSON()
+	if !bytes.Equal(first, again) {
+		t.Errorf("expected a parsed manifest to encode identically:\n%s\n%s", first, again)
+	}
+
+	if _, err := ParseManifest([]byte(`{"manifestVersion":99}`)); err == nil {
+		t.Error("expected an unsupported version to fail")
+	}
+}
+
+func TestManifest_EmptyServer(t *testing.T) {
+	data, err := New("empty").Manifest().JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]json.RawMessage
+	_ = json.Unmarshal(data, &m)
+	for _, field := range []string{"tools", "resources", "resourceTemplates", "prompts"} {
+		if string(m[field]) != "[]" {
+			t.Errorf("expected %s to be an empty list, got %s", field, m[field])
+		}
+	}
+}
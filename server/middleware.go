@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 
+	"github.com/jmcarbo/fullmcp/auth"
 	"github.com/jmcarbo/fullmcp/mcp"
 )
 
@@ -66,6 +67,39 @@ func RecoveryMiddleware() Middleware {
 	}
 }
 
+// unauthenticatedMethods can always be called, so clients can connect and
+// check liveness before authenticating
+var unauthenticatedMethods = map[string]bool{
+	"initialize":                true,
+	"notifications/initialized": true,
+	"ping":                      true,
+}
+
+// AuthRequiredMiddleware rejects requests without auth claims in their
+// context for the given methods, or for every method except initialize and
+// ping when none are given. Claims are added by an auth provider's HTTP
+// middleware when the request context is passed to HandleMessage. Rejected
+// requests get an mcp.Unauthenticated error.
+func AuthRequiredMiddleware(methods ...string) Middleware {
+	required := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		required[method] = true
+	}
+
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			protected := required[req.Method] || (len(required) == 0 && !unauthenticatedMethods[req.Method])
+			if protected {
+				if _, ok := auth.GetClaims(ctx); !ok {
+					err := mcp.NewUnauthenticated(auth.ErrUnauthenticated.Error() + " for " + req.Method)
+					return &Response{Error: err.RPCError()}, nil
+				}
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
 // Logger interface for middleware
 type Logger interface {
 	Infof(format string, args ...interface{})
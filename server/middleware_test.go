@@ -2,9 +2,12 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 
+	"github.com/jmcarbo/fullmcp/auth"
 	"github.com/jmcarbo/fullmcp/mcp"
 )
 
@@ -227,3 +230,76 @@ func TestApplyMiddleware_Empty(t *testing.T) {
 		t.Error("expected handler to be called")
 	}
 }
+
+func TestServer_WithMiddleware(t *testing.T) {
+	var methods []string
+	record := func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			methods = append(methods, req.Method)
+			return next(ctx, req)
+		}
+	}
+
+	srv := New("test", WithMiddleware(record))
+	resp := srv.HandleMessage(context.Background(), &mcp.Message{JSONRPC: "2.0", ID: 1, Method: "ping"})
+	if resp == nil || resp.Error != nil || string(resp.Result) != "{}" {
+		t.Fatalf("unexpected ping response: %+v", resp)
+	}
+	if len(methods) != 1 || methods[0] != "ping" {
+		t.Errorf("expected middleware to see ping, got %v", methods)
+	}
+}
+
+func TestAuthRequiredMiddleware(t *testing.T) {
+	srv := New("test", WithMiddleware(AuthRequiredMiddleware()))
+	_ = srv.AddTool(&ToolHandler{
+		Name: "write",
+		Handler: func(ctx context.Context, _ json.RawMessage) (interface{}, error) {
+			if err := auth.RequireScope(ctx, "write"); err != nil {
+				return nil, err
+			}
+			return "written", nil
+		},
+	})
+
+	call := func(ctx context.Context, method string) *mcp.Message {
+		return srv.HandleMessage(ctx, &mcp.Message{
+			JSONRPC: "2.0",
+			ID:      1,
+			Method:  method,
+			Params:  json.RawMessage(`{"name":"write"}`),
+		})
+	}
+
+	if resp := call(context.Background(), "ping"); resp.Error != nil {
+		t.Errorf("ping should not require auth: %v", resp.Error)
+	}
+	if resp := call(context.Background(), "tools/call"); resp.Error == nil || resp.Error.Code != int(mcp.Unauthenticated) {
+		t.Errorf("expected unauthenticated call to be rejected, got %+v", resp)
+	}
+
+	readOnly := auth.WithClaims(context.Background(), auth.Claims{Subject: "u", Scopes: []string{"read"}})
+	var result mcp.CallToolResult
+	resp := call(readOnly, "tools/call")
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	_ = json.Unmarshal(resp.Result, &result)
+	if !result.IsError {
+		t.Error("expected missing scope to be reported as a tool error")
+	}
+
+	writer := auth.WithClaims(context.Background(), auth.Claims{Subject: "u", Scopes: []string{"write"}})
+	if resp := call(writer, "tools/call"); resp.Error != nil || !strings.Contains(string(resp.Result), "written") {
+		t.Errorf("expected call with scope to succeed, got %+v", resp)
+	}
+}
+
+func TestAuthRequiredMiddleware_Methods(t *testing.T) {
+	srv := New("test", WithMiddleware(AuthRequiredMiddleware("tools/call")))
+
+	resp := srv.HandleMessage(context.Background(), &mcp.Message{JSONRPC: "2.0", ID: 1, Method: "tools/list"})
+	if resp.Error != nil {
+		t.Errorf("unlisted methods should not require auth: %v", resp.Error)
+	}
+}
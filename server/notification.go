@@ -0,0 +1,221 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/jmcarbo/fullmcp/internal/jsonrpc"
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// NotificationSender sends a server-to-client notification
+type NotificationSender func(method string, params interface{}) error
+
+// SetNotificationSender sets an additional function used to deliver
+// notifications. Sessions started by Serve are reached automatically;
+// embedders that call HandleMessage directly should set it to reach their
+// transport.
+func (s *Server) SetNotificationSender(sender NotificationSender) {
+	s.notifyMu.Lock()
+	defer s.notifyMu.Unlock()
+	s.notifier = sender
+}
+
+// ErrUnknownSession is returned by NotifySession and Request for sessions
+// that are not connected
+var ErrUnknownSession = errors.New("unknown session")
+
+// contextWithSession returns a context carrying the session a request
+// arrived on
+func contextWithSession(ctx context.Context, ss *session) context.Context {
+	return context.WithValue(ctx, sessionContextKey, ss)
+}
+
+func sessionFromContext(ctx context.Context) *session {
+	ss, _ := ctx.Value(sessionContextKey).(*session)
+	return ss
+}
+
+// SessionIDFromContext returns the ID of the session whose request is being
+// handled
+func SessionIDFromContext(ctx context.Context) (string, bool) {
+	if ss := sessionFromContext(ctx); ss != nil {
+		return ss.id, true
+	}
+	return "", false
+}
+
+// requestSessionID identifies the session a request arrived on: a session
+// started by Serve, or the session ID of the request's ConnInfo for
+// transports that add one. It is "" for requests without a session.
+func requestSessionID(ctx context.Context) string {
+	if ss := sessionFromContext(ctx); ss != nil {
+		return ss.id
+	}
+	if info, ok := ctx.Value(connInfoContextKey).(*ConnInfo); ok {
+		return info.SessionID
+	}
+	return ""
+}
+
+// liveSessionIDs returns the IDs of the sessions started by Serve and those
+// of the broadcast targets
+func (s *Server) liveSessionIDs() map[string]bool {
+	live := make(map[string]bool)
+	s.sessionsMu.RLock()
+	for id := range s.sessions {
+		live[id] = true
+	}
+	s.sessionsMu.RUnlock()
+
+	s.notifyMu.RLock()
+	targets := s.broadcastTargets
+	s.notifyMu.RUnlock()
+	for _, target := range targets {
+		for _, id := range target.SessionIDs() {
+			live[id] = true
+		}
+	}
+	return live
+}
+
+// SessionIDs returns the IDs of the sessions currently served, sorted
+func (s *Server) SessionIDs() []string {
+	s.sessionsMu.RLock()
+	defer s.sessionsMu.RUnlock()
+
+	ids := make([]string, 0, len(s.sessions))
+	for id := range s.sessions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Notify sends a notification to the client whose request is being handled
+// in ctx. Outside a request it goes to every connected client, like
+// NotifyAll.
+func (s *Server) Notify(ctx context.Context, method string, params interface{}) error {
+	if ss := sessionFromContext(ctx); ss != nil {
+		return ss.writer.notify(method, params)
+	}
+	return s.NotifyAll(method, params)
+}
+
+// NotifySession sends a notification to a single session, started by Serve
+// or served by a transport added with AddBroadcastTarget
+func (s *Server) NotifySession(sessionID, method string, params interface{}) error {
+	if ss, err := s.lookupSession(sessionID); err == nil {
+		return ss.writer.notify(method, params)
+	}
+
+	s.notifyMu.RLock()
+	targets := s.broadcastTargets
+	s.notifyMu.RUnlock()
+	for _, target := range targets {
+		if slices.Contains(target.SessionIDs(), sessionID) {
+			return s.sendToTarget(target, sessionID, method, params)
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownSession, sessionID)
+}
+
+// NotifyAll sends a notification to every connected session: those started
+// by Serve, those of transports added with AddBroadcastTarget, and the
+// sender installed with SetNotificationSender, if any. Sessions rejected by
+// a broadcast filter are skipped.
+func (s *Server) NotifyAll(method string, params interface{}) error {
+	s.sessionsMu.RLock()
+	sessions := make([]*session, 0, len(s.sessions))
+	for _, ss := range s.sessions {
+		sessions = append(sessions, ss)
+	}
+	s.sessionsMu.RUnlock()
+
+	s.notifyMu.RLock()
+	sender := s.notifier
+	targets := s.broadcastTargets
+	s.notifyMu.RUnlock()
+
+	var errs []error
+	for _, ss := range sessions {
+		if !s.broadcastAllowed(ss.id, method, params) {
+			continue
+		}
+		if err := ss.writer.notify(method, params); err != nil {
+			errs = append(errs, fmt.Errorf("session %s: %w", ss.id, err))
+		}
+	}
+	if len(targets) > 0 {
+		if err := s.broadcastToTargets(targets, method, params); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if sender != nil {
+		if err := sender(method, params); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sendNotification delivers a notification to every connected client
+func (s *Server) sendNotification(method string, params interface{}) error {
+	return s.NotifyAll(method, params)
+}
+
+// addSession tracks a session so notifications reach it
+func (s *Server) addSession(ss *session) {
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+	s.sessions[ss.id] = ss
+}
+
+// removeSession stops tracking a session and drops its subscriptions and
+// log level
+func (s *Server) removeSession(ss *session) {
+	s.sessionsMu.Lock()
+	delete(s.sessions, ss.id)
+	s.sessionsMu.Unlock()
+	s.subscriptions.RemoveSession(ss.id)
+	if s.logging != nil {
+		s.logging.mu.Lock()
+		delete(s.logging.sessionLevel, ss.id)
+		s.logging.mu.Unlock()
+	}
+}
+
+// connWriter serializes writes from the serve loop and notification senders
+type connWriter struct {
+	mu     sync.Mutex
+	writer *jsonrpc.MessageWriter
+}
+
+func (w *connWriter) Write(msg *mcp.Message) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.writer.Write(msg)
+}
+
+// notify writes a notification message
+func (w *connWriter) notify(method string, params interface{}) error {
+	msg := &mcp.Message{
+		JSONRPC: "2.0",
+		Method:  method,
+	}
+
+	if params != nil {
+		paramsJSON, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		msg.Params = paramsJSON
+	}
+
+	return w.Write(msg)
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/internal/jsonrpc"
+	"github.com/jmcarbo/fullmcp/internal/testutil"
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func TestServer_ServeDeliversNotifications(t *testing.T) {
+	srv := New("test")
+	clientSide, serverSide := testutil.NewPipeTransport()
+	defer clientSide.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = srv.Serve(ctx, serverSide) }()
+
+	reader := jsonrpc.NewMessageReader(clientSide)
+	writer := jsonrpc.NewMessageWriter(clientSide)
+
+	// A round trip guarantees the serve loop has installed its sender
+	_ = writer.Write(&mcp.Message{JSONRPC: "2.0", ID: 1, Method: "ping"})
+	if _, err := reader.Read(); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+
+	go func() { _ = srv.NotifyResourceListChanged() }()
+
+	done := make(chan *mcp.Message, 1)
+	go func() {
+		msg, _ := reader.Read()
+		done <- msg
+	}()
+
+	select {
+	case msg := <-done:
+		if msg == nil || msg.Method != "notifications/resources/list_changed" {
+			t.Errorf("unexpected message: %+v", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("notification was not delivered")
+	}
+}
+
+func TestServer_NotificationWithoutConnection(t *testing.T) {
+	srv := New("test")
+	if err := srv.NotifyResourceListChanged(); err != nil {
+		t.Errorf("expected no error without a connection, got %v", err)
+	}
+}
+
+// servePipe serves one pipe connection and returns the client side once the
+// session is registered
+func servePipe(t *testing.T, srv *Server) (*jsonrpc.MessageReader, *jsonrpc.MessageWriter) {
+	t.Helper()
+
+	clientSide, serverSide := testutil.NewPipeTransport()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		_ = clientSide.Close()
+	})
+	go func() { _ = srv.Serve(ctx, serverSide) }()
+
+	reader := jsonrpc.NewMessageReader(clientSide)
+	writer := jsonrpc.NewMessageWriter(clientSide)
+	_ = writer.Write(&mcp.Message{JSONRPC: "2.0", ID: 1, Method: "ping"})
+	if _, err := reader.Read(); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	return reader, writer
+}
+
+// readMessage reads the next message or fails after a timeout
+func readMessage(t *testing.T, reader *jsonrpc.MessageReader) *mcp.Message {
+	t.Helper()
+
+	done := make(chan *mcp.Message, 1)
+	go func() {
+		msg, _ := reader.Read()
+		done <- msg
+	}()
+
+	select {
+	case msg := <-done:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for message")
+		return nil
+	}
+}
+
+func TestServer_NotifySessions(t *testing.T) {
+	srv := New("test")
+	sessionIDs := make(chan string, 1)
+	_ = srv.AddTool(&ToolHandler{
+		Name: "announce",
+		Handler: func(ctx context.Context, _ json.RawMessage) (interface{}, error) {
+			id, _ := SessionIDFromContext(ctx)
+			sessionIDs <- id
+			return "ok", srv.Notify(ctx, "notifications/vendor/hello", map[string]string{"to": "caller"})
+		},
+	})
+
+	aliceReader, aliceWriter := servePipe(t, srv)
+	bobReader, _ := servePipe(t, srv)
+
+	if got := len(srv.SessionIDs()); got != 2 {
+		t.Fatalf("expected 2 sessions, got %d", got)
+	}
+
+	// Notify inside a request reaches only the caller
+	_ = aliceWriter.Write(&mcp.Message{JSONRPC: "2.0", ID: 2, Method: "tools/call", Params: json.RawMessage(`{"name":"announce"}`)})
+	msg := readMessage(t, aliceReader)
+	if msg.Method != "notifications/vendor/hello" {
+		t.Fatalf("expected notification before the result, got %+v", msg)
+	}
+	if msg := readMessage(t, aliceReader); msg.ID == nil {
+		t.Fatalf("expected tool result, got %+v", msg)
+	}
+	aliceID := <-sessionIDs
+
+	// Pipes are unbuffered, so notifications are sent while the client reads
+	errs := make(chan error, 1)
+
+	// NotifySession targets one session by ID
+	go func() { errs <- srv.NotifySession(aliceID, "notifications/vendor/direct", nil) }()
+	if msg := readMessage(t, aliceReader); msg.Method != "notifications/vendor/direct" {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("notify session failed: %v", err)
+	}
+	if err := srv.NotifySession("missing", "notifications/vendor/direct", nil); !errors.Is(err, ErrUnknownSession) {
+		t.Errorf("expected ErrUnknownSession, got %v", err)
+	}
+
+	// Outside a request, Notify reaches every session; bob only sees this
+	go func() { errs <- srv.Notify(context.Background(), "notifications/vendor/all", nil) }()
+	received := make(chan string, 2)
+	for name, reader := range map[string]*jsonrpc.MessageReader{"alice": aliceReader, "bob": bobReader} {
+		go func() {
+			if msg, err := reader.Read(); err == nil && msg.Method == "notifications/vendor/all" {
+				received <- name
+			}
+		}()
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-received:
+		case <-time.After(time.Second):
+			t.Fatal("notification did not reach every session")
+		}
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package server
+
+// parentAlive always reports true on platforms without a parent check; EOF on
+// stdin remains the shutdown signal there
+func parentAlive(int) bool {
+	return true
+}
@@ -0,0 +1,21 @@
+//go:build unix
+
+package server
+
+import (
+	"os"
+	"syscall"
+)
+
+// parentAlive reports whether ppid is still this process's parent. Orphaned
+// processes are re-parented to init or a subreaper, so a changed parent PID
+// means the original parent exited.
+func parentAlive(ppid int) bool {
+	if ppid <= 1 {
+		return true // Started by init; there is no parent to outlive
+	}
+	if os.Getppid() != ppid {
+		return false
+	}
+	return syscall.Kill(ppid, 0) != syscall.ESRCH
+}
@@ -13,10 +13,13 @@ type PromptFunc func(context.Context, map[string]interface{}) ([]*mcp.PromptMess
 // PromptHandler wraps a prompt function
 type PromptHandler struct {
 	Name        string
+	Title       string // 2025-06-18
 	Description string
 	Arguments   []mcp.PromptArgument
 	Renderer    PromptFunc
 	Tags        []string
+	Meta        map[string]interface{} // 2025-06-18 _meta
+	Deprecated  string                 // Marks the prompt deprecated, saying what to use instead
 }
 
 // PromptManager manages prompts
@@ -54,6 +57,16 @@ func (pm *PromptManager) Get(ctx context.Context, name string, args map[string]i
 	return handler.Renderer(ctx, args)
 }
 
+// deprecation returns the deprecation message of the named prompt, or ""
+func (pm *PromptManager) deprecation(name string) string {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	if handler, ok := pm.prompts[name]; ok {
+		return handler.Deprecated
+	}
+	return ""
+}
+
 // List returns all prompts
 func (pm *PromptManager) List() []*mcp.Prompt {
 	pm.mu.RLock()
@@ -63,8 +76,10 @@ func (pm *PromptManager) List() []*mcp.Prompt {
 	for _, handler := range pm.prompts {
 		prompts = append(prompts, &mcp.Prompt{
 			Name:        handler.Name,
+			Title:       handler.Title,
 			Description: handler.Description,
 			Arguments:   handler.Arguments,
+			Meta:        withDeprecation(handler.Meta, handler.Deprecated),
 		})
 	}
 
@@ -0,0 +1,65 @@
+package proxy
+
+import (
+	"fmt"
+
+	"github.com/jmcarbo/fullmcp/client"
+	"github.com/jmcarbo/fullmcp/server"
+)
+
+const (
+	// nameSeparator joins a namespace to tool and prompt names. Many clients
+	// only accept letters, digits, underscores and hyphens in tool names.
+	nameSeparator = "_"
+	// uriSeparator joins a namespace to resource URIs, as server.CompositeServer does
+	uriSeparator = "/"
+)
+
+// WithNamespace exposes the backend's tools and prompts as
+// "<namespace>_<name>" and its resources as "<namespace>/<uri>", so several
+// backends can share one server. Names are namespaced after filtering and
+// renaming.
+func WithNamespace(namespace string) Option {
+	return func(ps *Server) {
+		ps.namespace = namespace
+	}
+}
+
+// namespaced returns name under the proxy's namespace, if any
+func (ps *Server) namespaced(name, separator string) string {
+	if ps.namespace == "" {
+		return name
+	}
+	return ps.namespace + separator + name
+}
+
+// Upstream is a backend exposed by an aggregating proxy
+type Upstream struct {
+	Namespace string         // Prefix for the backend's names; see WithNamespace
+	Client    *client.Client // Connected backend client
+	Options   []Option       // Filters and transforms for this backend only
+}
+
+// Aggregate creates a server exposing several backends as one, each under
+// its own namespace, for clients that can only configure a single server.
+// Server options apply to the shared server.
+func Aggregate(name string, upstreams []Upstream, opts ...server.Option) (*server.Server, error) {
+	srv := server.New(name, opts...)
+
+	seen := make(map[string]bool, len(upstreams))
+	for _, upstream := range upstreams {
+		if upstream.Namespace == "" {
+			return nil, fmt.Errorf("upstream namespace cannot be empty")
+		}
+		if seen[upstream.Namespace] {
+			return nil, fmt.Errorf("duplicate upstream namespace: %s", upstream.Namespace)
+		}
+		seen[upstream.Namespace] = true
+
+		proxyOpts := append(upstream.Options[:len(upstream.Options):len(upstream.Options)], WithNamespace(upstream.Namespace))
+		if _, err := attach(srv, upstream.Client, proxyOpts); err != nil {
+			return nil, fmt.Errorf("upstream %s: %w", upstream.Namespace, err)
+		}
+	}
+	return srv, nil
+}
@@ -0,0 +1,83 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+	"github.com/jmcarbo/fullmcp/server"
+)
+
+// newNamedBackend has a "whoami" tool and a "notes" resource reporting the
+// backend's name
+func newNamedBackend(name string) *server.Server {
+	backend := server.New(name)
+	_ = backend.AddTool(&server.ToolHandler{
+		Name: "whoami",
+		Handler: func(context.Context, json.RawMessage) (interface{}, error) {
+			return name, nil
+		},
+	})
+	_ = backend.AddResource(&server.ResourceHandler{
+		URI:    "notes://today",
+		Name:   "notes",
+		Reader: func(context.Context) ([]byte, error) { return []byte(name + " notes"), nil },
+	})
+	return backend
+}
+
+func TestAggregate(t *testing.T) {
+	github := connectBackend(t, newNamedBackend("github"))
+	jira := connectBackend(t, newNamedBackend("jira"))
+
+	srv, err := Aggregate("aggregate", []Upstream{
+		{Namespace: "github", Client: github},
+		{Namespace: "jira", Client: jira, Options: []Option{
+			WithToolFilter(Filter{Rename: map[string]string{"whoami": "identify"}}),
+		}},
+	})
+	if err != nil {
+		t.Fatalf("failed to aggregate: %v", err)
+	}
+	proxy := &Server{Server: srv}
+
+	if got := listNames(t, proxy, "tools/list", "tools", "name"); len(got) != 2 || got[0] != "github_whoami" || got[1] != "jira_identify" {
+		t.Errorf("unexpected tools %v", got)
+	}
+	if got := listNames(t, proxy, "resources/list", "resources", "uri"); len(got) != 2 || got[0] != "github/notes://today" || got[1] != "jira/notes://today" {
+		t.Errorf("unexpected resources %v", got)
+	}
+
+	for tool, want := range map[string]string{"github_whoami": "github", "jira_identify": "jira"} {
+		result, rpcErr := proxiedResult(t, proxy, `{"name":"`+tool+`","arguments":{}}`)
+		if rpcErr != nil {
+			t.Fatalf("%s failed: %s", tool, rpcErr.Message)
+		}
+		if text := result.Content[0].(mcp.TextContent).Text; text != want {
+			t.Errorf("%s: expected %q, got %q", tool, want, text)
+		}
+	}
+
+	resp := srv.HandleMessage(context.Background(), &mcp.Message{
+		JSONRPC: "2.0", ID: 2, Method: "resources/read",
+		Params: json.RawMessage(`{"uri":"jira/notes://today"}`),
+	})
+	if resp.Error != nil {
+		t.Fatalf("read failed: %s", resp.Error.Message)
+	}
+}
+
+func TestAggregate_InvalidNamespaces(t *testing.T) {
+	backend := connectBackend(t, newNamedBackend("github"))
+
+	if _, err := Aggregate("aggregate", []Upstream{{Client: backend}}); err == nil {
+		t.Error("expected an empty namespace to fail")
+	}
+	if _, err := Aggregate("aggregate", []Upstream{
+		{Namespace: "a", Client: backend},
+		{Namespace: "a", Client: backend},
+	}); err == nil {
+		t.Error("expected a duplicate namespace to fail")
+	}
+}
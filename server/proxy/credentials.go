@@ -0,0 +1,43 @@
+package proxy
+
+import (
+	"context"
+
+	"github.com/jmcarbo/fullmcp/client"
+	"github.com/jmcarbo/fullmcp/transport"
+	"golang.org/x/oauth2"
+)
+
+// Credentials authenticate the proxy to an upstream server. They are only
+// sent upstream, so clients authenticate once to the proxy and never see
+// them.
+type Credentials struct {
+	APIKey      string             // Sent as the X-API-Key header
+	BearerToken string             // Sent as a static Authorization bearer token
+	TokenSource oauth2.TokenSource // Supplies refreshed bearer tokens, such as from an OAuth client credentials flow
+	Headers     map[string]string  // Extra headers, such as a vendor's own auth header
+}
+
+// config returns the transport configuration carrying c
+func (c Credentials) config() transport.Config {
+	cfg := transport.Config{APIKey: c.APIKey, Headers: c.Headers, TokenSource: c.TokenSource}
+	if cfg.TokenSource == nil && c.BearerToken != "" {
+		cfg.TokenSource = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: c.BearerToken, TokenType: "Bearer"})
+	}
+	return cfg
+}
+
+// DialUpstream connects to the upstream server at rawURL, choosing the
+// transport from the URL scheme as client.Dial does, and authenticates every
+// request with creds. Each upstream gets its own credentials:
+//
+//	github, err := proxy.DialUpstream(ctx, "https+stream://api.example.com/mcp", proxy.Credentials{
+//		TokenSource: oauthConfig.TokenSource(ctx),
+//	})
+//
+// Credentials only apply to HTTP and WebSocket upstreams; DialUpstream fails
+// for other transports rather than connect unauthenticated. Pass secrets to
+// a stdio upstream through its environment instead.
+func DialUpstream(ctx context.Context, rawURL string, creds Credentials, opts ...client.Option) (*client.Client, error) {
+	return client.DialWithConfig(ctx, rawURL, creds.config(), opts...)
+}
@@ -0,0 +1,95 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+	"github.com/jmcarbo/fullmcp/server"
+	transporthttp "github.com/jmcarbo/fullmcp/transport/http"
+)
+
+// headerRecorder serves backend over HTTP, recording the auth headers of
+// every request
+type headerRecorder struct {
+	mu      sync.Mutex
+	headers []http.Header
+}
+
+func (r *headerRecorder) serve(t *testing.T, backend *server.Server) string {
+	t.Helper()
+	handler := transporthttp.NewMCPHandler(func(ctx context.Context, body []byte) ([]byte, error) {
+		var msg mcp.Message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			return nil, err
+		}
+		return json.Marshal(backend.HandleMessage(ctx, &msg))
+	})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.mu.Lock()
+		r.headers = append(r.headers, req.Header.Clone())
+		r.mu.Unlock()
+		handler.ServeHTTP(w, req)
+	}))
+	t.Cleanup(ts.Close)
+	return ts.URL
+}
+
+func TestDialUpstream_InjectsCredentials(t *testing.T) {
+	backend := server.New("vendor")
+	_ = backend.AddTool(&server.ToolHandler{
+		Name: "ping",
+		Handler: func(context.Context, json.RawMessage) (interface{}, error) {
+			return "pong", nil
+		},
+	})
+
+	var recorder headerRecorder
+	url := recorder.serve(t, backend)
+
+	ctx := context.Background()
+	upstream, err := DialUpstream(ctx, url, Credentials{
+		APIKey:      "vendor-key",
+		BearerToken: "vendor-token",
+		Headers:     map[string]string{"X-Tenant": "acme"},
+	})
+	if err != nil {
+		t.Fatalf("failed to dial upstream: %v", err)
+	}
+	defer func() { _ = upstream.Close() }()
+
+	proxy, err := New("proxy-server", upstream)
+	if err != nil {
+		t.Fatalf("failed to create proxy: %v", err)
+	}
+
+	// The end client sends no credentials of its own
+	if _, rpcErr := proxiedResult(t, proxy, `{"name":"ping","arguments":{}}`); rpcErr != nil {
+		t.Fatalf("call failed: %s", rpcErr.Message)
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if len(recorder.headers) == 0 {
+		t.Fatal("expected upstream requests")
+	}
+	for _, h := range recorder.headers {
+		if h.Get("X-API-Key") != "vendor-key" || h.Get("Authorization") != "Bearer vendor-token" || h.Get("X-Tenant") != "acme" {
+			t.Fatalf("expected credentials on every upstream request, got %v", h)
+		}
+	}
+}
+
+func TestDialUpstream_RejectsUnsupportedCredentials(t *testing.T) {
+	for _, rawURL := range []string{"stdio:./server", "unix:///tmp/mcp.sock", "tcp://localhost:9000"} {
+		_, err := DialUpstream(context.Background(), rawURL, Credentials{APIKey: "secret"})
+		if err == nil || !strings.Contains(err.Error(), "does not support credentials") {
+			t.Errorf("%s: expected credentials to be rejected, got %v", rawURL, err)
+		}
+	}
+}
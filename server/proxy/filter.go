@@ -0,0 +1,64 @@
+package proxy
+
+import "path"
+
+// Filter selects and renames the backend tools, resources or prompts the
+// proxy exposes, so an organization can offer a vetted subset of a vendor
+// server. Names are tool and prompt names, or resource URIs; Allow and Deny
+// entries may be path.Match patterns such as "github_*".
+type Filter struct {
+	Allow  []string          // Names exposed; when empty, every name not denied is
+	Deny   []string          // Names never exposed, even if allowed
+	Rename map[string]string // Names clients see, keyed by backend name
+}
+
+// WithToolFilter sets the filter for backend tools. Calls to a renamed tool
+// reach the backend under its original name.
+func WithToolFilter(filter Filter) Option {
+	return func(ps *Server) {
+		ps.toolFilter = &filter
+	}
+}
+
+// WithResourceFilter sets the filter for backend resources, by URI
+func WithResourceFilter(filter Filter) Option {
+	return func(ps *Server) {
+		ps.resourceFilter = &filter
+	}
+}
+
+// WithPromptFilter sets the filter for backend prompts
+func WithPromptFilter(filter Filter) Option {
+	return func(ps *Server) {
+		ps.promptFilter = &filter
+	}
+}
+
+// expose returns the name clients see for a backend name, and whether it is
+// exposed at all. A nil filter exposes everything unchanged.
+func (f *Filter) expose(name string) (string, bool) {
+	if f == nil {
+		return name, true
+	}
+	if len(f.Allow) > 0 && !matchAny(f.Allow, name) {
+		return "", false
+	}
+	if matchAny(f.Deny, name) {
+		return "", false
+	}
+	if renamed, ok := f.Rename[name]; ok {
+		return renamed, true
+	}
+	return name, true
+}
+
+// matchAny reports whether name matches any of patterns. Malformed patterns
+// match nothing.
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+	"github.com/jmcarbo/fullmcp/server"
+)
+
+func TestFilter_Expose(t *testing.T) {
+	f := &Filter{
+		Allow:  []string{"github_*", "search"},
+		Deny:   []string{"github_delete_*"},
+		Rename: map[string]string{"search": "web_search"},
+	}
+
+	tests := []struct {
+		name    string
+		exposed string
+		ok      bool
+	}{
+		{"github_list_issues", "github_list_issues", true},
+		{"github_delete_repo", "", false},
+		{"search", "web_search", true},
+		{"shell", "", false},
+	}
+	for _, tt := range tests {
+		exposed, ok := f.expose(tt.name)
+		if exposed != tt.exposed || ok != tt.ok {
+			t.Errorf("expose(%q) = %q, %v; expected %q, %v", tt.name, exposed, ok, tt.exposed, tt.ok)
+		}
+	}
+
+	var none *Filter
+	if exposed, ok := none.expose("anything"); !ok || exposed != "anything" {
+		t.Errorf("expected a nil filter to expose everything, got %q, %v", exposed, ok)
+	}
+}
+
+// listNames lists the names, or URIs, of a proxy's tools, resources or
+// prompts
+func listNames(t *testing.T, proxy *Server, method, field, key string) []string {
+	t.Helper()
+	resp := proxy.HandleMessage(context.Background(), &mcp.Message{JSONRPC: "2.0", ID: 1, Method: method})
+	if resp.Error != nil {
+		t.Fatalf("%s failed: %s", method, resp.Error.Message)
+	}
+	var result map[string][]map[string]interface{}
+	_ = json.Unmarshal(resp.Result, &result)
+
+	var names []string
+	for _, item := range result[field] {
+		names = append(names, item[key].(string))
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestProxyFilters(t *testing.T) {
+	backend := server.New("vendor")
+	for _, name := range []string{"read_file", "write_file", "delete_file"} {
+		_ = backend.AddTool(&server.ToolHandler{
+			Name: name,
+			Handler: func(context.Context, json.RawMessage) (interface{}, error) {
+				return "called " + name, nil
+			},
+		})
+	}
+	for _, uri := range []string{"config://public", "config://secrets"} {
+		_ = backend.AddResource(&server.ResourceHandler{
+			URI:    uri,
+			Name:   uri,
+			Reader: func(context.Context) ([]byte, error) { return []byte("data"), nil },
+		})
+	}
+	for _, name := range []string{"summarize", "jailbreak"} {
+		_ = backend.AddPrompt(&server.PromptHandler{
+			Name: name,
+			Renderer: func(context.Context, map[string]interface{}) ([]*mcp.PromptMessage, error) {
+				return nil, nil
+			},
+		})
+	}
+	backendClient := connectBackend(t, backend)
+
+	proxy, err := New("proxy-server", backendClient,
+		WithToolFilter(Filter{
+			Deny:   []string{"delete_*"},
+			Rename: map[string]string{"read_file": "vendor_read"},
+		}),
+		WithResourceFilter(Filter{Allow: []string{"config://public"}}),
+		WithPromptFilter(Filter{Deny: []string{"jailbreak"}}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create proxy: %v", err)
+	}
+
+	if got := listNames(t, proxy, "tools/list", "tools", "name"); len(got) != 2 || got[0] != "vendor_read" || got[1] != "write_file" {
+		t.Errorf("unexpected tools %v", got)
+	}
+	if got := listNames(t, proxy, "resources/list", "resources", "uri"); len(got) != 1 || got[0] != "config://public" {
+		t.Errorf("unexpected resources %v", got)
+	}
+	if got := listNames(t, proxy, "prompts/list", "prompts", "name"); len(got) != 1 || got[0] != "summarize" {
+		t.Errorf("unexpected prompts %v", got)
+	}
+
+	// A renamed tool reaches the backend under its original name
+	result, rpcErr := proxiedResult(t, proxy, `{"name":"vendor_read","arguments":{}}`)
+	if rpcErr != nil {
+		t.Fatalf("call failed: %s", rpcErr.Message)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; text != "called read_file" {
+		t.Errorf("unexpected result %q", text)
+	}
+
+	if _, rpcErr := proxiedResult(t, proxy, `{"name":"delete_file","arguments":{}}`); rpcErr == nil {
+		t.Error("expected a denied tool to be unknown")
+	}
+}
@@ -14,18 +14,33 @@ import (
 type Server struct {
 	*server.Server
 	backend *client.Client
+	relay   *relay
+
+	requestTransforms  []RequestTransform
+	responseTransforms []ResponseTransform
+
+	toolFilter     *Filter
+	resourceFilter *Filter
+	promptFilter   *Filter
+	namespace      string
 }
 
 // Option configures the proxy server
 type Option func(*Server)
 
-// New creates a new proxy server that forwards all requests to the backend
+// New creates a new proxy server that forwards all requests to the backend.
+// Progress and log notifications the backend sends during tool calls are
+// relayed to the calling client.
 func New(name string, backend *client.Client, opts ...Option) (*Server, error) {
-	srv := server.New(name)
+	return attach(server.New(name), backend, opts)
+}
 
+// attach registers proxy handlers for backend's capabilities on srv
+func attach(srv *server.Server, backend *client.Client, opts []Option) (*Server, error) {
 	ps := &Server{
 		Server:  srv,
 		backend: backend,
+		relay:   newRelay(),
 	}
 
 	// Apply options
@@ -33,6 +48,8 @@ func New(name string, backend *client.Client, opts ...Option) (*Server, error) {
 		opt(ps)
 	}
 
+	ps.forwardNotifications()
+
 	// Register proxy handlers by fetching from backend and creating local handlers
 	if err := ps.syncFromBackend(context.Background()); err != nil {
 		return nil, err
@@ -58,15 +75,30 @@ func (ps *Server) syncTools(ctx context.Context) error {
 	}
 
 	for _, tool := range tools {
+		exposed, ok := ps.toolFilter.expose(tool.Name)
+		if !ok {
+			continue
+		}
+		exposed = ps.namespaced(exposed, nameSeparator)
 		toolName := tool.Name
 		toolHandler := &server.ToolHandler{
-			Name:        tool.Name,
-			Description: tool.Description,
-			Schema:      tool.InputSchema,
+			Name:         exposed,
+			Description:  tool.Description,
+			Schema:       tool.InputSchema,
+			OutputSchema: tool.OutputSchema,
+			Title:        tool.Title,
+			Meta:         tool.Meta,
 			Handler: func(ctx context.Context, args json.RawMessage) (interface{}, error) {
-				return ps.backend.CallTool(ctx, toolName, args)
+				return ps.callTool(ctx, toolName, args)
 			},
 		}
+		if annotations := tool.Annotations; annotations != nil {
+			toolHandler.ReadOnlyHint = annotations.ReadOnlyHint
+			toolHandler.DestructiveHint = annotations.DestructiveHint
+			toolHandler.IdempotentHint = annotations.IdempotentHint
+			toolHandler.OpenWorldHint = annotations.OpenWorldHint
+			toolHandler.CustomAnnotations = annotations.Custom
+		}
 		if err := ps.Server.AddTool(toolHandler); err != nil {
 			return err
 		}
@@ -82,12 +114,19 @@ func (ps *Server) syncResources(ctx context.Context) error {
 	}
 
 	for _, resource := range resources {
+		exposed, ok := ps.resourceFilter.expose(resource.URI)
+		if !ok {
+			continue
+		}
+		exposed = ps.namespaced(exposed, uriSeparator)
 		resourceURI := resource.URI
 		resourceHandler := &server.ResourceHandler{
-			URI:         resource.URI,
+			URI:         exposed,
 			Name:        resource.Name,
+			Title:       resource.Title,
 			Description: resource.Description,
 			MimeType:    resource.MimeType,
+			Meta:        resource.Meta,
 			Reader: func(ctx context.Context) ([]byte, error) {
 				contents, err := ps.backend.ReadResource(ctx, resourceURI)
 				if err != nil {
@@ -115,11 +154,18 @@ func (ps *Server) syncPrompts(ctx context.Context) error {
 	}
 
 	for _, prompt := range prompts {
+		exposed, ok := ps.promptFilter.expose(prompt.Name)
+		if !ok {
+			continue
+		}
+		exposed = ps.namespaced(exposed, nameSeparator)
 		promptName := prompt.Name
 		promptHandler := &server.PromptHandler{
-			Name:        prompt.Name,
+			Name:        exposed,
+			Title:       prompt.Title,
 			Description: prompt.Description,
 			Arguments:   prompt.Arguments,
+			Meta:        prompt.Meta,
 			Renderer: func(ctx context.Context, args map[string]interface{}) ([]*mcp.PromptMessage, error) {
 				return ps.backend.GetPrompt(ctx, promptName, args)
 			},
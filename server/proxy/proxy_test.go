@@ -418,3 +418,261 @@ func (c *closedConn) Write(p []byte) (int, error) {
 func (c *closedConn) Close() error {
 	return nil
 }
+
+func TestProxyPreservesMetadata(t *testing.T) {
+	backend := server.New("backend-server")
+
+	tool, err := builder.NewTool("lookup").
+		Handler(func(ctx context.Context, args AddArgs) (int, error) { return 0, nil }).
+		Title("Lookup").
+		ReadOnly().
+		Annotation("owner", "search").
+		Meta("example.com/version", "2").
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build tool: %v", err)
+	}
+	_ = backend.AddTool(tool)
+	_ = backend.AddResource(builder.NewResource("file:///data.txt").
+		Name("data").
+		Reader(func(ctx context.Context) ([]byte, error) { return []byte("data"), nil }).
+		Meta("example.com/source", "disk").
+		Build())
+	_ = backend.AddPrompt(builder.NewPrompt("greet").
+		Renderer(func(ctx context.Context, args map[string]interface{}) ([]*mcp.PromptMessage, error) { return nil, nil }).
+		Meta("example.com/locale", "en").
+		Build())
+
+	clientConn, serverConn := newMockTransportPair()
+	defer func() { _ = clientConn.Close() }()
+
+	backendCtx, backendCancel := context.WithCancel(context.Background())
+	defer backendCancel()
+	go func() {
+		_ = backend.Serve(backendCtx, serverConn)
+	}()
+
+	backendClient := client.New(clientConn)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := backendClient.Connect(ctx); err != nil {
+		t.Fatalf("failed to connect to backend: %v", err)
+	}
+	defer func() { _ = backendClient.Close() }()
+
+	proxy, err := New("proxy-server", backendClient)
+	if err != nil {
+		t.Fatalf("failed to create proxy: %v", err)
+	}
+
+	list := func(method string, out interface{}) {
+		resp := proxy.HandleMessage(ctx, &mcp.Message{JSONRPC: "2.0", ID: 1, Method: method})
+		if resp.Error != nil {
+			t.Fatalf("%s failed: %v", method, resp.Error.Message)
+		}
+		if err := json.Unmarshal(resp.Result, out); err != nil {
+			t.Fatalf("failed to unmarshal %s result: %v", method, err)
+		}
+	}
+
+	var tools struct {
+		Tools []*mcp.Tool `json:"tools"`
+	}
+	list("tools/list", &tools)
+	if len(tools.Tools) != 1 {
+		t.Fatalf("expected 1 tool, got %d", len(tools.Tools))
+	}
+	got := tools.Tools[0]
+	if got.Meta["example.com/version"] != "2" {
+		t.Errorf("expected tool _meta to be preserved, got %v", got.Meta)
+	}
+	if got.Title != "Lookup" || !got.Annotations.IsReadOnly() {
+		t.Errorf("expected tool title and hints to be preserved, got %+v", got)
+	}
+	if got.Annotations.Custom["owner"] != "search" {
+		t.Errorf("expected tool annotations to be preserved, got %+v", got.Annotations)
+	}
+
+	var resources struct {
+		Resources []*mcp.Resource `json:"resources"`
+	}
+	list("resources/list", &resources)
+	if len(resources.Resources) != 1 || resources.Resources[0].Meta["example.com/source"] != "disk" {
+		t.Errorf("expected resource _meta to be preserved, got %+v", resources.Resources)
+	}
+
+	var prompts struct {
+		Prompts []*mcp.Prompt `json:"prompts"`
+	}
+	list("prompts/list", &prompts)
+	if len(prompts.Prompts) != 1 || prompts.Prompts[0].Meta["example.com/locale"] != "en" {
+		t.Errorf("expected prompt _meta to be preserved, got %+v", prompts.Prompts)
+	}
+}
+
+func TestProxyRelaysProgressAndLogs(t *testing.T) {
+	backend := server.New("backend-server")
+
+	tool, err := builder.NewTool("slow").
+		Handler(func(ctx context.Context, args AddArgs) (int, error) {
+			req, _ := server.CallToolRequestFromContext(ctx)
+			total := 2.0
+			_ = backend.Notify(ctx, "notifications/progress", &mcp.ProgressNotification{
+				ProgressToken: req.ProgressToken(), Progress: 1, Total: &total, Message: "halfway",
+			})
+			_ = backend.Notify(ctx, "notifications/message", &mcp.LogMessage{
+				Level: mcp.LogLevelInfo, Logger: "slow", Data: map[string]interface{}{"step": "one"},
+			})
+			// Let the relayed notifications arrive before the result
+			time.Sleep(50 * time.Millisecond)
+			return args.A + args.B, nil
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build tool: %v", err)
+	}
+	_ = backend.AddTool(tool)
+
+	clientConn, serverConn := newMockTransportPair()
+	defer func() { _ = clientConn.Close() }()
+
+	backendCtx, backendCancel := context.WithCancel(context.Background())
+	defer backendCancel()
+	go func() {
+		_ = backend.Serve(backendCtx, serverConn)
+	}()
+
+	backendClient := client.New(clientConn)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := backendClient.Connect(ctx); err != nil {
+		t.Fatalf("failed to connect to backend: %v", err)
+	}
+	defer func() { _ = backendClient.Close() }()
+
+	proxy, err := New("proxy-server", backendClient)
+	if err != nil {
+		t.Fatalf("failed to create proxy: %v", err)
+	}
+
+	notifications := make(chan *mcp.Message, 10)
+	proxy.SetNotificationSender(func(method string, params interface{}) error {
+		data, _ := json.Marshal(params)
+		notifications <- &mcp.Message{Method: method, Params: data}
+		return nil
+	})
+
+	resp := proxy.HandleMessage(ctx, &mcp.Message{
+		JSONRPC: "2.0",
+		ID:      7,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"slow","arguments":{"a":1,"b":2},"_meta":{"progressToken":"caller-token"}}`),
+	})
+	if resp.Error != nil {
+		t.Fatalf("call failed: %v", resp.Error.Message)
+	}
+
+	var progress *mcp.ProgressNotification
+	var logMsg *mcp.LogMessage
+	for progress == nil || logMsg == nil {
+		select {
+		case msg := <-notifications:
+			switch msg.Method {
+			case "notifications/progress":
+				progress = &mcp.ProgressNotification{}
+				_ = json.Unmarshal(msg.Params, progress)
+			case "notifications/message":
+				logMsg = &mcp.LogMessage{}
+				_ = json.Unmarshal(msg.Params, logMsg)
+			}
+		case <-ctx.Done():
+			t.Fatalf("missing relayed notifications: progress=%v log=%v", progress, logMsg)
+		}
+	}
+
+	if progress.ProgressToken != "caller-token" {
+		t.Errorf("expected progress under the caller's token, got %v", progress.ProgressToken)
+	}
+	if progress.Progress != 1 || progress.Total == nil || *progress.Total != 2 || progress.Message != "halfway" {
+		t.Errorf("unexpected progress %+v", progress)
+	}
+	if logMsg.Logger != "slow" || logMsg.Data["step"] != "one" {
+		t.Errorf("unexpected log message %+v", logMsg)
+	}
+}
+
+func TestProxyWithoutProgressToken(t *testing.T) {
+	backend := server.New("backend-server")
+
+	tool, _ := builder.NewTool("quiet").
+		Handler(func(ctx context.Context, args AddArgs) (int, error) {
+			req, _ := server.CallToolRequestFromContext(ctx)
+			if req.ProgressToken() != nil {
+				t.Errorf("expected no progress token upstream, got %v", req.ProgressToken())
+			}
+			return 0, nil
+		}).
+		Build()
+	_ = backend.AddTool(tool)
+
+	clientConn, serverConn := newMockTransportPair()
+	defer func() { _ = clientConn.Close() }()
+
+	backendCtx, backendCancel := context.WithCancel(context.Background())
+	defer backendCancel()
+	go func() {
+		_ = backend.Serve(backendCtx, serverConn)
+	}()
+
+	backendClient := client.New(clientConn)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := backendClient.Connect(ctx); err != nil {
+		t.Fatalf("failed to connect to backend: %v", err)
+	}
+	defer func() { _ = backendClient.Close() }()
+
+	proxy, err := New("proxy-server", backendClient)
+	if err != nil {
+		t.Fatalf("failed to create proxy: %v", err)
+	}
+
+	resp := proxy.HandleMessage(ctx, &mcp.Message{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"quiet","arguments":{"a":1,"b":2}}`),
+	})
+	if resp.Error != nil {
+		t.Fatalf("call failed: %v", resp.Error.Message)
+	}
+}
+
+// connectBackend serves backend over an in-memory pipe and returns a client
+// connected to it
+func connectBackend(t *testing.T, backend *server.Server) *client.Client {
+	t.Helper()
+	clientConn, serverConn := newMockTransportPair()
+
+	backendCtx, backendCancel := context.WithCancel(context.Background())
+	go func() {
+		_ = backend.Serve(backendCtx, serverConn)
+	}()
+
+	backendClient := client.New(clientConn)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := backendClient.Connect(ctx); err != nil {
+		backendCancel()
+		t.Fatalf("failed to connect to backend: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = backendClient.Close()
+		backendCancel()
+		_ = clientConn.Close()
+	})
+	return backendClient
+}
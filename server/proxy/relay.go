@@ -0,0 +1,135 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/jmcarbo/fullmcp/client"
+	"github.com/jmcarbo/fullmcp/mcp"
+	"github.com/jmcarbo/fullmcp/server"
+)
+
+// relay tracks proxied tool calls in flight so notifications from the
+// backend reach the callers they belong to
+type relay struct {
+	mu     sync.Mutex
+	calls  map[int64]context.Context // Request contexts of calls in flight
+	nextID int64
+}
+
+func newRelay() *relay {
+	return &relay{calls: make(map[int64]context.Context)}
+}
+
+// begin records a call in flight and returns a function ending it
+func (r *relay) begin(ctx context.Context) func() {
+	r.mu.Lock()
+	r.nextID++
+	id := r.nextID
+	r.calls[id] = ctx
+	r.mu.Unlock()
+
+	return func() {
+		r.mu.Lock()
+		delete(r.calls, id)
+		r.mu.Unlock()
+	}
+}
+
+// caller returns the request context of the calls in flight when they all
+// come from one session, the only case a backend notification can be
+// attributed to its caller
+func (r *relay) caller() (context.Context, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var caller context.Context
+	for _, ctx := range r.calls {
+		if caller != nil && callerSession(ctx) != callerSession(caller) {
+			return nil, false
+		}
+		caller = ctx
+	}
+	return caller, caller != nil
+}
+
+// callerSession returns the ID of the session a call arrived on, from Serve
+// or the request's ConnInfo, or "" without one
+func callerSession(ctx context.Context) string {
+	if id, ok := server.SessionIDFromContext(ctx); ok {
+		return id
+	}
+	if info, ok := server.ConnInfoFromContext(ctx); ok {
+		return info.SessionID
+	}
+	return ""
+}
+
+// callTool forwards a tool call to the backend through the request and
+// response transforms. When the caller asked for progress, the backend call
+// gets a progress token of its own and its progress notifications are
+// relayed to the caller under the caller's token.
+func (ps *Server) callTool(ctx context.Context, name string, args json.RawMessage) (interface{}, error) {
+	defer ps.relay.begin(ctx)()
+
+	call := &ToolCall{Name: name, Arguments: args}
+	if err := ps.transformRequest(ctx, call); err != nil {
+		return nil, err
+	}
+
+	var opts []client.CallOption
+	if len(call.Meta) > 0 {
+		opts = append(opts, client.WithMeta(call.Meta))
+	}
+	req, _ := server.CallToolRequestFromContext(ctx)
+	if token := req.ProgressToken(); token != nil {
+		opts = append(opts, client.WithProgress(func(_ context.Context, n *mcp.ProgressNotification) {
+			relayed := *n
+			relayed.ProgressToken = token
+			_ = ps.Server.Notify(ctx, "notifications/progress", &relayed)
+		}))
+	}
+
+	result, err := ps.backend.CallToolResult(ctx, call.Name, call.Arguments, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := ps.transformResponse(ctx, call, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// relayLog forwards a log message from the backend to the session whose
+// call produced it, at or above the level the session set. Log messages
+// carry no request ID, so they are dropped when no call is in flight or
+// calls from several sessions are, rather than risk leaking them to
+// another client.
+func (ps *Server) relayLog(_ context.Context, params json.RawMessage) {
+	ctx, ok := ps.relay.caller()
+	if !ok {
+		return
+	}
+	session := callerSession(ctx)
+
+	if minLevel, ok := ps.Server.SessionLogLevel(session); ok {
+		var msg mcp.LogMessage
+		if err := json.Unmarshal(params, &msg); err != nil || !msg.Level.ShouldLog(minLevel) {
+			return
+		}
+	}
+
+	if session != "" {
+		_ = ps.Server.NotifySession(session, "notifications/message", params)
+		return
+	}
+	_ = ps.Server.Notify(ctx, "notifications/message", params)
+}
+
+// forwardNotifications relays the backend's log messages to the proxy's
+// clients. It replaces any notifications/message handler registered on the
+// backend client.
+func (ps *Server) forwardNotifications() {
+	ps.backend.OnNotification("notifications/message", ps.relayLog)
+}
@@ -0,0 +1,71 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+	"github.com/jmcarbo/fullmcp/server"
+)
+
+// sessionTarget records the messages sent to each session
+type sessionTarget struct {
+	ids  []string
+	sent map[string]int
+}
+
+func (st *sessionTarget) SessionIDs() []string {
+	return st.ids
+}
+
+func (st *sessionTarget) Send(sessionID string, _ []byte) error {
+	st.sent[sessionID]++
+	return nil
+}
+
+func TestRelayLog_OnlyToCaller(t *testing.T) {
+	ps := &Server{Server: server.New("proxy", server.EnableLogging()), relay: newRelay()}
+	target := &sessionTarget{ids: []string{"a", "b"}, sent: make(map[string]int)}
+	ps.AddBroadcastTarget(target)
+
+	sessionCtx := func(id string) context.Context {
+		return server.ContextWithConnInfo(context.Background(), &server.ConnInfo{SessionID: id})
+	}
+	logAt := func(level mcp.LogLevel) {
+		params, _ := json.Marshal(&mcp.LogMessage{Level: level, Logger: "backend"})
+		ps.relayLog(context.Background(), params)
+	}
+
+	// Unattributable logs are dropped
+	logAt(mcp.LogLevelError)
+	endA := ps.relay.begin(sessionCtx("a"))
+	endB := ps.relay.begin(sessionCtx("b"))
+	logAt(mcp.LogLevelError)
+	endB()
+	if target.sent["a"] != 0 || target.sent["b"] != 0 {
+		t.Fatalf("expected unattributable logs to be dropped, got %v", target.sent)
+	}
+
+	logAt(mcp.LogLevelInfo)
+	if target.sent["a"] != 1 || target.sent["b"] != 0 {
+		t.Fatalf("expected the log to reach the caller only, got %v", target.sent)
+	}
+
+	// The caller's log level applies
+	resp := ps.HandleMessage(sessionCtx("a"), &mcp.Message{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "logging/setLevel",
+		Params:  json.RawMessage(`{"level":"warning"}`),
+	})
+	if resp.Error != nil {
+		t.Fatalf("setLevel failed: %s", resp.Error.Message)
+	}
+	logAt(mcp.LogLevelInfo)
+	logAt(mcp.LogLevelError)
+	endA()
+	if target.sent["a"] != 2 {
+		t.Errorf("expected only the error to pass the caller's level, got %v", target.sent)
+	}
+}
@@ -0,0 +1,61 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// ToolCall is a tool call on its way to the backend
+type ToolCall struct {
+	Name      string
+	Arguments json.RawMessage
+	Meta      map[string]interface{} // Added to the backend request's _meta
+}
+
+// RequestTransform rewrites a tool call before it is forwarded, such as to
+// enforce argument policies or inject _meta. Returning an error fails the
+// call without reaching the backend.
+type RequestTransform func(ctx context.Context, call *ToolCall) error
+
+// ResponseTransform rewrites the backend's result for a tool call before it
+// is returned to the client, such as to redact content. Returning an error
+// fails the call.
+type ResponseTransform func(ctx context.Context, call *ToolCall, result *mcp.CallToolResult) error
+
+// WithRequestTransform adds a transform applied to every proxied tool call.
+// Transforms run in the order they were added.
+func WithRequestTransform(transform RequestTransform) Option {
+	return func(ps *Server) {
+		ps.requestTransforms = append(ps.requestTransforms, transform)
+	}
+}
+
+// WithResponseTransform adds a transform applied to every proxied tool
+// result. Transforms run in the order they were added.
+func WithResponseTransform(transform ResponseTransform) Option {
+	return func(ps *Server) {
+		ps.responseTransforms = append(ps.responseTransforms, transform)
+	}
+}
+
+// transformRequest applies the request transforms to call
+func (ps *Server) transformRequest(ctx context.Context, call *ToolCall) error {
+	for _, transform := range ps.requestTransforms {
+		if err := transform(ctx, call); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// transformResponse applies the response transforms to result
+func (ps *Server) transformResponse(ctx context.Context, call *ToolCall, result *mcp.CallToolResult) error {
+	for _, transform := range ps.responseTransforms {
+		if err := transform(ctx, call, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+	"github.com/jmcarbo/fullmcp/server"
+)
+
+// newLookupBackend has a "lookup" tool echoing its arguments and the _meta
+// it received
+func newLookupBackend(t *testing.T) *server.Server {
+	t.Helper()
+	backend := server.New("backend-server")
+	_ = backend.AddTool(&server.ToolHandler{
+		Name: "lookup",
+		Handler: func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+			req, _ := server.CallToolRequestFromContext(ctx)
+			meta, _ := json.Marshal(req.Meta)
+			return &mcp.CallToolResult{Content: []mcp.Content{
+				mcp.TextContent{Type: "text", Text: string(args)},
+				mcp.TextContent{Type: "text", Text: "meta " + string(meta)},
+				mcp.TextContent{Type: "text", Text: "ssn 123-45-6789"},
+			}}, nil
+		},
+	})
+	return backend
+}
+
+func proxiedResult(t *testing.T, proxy *Server, params string) (*mcp.CallToolResult, *mcp.RPCError) {
+	t.Helper()
+	resp := proxy.HandleMessage(context.Background(), &mcp.Message{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params:  json.RawMessage(params),
+	})
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	var result mcp.CallToolResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatalf("failed to decode result: %v", err)
+	}
+	return &result, nil
+}
+
+func TestProxyTransforms(t *testing.T) {
+	backendClient := connectBackend(t, newLookupBackend(t))
+
+	proxy, err := New("proxy-server", backendClient,
+		WithRequestTransform(func(_ context.Context, call *ToolCall) error {
+			call.Arguments = json.RawMessage(`{"query":"rewritten"}`)
+			call.Meta = map[string]interface{}{"tenant": "acme"}
+			return nil
+		}),
+		WithResponseTransform(func(_ context.Context, call *ToolCall, result *mcp.CallToolResult) error {
+			if call.Name != "lookup" {
+				t.Errorf("unexpected call %q", call.Name)
+			}
+			for i, content := range result.Content {
+				if text, ok := content.(mcp.TextContent); ok && strings.HasPrefix(text.Text, "ssn ") {
+					result.Content[i] = mcp.TextContent{Type: "text", Text: "[redacted]"}
+				}
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create proxy: %v", err)
+	}
+
+	result, rpcErr := proxiedResult(t, proxy, `{"name":"lookup","arguments":{"query":"original"}}`)
+	if rpcErr != nil {
+		t.Fatalf("call failed: %s", rpcErr.Message)
+	}
+
+	var texts []string
+	for _, content := range result.Content {
+		texts = append(texts, content.(mcp.TextContent).Text)
+	}
+	if texts[0] != `{"query":"rewritten"}` {
+		t.Errorf("expected rewritten arguments, got %s", texts[0])
+	}
+	if texts[1] != `meta {"tenant":"acme"}` {
+		t.Errorf("expected injected _meta, got %s", texts[1])
+	}
+	if texts[2] != "[redacted]" {
+		t.Errorf("expected redacted content, got %s", texts[2])
+	}
+}
+
+func TestProxyRequestTransformRejects(t *testing.T) {
+	backendClient := connectBackend(t, newLookupBackend(t))
+
+	proxy, err := New("proxy-server", backendClient,
+		WithRequestTransform(func(_ context.Context, call *ToolCall) error {
+			if strings.Contains(string(call.Arguments), "drop") {
+				return mcp.NewInvalidParams("query not allowed by policy")
+			}
+			return nil
+		}),
+		WithRequestTransform(func(context.Context, *ToolCall) error {
+			return errors.New("second transform should not run")
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create proxy: %v", err)
+	}
+
+	_, rpcErr := proxiedResult(t, proxy, `{"name":"lookup","arguments":{"query":"drop table"}}`)
+	if rpcErr == nil || rpcErr.Message != "query not allowed by policy" {
+		t.Fatalf("expected the policy error, got %+v", rpcErr)
+	}
+}
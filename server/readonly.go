@@ -0,0 +1,28 @@
+package server
+
+import "github.com/jmcarbo/fullmcp/mcp"
+
+// WithReadOnly puts the server in read-only mode, for exposing it to
+// untrusted agents in audit or demo environments. Only tools with
+// ReadOnlyHint set to true can be called; calls to any other tool fail with
+// InvalidRequest without running it. Resources and prompts are only ever
+// read, so they are unaffected.
+func WithReadOnly() Option {
+	return func(s *Server) {
+		s.readOnly = true
+	}
+}
+
+// readOnlyViolation returns the error for a call to the named tool in
+// read-only mode, or nil if the call may run. Unknown tools are left to
+// fail as usual.
+func (s *Server) readOnlyViolation(tool string) *mcp.Error {
+	if !s.readOnly {
+		return nil
+	}
+	readOnly, exists := s.tools.readOnly(tool)
+	if !exists || readOnly {
+		return nil
+	}
+	return mcp.NewInvalidRequest("tool " + tool + " is not read-only and the server is in read-only mode")
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func TestReadOnly_RejectsWritingTools(t *testing.T) {
+	readOnly, writes := true, false
+	var ran []string
+	srv := New("test", WithReadOnly())
+	for _, tool := range []*ToolHandler{
+		{Name: "search", ReadOnlyHint: &readOnly},
+		{Name: "delete", ReadOnlyHint: &writes},
+		{Name: "unannotated"},
+	} {
+		name := tool.Name
+		tool.Handler = func(_ context.Context, _ json.RawMessage) (interface{}, error) {
+			ran = append(ran, name)
+			return "ok", nil
+		}
+		_ = srv.AddTool(tool)
+	}
+
+	call := func(name string) *mcp.Message {
+		return srv.HandleMessage(context.Background(), &mcp.Message{
+			JSONRPC: "2.0",
+			ID:      1,
+			Method:  "tools/call",
+			Params:  json.RawMessage(`{"name":"` + name + `"}`),
+		})
+	}
+
+	if resp := call("search"); resp.Error != nil {
+		t.Errorf("expected the read-only tool to run, got %v", resp.Error)
+	}
+	for _, name := range []string{"delete", "unannotated"} {
+		resp := call(name)
+		if resp.Error == nil || resp.Error.Code != int(mcp.InvalidRequest) {
+			t.Errorf("expected %s to be rejected, got %+v", name, resp)
+		}
+	}
+	if len(ran) != 1 || ran[0] != "search" {
+		t.Errorf("expected only search to run, ran %v", ran)
+	}
+
+	if resp := call("missing"); resp.Error == nil || resp.Error.Code == int(mcp.InvalidRequest) {
+		t.Errorf("expected an unknown tool to fail as usual, got %+v", resp)
+	}
+}
@@ -0,0 +1,205 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// RefreshSchedule decides when a resource is next refreshed. Cron schedules
+// from libraries such as robfig/cron satisfy it.
+type RefreshSchedule interface {
+	Next(time.Time) time.Time
+}
+
+// Every returns a schedule refreshing at a fixed interval
+func Every(interval time.Duration) RefreshSchedule {
+	return everySchedule(interval)
+}
+
+type everySchedule time.Duration
+
+func (e everySchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+// RefresherOption configures a ResourceRefresher
+type RefresherOption func(*ResourceRefresher)
+
+// WithRefreshErrorHandler sets a function called when a scheduled refresh
+// fails. The previously cached content keeps being served.
+func WithRefreshErrorHandler(fn func(uri string, err error)) RefresherOption {
+	return func(r *ResourceRefresher) {
+		r.onError = fn
+	}
+}
+
+// ResourceRefresher re-reads selected resources on a schedule and serves
+// reads from the cached result, for resources backed by slow upstream APIs.
+// When a refresh returns content whose SHA-256 hash differs from the cached
+// content, notifications/resources/updated is sent for subscribed URIs.
+type ResourceRefresher struct {
+	server  *Server
+	onError func(uri string, err error)
+
+	ctx    context.Context // Cancelled by Close to abort in-flight reads
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	entries map[string]*refreshEntry
+}
+
+// refreshEntry is the schedule and cache of a single resource
+type refreshEntry struct {
+	reader   ResourceFunc // The resource's original reader
+	schedule RefreshSchedule
+	stop     chan struct{}
+
+	mu     sync.RWMutex
+	data   []byte
+	hash   [sha256.Size]byte
+	loaded bool
+}
+
+// NewResourceRefresher creates a refresher for resources registered on s
+func NewResourceRefresher(s *Server, opts ...RefresherOption) *ResourceRefresher {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &ResourceRefresher{
+		server:  s,
+		ctx:     ctx,
+		cancel:  cancel,
+		entries: make(map[string]*refreshEntry),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Schedule refreshes the resource registered at uri every interval
+func (r *ResourceRefresher) Schedule(uri string, interval time.Duration) error {
+	return r.ScheduleAt(uri, Every(interval))
+}
+
+// ScheduleAt refreshes the resource registered at uri according to
+// schedule. The resource is first read when a client reads it or at the
+// first scheduled refresh, whichever comes first.
+func (r *ResourceRefresher) ScheduleAt(uri string, schedule RefreshSchedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.entries[uri]; exists {
+		return mcp.NewInvalidParams("resource " + uri + " is already scheduled")
+	}
+
+	e := &refreshEntry{schedule: schedule, stop: make(chan struct{})}
+	original, err := r.server.resources.replaceReader(uri, func(ctx context.Context) ([]byte, error) {
+		return r.cached(ctx, uri, e)
+	})
+	if err != nil {
+		return err
+	}
+	e.reader = original
+	r.entries[uri] = e
+
+	r.wg.Add(1)
+	go r.run(uri, e)
+	return nil
+}
+
+// Unschedule stops refreshing uri and restores its original reader
+func (r *ResourceRefresher) Unschedule(uri string) {
+	r.mu.Lock()
+	e, exists := r.entries[uri]
+	delete(r.entries, uri)
+	r.mu.Unlock()
+
+	if !exists {
+		return
+	}
+	close(e.stop)
+	_, _ = r.server.resources.replaceReader(uri, e.reader)
+}
+
+// Close stops every schedule and waits for in-flight refreshes. Resources
+// keep serving their last cached content.
+func (r *ResourceRefresher) Close() error {
+	r.cancel()
+	r.wg.Wait()
+	return nil
+}
+
+func (r *ResourceRefresher) run(uri string, e *refreshEntry) {
+	defer r.wg.Done()
+
+	for {
+		timer := time.NewTimer(time.Until(e.schedule.Next(time.Now())))
+		select {
+		case <-r.ctx.Done():
+			timer.Stop()
+			return
+		case <-e.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+			if _, err := r.refresh(r.ctx, uri, e); err != nil && r.onError != nil && r.ctx.Err() == nil {
+				r.onError(uri, err)
+			}
+		}
+	}
+}
+
+// cached returns the cached content of uri, reading it if nothing has been
+// cached yet
+func (r *ResourceRefresher) cached(ctx context.Context, uri string, e *refreshEntry) ([]byte, error) {
+	e.mu.RLock()
+	data, loaded := e.data, e.loaded
+	e.mu.RUnlock()
+
+	if loaded {
+		return data, nil
+	}
+	return r.refresh(ctx, uri, e)
+}
+
+// refresh reads uri and caches the content, notifying subscribers if it
+// changed since the last read
+func (r *ResourceRefresher) refresh(ctx context.Context, uri string, e *refreshEntry) ([]byte, error) {
+	data, err := e.reader(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(data)
+	e.mu.Lock()
+	changed := e.loaded && hash != e.hash
+	e.data, e.hash, e.loaded = data, hash, true
+	e.mu.Unlock()
+
+	if changed {
+		_ = r.server.NotifyResourceUpdated(uri)
+	}
+	return data, nil
+}
+
+// replaceReader swaps the reader of the resource at uri, returning the
+// previous one
+func (rm *ResourceManager) replaceReader(uri string, reader ResourceFunc) (ResourceFunc, error) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	handler, exists := rm.resources[uri]
+	if !exists {
+		return nil, &mcp.NotFoundError{Type: "resource", Name: uri}
+	}
+
+	// Copy the handler so the caller's struct is left untouched
+	replaced := *handler
+	replaced.Reader = reader
+	rm.resources[uri] = &replaced
+	return handler.Reader, nil
+}
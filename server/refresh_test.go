@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// upstream is a resource reader whose content can be changed by tests
+type upstream struct {
+	mu    sync.Mutex
+	value string
+	err   error
+	reads atomic.Int32
+}
+
+func (u *upstream) set(value string, err error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.value, u.err = value, err
+}
+
+func (u *upstream) read(_ context.Context) ([]byte, error) {
+	u.reads.Add(1)
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return []byte(u.value), u.err
+}
+
+func newDashboardServer(t *testing.T, u *upstream) *Server {
+	t.Helper()
+	srv := New("test")
+	_ = srv.AddResource(&ResourceHandler{URI: "dashboard://sales", Reader: u.read})
+	return srv
+}
+
+func TestResourceRefresher_NotifiesOnChange(t *testing.T) {
+	u := &upstream{value: "v1"}
+	srv := newDashboardServer(t, u)
+	sent := captureNotifications(srv)
+	subscribe(t, srv, "dashboard://sales")
+
+	r := NewResourceRefresher(srv)
+	defer func() { _ = r.Close() }()
+	if err := r.Schedule("dashboard://sales", 10*time.Millisecond); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+
+	// Unchanged content does not notify
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case n := <-sent:
+		t.Fatalf("unexpected notification %s", n.method)
+	default:
+	}
+
+	u.set("v2", nil)
+	n := waitForNotification(t, sent, "notifications/resources/updated")
+	if n.params.(*mcp.ResourceUpdatedNotification).URI != "dashboard://sales" {
+		t.Errorf("unexpected notification params %+v", n.params)
+	}
+
+	data, err := srv.resources.Read(context.Background(), "dashboard://sales")
+	if err != nil || string(data) != "v2" {
+		t.Errorf("expected the refreshed content, got %q, %v", data, err)
+	}
+}
+
+func TestResourceRefresher_ServesCache(t *testing.T) {
+	u := &upstream{value: "v1"}
+	srv := newDashboardServer(t, u)
+
+	r := NewResourceRefresher(srv)
+	defer func() { _ = r.Close() }()
+	_ = r.Schedule("dashboard://sales", time.Hour)
+
+	for i := 0; i < 3; i++ {
+		data, err := srv.resources.Read(context.Background(), "dashboard://sales")
+		if err != nil || string(data) != "v1" {
+			t.Fatalf("unexpected read %q, %v", data, err)
+		}
+	}
+	if u.reads.Load() != 1 {
+		t.Errorf("expected reads to be served from the cache, upstream read %d times", u.reads.Load())
+	}
+
+	r.Unschedule("dashboard://sales")
+	_, _ = srv.resources.Read(context.Background(), "dashboard://sales")
+	if u.reads.Load() != 2 {
+		t.Errorf("expected unscheduling to restore the reader, upstream read %d times", u.reads.Load())
+	}
+}
+
+func TestResourceRefresher_KeepsStaleContentOnError(t *testing.T) {
+	u := &upstream{value: "v1"}
+	srv := newDashboardServer(t, u)
+
+	failures := make(chan error, 10)
+	r := NewResourceRefresher(srv, WithRefreshErrorHandler(func(_ string, err error) {
+		select {
+		case failures <- err:
+		default:
+		}
+	}))
+	defer func() { _ = r.Close() }()
+	_ = r.Schedule("dashboard://sales", 10*time.Millisecond)
+
+	if _, err := srv.resources.Read(context.Background(), "dashboard://sales"); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+
+	u.set("", errors.New("upstream unavailable"))
+	select {
+	case <-failures:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the refresh error")
+	}
+
+	data, err := srv.resources.Read(context.Background(), "dashboard://sales")
+	if err != nil || string(data) != "v1" {
+		t.Errorf("expected the stale content, got %q, %v", data, err)
+	}
+}
+
+func TestResourceRefresher_UnknownResource(t *testing.T) {
+	r := NewResourceRefresher(New("test"))
+	defer func() { _ = r.Close() }()
+
+	var notFound *mcp.NotFoundError
+	if err := r.Schedule("dashboard://missing", time.Minute); !errors.As(err, &notFound) {
+		t.Errorf("expected a NotFoundError, got %v", err)
+	}
+}
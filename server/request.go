@@ -0,0 +1,37 @@
+package server
+
+import (
+	"context"
+	"fmt"
+)
+
+// Request sends a request to the client of a session and decodes the
+// response into result, which may be nil to discard it. It is the plumbing
+// behind roots/list and keepalive pings, exposed so extensions can issue
+// their own server-to-client requests. A JSON-RPC error from the client is
+// returned as an *mcp.RPCError; ErrSessionClosed is returned if the session
+// ends before the client answers.
+func (s *Server) Request(ctx context.Context, sessionID, method string, params, result interface{}) error {
+	ss, err := s.lookupSession(sessionID)
+	if err != nil {
+		return err
+	}
+	return ss.request(ctx, method, params, result)
+}
+
+// PingClient pings the client of a session, returning nil once it answers
+func (s *Server) PingClient(ctx context.Context, sessionID string) error {
+	return s.Request(ctx, sessionID, "ping", nil, nil)
+}
+
+// lookupSession returns the connected session with id
+func (s *Server) lookupSession(id string) (*session, error) {
+	s.sessionsMu.RLock()
+	ss, ok := s.sessions[id]
+	s.sessionsMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSession, id)
+	}
+	return ss, nil
+}
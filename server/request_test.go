@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func TestServer_Request(t *testing.T) {
+	srv := New("test")
+	reader, writer := servePipe(t, srv)
+	sessionID := srv.SessionIDs()[0]
+
+	type result struct {
+		Theme string `json:"theme"`
+	}
+	done := make(chan error, 1)
+	var got result
+	go func() {
+		done <- srv.Request(context.Background(), sessionID, "vendor/getSettings", map[string]string{"key": "theme"}, &got)
+	}()
+
+	req := readMessage(t, reader)
+	if req.Method != "vendor/getSettings" || string(req.Params) != `{"key":"theme"}` {
+		t.Fatalf("unexpected request %+v", req)
+	}
+	_ = writer.Write(&mcp.Message{JSONRPC: "2.0", ID: req.ID, Result: json.RawMessage(`{"theme":"dark"}`)})
+
+	if err := <-done; err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if got.Theme != "dark" {
+		t.Errorf("expected the decoded result, got %+v", got)
+	}
+
+	// Client errors are returned as RPC errors
+	go func() {
+		done <- srv.Request(context.Background(), sessionID, "vendor/unknown", nil, nil)
+	}()
+	req = readMessage(t, reader)
+	_ = writer.Write(&mcp.Message{JSONRPC: "2.0", ID: req.ID, Error: &mcp.RPCError{Code: int(mcp.MethodNotFound), Message: "method not found"}})
+
+	var rpcErr *mcp.RPCError
+	if err := <-done; !errors.As(err, &rpcErr) || rpcErr.Code != int(mcp.MethodNotFound) {
+		t.Errorf("expected a MethodNotFound error, got %v", err)
+	}
+}
+
+func TestServer_PingClient(t *testing.T) {
+	srv := New("test")
+	reader, writer := servePipe(t, srv)
+	sessionID := srv.SessionIDs()[0]
+
+	done := make(chan error, 1)
+	go func() { done <- srv.PingClient(context.Background(), sessionID) }()
+
+	req := readMessage(t, reader)
+	if req.Method != "ping" {
+		t.Fatalf("expected a ping, got %+v", req)
+	}
+	_ = writer.Write(&mcp.Message{JSONRPC: "2.0", ID: req.ID, Result: json.RawMessage(`{}`)})
+	if err := <-done; err != nil {
+		t.Errorf("ping failed: %v", err)
+	}
+
+	if err := srv.PingClient(context.Background(), "missing"); !errors.Is(err, ErrUnknownSession) {
+		t.Errorf("expected ErrUnknownSession, got %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"regexp"
+	"sort"
 	"sync"
 
 	"github.com/jmcarbo/fullmcp/mcp"
@@ -19,26 +20,35 @@ type ResourceContentWithMetadata struct {
 	Data     []byte
 	MimeType string
 	URI      string
+	Binary   bool // Data is sent as a base64 blob
 }
 
 // ResourceHandler wraps a resource function
 type ResourceHandler struct {
 	URI         string
 	Name        string
+	Title       string // 2025-06-18
 	Description string
 	MimeType    string
+	Annotations *mcp.Annotations // Audience, priority and modification time
+	Binary      bool             // Read data is sent as a base64 blob instead of text
 	Reader      ResourceFunc
 	Tags        []string
+	Meta        map[string]interface{} // 2025-06-18 _meta
 }
 
 // ResourceTemplateHandler handles parameterized resources
 type ResourceTemplateHandler struct {
 	URITemplate string
 	Name        string
+	Title       string // 2025-06-18
 	Description string
 	MimeType    string
+	Annotations *mcp.Annotations // Audience, priority and modification time
+	Binary      bool             // Read data is sent as a base64 blob instead of text
 	Reader      ResourceTemplateFunc
 	Tags        []string
+	Meta        map[string]interface{} // 2025-06-18 _meta
 	pattern     *regexp.Regexp
 }
 
@@ -111,6 +121,7 @@ func (rm *ResourceManager) ReadWithMetadata(ctx context.Context, uri string) (*R
 			Data:     data,
 			MimeType: mimeType,
 			URI:      uri,
+			Binary:   handler.Binary,
 		}, nil
 	}
 
@@ -129,6 +140,7 @@ func (rm *ResourceManager) ReadWithMetadata(ctx context.Context, uri string) (*R
 				Data:     data,
 				MimeType: mimeType,
 				URI:      uri,
+				Binary:   template.Binary,
 			}, nil
 		}
 	}
@@ -138,19 +150,33 @@ func (rm *ResourceManager) ReadWithMetadata(ctx context.Context, uri string) (*R
 
 // List returns all resources
 func (rm *ResourceManager) List() []*mcp.Resource {
+	return rm.ListFiltered(mcp.ResourceFilter{})
+}
+
+// ListFiltered returns the resources matching filter, ordered by URI
+func (rm *ResourceManager) ListFiltered(filter mcp.ResourceFilter) []*mcp.Resource {
 	rm.mu.RLock()
 	defer rm.mu.RUnlock()
 
 	resources := make([]*mcp.Resource, 0, len(rm.resources))
 	for _, handler := range rm.resources {
+		if !filter.Matches(handler.URI, handler.Tags) {
+			continue
+		}
 		resources = append(resources, &mcp.Resource{
 			URI:         handler.URI,
 			Name:        handler.Name,
+			Title:       handler.Title,
 			Description: handler.Description,
 			MimeType:    handler.MimeType,
+			Annotations: handler.Annotations,
+			Meta:        handler.Meta,
 		})
 	}
 
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].URI < resources[j].URI
+	})
 	return resources
 }
 
@@ -164,8 +190,11 @@ func (rm *ResourceManager) ListTemplates() []*mcp.ResourceTemplate {
 		templates = append(templates, &mcp.ResourceTemplate{
 			URITemplate: handler.URITemplate,
 			Name:        handler.Name,
+			Title:       handler.Title,
 			Description: handler.Description,
 			MimeType:    handler.MimeType,
+			Annotations: handler.Annotations,
+			Meta:        handler.Meta,
 		})
 	}
 
@@ -2,8 +2,11 @@ package server
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
 )
 
 func TestResourceManager_ReadWithMetadata(t *testing.T) {
@@ -190,3 +193,47 @@ func TestResourceManager_Read_BackwardCompatibility(t *testing.T) {
 		t.Errorf("Data = %v, want {\"test\":\"data\"}", string(data))
 	}
 }
+
+func TestServer_ResourcesReadBinary(t *testing.T) {
+	srv := New("test-server")
+	png := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff}
+	_ = srv.AddResource(&ResourceHandler{
+		URI:      "image://logo",
+		MimeType: "image/png",
+		Binary:   true,
+		Reader: func(ctx context.Context) ([]byte, error) {
+			return png, nil
+		},
+	})
+
+	response := srv.HandleMessage(context.Background(), &mcp.Message{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "resources/read",
+		Params:  json.RawMessage(`{"uri":"image://logo"}`),
+	})
+	if response.Error != nil {
+		t.Fatalf("unexpected error: %v", response.Error)
+	}
+
+	var result struct {
+		Contents []map[string]interface{} `json:"contents"`
+	}
+	if err := json.Unmarshal(response.Result, &result); err != nil {
+		t.Fatalf("failed to unmarshal result: %v", err)
+	}
+	if len(result.Contents) != 1 {
+		t.Fatalf("expected 1 content, got %d", len(result.Contents))
+	}
+
+	content := result.Contents[0]
+	if _, ok := content["text"]; ok {
+		t.Error("binary contents should not have a text field")
+	}
+	if content["blob"] != base64.StdEncoding.EncodeToString(png) {
+		t.Errorf("blob = %v, want base64 of the data", content["blob"])
+	}
+	if content["mimeType"] != "image/png" {
+		t.Errorf("mimeType = %v, want image/png", content["mimeType"])
+	}
+}
@@ -2,7 +2,9 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/jmcarbo/fullmcp/mcp"
@@ -139,6 +141,32 @@ func TestResourceManager_List(t *testing.T) {
 	}
 }
 
+func TestResourceManager_ListAnnotations(t *testing.T) {
+	rm := NewResourceManager()
+	reader := func(context.Context) ([]byte, error) { return nil, nil }
+
+	_ = rm.Register(&ResourceHandler{
+		URI:         "file:///notes.md",
+		Name:        "notes",
+		Annotations: mcp.NewAnnotations().WithAudience(mcp.RoleAssistant).WithPriority(0.9),
+		Reader:      reader,
+	})
+	_ = rm.Register(&ResourceHandler{URI: "file:///plain.txt", Name: "plain", Reader: reader})
+
+	for _, resource := range rm.List() {
+		switch resource.URI {
+		case "file:///notes.md":
+			if resource.Annotations.PriorityOr(0) != 0.9 || resource.Annotations.IsFor(mcp.RoleUser) {
+				t.Errorf("unexpected annotations: %+v", resource.Annotations)
+			}
+		case "file:///plain.txt":
+			if resource.Annotations != nil {
+				t.Errorf("expected no annotations, got %+v", resource.Annotations)
+			}
+		}
+	}
+}
+
 func TestResourceManager_ConcurrentAccess(t *testing.T) {
 	rm := NewResourceManager()
 
@@ -192,3 +220,64 @@ func TestResourceHandler_WithMimeType(t *testing.T) {
 		t.Errorf("expected mime type 'application/json', got '%s'", handler.MimeType)
 	}
 }
+
+func TestServer_ResourcesListFiltered(t *testing.T) {
+	srv := New("test")
+	reader := func(context.Context) ([]byte, error) { return nil, nil }
+	for _, h := range []*ResourceHandler{
+		{URI: "file:///docs/guide.md", Name: "guide", Tags: []string{"docs", "public"}},
+		{URI: "file:///docs/internal.md", Name: "internal", Tags: []string{"docs"}},
+		{URI: "file:///src/main.go", Name: "main"},
+		{URI: "db://users", Name: "users", Tags: []string{"public"}},
+	} {
+		h.Reader = reader
+		_ = srv.AddResource(h)
+	}
+
+	uris := func(resources []*mcp.Resource) []string {
+		names := make([]string, 0, len(resources))
+		for _, r := range resources {
+			names = append(names, r.URI)
+		}
+		return names
+	}
+
+	if got := uris(srv.resources.ListFiltered(mcp.ResourceFilter{Scheme: "file", Tags: []string{"docs"}})); len(got) != 2 ||
+		got[0] != "file:///docs/guide.md" || got[1] != "file:///docs/internal.md" {
+		t.Errorf("unexpected filtered resources: %v", got)
+	}
+
+	tests := []struct {
+		name   string
+		params string
+		want   []string
+	}{
+		{"no params", ``, []string{"db://users", "file:///docs/guide.md", "file:///docs/internal.md", "file:///src/main.go"}},
+		{"prefix param", `{"filter":{"prefix":"file:///src/"}}`, []string{"file:///src/main.go"}},
+		{"tags in meta", `{"_meta":{"filter":{"tags":["public"]}}}`, []string{"db://users", "file:///docs/guide.md"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &mcp.Message{JSONRPC: "2.0", ID: 1, Method: "resources/list"}
+			if tt.params != "" {
+				msg.Params = json.RawMessage(tt.params)
+			}
+
+			resp := srv.HandleMessage(context.Background(), msg)
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %v", resp.Error)
+			}
+
+			var result struct {
+				Resources []*mcp.Resource `json:"resources"`
+			}
+			if err := json.Unmarshal(resp.Result, &result); err != nil {
+				t.Fatalf("failed to decode result: %v", err)
+			}
+			if got := uris(result.Resources); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
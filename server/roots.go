@@ -2,6 +2,11 @@ package server
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
 
 	"github.com/jmcarbo/fullmcp/mcp"
 )
@@ -16,13 +21,184 @@ func WithRootsHandler(handler RootsHandler) Option {
 	}
 }
 
-// ListRoots requests the list of roots from the client
-// Note: This requires bidirectional communication with the client
-func (s *Server) ListRoots(_ context.Context) ([]mcp.Root, error) {
-	// In a real implementation, this would send a request to the connected client
-	// For now, return an error indicating this needs to be implemented in the transport layer
-	return nil, &mcp.Error{
-		Code:    mcp.InternalError,
-		Message: "roots/list requests require bidirectional communication with client",
+// ListRoots requests the list of roots from the client of the session in
+// ctx, which must come from a request handler
+func (s *Server) ListRoots(ctx context.Context) ([]mcp.Root, error) {
+	ss := sessionFromContext(ctx)
+	if ss == nil {
+		return nil, mcp.NewInternalError("roots/list requests require a client session")
+	}
+
+	var result mcp.RootsListResult
+	if err := ss.request(ctx, "roots/list", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Roots, nil
+}
+
+// RootBoundaryError reports a path that falls outside every root declared by
+// the client
+type RootBoundaryError struct {
+	Path  string   // Resolved path that was rejected
+	Roots []string // Root directories it was checked against
+}
+
+func (e *RootBoundaryError) Error() string {
+	return fmt.Sprintf("path %q is outside the client's roots", e.Path)
+}
+
+// RootBoundary restricts file paths to the directories of a client's file://
+// roots. Symbolic links are resolved before paths are compared, so a link
+// inside a root cannot point outside it.
+type RootBoundary struct {
+	dirs []string
+}
+
+// NewRootBoundary creates a boundary from roots; roots with other schemes
+// are ignored
+func NewRootBoundary(roots []mcp.Root) *RootBoundary {
+	b := &RootBoundary{}
+	for _, root := range roots {
+		u, err := url.Parse(root.URI)
+		if err != nil || u.Scheme != "file" || u.Path == "" {
+			continue
+		}
+		b.dirs = append(b.dirs, resolvePath(filepath.FromSlash(u.Path)))
+	}
+	return b
+}
+
+// RootBoundary fetches the client's roots and returns a boundary for them
+func (s *Server) RootBoundary(ctx context.Context) (*RootBoundary, error) {
+	roots, err := s.ListRoots(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewRootBoundary(roots), nil
+}
+
+// Check resolves path, which may also be a file:// URI, and returns it when
+// it lies inside a root. Otherwise it returns a *RootBoundaryError. Relative
+// paths are resolved against the working directory.
+func (b *RootBoundary) Check(path string) (string, error) {
+	if strings.HasPrefix(path, "file://") {
+		u, err := url.Parse(path)
+		if err != nil {
+			return "", err
+		}
+		path = filepath.FromSlash(u.Path)
+	}
+
+	resolved := resolvePath(path)
+	for _, dir := range b.dirs {
+		rel, err := filepath.Rel(dir, resolved)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return resolved, nil
+		}
+	}
+	return "", &RootBoundaryError{Path: resolved, Roots: append([]string{}, b.dirs...)}
+}
+
+// ToolWithinRoots wraps a tool handler so the named string (or string array)
+// arguments are checked against the client's roots before it runs. Missing
+// arguments are left to schema validation.
+func (s *Server) ToolWithinRoots(handler ToolFunc, fields ...string) ToolFunc {
+	return func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+		var values map[string]interface{}
+		if err := json.Unmarshal(args, &values); err != nil {
+			return nil, &mcp.ValidationError{Field: "arguments", Message: "must be an object"}
+		}
+
+		boundary, err := s.RootBoundary(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, field := range fields {
+			paths, ok := pathArgument(values[field])
+			if !ok {
+				return nil, &mcp.ValidationError{Field: field, Message: "must be a path or list of paths"}
+			}
+			for _, path := range paths {
+				if _, err := boundary.Check(path); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return handler(ctx, args)
+	}
+}
+
+// ResourceWithinRoots wraps a resource reader backed by path so it fails
+// unless path is inside the client's roots
+func (s *Server) ResourceWithinRoots(path string, reader ResourceFunc) ResourceFunc {
+	return func(ctx context.Context) ([]byte, error) {
+		boundary, err := s.RootBoundary(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := boundary.Check(path); err != nil {
+			return nil, err
+		}
+		return reader(ctx)
+	}
+}
+
+// TemplateWithinRoots wraps a resource template reader so the named URI
+// template parameter must be a path inside the client's roots
+func (s *Server) TemplateWithinRoots(param string, reader ResourceTemplateFunc) ResourceTemplateFunc {
+	return func(ctx context.Context, params map[string]string) ([]byte, error) {
+		boundary, err := s.RootBoundary(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := boundary.Check(params[param]); err != nil {
+			return nil, err
+		}
+		return reader(ctx, params)
+	}
+}
+
+// pathArgument returns the paths in a string or string array argument
+func pathArgument(v interface{}) ([]string, bool) {
+	switch v := v.(type) {
+	case nil:
+		return nil, true
+	case string:
+		return []string{v}, true
+	case []interface{}:
+		paths := make([]string, 0, len(v))
+		for _, item := range v {
+			path, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			paths = append(paths, path)
+		}
+		return paths, true
+	}
+	return nil, false
+}
+
+// resolvePath returns the absolute form of path with symbolic links resolved.
+// Components that do not exist yet are kept as written, so paths of files
+// about to be created can still be checked.
+func resolvePath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+
+	existing, rest := abs, ""
+	for {
+		if resolved, err := filepath.EvalSymlinks(existing); err == nil {
+			return filepath.Join(resolved, rest)
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return abs
+		}
+		rest = filepath.Join(filepath.Base(existing), rest)
+		existing = parent
 	}
 }
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func TestRootBoundary_Check(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	b := NewRootBoundary([]mcp.Root{
+		{URI: "file://" + filepath.ToSlash(root)},
+		{URI: "https://example.com/repo"},
+	})
+
+	for _, path := range []string{
+		root,
+		filepath.Join(root, "src", "new.go"),
+		"file://" + filepath.ToSlash(filepath.Join(root, "notes.txt")),
+	} {
+		if _, err := b.Check(path); err != nil {
+			t.Errorf("expected %s to be allowed, got %v", path, err)
+		}
+	}
+
+	for _, path := range []string{
+		outside,
+		filepath.Join(root, "..", filepath.Base(outside)),
+		filepath.Join(root, "escape", "secret.txt"),
+		root + "-sibling",
+	} {
+		_, err := b.Check(path)
+		var boundaryErr *RootBoundaryError
+		if !errors.As(err, &boundaryErr) {
+			t.Errorf("expected boundary error for %s, got %v", path, err)
+		}
+	}
+}
+
+func TestRootBoundary_NoRoots(t *testing.T) {
+	b := NewRootBoundary(nil)
+	if _, err := b.Check(t.TempDir()); err == nil {
+		t.Error("expected every path to be rejected without roots")
+	}
+}
+
+func TestServer_ListRootsWithoutSession(t *testing.T) {
+	srv := New("test")
+	if _, err := srv.ListRoots(context.Background()); err == nil {
+		t.Error("expected an error outside a session")
+	}
+}
+
+func TestServer_ToolWithinRoots(t *testing.T) {
+	root := t.TempDir()
+	srv := New("test")
+	_ = srv.AddTool(&ToolHandler{
+		Name: "read",
+		Handler: srv.ToolWithinRoots(func(_ context.Context, _ json.RawMessage) (interface{}, error) {
+			return "ok", nil
+		}, "path"),
+	})
+
+	reader, writer := servePipe(t, srv)
+
+	call := func(id int, path string) *mcp.Message {
+		t.Helper()
+		args, _ := json.Marshal(map[string]interface{}{"name": "read", "arguments": map[string]string{"path": path}})
+		_ = writer.Write(&mcp.Message{JSONRPC: "2.0", ID: id, Method: "tools/call", Params: args})
+
+		// The handler asks for the roots before answering
+		req := readMessage(t, reader)
+		if req.Method != "roots/list" {
+			t.Fatalf("expected roots/list request, got %+v", req)
+		}
+		result, _ := json.Marshal(mcp.RootsListResult{Roots: []mcp.Root{{URI: "file://" + filepath.ToSlash(root)}}})
+		_ = writer.Write(&mcp.Message{JSONRPC: "2.0", ID: req.ID, Result: result})
+
+		return readMessage(t, reader)
+	}
+
+	resp := call(2, filepath.Join(root, "file.txt"))
+	if resp.Error != nil || strings.Contains(string(resp.Result), `"isError":true`) {
+		t.Errorf("expected path inside root to be allowed, got %s %v", resp.Result, resp.Error)
+	}
+
+	resp = call(3, "/etc/passwd")
+	if !strings.Contains(string(resp.Result), `"isError":true`) || !strings.Contains(string(resp.Result), "outside the client's roots") {
+		t.Errorf("expected boundary violation result, got %s %v", resp.Result, resp.Error)
+	}
+}
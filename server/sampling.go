@@ -22,18 +22,12 @@ func EnableSampling() Option {
 // This allows servers to leverage client-side LLM capabilities
 func (s *Server) CreateMessage(_ context.Context, _ *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
 	if s.sampling == nil || !s.sampling.enabled {
-		return nil, &mcp.Error{
-			Code:    mcp.MethodNotFound,
-			Message: "sampling not enabled on this server",
-		}
+		return nil, &mcp.Error{Code: mcp.MethodNotFound, Message: "sampling not enabled on this server"}
 	}
 
 	// In a real implementation, this would send a request to the connected client
 	// For now, return an error indicating this needs to be implemented in the transport layer
-	return nil, &mcp.Error{
-		Code:    mcp.InternalError,
-		Message: "sampling requests require bidirectional communication with client",
-	}
+	return nil, mcp.NewInternalError("sampling requests require bidirectional communication with client")
 }
 
 // Helper functions for building sampling requests
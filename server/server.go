@@ -5,20 +5,29 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"reflect"
+	"sync"
+	"time"
 
 	"github.com/jmcarbo/fullmcp/internal/jsonrpc"
 	"github.com/jmcarbo/fullmcp/mcp"
 )
 
+// protocolVersion is the MCP protocol version the server speaks
+const protocolVersion = "2025-06-18"
+
 // Server is the main MCP server
 type Server struct {
 	name         string
+	title        string
 	version      string
 	instructions string
+	instructFunc InstructionsFunc
 
-	tools     *ToolManager
-	resources *ResourceManager
-	prompts   *PromptManager
+	tools         *ToolManager
+	resources     *ResourceManager
+	prompts       *PromptManager
+	subscriptions *SubscriptionManager
 
 	middleware   []Middleware
 	lifespan     LifespanFunc
@@ -28,6 +37,29 @@ type Server struct {
 	progress     *ProgressTracker
 	cancellation *CancellationManager
 	completion   *CompletionManager
+	keepAlive    *keepAlive
+	stats        *statsCollector
+	parentCheck  time.Duration
+
+	maxMessageSize int64 // Incoming message limit for Serve, 0 for none
+	maxResultSize  int64 // Result limit for tools/call and resources/read
+	codec          Codec
+	idempotency    *idempotencyCache // Set by WithIdempotency
+	journal        *callJournal      // Set by WithJournal
+
+	legacyToolErrors bool                // Report handler errors as JSON-RPC errors
+	experimental     mcp.Experiments     // Advertised experimental capabilities
+	hiddenCaps       map[Capability]bool // Set by WithoutCapabilities
+	omitEmptyCaps    bool                // Set by WithOmitEmptyCapabilities
+	readOnly         bool                // Set by WithReadOnly
+
+	notifyMu         sync.RWMutex
+	notifier         NotificationSender
+	broadcastTargets []BroadcastTarget // Transports reached by NotifyAll
+	broadcastFilters []BroadcastFilter // Set by WithBroadcastFilter
+
+	sessionsMu sync.RWMutex
+	sessions   map[string]*session // Sessions started by Serve, by ID
 }
 
 // Option configures a Server
@@ -40,6 +72,12 @@ func New(name string, opts ...Option) *Server {
 		tools:     NewToolManager(),
 		resources: NewResourceManager(),
 		prompts:   NewPromptManager(),
+
+		subscriptions: NewSubscriptionManager(),
+		stats:         newStatsCollector(),
+		sessions:      make(map[string]*session),
+		parentCheck:   defaultParentCheckInterval,
+		codec:         jsonrpc.DefaultCodec(),
 	}
 
 	for _, opt := range opts {
@@ -56,6 +94,13 @@ func WithVersion(version string) Option {
 	}
 }
 
+// WithTitle sets the human-readable server name sent in serverInfo
+func WithTitle(title string) Option {
+	return func(s *Server) {
+		s.title = title
+	}
+}
+
 // WithInstructions sets server instructions
 func WithInstructions(instructions string) Option {
 	return func(s *Server) {
@@ -63,6 +108,20 @@ func WithInstructions(instructions string) Option {
 	}
 }
 
+// InstructionsFunc returns the instructions for a client at initialize time.
+// ctx carries the session's connection info and, over authenticated
+// transports, its claims.
+type InstructionsFunc func(ctx context.Context, clientInfo mcp.Implementation) string
+
+// WithInstructionsFunc computes instructions per client at initialize time,
+// for instructions that depend on the user, the enabled tools or the
+// protocol version. It takes precedence over WithInstructions.
+func WithInstructionsFunc(fn InstructionsFunc) Option {
+	return func(s *Server) {
+		s.instructFunc = fn
+	}
+}
+
 // WithMiddleware adds middleware to the server
 func WithMiddleware(mw ...Middleware) Option {
 	return func(s *Server) {
@@ -97,15 +156,34 @@ func (s *Server) AddPrompt(handler *PromptHandler) error {
 	return s.prompts.Register(handler)
 }
 
-// Run starts the server with stdio transport
+// Run starts the server with stdio transport. The lifespan function runs
+// first and its cleanup runs once the client closes stdin, ctx is cancelled or
+// the parent process exits, in which case ErrParentExited is returned.
 func (s *Server) Run(ctx context.Context) error {
-	return s.Serve(ctx, NewStdioTransport())
+	return s.serveUntilOrphaned(ctx, NewStdioTransport(), parentWatcher())
 }
 
 // Serve starts the server with a custom transport
 func (s *Server) Serve(ctx context.Context, conn io.ReadWriteCloser) error {
-	reader := jsonrpc.NewMessageReader(conn)
-	writer := jsonrpc.NewMessageWriter(conn)
+	reader := jsonrpc.NewMessageReader(conn, s.readerOptions()...)
+	writer := &connWriter{writer: jsonrpc.NewMessageWriter(conn, jsonrpc.WithCodec(s.codec))}
+	ss := newSession(conn, writer)
+	defer ss.end()
+
+	s.stats.sessions.Add(1)
+	defer s.stats.sessions.Add(-1)
+
+	s.addSession(ss)
+	defer s.removeSession(ss)
+
+	ctx = contextWithSession(ctx, ss)
+	ctx = ContextWithConnInfo(ctx, connInfoFor(conn, ss.id))
+
+	if s.keepAlive != nil {
+		go s.keepAliveLoop(ctx, ss)
+	}
+
+	msgs, readErr := readMessages(reader, ss)
 
 	for {
 		select {
@@ -114,8 +192,15 @@ func (s *Server) Serve(ctx context.Context, conn io.ReadWriteCloser) error {
 		default:
 		}
 
-		msg, err := reader.Read()
-		if err != nil {
+		var msg *mcp.Message
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg = <-msgs:
+		case err := <-readErr:
+			if ss.expired.Load() {
+				return ErrKeepAliveTimeout
+			}
 			if err == io.EOF {
 				return nil
 			}
@@ -131,17 +216,55 @@ func (s *Server) Serve(ctx context.Context, conn io.ReadWriteCloser) error {
 	}
 }
 
+// readMessages reads from the connection in the background. Responses to
+// server-initiated requests are routed as they arrive, so handlers can wait
+// on requests such as roots/list; other messages are delivered in order.
+func readMessages(reader *jsonrpc.MessageReader, ss *session) (<-chan *mcp.Message, <-chan error) {
+	msgs := make(chan *mcp.Message)
+	readErr := make(chan error, 1)
+
+	go func() {
+		for {
+			msg, err := reader.Read()
+			if response, ok := tooLargeResponse(err); ok {
+				// The reader skipped the message, so the session goes on
+				_ = ss.writer.Write(response)
+				continue
+			}
+			if err != nil {
+				readErr <- err
+				return
+			}
+
+			if msg.Method == "" && msg.ID != nil {
+				ss.handleResponse(msg)
+				continue
+			}
+
+			select {
+			case msgs <- msg:
+			case <-ss.done:
+				return
+			}
+		}
+	}()
+
+	return msgs, readErr
+}
+
 type messageHandler func(context.Context, *mcp.Message) *mcp.Message
 
 // getMessageRouter returns the method routing map
 func (s *Server) getMessageRouter() map[string]messageHandler {
 	return map[string]messageHandler{
-		"initialize":                       func(_ context.Context, msg *mcp.Message) *mcp.Message { return s.handleInitialize(msg) },
+		"initialize":                       s.handleInitialize,
 		"tools/list":                       s.handleToolsList,
 		"tools/call":                       s.handleToolsCall,
 		"resources/list":                   func(_ context.Context, msg *mcp.Message) *mcp.Message { return s.handleResourcesList(msg) },
 		"resources/read":                   s.handleResourcesRead,
 		"resources/templates/list":         func(_ context.Context, msg *mcp.Message) *mcp.Message { return s.handleResourceTemplatesList(msg) },
+		"resources/subscribe":              s.handleResourcesSubscribe,
+		"resources/unsubscribe":            s.handleResourcesUnsubscribe,
 		"prompts/list":                     func(_ context.Context, msg *mcp.Message) *mcp.Message { return s.handlePromptsList(msg) },
 		"prompts/get":                      s.handlePromptsGet,
 		"notifications/roots/list_changed": s.handleRootsListChanged,
@@ -155,12 +278,25 @@ func (s *Server) getMessageRouter() map[string]messageHandler {
 // HandleMessage processes an MCP message and returns a response
 func (s *Server) HandleMessage(ctx context.Context, msg *mcp.Message) *mcp.Message {
 	if msg.Method == "" {
+		// Responses are routed elsewhere; anything else is not a valid
+		// request and is answered with the request's ID, or null
+		if msg.Result == nil && msg.Error == nil {
+			return s.errorResponse(msg.ID, mcp.InvalidRequest, "invalid request: missing method")
+		}
 		return nil
 	}
 
 	router := s.getMessageRouter()
-	if handler, ok := router[msg.Method]; ok {
-		return handler(ctx, msg)
+	if handler, ok := router[msg.Method]; ok && !s.methodHidden(msg.Method) {
+		var response *mcp.Message
+		if len(s.middleware) > 0 {
+			response = s.handleWithMiddleware(ctx, msg, handler)
+		} else {
+			response = handler(ctx, msg)
+		}
+		response = s.limitResult(msg.Method, response)
+		s.stats.recordRequest(msg.Method, response != nil && response.Error != nil)
+		return response
 	}
 
 	// Don't send error responses for notifications (messages without ID)
@@ -169,39 +305,96 @@ func (s *Server) HandleMessage(ctx context.Context, msg *mcp.Message) *mcp.Messa
 		return nil
 	}
 
-	return s.errorResponse(msg.ID, mcp.MethodNotFound, "method not found")
+	return s.errorResponseFrom(msg.ID, mcp.NewMethodNotFound(msg.Method))
 }
 
-func (s *Server) handleInitialize(msg *mcp.Message) *mcp.Message {
-	caps := mcp.ServerCapabilities{
-		Tools:     &mcp.ToolsCapability{},
-		Resources: &mcp.ResourcesCapability{},
-		Prompts:   &mcp.PromptsCapability{},
+// handleWithMiddleware runs handler for msg through the configured
+// middleware chain
+func (s *Server) handleWithMiddleware(ctx context.Context, msg *mcp.Message, handler messageHandler) *mcp.Message {
+	final := func(ctx context.Context, _ *Request) (*Response, error) {
+		response := handler(ctx, msg)
+		if response == nil {
+			return nil, nil
+		}
+		return &Response{Result: response.Result, Error: response.Error}, nil
 	}
 
-	// Add completions capability if enabled (2025-03-26)
-	if s.completion != nil {
-		caps.Completions = &mcp.CompletionsCapability{}
+	req := &Request{Method: msg.Method, Params: msg.Params, ID: msg.ID}
+	resp, err := ApplyMiddleware(final, s.middleware)(ctx, req)
+	if msg.ID == nil {
+		// Notifications never get a response
+		return nil
+	}
+	switch {
+	case err != nil:
+		return s.errorResponseFrom(msg.ID, err)
+	case resp == nil:
+		return nil
+	case resp.Error != nil:
+		return &mcp.Message{JSONRPC: "2.0", ID: msg.ID, Error: resp.Error}
+	default:
+		return s.successResponse(msg.ID, resp.Result)
+	}
+}
+
+func (s *Server) handleInitialize(ctx context.Context, msg *mcp.Message) *mcp.Message {
+	caps := s.capabilities(ctx)
+
+	var params struct {
+		ClientInfo   mcp.Implementation     `json:"clientInfo"`
+		Capabilities mcp.ClientCapabilities `json:"capabilities"`
 	}
+	_ = s.codec.Unmarshal(msg.Params, &params)
 
 	result := map[string]interface{}{
-		"protocolVersion": "2025-06-18",
+		"protocolVersion": protocolVersion,
 		"capabilities":    caps,
-		"serverInfo": map[string]string{
-			"name":    s.name,
-			"version": s.version,
+		"serverInfo": mcp.Implementation{
+			Name:    s.name,
+			Title:   s.title,
+			Version: s.version,
 		},
 	}
 
+	if ss := sessionFromContext(ctx); ss != nil {
+		ss.version.Store(protocolVersion)
+		ss.clientCaps.Store(&params.Capabilities)
+	}
+
+	instructions := s.instructions
+	if s.instructFunc != nil {
+		instructions = s.instructFunc(ctx, params.ClientInfo)
+	}
+	if instructions != "" {
+		result["instructions"] = instructions
+	}
+
 	return s.successResponse(msg.ID, result)
 }
 
-func (s *Server) handleToolsList(ctx context.Context, msg *mcp.Message) *mcp.Message {
-	tools, _ := s.tools.List(ctx)
-	result := map[string]interface{}{
-		"tools": tools,
+func (s *Server) handleToolsList(_ context.Context, msg *mcp.Message) *mcp.Message {
+	tools, err := s.tools.listEncoded(s.codec.Marshal)
+	if err != nil {
+		return s.errorResponse(msg.ID, mcp.InternalError, err.Error())
 	}
-	return s.successResponse(msg.ID, result)
+
+	// The listings are already valid JSON, so the result is assembled
+	// directly rather than re-encoded
+	size := len(`{"tools":[]}`) + len(tools)
+	for _, tool := range tools {
+		size += len(tool)
+	}
+	result := make([]byte, 0, size)
+	result = append(result, `{"tools":[`...)
+	for i, tool := range tools {
+		if i > 0 {
+			result = append(result, ',')
+		}
+		result = append(result, tool...)
+	}
+	result = append(result, "]}"...)
+
+	return &mcp.Message{JSONRPC: "2.0", ID: msg.ID, Result: result}
 }
 
 // convertToContent converts various result types to MCP Content
@@ -240,6 +433,11 @@ func convertToContent(result interface{}) ([]mcp.Content, error) {
 			mcp.TextContent{Type: "text", Text: string(v)},
 		}, nil
 	default:
+		// Slices of concrete content types, such as []mcp.ResourceContent
+		if contents, ok := contentSlice(result); ok {
+			return contents, nil
+		}
+
 		// For other types, marshal to JSON for better representation
 		jsonBytes, err := json.Marshal(result)
 		if err != nil {
@@ -254,33 +452,119 @@ func convertToContent(result interface{}) ([]mcp.Content, error) {
 	}
 }
 
-func (s *Server) handleToolsCall(ctx context.Context, msg *mcp.Message) *mcp.Message {
-	var params struct {
-		Name      string          `json:"name"`
-		Arguments json.RawMessage `json:"arguments"`
+var contentType = reflect.TypeOf((*mcp.Content)(nil)).Elem()
+
+// contentSlice converts a slice whose elements are content blocks
+func contentSlice(result interface{}) ([]mcp.Content, bool) {
+	v := reflect.ValueOf(result)
+	if v.Kind() != reflect.Slice || !v.Type().Elem().Implements(contentType) {
+		return nil, false
 	}
 
-	if err := json.Unmarshal(msg.Params, &params); err != nil {
+	contents := make([]mcp.Content, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		if c, ok := v.Index(i).Interface().(mcp.Content); ok && c != nil {
+			contents = append(contents, c)
+		}
+	}
+	return contents, true
+}
+
+func (s *Server) handleToolsCall(ctx context.Context, msg *mcp.Message) *mcp.Message {
+	var params mcp.CallToolRequest
+	if err := s.codec.Unmarshal(msg.Params, &params); err != nil {
 		return s.errorResponse(msg.ID, mcp.InvalidParams, "invalid parameters")
 	}
+	if err := s.readOnlyViolation(params.Name); err != nil {
+		return s.errorResponseFrom(msg.ID, err)
+	}
+	ctx = context.WithValue(ctx, toolCallContextKey, &params)
+
+	call := func() *mcp.Message {
+		return s.callTool(ctx, msg.ID, &params)
+	}
+	if s.journal != nil {
+		run := call
+		call = func() *mcp.Message {
+			return s.journal.run(ctx, msg.ID, &params, run)
+		}
+	}
+
+	key := params.IdempotencyKey()
+	if key != "" && s.journal != nil {
+		if entry, ok := s.journal.orphanedCall(ctx, key); ok {
+			return orphanedResponse(msg.ID, entry)
+		}
+	}
+	if key != "" && s.idempotency != nil {
+		return s.idempotency.do(ctx, msg.ID, key, &params, call)
+	}
+	return call()
+}
 
+// callTool runs a tools/call request and builds its response
+func (s *Server) callTool(ctx context.Context, id interface{}, params *mcp.CallToolRequest) *mcp.Message {
+	start := time.Now()
 	result, err := s.tools.Call(ctx, params.Name, params.Arguments)
+	if _, notFound := err.(*mcp.NotFoundError); !notFound {
+		s.stats.recordToolCall(params.Name, time.Since(start), err != nil)
+	}
+	deprecation := s.tools.deprecation(params.Name)
+	if deprecation != "" {
+		s.warnDeprecated("tool", params.Name, deprecation)
+	}
 	if err != nil {
-		return s.errorResponse(msg.ID, mcp.InternalError, err.Error())
+		return s.toolErrorResponse(ctx, id, err)
+	}
+
+	// Handlers that build the full result control isError, structured
+	// content and _meta themselves
+	if full, ok := result.(*mcp.CallToolResult); ok && full != nil {
+		if full.Content == nil {
+			full.Content = []mcp.Content{}
+		}
+		full.Meta = withDeprecation(full.Meta, deprecation)
+		return s.successResponse(id, full)
 	}
 
 	content, err := convertToContent(result)
 	if err != nil {
-		return s.errorResponse(msg.ID, mcp.InternalError, fmt.Sprintf("failed to convert result: %v", err))
+		return s.errorResponse(id, mcp.InternalError, fmt.Sprintf("failed to convert result: %v", err))
 	}
 
-	return s.successResponse(msg.ID, map[string]interface{}{
+	response := map[string]interface{}{
 		"content": content,
-	})
+	}
+	if deprecation != "" {
+		response["_meta"] = withDeprecation(nil, deprecation)
+	}
+	return s.successResponse(id, response)
 }
 
 func (s *Server) handleResourcesList(msg *mcp.Message) *mcp.Message {
-	resources := s.resources.List()
+	var params struct {
+		Filter *mcp.ResourceFilter `json:"filter"`
+		Meta   struct {
+			Filter *mcp.ResourceFilter `json:"filter"`
+		} `json:"_meta"`
+	}
+	if len(msg.Params) > 0 {
+		if err := s.codec.Unmarshal(msg.Params, &params); err != nil {
+			return s.errorResponse(msg.ID, mcp.InvalidParams, "invalid parameters")
+		}
+	}
+
+	// The filter may be sent as a parameter or, for clients that cannot add
+	// parameters, in _meta
+	var filter mcp.ResourceFilter
+	switch {
+	case params.Filter != nil:
+		filter = *params.Filter
+	case params.Meta.Filter != nil:
+		filter = *params.Meta.Filter
+	}
+
+	resources := s.resources.ListFiltered(filter)
 	result := map[string]interface{}{
 		"resources": resources,
 	}
@@ -292,27 +576,22 @@ func (s *Server) handleResourcesRead(ctx context.Context, msg *mcp.Message) *mcp
 		URI string `json:"uri"`
 	}
 
-	if err := json.Unmarshal(msg.Params, &params); err != nil {
+	if err := s.codec.Unmarshal(msg.Params, &params); err != nil {
 		return s.errorResponse(msg.ID, mcp.InvalidParams, "invalid parameters")
 	}
 
 	resource, err := s.resources.ReadWithMetadata(ctx, params.URI)
 	if err != nil {
-		return s.errorResponse(msg.ID, mcp.InternalError, err.Error())
+		return s.errorResponseFrom(msg.ID, mapError(ctx, err))
 	}
 
-	// Build resource content based on MIME type
-	content := map[string]interface{}{
-		"uri":      resource.URI,
-		"mimeType": resource.MimeType,
+	var content mcp.ResourceContents
+	if resource.Binary {
+		content = mcp.NewBlobResourceContents(resource.URI, resource.MimeType, resource.Data)
+	} else {
+		content = mcp.TextResourceContents{URI: resource.URI, MimeType: resource.MimeType, Text: string(resource.Data)}
 	}
-
-	// For text-based MIME types, include as text
-	// For binary types, we'd need to base64 encode (future enhancement)
-	// For now, always include as text for backward compatibility
-	content["text"] = string(resource.Data)
-
-	contents := []map[string]interface{}{content}
+	contents := []mcp.ResourceContents{content}
 
 	return s.successResponse(msg.ID, map[string]interface{}{
 		"contents": contents,
@@ -341,18 +620,26 @@ func (s *Server) handlePromptsGet(ctx context.Context, msg *mcp.Message) *mcp.Me
 		Arguments map[string]interface{} `json:"arguments"`
 	}
 
-	if err := json.Unmarshal(msg.Params, &params); err != nil {
+	if err := s.codec.Unmarshal(msg.Params, &params); err != nil {
 		return s.errorResponse(msg.ID, mcp.InvalidParams, "invalid parameters")
 	}
 
 	messages, err := s.prompts.Get(ctx, params.Name, params.Arguments)
+	deprecation := s.prompts.deprecation(params.Name)
+	if deprecation != "" {
+		s.warnDeprecated("prompt", params.Name, deprecation)
+	}
 	if err != nil {
-		return s.errorResponse(msg.ID, mcp.InternalError, err.Error())
+		return s.errorResponseFrom(msg.ID, mapError(ctx, err))
 	}
 
-	return s.successResponse(msg.ID, map[string]interface{}{
+	response := map[string]interface{}{
 		"messages": messages,
-	})
+	}
+	if deprecation != "" {
+		response["_meta"] = withDeprecation(nil, deprecation)
+	}
+	return s.successResponse(msg.ID, response)
 }
 
 func (s *Server) handleRootsListChanged(ctx context.Context, _ *mcp.Message) *mcp.Message {
@@ -365,12 +652,12 @@ func (s *Server) handleRootsListChanged(ctx context.Context, _ *mcp.Message) *mc
 
 func (s *Server) handleLoggingSetLevel(ctx context.Context, msg *mcp.Message) *mcp.Message {
 	var params mcp.SetLevelRequest
-	if err := json.Unmarshal(msg.Params, &params); err != nil {
+	if err := s.codec.Unmarshal(msg.Params, &params); err != nil {
 		return s.errorResponse(msg.ID, mcp.InvalidParams, "invalid parameters")
 	}
 
 	if err := s.SetLogLevel(ctx, params.Level); err != nil {
-		return s.errorResponse(msg.ID, mcp.InternalError, err.Error())
+		return s.errorResponseFrom(msg.ID, err)
 	}
 
 	return s.successResponse(msg.ID, map[string]interface{}{})
@@ -380,7 +667,7 @@ func (s *Server) handleCancelled(_ context.Context, msg *mcp.Message) *mcp.Messa
 	// This is a notification, so no response is expected
 	if s.cancellation != nil {
 		var notification mcp.CancelledNotification
-		if err := json.Unmarshal(msg.Params, &notification); err == nil {
+		if err := s.codec.Unmarshal(msg.Params, &notification); err == nil {
 			s.cancellation.HandleCancellation(&notification)
 		}
 	}
@@ -393,23 +680,28 @@ func (s *Server) handlePing(msg *mcp.Message) *mcp.Message {
 }
 
 func (s *Server) handleCompletionComplete(ctx context.Context, msg *mcp.Message) *mcp.Message {
-	if s.completion == nil {
-		// Return empty completions if not enabled
-		return s.successResponse(msg.ID, map[string]interface{}{
-			"completion": map[string]interface{}{
-				"values": []string{},
-			},
-		})
-	}
-
 	var params mcp.CompleteRequest
-	if err := json.Unmarshal(msg.Params, &params); err != nil {
+	if err := s.codec.Unmarshal(msg.Params, &params); err != nil {
 		return s.errorResponse(msg.ID, mcp.InvalidParams, "invalid parameters")
 	}
 
-	values, err := s.completion.GetCompletion(ctx, params.Ref, params.Argument)
+	handler, ok, err := s.completion.handler(params.Ref)
 	if err != nil {
-		return s.errorResponse(msg.ID, mcp.InternalError, err.Error())
+		return s.errorResponseFrom(msg.ID, err)
+	}
+
+	var values []string
+	switch {
+	case ok:
+		values, err = handler(ctx, params.Ref, params.Argument)
+		if err != nil {
+			return s.errorResponseFrom(msg.ID, mapError(ctx, err))
+		}
+	case params.Ref.Type == "ref/resource":
+		// Without a handler, complete from the registered resources
+		values = s.resources.completeURI(resourceRefURI(params.Ref), params.Argument)
+	default:
+		values = []string{}
 	}
 
 	return s.successResponse(msg.ID, map[string]interface{}{
@@ -420,7 +712,7 @@ func (s *Server) handleCompletionComplete(ctx context.Context, msg *mcp.Message)
 }
 
 func (s *Server) successResponse(id interface{}, result interface{}) *mcp.Message {
-	resultJSON, _ := json.Marshal(result)
+	resultJSON, _ := s.codec.Marshal(result)
 	return &mcp.Message{
 		JSONRPC: "2.0",
 		ID:      id,
@@ -428,6 +720,16 @@ func (s *Server) successResponse(id interface{}, result interface{}) *mcp.Messag
 	}
 }
 
+// errorResponseFrom builds an error response with the protocol error code
+// matching err
+func (s *Server) errorResponseFrom(id interface{}, err error) *mcp.Message {
+	return &mcp.Message{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   mcp.ToError(err).RPCError(),
+	}
+}
+
 func (s *Server) errorResponse(id interface{}, code mcp.ErrorCode, message string) *mcp.Message {
 	return &mcp.Message{
 		JSONRPC: "2.0",
@@ -117,6 +117,85 @@ func TestServer_Initialize(t *testing.T) {
 	}
 }
 
+func TestServer_InitializeInstructions(t *testing.T) {
+	srv := New("test-server", WithInstructions("Use the search tool first"))
+
+	msg := &mcp.Message{JSONRPC: "2.0", ID: 1, Method: "initialize"}
+	response := srv.HandleMessage(context.Background(), msg)
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(response.Result, &result); err != nil {
+		t.Fatalf("failed to unmarshal result: %v", err)
+	}
+
+	if result["instructions"] != "Use the search tool first" {
+		t.Errorf("unexpected instructions: %v", result["instructions"])
+	}
+}
+
+func TestServer_InitializeServerInfo(t *testing.T) {
+	srv := New("test-server", WithTitle("Test Server"), WithVersion("2.1.0"), EnableLogging())
+
+	msg := &mcp.Message{JSONRPC: "2.0", ID: 1, Method: "initialize"}
+	response := srv.HandleMessage(context.Background(), msg)
+
+	var result struct {
+		ServerInfo   mcp.Implementation     `json:"serverInfo"`
+		Capabilities mcp.ServerCapabilities `json:"capabilities"`
+	}
+	if err := json.Unmarshal(response.Result, &result); err != nil {
+		t.Fatalf("failed to unmarshal result: %v", err)
+	}
+
+	want := mcp.Implementation{Name: "test-server", Title: "Test Server", Version: "2.1.0"}
+	if result.ServerInfo != want {
+		t.Errorf("unexpected server info: %+v", result.ServerInfo)
+	}
+	if result.Capabilities.Logging == nil {
+		t.Error("expected logging capability when logging is enabled")
+	}
+}
+
+func TestServer_InitializeInstructionsFunc(t *testing.T) {
+	var gotInfo mcp.Implementation
+	srv := New("test-server",
+		WithInstructions("static"),
+		WithInstructionsFunc(func(_ context.Context, info mcp.Implementation) string {
+			gotInfo = info
+			if info.Name == "quiet-client" {
+				return ""
+			}
+			return "Hello " + info.Name
+		}),
+	)
+
+	initialize := func(clientName string) map[string]interface{} {
+		msg := &mcp.Message{
+			JSONRPC: "2.0",
+			ID:      1,
+			Method:  "initialize",
+			Params:  json.RawMessage(`{"protocolVersion":"2025-06-18","clientInfo":{"name":"` + clientName + `","version":"1.2"}}`),
+		}
+		response := srv.HandleMessage(context.Background(), msg)
+
+		var result map[string]interface{}
+		if err := json.Unmarshal(response.Result, &result); err != nil {
+			t.Fatalf("failed to unmarshal result: %v", err)
+		}
+		return result
+	}
+
+	if got := initialize("ide")["instructions"]; got != "Hello ide" {
+		t.Errorf("unexpected instructions: %v", got)
+	}
+	if gotInfo.Version != "1.2" {
+		t.Errorf("expected client info to reach the callback, got %+v", gotInfo)
+	}
+	if got, ok := initialize("quiet-client")["instructions"]; ok {
+		t.Errorf("expected no instructions, got %v", got)
+	}
+}
+
 func TestServer_ToolsList(t *testing.T) {
 	srv := New("test-server")
 	srv.AddTool(&ToolHandler{
@@ -357,6 +436,38 @@ func TestServer_MethodNotFound(t *testing.T) {
 	}
 }
 
+func TestServer_NotFoundErrorCodes(t *testing.T) {
+	srv := New("test-server")
+
+	tests := []struct {
+		method string
+		params string
+		code   mcp.ErrorCode
+	}{
+		{"resources/read", `{"uri":"config://missing"}`, mcp.ResourceNotFound},
+		{"tools/call", `{"name":"missing"}`, mcp.InvalidParams},
+		{"prompts/get", `{"name":"missing"}`, mcp.InvalidParams},
+		{"logging/setLevel", `{"level":"info"}`, mcp.MethodNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			response := srv.HandleMessage(context.Background(), &mcp.Message{
+				JSONRPC: "2.0",
+				ID:      1,
+				Method:  tt.method,
+				Params:  json.RawMessage(tt.params),
+			})
+			if response.Error == nil {
+				t.Fatal("expected an error response")
+			}
+			if response.Error.Code != int(tt.code) {
+				t.Errorf("expected error code %s, got %d", tt.code, response.Error.Code)
+			}
+		})
+	}
+}
+
 func TestServer_InvalidParams(t *testing.T) {
 	srv := New("test-server")
 
@@ -433,3 +544,78 @@ func TestServer_UnknownRequest(t *testing.T) {
 		t.Errorf("expected error code %d, got %d", mcp.MethodNotFound, response.Error.Code)
 	}
 }
+
+func TestServer_IDShapes(t *testing.T) {
+	srv := New("test")
+
+	tests := []struct {
+		name   string
+		input  string
+		wantID string
+		code   int // Expected error code, 0 for success
+	}{
+		{"string", `{"jsonrpc":"2.0","id":"abc-1","method":"ping"}`, `"abc-1"`, 0},
+		{"numeric string", `{"jsonrpc":"2.0","id":"1","method":"ping"}`, `"1"`, 0},
+		{"number", `{"jsonrpc":"2.0","id":1,"method":"ping"}`, `1`, 0},
+		{"large number", `{"jsonrpc":"2.0","id":9007199254740993,"method":"ping"}`, `9007199254740993`, 0},
+		{"unknown method", `{"jsonrpc":"2.0","id":"x","method":"nope"}`, `"x"`, int(mcp.MethodNotFound)},
+		{"missing method", `{"jsonrpc":"2.0","id":5}`, `5`, int(mcp.InvalidRequest)},
+		{"missing method and id", `{"jsonrpc":"2.0"}`, `null`, int(mcp.InvalidRequest)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var msg mcp.Message
+			if err := json.Unmarshal([]byte(tt.input), &msg); err != nil {
+				t.Fatalf("unmarshal failed: %v", err)
+			}
+
+			resp := srv.HandleMessage(context.Background(), &msg)
+			if resp == nil {
+				t.Fatal("expected a response")
+			}
+
+			data, _ := json.Marshal(resp)
+			var raw struct {
+				ID    json.RawMessage `json:"id"`
+				Error *mcp.RPCError   `json:"error"`
+			}
+			if err := json.Unmarshal(data, &raw); err != nil {
+				t.Fatalf("decode failed: %v", err)
+			}
+			if string(raw.ID) != tt.wantID {
+				t.Errorf("expected id %s, got %s", tt.wantID, raw.ID)
+			}
+
+			gotCode := 0
+			if raw.Error != nil {
+				gotCode = raw.Error.Code
+			}
+			if gotCode != tt.code {
+				t.Errorf("expected error code %d, got %d", tt.code, gotCode)
+			}
+		})
+	}
+}
+
+func TestCancellationManager_MatchesDecodedIDs(t *testing.T) {
+	cm := NewCancellationManager()
+
+	var msg mcp.Message
+	_ = json.Unmarshal([]byte(`{"jsonrpc":"2.0","id":42,"method":"tools/call"}`), &msg)
+
+	cancelled := false
+	cm.Register(msg.ID, func() { cancelled = true })
+
+	// Notifications decode requestId separately, as float64
+	var notification mcp.CancelledNotification
+	_ = json.Unmarshal([]byte(`{"requestId":42}`), &notification)
+	cm.HandleCancellation(&notification)
+
+	if !cancelled {
+		t.Error("expected request to be cancelled")
+	}
+	if cm.Cancel("42", "") {
+		t.Error("string ID must not match a numeric ID")
+	}
+}
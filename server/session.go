@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// ErrSessionClosed is returned for server requests whose session closed
+// before the client responded
+var ErrSessionClosed = errors.New("session closed")
+
+// session is a single client connection handled by Serve
+type session struct {
+	id     string
+	conn   io.ReadWriteCloser
+	writer *connWriter
+
+	nextID  atomic.Int64
+	mu      sync.Mutex
+	pending map[string]chan *mcp.Message // Server-initiated requests by ID
+
+	closeOnce sync.Once
+	done      chan struct{}
+	expired   atomic.Bool // Closed by the server after missed keepalive pings
+
+	version    atomic.Value                           // Protocol version negotiated during initialize
+	clientCaps atomic.Pointer[mcp.ClientCapabilities] // Declared by the client during initialize
+}
+
+// protocolVersion returns the negotiated protocol version, or "" before
+// initialize
+func (ss *session) protocolVersion() string {
+	v, _ := ss.version.Load().(string)
+	return v
+}
+
+func newSession(conn io.ReadWriteCloser, writer *connWriter) *session {
+	return &session{
+		id:      newSessionID(),
+		conn:    conn,
+		writer:  writer,
+		pending: make(map[string]chan *mcp.Message),
+		done:    make(chan struct{}),
+	}
+}
+
+// newSessionID generates a random session ID
+func newSessionID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// request sends a server-to-client request and waits for the response
+func (ss *session) request(ctx context.Context, method string, params, result interface{}) error {
+	id := fmt.Sprintf("srv-%d", ss.nextID.Add(1))
+
+	msg := &mcp.Message{
+		JSONRPC: "2.0",
+		ID:      id,
+		Method:  method,
+	}
+
+	if params != nil {
+		paramsJSON, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		msg.Params = paramsJSON
+	}
+
+	respChan := make(chan *mcp.Message, 1)
+
+	ss.mu.Lock()
+	ss.pending[id] = respChan
+	ss.mu.Unlock()
+
+	defer func() {
+		ss.mu.Lock()
+		delete(ss.pending, id)
+		ss.mu.Unlock()
+	}()
+
+	if err := ss.writer.Write(msg); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-ss.done:
+		return ErrSessionClosed
+	case resp := <-respChan:
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result != nil && len(resp.Result) > 0 {
+			return json.Unmarshal(resp.Result, result)
+		}
+		return nil
+	}
+}
+
+// handleResponse routes a client response to the waiting request. It
+// reports whether msg answered a server-initiated request.
+func (ss *session) handleResponse(msg *mcp.Message) bool {
+	id, ok := msg.ID.(string)
+	if !ok {
+		return false
+	}
+
+	ss.mu.Lock()
+	respChan, exists := ss.pending[id]
+	ss.mu.Unlock()
+
+	if exists {
+		respChan <- msg
+	}
+	return exists
+}
+
+// end stops the session's background work and fails pending requests
+func (ss *session) end() {
+	ss.closeOnce.Do(func() {
+		close(ss.done)
+	})
+}
+
+// expire ends the session and closes its connection
+func (ss *session) expire() error {
+	ss.expired.Store(true)
+	ss.end()
+	return ss.conn.Close()
+}
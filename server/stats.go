@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// StatsResourceURI is the URI of the resource registered by WithStatsResource
+const StatsResourceURI = "mcp://stats"
+
+// Stats is a snapshot of server runtime counters
+type Stats struct {
+	StartedAt      time.Time            `json:"startedAt"`
+	Uptime         time.Duration        `json:"uptimeNs"`
+	ActiveSessions int64                `json:"activeSessions"`
+	Requests       map[string]int64     `json:"requests"` // Handled messages by method
+	Errors         map[string]int64     `json:"errors"`   // Error responses by method
+	Tools          map[string]ToolStats `json:"tools"`
+	Deprecated     map[string]int64     `json:"deprecated"` // Uses of deprecated tools and prompts, by "tool:<name>" or "prompt:<name>"
+}
+
+// ToolStats holds call counters and latencies for a single tool
+type ToolStats struct {
+	Calls        int64         `json:"calls"`
+	Errors       int64         `json:"errors"`
+	TotalLatency time.Duration `json:"totalLatencyNs"`
+	MaxLatency   time.Duration `json:"maxLatencyNs"`
+}
+
+// AvgLatency returns the mean call latency
+func (ts ToolStats) AvgLatency() time.Duration {
+	if ts.Calls == 0 {
+		return 0
+	}
+	return ts.TotalLatency / time.Duration(ts.Calls)
+}
+
+// statsCollector accumulates runtime counters
+type statsCollector struct {
+	startedAt time.Time
+	sessions  atomic.Int64
+
+	mu         sync.Mutex
+	requests   map[string]int64
+	errors     map[string]int64
+	tools      map[string]*ToolStats
+	deprecated map[string]int64
+}
+
+func newStatsCollector() *statsCollector {
+	return &statsCollector{
+		startedAt:  time.Now(),
+		requests:   make(map[string]int64),
+		errors:     make(map[string]int64),
+		tools:      make(map[string]*ToolStats),
+		deprecated: make(map[string]int64),
+	}
+}
+
+func (sc *statsCollector) recordRequest(method string, failed bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.requests[method]++
+	if failed {
+		sc.errors[method]++
+	}
+}
+
+func (sc *statsCollector) recordToolCall(name string, latency time.Duration, failed bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	ts, ok := sc.tools[name]
+	if !ok {
+		ts = &ToolStats{}
+		sc.tools[name] = ts
+	}
+
+	ts.Calls++
+	if failed {
+		ts.Errors++
+	}
+	ts.TotalLatency += latency
+	if latency > ts.MaxLatency {
+		ts.MaxLatency = latency
+	}
+}
+
+func (sc *statsCollector) recordDeprecated(key string) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.deprecated[key]++
+}
+
+func (sc *statsCollector) snapshot() Stats {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	stats := Stats{
+		StartedAt:      sc.startedAt,
+		Uptime:         time.Since(sc.startedAt),
+		ActiveSessions: sc.sessions.Load(),
+		Requests:       make(map[string]int64, len(sc.requests)),
+		Errors:         make(map[string]int64, len(sc.errors)),
+		Tools:          make(map[string]ToolStats, len(sc.tools)),
+		Deprecated:     make(map[string]int64, len(sc.deprecated)),
+	}
+	for method, n := range sc.requests {
+		stats.Requests[method] = n
+	}
+	for method, n := range sc.errors {
+		stats.Errors[method] = n
+	}
+	for name, ts := range sc.tools {
+		stats.Tools[name] = *ts
+	}
+	for key, n := range sc.deprecated {
+		stats.Deprecated[key] = n
+	}
+	return stats
+}
+
+// Stats returns a snapshot of the server's runtime counters
+func (s *Server) Stats() Stats {
+	return s.stats.snapshot()
+}
+
+// WithStatsResource exposes Stats as a JSON resource at mcp://stats
+func WithStatsResource() Option {
+	return func(s *Server) {
+		_ = s.resources.Register(&ResourceHandler{
+			URI:         StatsResourceURI,
+			Name:        "Server statistics",
+			Description: "Runtime counters for requests, tool calls, errors and sessions",
+			MimeType:    "application/json",
+			Reader: func(_ context.Context) ([]byte, error) {
+				return json.Marshal(s.Stats())
+			},
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func callTool(s *Server, name string) *mcp.Message {
+	return s.HandleMessage(context.Background(), &mcp.Message{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"` + name + `","arguments":{}}`),
+	})
+}
+
+func TestServer_Stats(t *testing.T) {
+	srv := New("test")
+	_ = srv.AddTool(&ToolHandler{
+		Name:    "ok",
+		Handler: func(context.Context, json.RawMessage) (interface{}, error) { return "done", nil },
+	})
+	_ = srv.AddTool(&ToolHandler{
+		Name:    "fail",
+		Handler: func(context.Context, json.RawMessage) (interface{}, error) { return nil, errors.New("boom") },
+	})
+
+	callTool(srv, "ok")
+	callTool(srv, "ok")
+	callTool(srv, "fail")
+	callTool(srv, "missing")
+	srv.HandleMessage(context.Background(), &mcp.Message{JSONRPC: "2.0", ID: 2, Method: "ping"})
+
+	stats := srv.Stats()
+
+	if stats.Requests["tools/call"] != 4 {
+		t.Errorf("expected 4 tools/call requests, got %d", stats.Requests["tools/call"])
+	}
+	if stats.Requests["ping"] != 1 {
+		t.Errorf("expected 1 ping request, got %d", stats.Requests["ping"])
+	}
+	// Handler failures are results with isError set and only count against
+	// the tool; the unknown tool is a protocol error
+	if stats.Errors["tools/call"] != 1 {
+		t.Errorf("expected 1 tools/call error, got %d", stats.Errors["tools/call"])
+	}
+
+	if ok := stats.Tools["ok"]; ok.Calls != 2 || ok.Errors != 0 {
+		t.Errorf("unexpected stats for ok: %+v", ok)
+	}
+	if fail := stats.Tools["fail"]; fail.Calls != 1 || fail.Errors != 1 {
+		t.Errorf("unexpected stats for fail: %+v", fail)
+	}
+	if _, exists := stats.Tools["missing"]; exists {
+		t.Error("expected unknown tools not to be tracked")
+	}
+	if stats.ActiveSessions != 0 {
+		t.Errorf("expected no active sessions, got %d", stats.ActiveSessions)
+	}
+}
+
+func TestServer_StatsResource(t *testing.T) {
+	srv := New("test", WithStatsResource())
+	srv.HandleMessage(context.Background(), &mcp.Message{JSONRPC: "2.0", ID: 1, Method: "ping"})
+
+	data, err := srv.resources.Read(context.Background(), StatsResourceURI)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+
+	var stats Stats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		t.Fatalf("invalid stats JSON: %v", err)
+	}
+	if stats.Requests["ping"] != 1 {
+		t.Errorf("expected ping to be counted, got %v", stats.Requests)
+	}
+}
+
+func TestToolStats_AvgLatency(t *testing.T) {
+	if (ToolStats{}).AvgLatency() != 0 {
+		t.Error("expected zero average without calls")
+	}
+	if got := (ToolStats{Calls: 2, TotalLatency: 10}).AvgLatency(); got != 5 {
+		t.Errorf("expected 5, got %v", got)
+	}
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+// SubscriptionManager tracks the resource URIs each session subscribed to.
+// Subscriptions made without a session, by embedders calling HandleMessage
+// directly, are tracked under the session ID "".
+type SubscriptionManager struct {
+	mu   sync.RWMutex
+	uris map[string]map[string]struct{} // Session IDs by URI
+}
+
+// NewSubscriptionManager creates a new subscription manager
+func NewSubscriptionManager() *SubscriptionManager {
+	return &SubscriptionManager{
+		uris: make(map[string]map[string]struct{}),
+	}
+}
+
+// Subscribe records a subscription to uri by a session
+func (sm *SubscriptionManager) Subscribe(sessionID, uri string) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sessions, ok := sm.uris[uri]
+	if !ok {
+		sessions = make(map[string]struct{})
+		sm.uris[uri] = sessions
+	}
+	sessions[sessionID] = struct{}{}
+}
+
+// Unsubscribe removes a session's subscription to uri
+func (sm *SubscriptionManager) Unsubscribe(sessionID, uri string) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.remove(sessionID, uri)
+}
+
+// RemoveSession removes every subscription of a session
+func (sm *SubscriptionManager) RemoveSession(sessionID string) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	for uri := range sm.uris {
+		sm.remove(sessionID, uri)
+	}
+}
+
+// remove deletes a subscription. The caller must hold sm.mu.
+func (sm *SubscriptionManager) remove(sessionID, uri string) {
+	sessions := sm.uris[uri]
+	delete(sessions, sessionID)
+	if len(sessions) == 0 {
+		delete(sm.uris, uri)
+	}
+}
+
+// IsSubscribed reports whether any session subscribed to uri
+func (sm *SubscriptionManager) IsSubscribed(uri string) bool {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	_, ok := sm.uris[uri]
+	return ok
+}
+
+// Subscribers returns the IDs of the sessions subscribed to uri, sorted
+func (sm *SubscriptionManager) Subscribers(uri string) []string {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	ids := make([]string, 0, len(sm.uris[uri]))
+	for id := range sm.uris[uri] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// NotifyResourceUpdated sends notifications/resources/updated to the
+// sessions subscribed to uri. Unsubscribed URIs are ignored, and sessions
+// that have disconnected lose their subscriptions.
+func (s *Server) NotifyResourceUpdated(uri string) error {
+	params := &mcp.ResourceUpdatedNotification{URI: uri}
+
+	var errs []error
+	for _, id := range s.subscriptions.Subscribers(uri) {
+		err := s.notifySubscriber(id, "notifications/resources/updated", params)
+		if errors.Is(err, ErrUnknownSession) {
+			s.subscriptions.RemoveSession(id)
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// notifySubscriber sends a notification to a subscribed session. Sessions
+// without an ID are reached through the sender installed with
+// SetNotificationSender.
+func (s *Server) notifySubscriber(sessionID, method string, params interface{}) error {
+	if sessionID != "" {
+		return s.NotifySession(sessionID, method, params)
+	}
+
+	s.notifyMu.RLock()
+	sender := s.notifier
+	s.notifyMu.RUnlock()
+	if sender == nil {
+		return nil
+	}
+	return sender(method, params)
+}
+
+// NotifyResourceListChanged sends notifications/resources/list_changed
+func (s *Server) NotifyResourceListChanged() error {
+	return s.sendNotification("notifications/resources/list_changed", nil)
+}
+
+func (s *Server) handleResourcesSubscribe(ctx context.Context, msg *mcp.Message) *mcp.Message {
+	var params struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(msg.Params, &params); err != nil || params.URI == "" {
+		return s.errorResponse(msg.ID, mcp.InvalidParams, "invalid parameters")
+	}
+
+	s.subscriptions.Subscribe(requestSessionID(ctx), params.URI)
+	return s.successResponse(msg.ID, map[string]interface{}{})
+}
+
+func (s *Server) handleResourcesUnsubscribe(ctx context.Context, msg *mcp.Message) *mcp.Message {
+	var params struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(msg.Params, &params); err != nil || params.URI == "" {
+		return s.errorResponse(msg.ID, mcp.InvalidParams, "invalid parameters")
+	}
+
+	s.subscriptions.Unsubscribe(requestSessionID(ctx), params.URI)
+	return s.successResponse(msg.ID, map[string]interface{}{})
+}
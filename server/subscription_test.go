@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+func TestServer_NotifyResourceUpdated(t *testing.T) {
+	srv := New("test")
+	sent := captureNotifications(srv)
+
+	if err := srv.NotifyResourceUpdated("file:///ignored.txt"); err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+	select {
+	case n := <-sent:
+		t.Fatalf("expected no notification for an unsubscribed uri, got %s", n.method)
+	default:
+	}
+
+	subscribe(t, srv, "file:///watched.txt")
+	if err := srv.NotifyResourceUpdated("file:///watched.txt"); err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+
+	select {
+	case n := <-sent:
+		if n.method != "notifications/resources/updated" {
+			t.Errorf("unexpected method: %s", n.method)
+		}
+		if params, ok := n.params.(*mcp.ResourceUpdatedNotification); !ok || params.URI != "file:///watched.txt" {
+			t.Errorf("unexpected params: %v", n.params)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("notification was not sent")
+	}
+}
+
+func TestServer_SubscribeCapability(t *testing.T) {
+	srv := New("test")
+	resp := srv.HandleMessage(context.Background(), &mcp.Message{JSONRPC: "2.0", ID: 1, Method: "initialize"})
+
+	var result struct {
+		Capabilities mcp.ServerCapabilities `json:"capabilities"`
+	}
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatalf("failed to unmarshal result: %v", err)
+	}
+	if result.Capabilities.Resources == nil || !result.Capabilities.Resources.Subscribe {
+		t.Error("expected resources subscribe capability")
+	}
+}
+
+func TestServer_SubscriptionsPerSession(t *testing.T) {
+	srv := New("test")
+	ws := &recordingTarget{ids: []string{"ws-1", "ws-2"}}
+	srv.AddBroadcastTarget(ws)
+
+	request := func(sessionID, method string) {
+		t.Helper()
+		ctx := ContextWithConnInfo(context.Background(), &ConnInfo{SessionID: sessionID})
+		resp := srv.HandleMessage(ctx, &mcp.Message{
+			JSONRPC: "2.0",
+			ID:      1,
+			Method:  method,
+			Params:  []byte(`{"uri":"file:///watched.txt"}`),
+		})
+		if resp.Error != nil {
+			t.Fatalf("%s failed: %s", method, resp.Error.Message)
+		}
+	}
+
+	request("ws-1", "resources/subscribe")
+	request("ws-2", "resources/subscribe")
+	request("ws-2", "resources/unsubscribe")
+
+	if err := srv.NotifyResourceUpdated("file:///watched.txt"); err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+	if sent := ws.messages("ws-1"); len(sent) != 1 {
+		t.Errorf("expected the subscribed session to be notified once, got %v", sent)
+	}
+	if sent := ws.messages("ws-2"); len(sent) != 0 {
+		t.Errorf("expected the unsubscribed session to get nothing, got %v", sent)
+	}
+
+	// Sessions that went away lose their subscriptions
+	ws.ids = []string{"ws-2"}
+	if err := srv.NotifyResourceUpdated("file:///watched.txt"); err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+	if srv.subscriptions.IsSubscribed("file:///watched.txt") {
+		t.Error("expected the departed session's subscription to be dropped")
+	}
+}
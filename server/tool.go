@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 
@@ -27,22 +28,43 @@ type ToolHandler struct {
 	DestructiveHint *bool
 	IdempotentHint  *bool
 	OpenWorldHint   *bool
+	// Custom annotations, listed alongside the hints
+	CustomAnnotations map[string]interface{}
+	Meta              map[string]interface{} // 2025-06-18 _meta
+	// Deprecated marks the tool deprecated, saying what to use instead
+	Deprecated string
+}
+
+// CallToolRequestFromContext returns the tools/call request being handled,
+// including its _meta and progress token
+func CallToolRequestFromContext(ctx context.Context) (*mcp.CallToolRequest, bool) {
+	req, ok := ctx.Value(toolCallContextKey).(*mcp.CallToolRequest)
+	return req, ok
 }
 
 // ToolManager manages tool registration and execution
 type ToolManager struct {
 	tools map[string]*ToolHandler
 	mu    sync.RWMutex
+
+	// Encoded listings and compiled input schemas, computed on first use
+	// since registered handlers don't change
+	cacheMu  sync.Mutex
+	listings map[string]json.RawMessage
+	schemas  map[string]*gojsonschema.Schema
 }
 
 // NewToolManager creates a new tool manager
 func NewToolManager() *ToolManager {
 	return &ToolManager{
-		tools: make(map[string]*ToolHandler),
+		tools:    make(map[string]*ToolHandler),
+		listings: make(map[string]json.RawMessage),
+		schemas:  make(map[string]*gojsonschema.Schema),
 	}
 }
 
-// Register registers a tool
+// Register registers a tool. The handler must not be modified afterwards,
+// as its listing and input schema are encoded once.
 func (tm *ToolManager) Register(handler *ToolHandler) error {
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
@@ -67,49 +89,126 @@ func (tm *ToolManager) Call(ctx context.Context, name string, args json.RawMessa
 
 	// Validate arguments against JSON schema if schema is defined
 	if handler.Schema != nil {
-		if err := tm.validateArguments(args, handler.Schema); err != nil {
+		if err := tm.validateArguments(handler, args); err != nil {
 			return nil, err
 		}
 	}
 
-	return handler.Handler(ctx, args)
+	result, err := handler.Handler(ctx, args)
+	if err != nil {
+		return nil, &toolExecutionError{err: err}
+	}
+	return result, nil
+}
+
+// toolExecutionError marks an error returned by a tool handler, as opposed to
+// an unknown tool or invalid arguments
+type toolExecutionError struct {
+	err error
 }
 
-// validateArguments validates JSON arguments against a JSON schema
-func (tm *ToolManager) validateArguments(args json.RawMessage, schema map[string]interface{}) error {
-	// Convert schema to JSON
-	schemaJSON, err := json.Marshal(schema)
-	if err != nil {
-		return &mcp.ValidationError{Message: fmt.Sprintf("invalid schema: %v", err)}
+func (e *toolExecutionError) Error() string {
+	return e.err.Error()
+}
+
+func (e *toolExecutionError) Unwrap() error {
+	return e.err
+}
+
+// WithLegacyToolErrors reports tool handler errors as JSON-RPC errors instead
+// of results with isError set, for clients written against that behavior
+func WithLegacyToolErrors() Option {
+	return func(s *Server) {
+		s.legacyToolErrors = true
 	}
+}
 
-	// Create schema loader
-	schemaLoader := gojsonschema.NewBytesLoader(schemaJSON)
+// toolErrorResponse answers a tools/call whose handler failed. Per the spec
+// the failure is reported in a result with isError set, so the model can see
+// it; handlers return an *mcp.Error to fail the request itself. With an
+// error mapper, errors it maps fail the request too and the message of any
+// other error is hidden.
+func (s *Server) toolErrorResponse(ctx context.Context, id interface{}, err error) *mcp.Message {
+	var execErr *toolExecutionError
+	if s.legacyToolErrors || !errors.As(err, &execErr) {
+		return s.errorResponseFrom(id, mapError(ctx, err))
+	}
 
-	// Create document loader from arguments
-	documentLoader := gojsonschema.NewBytesLoader(args)
+	message := err.Error()
+	if m, ok := ctx.Value(errorMapperContextKey).(*ErrorMapper); ok {
+		mapped, matched := m.convert(err)
+		if matched {
+			return s.errorResponseFrom(id, mapped)
+		}
+		message = mapped.Message
+	}
 
-	// Validate
-	result, err := gojsonschema.Validate(schemaLoader, documentLoader)
+	var rpcErr *mcp.Error
+	if errors.As(err, &rpcErr) {
+		return s.errorResponseFrom(id, rpcErr)
+	}
+
+	return s.successResponse(id, &mcp.CallToolResult{
+		Content: []mcp.Content{mcp.TextContent{Type: "text", Text: message}},
+		IsError: true,
+	})
+}
+
+// validateArguments validates JSON arguments against the tool's input schema
+func (tm *ToolManager) validateArguments(handler *ToolHandler, args json.RawMessage) error {
+	schema, err := tm.compiledSchema(handler)
+	if err != nil {
+		return err
+	}
+
+	result, err := schema.Validate(gojsonschema.NewBytesLoader(args))
 	if err != nil {
 		return &mcp.ValidationError{Message: fmt.Sprintf("validation error: %v", err)}
 	}
 
 	if !result.Valid() {
-		// Build error message from validation errors
+		// Build error message from validation errors, naming the first
+		// field that violates a constraint
+		errs := result.Errors()
 		errMsg := "invalid arguments: "
-		for i, desc := range result.Errors() {
+		for i, desc := range errs {
 			if i > 0 {
 				errMsg += "; "
 			}
 			errMsg += desc.String()
 		}
-		return &mcp.ValidationError{Message: errMsg}
+
+		field := "arguments"
+		if f := errs[0].Field(); f != gojsonschema.STRING_ROOT_SCHEMA_PROPERTY {
+			field = f
+		}
+		return &mcp.ValidationError{Field: field, Message: errMsg}
 	}
 
 	return nil
 }
 
+// compiledSchema returns the handler's input schema, compiling it on first use
+func (tm *ToolManager) compiledSchema(handler *ToolHandler) (*gojsonschema.Schema, error) {
+	tm.cacheMu.Lock()
+	defer tm.cacheMu.Unlock()
+
+	if schema, ok := tm.schemas[handler.Name]; ok {
+		return schema, nil
+	}
+
+	schemaJSON, err := json.Marshal(handler.Schema)
+	if err != nil {
+		return nil, &mcp.ValidationError{Message: fmt.Sprintf("invalid schema: %v", err)}
+	}
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(schemaJSON))
+	if err != nil {
+		return nil, &mcp.ValidationError{Message: fmt.Sprintf("validation error: %v", err)}
+	}
+	tm.schemas[handler.Name] = schema
+	return schema, nil
+}
+
 // List returns all registered tools
 func (tm *ToolManager) List(_ context.Context) ([]*mcp.Tool, error) {
 	tm.mu.RLock()
@@ -117,18 +216,84 @@ func (tm *ToolManager) List(_ context.Context) ([]*mcp.Tool, error) {
 
 	tools := make([]*mcp.Tool, 0, len(tm.tools))
 	for _, handler := range tm.tools {
-		tools = append(tools, &mcp.Tool{
-			Name:            handler.Name,
-			Description:     handler.Description,
-			InputSchema:     handler.Schema,
-			OutputSchema:    handler.OutputSchema, // 2025-06-18
-			Title:           handler.Title,
-			ReadOnlyHint:    handler.ReadOnlyHint,
-			DestructiveHint: handler.DestructiveHint,
-			IdempotentHint:  handler.IdempotentHint,
-			OpenWorldHint:   handler.OpenWorldHint,
-		})
+		tools = append(tools, handler.tool())
 	}
 
 	return tools, nil
 }
+
+// listEncoded returns the listing of every registered tool encoded with
+// marshal. Each listing is encoded once, so tools/list doesn't re-marshal
+// every schema on every request.
+func (tm *ToolManager) listEncoded(marshal func(interface{}) ([]byte, error)) ([]json.RawMessage, error) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	tm.cacheMu.Lock()
+	defer tm.cacheMu.Unlock()
+
+	tools := make([]json.RawMessage, 0, len(tm.tools))
+	for name, handler := range tm.tools {
+		listing, ok := tm.listings[name]
+		if !ok {
+			var err error
+			if listing, err = marshal(handler.tool()); err != nil {
+				return nil, fmt.Errorf("failed to encode tool %s: %w", name, err)
+			}
+			tm.listings[name] = listing
+		}
+		tools = append(tools, listing)
+	}
+
+	return tools, nil
+}
+
+// tool returns the listing of the handler
+func (h *ToolHandler) tool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:         h.Name,
+		Description:  h.Description,
+		InputSchema:  h.Schema,
+		OutputSchema: h.OutputSchema, // 2025-06-18
+		Title:        h.Title,
+		Annotations:  h.annotations(),
+		Meta:         withDeprecation(h.Meta, h.Deprecated),
+	}
+}
+
+// annotations returns the hints and custom annotations of the handler, or
+// nil if it has none
+func (h *ToolHandler) annotations() *mcp.ToolAnnotations {
+	annotations := &mcp.ToolAnnotations{
+		ReadOnlyHint:    h.ReadOnlyHint,
+		DestructiveHint: h.DestructiveHint,
+		IdempotentHint:  h.IdempotentHint,
+		OpenWorldHint:   h.OpenWorldHint,
+		Custom:          h.CustomAnnotations,
+	}
+	if annotations.IsEmpty() {
+		return nil
+	}
+	return annotations
+}
+
+// readOnly reports whether the named tool has ReadOnlyHint set to true and
+// whether it exists
+func (tm *ToolManager) readOnly(name string) (readOnly, exists bool) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	handler, ok := tm.tools[name]
+	if !ok {
+		return false, false
+	}
+	return handler.ReadOnlyHint != nil && *handler.ReadOnlyHint, true
+}
+
+// deprecation returns the deprecation message of the named tool, or ""
+func (tm *ToolManager) deprecation(name string) string {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	if handler, ok := tm.tools[name]; ok {
+		return handler.Deprecated
+	}
+	return ""
+}
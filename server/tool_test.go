@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/jmcarbo/fullmcp/mcp"
@@ -358,3 +359,105 @@ func TestToolManager_NoSchemaNoValidation(t *testing.T) {
 		t.Errorf("expected 'ok', got %v", result)
 	}
 }
+
+func TestServer_ToolErrorsAsResults(t *testing.T) {
+	failing := func(err error) *ToolHandler {
+		return &ToolHandler{
+			Name:    "fail",
+			Handler: func(context.Context, json.RawMessage) (interface{}, error) { return nil, err },
+		}
+	}
+
+	srv := New("test")
+	_ = srv.AddTool(failing(errors.New("upstream unavailable")))
+
+	resp := callTool(srv, "fail")
+	if resp.Error != nil {
+		t.Fatalf("expected a result, got error %v", resp.Error)
+	}
+	var result mcp.CallToolResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatalf("failed to decode result: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected isError to be set")
+	}
+	if len(result.Content) != 1 || result.Content[0].(mcp.TextContent).Text != "upstream unavailable" {
+		t.Errorf("unexpected content: %+v", result.Content)
+	}
+
+	// Unknown tools are still protocol errors
+	if resp := callTool(srv, "missing"); resp.Error == nil {
+		t.Error("expected a JSON-RPC error for an unknown tool")
+	}
+
+	// Handlers can fail the request itself with an *mcp.Error
+	srv = New("test")
+	_ = srv.AddTool(failing(&mcp.Error{Code: mcp.InvalidParams, Message: "bad region"}))
+	resp = callTool(srv, "fail")
+	if resp.Error == nil || resp.Error.Code != int(mcp.InvalidParams) || resp.Error.Message != "bad region" {
+		t.Errorf("expected InvalidParams error, got %+v", resp.Error)
+	}
+}
+
+func TestServer_LegacyToolErrors(t *testing.T) {
+	srv := New("test", WithLegacyToolErrors())
+	_ = srv.AddTool(&ToolHandler{
+		Name:    "fail",
+		Handler: func(context.Context, json.RawMessage) (interface{}, error) { return nil, errors.New("boom") },
+	})
+
+	resp := callTool(srv, "fail")
+	if resp.Error == nil || resp.Error.Code != int(mcp.InternalError) || resp.Error.Message != "boom" {
+		t.Errorf("expected legacy InternalError, got %+v", resp)
+	}
+}
+
+func TestToolsList_EncodedOnce(t *testing.T) {
+	srv := New("test")
+	add := func(name string) {
+		_ = srv.AddTool(&ToolHandler{
+			Name:   name,
+			Schema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"n": map[string]interface{}{"type": "integer"}}},
+			Handler: func(_ context.Context, _ json.RawMessage) (interface{}, error) {
+				return "ok", nil
+			},
+		})
+	}
+	list := func() []mcp.Tool {
+		resp := srv.HandleMessage(context.Background(), &mcp.Message{JSONRPC: "2.0", ID: 1, Method: "tools/list"})
+		var result struct {
+			Tools []mcp.Tool `json:"tools"`
+		}
+		if err := json.Unmarshal(resp.Result, &result); err != nil {
+			t.Fatalf("invalid tools/list result: %v", err)
+		}
+		return result.Tools
+	}
+
+	add("first")
+	if tools := list(); len(tools) != 1 || tools[0].InputSchema["type"] != "object" {
+		t.Fatalf("unexpected tools: %+v", tools)
+	}
+	if len(srv.tools.listings) != 1 {
+		t.Errorf("expected the listing to be cached, got %d", len(srv.tools.listings))
+	}
+
+	// Tools registered later are listed alongside cached ones
+	add("second")
+	if tools := list(); len(tools) != 2 {
+		t.Fatalf("expected 2 tools, got %+v", tools)
+	}
+
+	// The compiled input schema is reused across calls
+	for i := 0; i < 2; i++ {
+		_, err := srv.tools.Call(context.Background(), "first", json.RawMessage(`{"n":"x"}`))
+		var validationErr *mcp.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("expected validation error, got %v", err)
+		}
+	}
+	if len(srv.tools.schemas) != 1 {
+		t.Errorf("expected one compiled schema, got %d", len(srv.tools.schemas))
+	}
+}
@@ -1,8 +1,11 @@
 package server
 
 import (
+	"context"
 	"io"
 	"os"
+
+	"github.com/jmcarbo/fullmcp/transport"
 )
 
 // StdioTransport implements stdio transport
@@ -31,3 +34,15 @@ func (t *StdioTransport) Write(p []byte) (int, error) {
 func (t *StdioTransport) Close() error {
 	return nil
 }
+
+// ServeTransport opens a connection with t and serves it until the peer
+// disconnects or ctx is cancelled, then closes the transport
+func (s *Server) ServeTransport(ctx context.Context, t transport.Transport) error {
+	defer func() { _ = t.Close() }()
+
+	conn, err := t.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, conn)
+}
@@ -2,7 +2,13 @@ package server
 
 import (
 	"bytes"
+	"context"
+	"io"
 	"testing"
+
+	"github.com/jmcarbo/fullmcp/internal/jsonrpc"
+	"github.com/jmcarbo/fullmcp/internal/testutil"
+	"github.com/jmcarbo/fullmcp/mcp"
 )
 
 func TestStdioTransport_New(t *testing.T) {
@@ -67,3 +73,46 @@ func TestStdioTransport_Close(t *testing.T) {
 		t.Fatalf("close failed: %v", err)
 	}
 }
+
+// pipeTransport hands out one end of an in-memory pipe
+type pipeTransport struct {
+	conn   io.ReadWriteCloser
+	closed bool
+}
+
+func (p *pipeTransport) Connect(context.Context) (io.ReadWriteCloser, error) { return p.conn, nil }
+
+func (p *pipeTransport) Close() error {
+	p.closed = true
+	return nil
+}
+
+func TestServer_ServeTransport(t *testing.T) {
+	srv := New("test")
+	clientSide, serverSide := testutil.NewPipeTransport()
+	tr := &pipeTransport{conn: serverSide}
+
+	done := make(chan error, 1)
+	go func() { done <- srv.ServeTransport(context.Background(), tr) }()
+
+	writer := jsonrpc.NewMessageWriter(clientSide)
+	reader := jsonrpc.NewMessageReader(clientSide)
+	if err := writer.Write(&mcp.Message{JSONRPC: "2.0", ID: 1, Method: "ping"}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	resp, err := reader.Read()
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+
+	_ = clientSide.Close()
+	if err := <-done; err != nil {
+		t.Errorf("expected clean shutdown, got %v", err)
+	}
+	if !tr.closed {
+		t.Error("expected transport to be closed")
+	}
+}
@@ -0,0 +1,162 @@
+package server
+
+import (
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// ResourceWatcher watches the files backing resources and notifies the
+// client when they change. Modified files produce
+// notifications/resources/updated for subscribed URIs; files appearing in or
+// disappearing from a watched directory produce
+// notifications/resources/list_changed.
+type ResourceWatcher struct {
+	server  *Server
+	watcher *fsnotify.Watcher
+
+	mu     sync.RWMutex
+	files  map[string]string   // File path to resource URI
+	dirs   map[string]struct{} // Directories whose entries are listed as resources
+	parent map[string]int      // Watch reference counts per directory
+
+	done chan struct{}
+}
+
+// NewResourceWatcher creates a watcher that reports changes through s
+func NewResourceWatcher(s *Server) (*ResourceWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &ResourceWatcher{
+		server:  s,
+		watcher: watcher,
+		files:   make(map[string]string),
+		dirs:    make(map[string]struct{}),
+		parent:  make(map[string]int),
+		done:    make(chan struct{}),
+	}
+
+	go w.run()
+	return w, nil
+}
+
+// WatchFile associates path with a resource URI. The file's directory is
+// watched rather than the file itself, so editors that save by replacing the
+// file are handled.
+func (w *ResourceWatcher) WatchFile(uri, path string) error {
+	path = filepath.Clean(path)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, exists := w.files[path]; !exists {
+		if err := w.addDir(filepath.Dir(path)); err != nil {
+			return err
+		}
+	}
+	w.files[path] = uri
+	return nil
+}
+
+// WatchDir reports files created, removed or renamed in dir as resource list
+// changes
+func (w *ResourceWatcher) WatchDir(dir string) error {
+	dir = filepath.Clean(dir)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, exists := w.dirs[dir]; exists {
+		return nil
+	}
+	if err := w.addDir(dir); err != nil {
+		return err
+	}
+	w.dirs[dir] = struct{}{}
+	return nil
+}
+
+// Unwatch stops watching a file registered with WatchFile
+func (w *ResourceWatcher) Unwatch(path string) error {
+	path = filepath.Clean(path)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, exists := w.files[path]; !exists {
+		return nil
+	}
+	delete(w.files, path)
+	return w.removeDir(filepath.Dir(path))
+}
+
+// Close stops the watcher
+func (w *ResourceWatcher) Close() error {
+	select {
+	case <-w.done:
+		return nil
+	default:
+		close(w.done)
+	}
+	return w.watcher.Close()
+}
+
+// addDir adds a reference to a watched directory; callers hold mu
+func (w *ResourceWatcher) addDir(dir string) error {
+	if w.parent[dir] == 0 {
+		if err := w.watcher.Add(dir); err != nil {
+			return err
+		}
+	}
+	w.parent[dir]++
+	return nil
+}
+
+// removeDir drops a reference to a watched directory; callers hold mu
+func (w *ResourceWatcher) removeDir(dir string) error {
+	w.parent[dir]--
+	if w.parent[dir] > 0 {
+		return nil
+	}
+	delete(w.parent, dir)
+	return w.watcher.Remove(dir)
+}
+
+func (w *ResourceWatcher) run() {
+	for {
+		select {
+		case <-w.done:
+			return
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			w.handleEvent(event)
+		case _, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+func (w *ResourceWatcher) handleEvent(event fsnotify.Event) {
+	path := filepath.Clean(event.Name)
+
+	w.mu.RLock()
+	uri, watched := w.files[path]
+	_, listed := w.dirs[filepath.Dir(path)]
+	w.mu.RUnlock()
+
+	if watched && event.Op != fsnotify.Chmod {
+		_ = w.server.NotifyResourceUpdated(uri)
+	}
+
+	if listed && event.Op&(fsnotify.Create|fsnotify.Remove|fsnotify.Rename) != 0 {
+		_ = w.server.NotifyResourceListChanged()
+	}
+}
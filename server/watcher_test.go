@@ -0,0 +1,168 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/mcp"
+)
+
+type sentNotification struct {
+	method string
+	params interface{}
+}
+
+// captureNotifications installs a sender that records notifications
+func captureNotifications(s *Server) chan sentNotification {
+	sent := make(chan sentNotification, 100)
+	s.SetNotificationSender(func(method string, params interface{}) error {
+		sent <- sentNotification{method: method, params: params}
+		return nil
+	})
+	return sent
+}
+
+func subscribe(t *testing.T, s *Server, uri string) {
+	t.Helper()
+
+	resp := s.HandleMessage(context.Background(), &mcp.Message{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "resources/subscribe",
+		Params:  []byte(`{"uri":"` + uri + `"}`),
+	})
+	if resp.Error != nil {
+		t.Fatalf("subscribe failed: %v", resp.Error.Message)
+	}
+}
+
+func waitForNotification(t *testing.T, sent chan sentNotification, method string) sentNotification {
+	t.Helper()
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case n := <-sent:
+			if n.method == method {
+				return n
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %s", method)
+		}
+	}
+}
+
+func TestResourceWatcher_FileUpdated(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(path, []byte("v1"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := New("test")
+	sent := captureNotifications(srv)
+	subscribe(t, srv, "file:///notes.txt")
+
+	w, err := NewResourceWatcher(srv)
+	if err != nil {
+		t.Fatalf("failed to create watcher: %v", err)
+	}
+	defer w.Close()
+
+	if err := w.WatchFile("file:///notes.txt", path); err != nil {
+		t.Fatalf("watch failed: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("v2"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	n := waitForNotification(t, sent, "notifications/resources/updated")
+	if params, ok := n.params.(*mcp.ResourceUpdatedNotification); !ok || params.URI != "file:///notes.txt" {
+		t.Errorf("unexpected params: %v", n.params)
+	}
+}
+
+func TestResourceWatcher_UnsubscribedFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(path, []byte("v1"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := New("test")
+	sent := captureNotifications(srv)
+
+	w, err := NewResourceWatcher(srv)
+	if err != nil {
+		t.Fatalf("failed to create watcher: %v", err)
+	}
+	defer w.Close()
+
+	if err := w.WatchFile("file:///notes.txt", path); err != nil {
+		t.Fatalf("watch failed: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("v2"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case n := <-sent:
+		t.Errorf("expected no notification without a subscription, got %s", n.method)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestResourceWatcher_DirListChanged(t *testing.T) {
+	dir := t.TempDir()
+
+	srv := New("test")
+	sent := captureNotifications(srv)
+
+	w, err := NewResourceWatcher(srv)
+	if err != nil {
+		t.Fatalf("failed to create watcher: %v", err)
+	}
+	defer w.Close()
+
+	if err := w.WatchDir(dir); err != nil {
+		t.Fatalf("watch failed: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("hi"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	waitForNotification(t, sent, "notifications/resources/list_changed")
+}
+
+func TestServer_ResourcesUnsubscribe(t *testing.T) {
+	srv := New("test")
+	subscribe(t, srv, "file:///a.txt")
+
+	resp := srv.HandleMessage(context.Background(), &mcp.Message{
+		JSONRPC: "2.0",
+		ID:      2,
+		Method:  "resources/unsubscribe",
+		Params:  []byte(`{"uri":"file:///a.txt"}`),
+	})
+	if resp.Error != nil {
+		t.Fatalf("unsubscribe failed: %v", resp.Error.Message)
+	}
+	if srv.subscriptions.IsSubscribed("file:///a.txt") {
+		t.Error("expected subscription to be removed")
+	}
+
+	resp = srv.HandleMessage(context.Background(), &mcp.Message{
+		JSONRPC: "2.0",
+		ID:      3,
+		Method:  "resources/subscribe",
+		Params:  []byte(`{}`),
+	})
+	if resp.Error == nil || resp.Error.Code != int(mcp.InvalidParams) {
+		t.Errorf("expected InvalidParams for missing uri, got %+v", resp.Error)
+	}
+}
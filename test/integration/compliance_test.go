@@ -4,6 +4,8 @@ package integration
 import (
 	"context"
 	"encoding/json"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -11,6 +13,7 @@ import (
 	"time"
 
 	"github.com/jmcarbo/fullmcp/client"
+	"github.com/jmcarbo/fullmcp/conformance"
 	"github.com/jmcarbo/fullmcp/mcp"
 	httpTransport "github.com/jmcarbo/fullmcp/transport/http"
 	"github.com/jmcarbo/fullmcp/transport/streamhttp"
@@ -521,3 +524,54 @@ func TestNotificationCompliance(t *testing.T) {
 		t.Log("Notification compliance verified")
 	}
 }
+
+// TestConformanceSuite runs the public conformance checks over each transport
+func TestConformanceSuite(t *testing.T) {
+	srv := createTestServer(t)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "failed to read request", http.StatusBadRequest)
+			return
+		}
+		var msg mcp.Message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			http.Error(w, "invalid JSON-RPC message", http.StatusBadRequest)
+			return
+		}
+		response := srv.HandleMessage(r.Context(), &msg)
+		if response == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	})
+
+	t.Run("stdio", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		conformance.RunAll(t, func() (io.ReadWriteCloser, error) {
+			clientConn, serverConn := net.Pipe()
+			go func() { _ = srv.Serve(ctx, serverConn) }()
+			return clientConn, nil
+		})
+	})
+
+	t.Run("http", func(t *testing.T) {
+		httpServer := httptest.NewServer(handler)
+		defer httpServer.Close()
+		conformance.RunAll(t, func() (io.ReadWriteCloser, error) {
+			return httpTransport.New(httpServer.URL).Connect(context.Background())
+		})
+	})
+
+	t.Run("streamhttp", func(t *testing.T) {
+		httpServer := httptest.NewServer(streamhttp.NewServer("", handler))
+		defer httpServer.Close()
+		conformance.RunAll(t, func() (io.ReadWriteCloser, error) {
+			return streamhttp.New(httpServer.URL).Connect(context.Background())
+		})
+	})
+}
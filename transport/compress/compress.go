@@ -0,0 +1,344 @@
+// Package compress negotiates compression for stream transports, such as TCP
+// and Unix sockets, that carry newline-delimited JSON-RPC.
+//
+// Before its first message the client sends an offer line listing the
+// algorithms it accepts, in order of preference:
+//
+//	#compress gzip,deflate
+//
+// A server that supports negotiation answers with the algorithm it picked,
+// or "none", after which both directions of the connection are compressed.
+// JSON-RPC messages never start with '#', so the server tells an offer from
+// an ordinary first message. A server without negotiation support rejects
+// the offer as malformed JSON, so Offer reports ErrNotSupported and the
+// client connects again without one.
+//
+// gzip and deflate are built in; other algorithms, such as zstd, can be
+// added with Register.
+package compress
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Built-in algorithm names
+const (
+	Gzip    = "gzip"
+	Deflate = "deflate"
+	None    = "none"
+)
+
+// offerPrefix starts the offer and answer lines
+const offerPrefix = "#compress "
+
+// ErrNotSupported is returned by Offer when the peer does not negotiate
+// compression. The connection is unusable and must be dialed again without
+// an offer.
+var ErrNotSupported = errors.New("compress: peer does not support compression negotiation")
+
+// Writer compresses data written to it. Flush must push everything written
+// so far to the underlying writer, so the peer can decode each message as
+// soon as it is sent.
+type Writer interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// Algorithm creates the compressing writer and decompressing reader of a
+// compression format
+type Algorithm struct {
+	NewWriter func(w io.Writer) (Writer, error)
+	NewReader func(r io.Reader) (io.ReadCloser, error)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Algorithm{
+		Gzip: {
+			NewWriter: func(w io.Writer) (Writer, error) { return gzip.NewWriter(w), nil },
+			NewReader: func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) },
+		},
+		Deflate: {
+			NewWriter: func(w io.Writer) (Writer, error) { return flate.NewWriter(w, flate.DefaultCompression) },
+			NewReader: func(r io.Reader) (io.ReadCloser, error) { return flate.NewReader(r), nil },
+		},
+	}
+)
+
+// Register makes an algorithm available for negotiation. It panics if the
+// name is already registered or is not a valid token.
+func Register(name string, algorithm Algorithm) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if name == "" || name == None || strings.ContainsAny(name, ", \n") {
+		panic("compress: invalid algorithm name " + fmt.Sprintf("%q", name))
+	}
+	if algorithm.NewWriter == nil || algorithm.NewReader == nil {
+		panic("compress: Register algorithm " + name + " is incomplete")
+	}
+	if _, exists := registry[name]; exists {
+		panic("compress: Register called twice for algorithm " + name)
+	}
+	registry[name] = algorithm
+}
+
+func lookup(name string) (Algorithm, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	algorithm, ok := registry[name]
+	return algorithm, ok
+}
+
+// Offer sends an offer for algorithms on conn and waits for the answer,
+// returning the connection to use and the algorithm chosen, which is None
+// when the peer declined. It returns ErrNotSupported for peers that do not
+// negotiate. Callers should bound the wait, for example with a read deadline
+// on the connection.
+func Offer(conn io.ReadWriteCloser, algorithms ...string) (io.ReadWriteCloser, string, error) {
+	for _, name := range algorithms {
+		if _, ok := lookup(name); !ok {
+			return nil, "", fmt.Errorf("compress: unknown algorithm %q", name)
+		}
+	}
+	if len(algorithms) == 0 {
+		return conn, None, nil
+	}
+
+	if _, err := io.WriteString(conn, offerPrefix+strings.Join(algorithms, ",")+"\n"); err != nil {
+		return nil, "", err
+	}
+
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadString('\n')
+	if errors.Is(err, io.EOF) {
+		// Closed by a server that failed to parse the offer
+		return nil, "", ErrNotSupported
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("compress: no answer to offer: %w", err)
+	}
+
+	chosen, ok := strings.CutPrefix(strings.TrimSpace(line), strings.TrimSpace(offerPrefix))
+	if !ok {
+		// Most likely a parse error answering the offer
+		return nil, "", ErrNotSupported
+	}
+	chosen = strings.TrimSpace(chosen)
+	if chosen == None {
+		return &bufferedConn{Reader: reader, conn: conn}, None, nil
+	}
+	if !contains(algorithms, chosen) {
+		return nil, "", fmt.Errorf("compress: peer chose unoffered algorithm %q", chosen)
+	}
+	return wrap(reader, conn, chosen)
+}
+
+// Accept answers a client's offer on conn, choosing the first offered
+// algorithm that is in algorithms, and returns the connection to use with
+// the algorithm chosen. Connections that start without an offer are returned
+// unchanged with None. Accept blocks until the client sends its first byte.
+func Accept(conn io.ReadWriteCloser, algorithms ...string) (io.ReadWriteCloser, string, error) {
+	reader := bufio.NewReader(conn)
+	first, err := reader.Peek(1)
+	if err != nil || first[0] != '#' {
+		// Let the first read report any error
+		return &bufferedConn{Reader: reader, conn: conn}, None, nil
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, "", err
+	}
+	offered, ok := strings.CutPrefix(strings.TrimSpace(line), strings.TrimSpace(offerPrefix))
+	if !ok {
+		return nil, "", fmt.Errorf("compress: invalid offer %q", strings.TrimSpace(line))
+	}
+
+	chosen := None
+	for _, name := range strings.Split(offered, ",") {
+		name = strings.TrimSpace(name)
+		if _, ok := lookup(name); ok && contains(algorithms, name) {
+			chosen = name
+			break
+		}
+	}
+
+	if _, err := io.WriteString(conn, offerPrefix+chosen+"\n"); err != nil {
+		return nil, "", err
+	}
+	if chosen == None {
+		return &bufferedConn{Reader: reader, conn: conn}, None, nil
+	}
+	return wrap(reader, conn, chosen)
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// wrap compresses both directions of conn, reading through reader so data
+// buffered during negotiation is not lost
+func wrap(reader io.Reader, conn io.ReadWriteCloser, name string) (io.ReadWriteCloser, string, error) {
+	algorithm, _ := lookup(name)
+	writer, err := algorithm.NewWriter(conn)
+	if err != nil {
+		return nil, "", err
+	}
+	return &compressedConn{
+		conn:      conn,
+		raw:       reader,
+		writer:    writer,
+		newReader: algorithm.NewReader,
+	}, name, nil
+}
+
+// bufferedConn reads through the buffer used during negotiation
+type bufferedConn struct {
+	*bufio.Reader
+	conn io.ReadWriteCloser
+}
+
+func (c *bufferedConn) Write(p []byte) (int, error) {
+	return c.conn.Write(p)
+}
+
+func (c *bufferedConn) Close() error {
+	return c.conn.Close()
+}
+
+// LocalAddr returns the local address of the underlying connection, or nil
+func (c *bufferedConn) LocalAddr() net.Addr {
+	return localAddr(c.conn)
+}
+
+// RemoteAddr returns the peer address of the underlying connection, or nil
+func (c *bufferedConn) RemoteAddr() net.Addr {
+	return remoteAddr(c.conn)
+}
+
+// compressedConn compresses writes and decompresses reads. Each Write is
+// flushed, so every JSON-RPC message reaches the peer as it is sent.
+type compressedConn struct {
+	conn io.ReadWriteCloser
+	raw  io.Reader // conn behind the negotiation buffer
+
+	writeMu sync.Mutex
+	writer  Writer
+
+	readMu    sync.Mutex
+	reader    io.ReadCloser // Created on the first read, since it may block on a header
+	newReader func(io.Reader) (io.ReadCloser, error)
+}
+
+func (c *compressedConn) Read(p []byte) (int, error) {
+	c.readMu.Lock()
+	defer c.readMu.Unlock()
+
+	if c.reader == nil {
+		reader, err := c.newReader(c.raw)
+		if err != nil {
+			return 0, err
+		}
+		c.reader = reader
+	}
+	n, err := c.reader.Read(p)
+	if errors.Is(err, io.ErrUnexpectedEOF) && n == 0 {
+		// The peer closed the connection mid-stream
+		err = io.EOF
+	}
+	return n, err
+}
+
+func (c *compressedConn) Write(p []byte) (int, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	n, err := c.writer.Write(p)
+	if err != nil {
+		return n, err
+	}
+	return n, c.writer.Flush()
+}
+
+func (c *compressedConn) Close() error {
+	c.writeMu.Lock()
+	_ = c.writer.Close()
+	c.writeMu.Unlock()
+	return c.conn.Close()
+}
+
+// LocalAddr returns the local address of the underlying connection, or nil
+func (c *compressedConn) LocalAddr() net.Addr {
+	return localAddr(c.conn)
+}
+
+// RemoteAddr returns the peer address of the underlying connection, or nil
+func (c *compressedConn) RemoteAddr() net.Addr {
+	return remoteAddr(c.conn)
+}
+
+// localAddr returns the local address of network connections, or nil
+func localAddr(conn io.ReadWriteCloser) net.Addr {
+	if c, ok := conn.(interface{ LocalAddr() net.Addr }); ok {
+		return c.LocalAddr()
+	}
+	return nil
+}
+
+// remoteAddr returns the peer address of network connections, or nil
+func remoteAddr(conn io.ReadWriteCloser) net.Addr {
+	if c, ok := conn.(interface{ RemoteAddr() net.Addr }); ok {
+		return c.RemoteAddr()
+	}
+	return nil
+}
+
+// DefaultNegotiationTimeout bounds OfferConn when ctx has no deadline
+const DefaultNegotiationTimeout = 10 * time.Second
+
+// OfferConn runs Offer on a freshly dialed network connection, bounded by
+// ctx's deadline or DefaultNegotiationTimeout. The connection is closed if
+// negotiation fails.
+func OfferConn(ctx context.Context, conn net.Conn, algorithms ...string) (io.ReadWriteCloser, string, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(DefaultNegotiationTimeout)
+	}
+	_ = conn.SetDeadline(deadline)
+
+	negotiated, chosen, err := Offer(conn, algorithms...)
+	if err != nil {
+		_ = conn.Close()
+		return nil, "", err
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return negotiated, chosen, nil
+}
+
+// ParseList splits a comma-separated list of algorithms, such as the value
+// of a "compress" URL query parameter
+func ParseList(list string) []string {
+	var names []string
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
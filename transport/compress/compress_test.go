@@ -0,0 +1,131 @@
+package compress
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+// negotiate runs Offer and Accept on the two ends of a pipe
+func negotiate(t *testing.T, offered, accepted []string) (client, server io.ReadWriteCloser, chosen string) {
+	t.Helper()
+	clientSide, serverSide := net.Pipe()
+	t.Cleanup(func() {
+		_ = clientSide.Close()
+		_ = serverSide.Close()
+	})
+
+	type result struct {
+		conn io.ReadWriteCloser
+		err  error
+	}
+	acceptResult := make(chan result, 1)
+	go func() {
+		conn, _, err := Accept(serverSide, accepted...)
+		acceptResult <- result{conn, err}
+	}()
+
+	client, chosen, err := Offer(clientSide, offered...)
+	if err != nil {
+		t.Fatalf("offer failed: %v", err)
+	}
+	r := <-acceptResult
+	if r.err != nil {
+		t.Fatalf("accept failed: %v", r.err)
+	}
+	return client, r.conn, chosen
+}
+
+// exchange sends a line each way and checks it arrives
+func exchange(t *testing.T, client, server io.ReadWriteCloser) {
+	t.Helper()
+	message := `{"jsonrpc":"2.0","id":1,"method":"tools/list","params":{"cursor":"` + strings.Repeat("x", 4096) + `"}}` + "\n"
+
+	go func() { _, _ = client.Write([]byte(message)) }()
+	line, err := bufio.NewReader(server).ReadString('\n')
+	if err != nil || line != message {
+		t.Fatalf("server received %d bytes (err=%v)", len(line), err)
+	}
+
+	go func() { _, _ = server.Write([]byte("pong\n")) }()
+	line, err = bufio.NewReader(client).ReadString('\n')
+	if err != nil || line != "pong\n" {
+		t.Fatalf("client received %q (err=%v)", line, err)
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name     string
+		offered  []string
+		accepted []string
+		want     string
+	}{
+		{"client preference wins", []string{Deflate, Gzip}, []string{Gzip, Deflate}, Deflate},
+		{"common algorithm", []string{Gzip}, []string{Deflate, Gzip}, Gzip},
+		{"nothing in common", []string{Gzip}, []string{Deflate}, None},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server, chosen := negotiate(t, tt.offered, tt.accepted)
+			if chosen != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, chosen)
+			}
+			exchange(t, client, server)
+		})
+	}
+}
+
+func TestAccept_WithoutOffer(t *testing.T) {
+	clientSide, serverSide := net.Pipe()
+	defer func() { _ = clientSide.Close() }()
+
+	go func() { _, _ = clientSide.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}` + "\n")) }()
+	conn, chosen, err := Accept(serverSide, Gzip)
+	if err != nil || chosen != None {
+		t.Fatalf("expected an uncompressed connection, got %s (err=%v)", chosen, err)
+	}
+	line, _ := bufio.NewReader(conn).ReadString('\n')
+	if !strings.Contains(line, `"method":"ping"`) {
+		t.Errorf("expected the first message to be kept, got %q", line)
+	}
+}
+
+func TestOffer_ServerWithoutNegotiation(t *testing.T) {
+	clientSide, serverSide := net.Pipe()
+	defer func() { _ = serverSide.Close() }()
+
+	// A plain server answers the offer line with a parse error
+	go func() {
+		_, _ = bufio.NewReader(serverSide).ReadString('\n')
+		_, _ = serverSide.Write([]byte(`{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"parse error"}}` + "\n"))
+	}()
+	if _, _, err := Offer(clientSide, Gzip); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("expected ErrNotSupported, got %v", err)
+	}
+
+	// or closes the connection
+	clientSide, serverSide = net.Pipe()
+	go func() {
+		_, _ = bufio.NewReader(serverSide).ReadString('\n')
+		_ = serverSide.Close()
+	}()
+	if _, _, err := Offer(clientSide, Gzip); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("expected ErrNotSupported, got %v", err)
+	}
+}
+
+func TestOffer_UnknownAlgorithm(t *testing.T) {
+	clientSide, serverSide := net.Pipe()
+	defer func() {
+		_ = clientSide.Close()
+		_ = serverSide.Close()
+	}()
+
+	if _, _, err := Offer(clientSide, "brotli"); err == nil {
+		t.Error("expected an error for an unregistered algorithm")
+	}
+}
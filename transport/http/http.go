@@ -4,17 +4,29 @@ package http
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"sync"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/internal/httptransport"
+	"github.com/jmcarbo/fullmcp/internal/jsonrpc"
+	"golang.org/x/oauth2"
 )
 
 // Transport implements HTTP transport for MCP
 type Transport struct {
-	url     string
-	client  *http.Client
-	headers map[string]string
+	url        string
+	client     *http.Client
+	headers    map[string]string
+	httpConfig httptransport.Config
+	timeout    time.Duration
+
+	requestFuncs []httptransport.RequestFunc // Applied to every request
 }
 
 // Option configures the HTTP transport
@@ -23,19 +35,27 @@ type Option func(*Transport)
 // New creates a new HTTP transport
 func New(url string, opts ...Option) *Transport {
 	t := &Transport{
-		url:     url,
-		client:  &http.Client{},
-		headers: make(map[string]string),
+		url:        url,
+		headers:    make(map[string]string),
+		httpConfig: httptransport.DefaultConfig(),
 	}
 
 	for _, opt := range opts {
 		opt(t)
 	}
 
+	if t.client == nil {
+		t.client = &http.Client{
+			Transport: httptransport.New(t.httpConfig),
+			Timeout:   t.timeout,
+		}
+	}
+
 	return t
 }
 
-// WithHTTPClient sets a custom HTTP client
+// WithHTTPClient sets a custom HTTP client. Pooling, timeout and proxy
+// options are ignored when a custom client is supplied.
 func WithHTTPClient(client *http.Client) Option {
 	return func(t *Transport) {
 		t.client = client
@@ -58,13 +78,113 @@ func WithAPIKey(apiKey string) Option {
 	}
 }
 
+// WithMaxIdleConns sets the maximum number of idle connections across all hosts
+func WithMaxIdleConns(n int) Option {
+	return func(t *Transport) {
+		t.httpConfig.MaxIdleConns = n
+	}
+}
+
+// WithMaxIdleConnsPerHost sets the maximum number of idle connections per host
+func WithMaxIdleConnsPerHost(n int) Option {
+	return func(t *Transport) {
+		t.httpConfig.MaxIdleConnsPerHost = n
+	}
+}
+
+// WithMaxConnsPerHost limits the total number of connections per host (0 = unlimited)
+func WithMaxConnsPerHost(n int) Option {
+	return func(t *Transport) {
+		t.httpConfig.MaxConnsPerHost = n
+	}
+}
+
+// WithIdleConnTimeout sets how long idle connections stay in the pool
+func WithIdleConnTimeout(d time.Duration) Option {
+	return func(t *Transport) {
+		t.httpConfig.IdleConnTimeout = d
+	}
+}
+
+// WithDialTimeout sets the TCP connect timeout
+func WithDialTimeout(d time.Duration) Option {
+	return func(t *Transport) {
+		t.httpConfig.DialTimeout = d
+	}
+}
+
+// WithTLSHandshakeTimeout sets the TLS handshake timeout
+func WithTLSHandshakeTimeout(d time.Duration) Option {
+	return func(t *Transport) {
+		t.httpConfig.TLSHandshakeTimeout = d
+	}
+}
+
+// WithResponseHeaderTimeout sets how long to wait for response headers after a request is sent
+func WithResponseHeaderTimeout(d time.Duration) Option {
+	return func(t *Transport) {
+		t.httpConfig.ResponseHeaderTimeout = d
+	}
+}
+
+// WithProxy routes requests through the given proxy (nil disables proxying)
+func WithProxy(proxyURL *url.URL) Option {
+	return func(t *Transport) {
+		if proxyURL == nil {
+			t.httpConfig.Proxy = nil
+			return
+		}
+		t.httpConfig.Proxy = http.ProxyURL(proxyURL)
+	}
+}
+
+// WithTLSConfig sets the TLS configuration, for example to present client
+// certificates or trust a private CA
+func WithTLSConfig(config *tls.Config) Option {
+	return func(t *Transport) {
+		t.httpConfig.TLSClientConfig = config
+	}
+}
+
+// WithTimeout limits the total time of each request, including reading the
+// response (0 = no limit)
+func WithTimeout(d time.Duration) Option {
+	return func(t *Transport) {
+		t.timeout = d
+	}
+}
+
+// WithUserAgent sets the User-Agent header
+func WithUserAgent(userAgent string) Option {
+	return func(t *Transport) {
+		t.headers["User-Agent"] = userAgent
+	}
+}
+
+// WithBearerToken sets the Authorization header on every request from a token
+// source, which refreshes expiring tokens
+func WithBearerToken(ts oauth2.TokenSource) Option {
+	return func(t *Transport) {
+		t.requestFuncs = append(t.requestFuncs, httptransport.BearerToken(ts))
+	}
+}
+
+// WithHeaderFunc registers a function called on every outgoing request, for
+// dynamic headers such as trace IDs
+func WithHeaderFunc(fn func(req *http.Request)) Option {
+	return func(t *Transport) {
+		t.requestFuncs = append(t.requestFuncs, httptransport.HeaderFunc(fn))
+	}
+}
+
 // Connect establishes an HTTP connection
 func (t *Transport) Connect(ctx context.Context) (io.ReadWriteCloser, error) {
 	return &httpConn{
-		url:     t.url,
-		client:  t.client,
-		ctx:     ctx,
-		headers: t.headers,
+		url:          t.url,
+		client:       t.client,
+		ctx:          ctx,
+		headers:      t.headers,
+		requestFuncs: t.requestFuncs,
 	}, nil
 }
 
@@ -86,6 +206,8 @@ type httpConn struct {
 	closed    bool
 	sessionID string
 	headers   map[string]string
+
+	requestFuncs []httptransport.RequestFunc
 }
 
 // Read reads from the response buffer, blocking until data is available
@@ -157,6 +279,10 @@ func (c *httpConn) createHTTPRequest(p []byte) (*http.Request, error) {
 		req.Header.Set("mcp-session-id", sessionID)
 	}
 
+	if err := httptransport.Apply(req, c.requestFuncs); err != nil {
+		return nil, err
+	}
+
 	return req, nil
 }
 
@@ -247,14 +373,30 @@ func (s *Server) ListenAndServe() error {
 
 // MCPHandler implements http.Handler for MCP
 type MCPHandler struct {
-	handleFunc func(context.Context, []byte) ([]byte, error)
+	handleFunc  func(context.Context, []byte) ([]byte, error)
+	maxBodySize int64
 }
 
+// HandlerOption configures an MCPHandler
+type HandlerOption func(*MCPHandler)
+
 // NewMCPHandler creates an HTTP handler for MCP
-func NewMCPHandler(handleFunc func(context.Context, []byte) ([]byte, error)) *MCPHandler {
-	return &MCPHandler{
+func NewMCPHandler(handleFunc func(context.Context, []byte) ([]byte, error), opts ...HandlerOption) *MCPHandler {
+	h := &MCPHandler{
 		handleFunc: handleFunc,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// WithMaxBodySize rejects request bodies larger than n bytes with 413
+// Content Too Large and a JSON-RPC MessageTooLarge error
+func WithMaxBodySize(n int64) HandlerOption {
+	return func(h *MCPHandler) {
+		h.maxBodySize = n
+	}
 }
 
 // ServeHTTP implements http.Handler
@@ -277,7 +419,12 @@ func (h *MCPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	body, err := io.ReadAll(r.Body)
+	body, err := jsonrpc.ReadBody(w, r, h.maxBodySize)
+	var tooLarge *jsonrpc.MessageTooLargeError
+	if errors.As(err, &tooLarge) {
+		jsonrpc.WriteTooLarge(w, tooLarge)
+		return
+	}
 	if err != nil {
 		http.Error(w, "failed to read request", http.StatusBadRequest)
 		return
@@ -3,10 +3,15 @@ package http
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
 )
 
 func TestNew(t *testing.T) {
@@ -301,3 +306,140 @@ func TestNewServer(t *testing.T) {
 		t.Error("expected handler to be set")
 	}
 }
+
+func TestNew_WithConnectionTuning(t *testing.T) {
+	proxyURL, _ := url.Parse("http://proxy.internal:3128")
+	transport := New("http://localhost:8080",
+		WithMaxIdleConnsPerHost(4),
+		WithMaxConnsPerHost(8),
+		WithResponseHeaderTimeout(5*time.Second),
+		WithProxy(proxyURL),
+	)
+
+	tr, ok := transport.client.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected *http.Transport, got %T", transport.client.Transport)
+	}
+
+	if tr.MaxIdleConnsPerHost != 4 || tr.MaxConnsPerHost != 8 {
+		t.Errorf("pool limits not applied: %d/%d", tr.MaxIdleConnsPerHost, tr.MaxConnsPerHost)
+	}
+
+	if tr.ResponseHeaderTimeout != 5*time.Second {
+		t.Errorf("expected response header timeout 5s, got %v", tr.ResponseHeaderTimeout)
+	}
+
+	if tr.Proxy == nil {
+		t.Error("expected proxy to be configured")
+	}
+}
+
+func TestNew_WithTLSAndTimeout(t *testing.T) {
+	tlsConfig := &tls.Config{ServerName: "mcp.internal", MinVersion: tls.VersionTLS12}
+	transport := New("https://localhost:8443",
+		WithTLSConfig(tlsConfig),
+		WithTimeout(3*time.Second),
+	)
+
+	tr, ok := transport.client.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected *http.Transport, got %T", transport.client.Transport)
+	}
+
+	if tr.TLSClientConfig != tlsConfig {
+		t.Error("expected TLS config to be applied")
+	}
+
+	if transport.client.Timeout != 3*time.Second {
+		t.Errorf("expected client timeout 3s, got %v", transport.client.Timeout)
+	}
+}
+
+func TestHTTPConn_UserAgent(t *testing.T) {
+	userAgent := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent <- r.Header.Get("User-Agent")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	transport := New(server.URL, WithUserAgent("mcp-test/1.0"))
+	conn, _ := transport.Connect(context.Background())
+	defer func() { _ = conn.Close() }()
+
+	if _, err := conn.Write([]byte(`{}`)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	if got := <-userAgent; got != "mcp-test/1.0" {
+		t.Errorf("expected User-Agent 'mcp-test/1.0', got %q", got)
+	}
+}
+
+func TestHTTPConn_BearerTokenAndHeaderFunc(t *testing.T) {
+	headers := make(chan http.Header, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers <- r.Header.Clone()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	transport := New(server.URL,
+		WithBearerToken(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "secret"})),
+		WithHeaderFunc(func(req *http.Request) {
+			req.Header.Set("X-Trace-Id", "trace-1")
+		}),
+	)
+	conn, _ := transport.Connect(context.Background())
+	defer func() { _ = conn.Close() }()
+
+	if _, err := conn.Write([]byte(`{}`)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	got := <-headers
+	if got.Get("Authorization") != "Bearer secret" {
+		t.Errorf("expected bearer token, got %q", got.Get("Authorization"))
+	}
+	if got.Get("X-Trace-Id") != "trace-1" {
+		t.Errorf("expected trace header, got %q", got.Get("X-Trace-Id"))
+	}
+}
+
+func TestMCPHandler_ServeHTTP_MaxBodySize(t *testing.T) {
+	called := false
+	handler := NewMCPHandler(func(ctx context.Context, data []byte) ([]byte, error) {
+		called = true
+		return data, nil
+	}, WithMaxBodySize(16))
+
+	req := httptest.NewRequest("POST", "/mcp", bytes.NewReader(bytes.Repeat([]byte("x"), 64)))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status 413, got %d", w.Code)
+	}
+	if called {
+		t.Error("expected oversized body not to reach the handler")
+	}
+	if !bytes.Contains(w.Body.Bytes(), []byte(`"code":-32003`)) {
+		t.Errorf("expected a JSON-RPC MessageTooLarge error, got %s", w.Body.String())
+	}
+
+	// Bodies without a Content-Length are limited while reading
+	req = httptest.NewRequest("POST", "/mcp", io.NopCloser(bytes.NewReader(bytes.Repeat([]byte("x"), 64))))
+	req.ContentLength = -1
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status 413 for a streamed body, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("POST", "/mcp", bytes.NewReader([]byte(`{"ok":true}`)))
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !called {
+		t.Errorf("expected small body to be handled, got %d", w.Code)
+	}
+}
@@ -0,0 +1,32 @@
+package http
+
+import (
+	"net/url"
+
+	"github.com/jmcarbo/fullmcp/transport"
+)
+
+var _ transport.Transport = (*Transport)(nil)
+
+func init() {
+	transport.Register("http", newFromURL)
+	transport.Register("https", newFromURL)
+}
+
+// newFromURL creates a transport for an http:// or https:// URL
+func newFromURL(target *url.URL, cfg transport.Config) (transport.Transport, error) {
+	opts := []Option{WithHeaders(cfg.Headers)}
+	if cfg.APIKey != "" {
+		opts = append(opts, WithAPIKey(cfg.APIKey))
+	}
+	if cfg.TokenSource != nil {
+		opts = append(opts, WithBearerToken(cfg.TokenSource))
+	}
+	return New(target.String(), opts...), nil
+}
+
+// SupportsServerPush reports false: responses only arrive in reply to POSTed
+// requests
+func (t *Transport) SupportsServerPush() bool {
+	return false
+}
@@ -0,0 +1,31 @@
+package sse
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/jmcarbo/fullmcp/transport"
+)
+
+var _ transport.Transport = (*Transport)(nil)
+
+func init() {
+	transport.Register("http+sse", newFromURL)
+	transport.Register("https+sse", newFromURL)
+}
+
+// newFromURL creates a transport for an http+sse:// or https+sse:// URL
+func newFromURL(target *url.URL, cfg transport.Config) (transport.Transport, error) {
+	if cfg.HasCredentials() {
+		return nil, fmt.Errorf("sse transport does not support custom headers")
+	}
+	scheme := strings.TrimSuffix(strings.ToLower(target.Scheme), "+sse")
+	return New(transport.BaseURL(target, scheme)), nil
+}
+
+// SupportsServerPush reports true: the event stream carries server-initiated
+// messages
+func (t *Transport) SupportsServerPush() bool {
+	return true
+}
@@ -5,11 +5,14 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"sync"
 	"time"
+
+	"github.com/jmcarbo/fullmcp/internal/jsonrpc"
 )
 
 // Transport implements SSE transport for MCP client
@@ -193,8 +196,20 @@ func (c *sseConn) connect() error {
 
 // Server provides SSE server support for MCP
 type Server struct {
-	handler Handler
-	addr    string
+	handler     Handler
+	addr        string
+	maxBodySize int64
+}
+
+// ServerOption configures the SSE server
+type ServerOption func(*Server)
+
+// WithMaxBodySize rejects POST bodies larger than n bytes with 413 Content
+// Too Large and a JSON-RPC MessageTooLarge error
+func WithMaxBodySize(n int64) ServerOption {
+	return func(s *Server) {
+		s.maxBodySize = n
+	}
 }
 
 // Handler processes MCP requests and streams responses
@@ -203,11 +218,15 @@ type Handler interface {
 }
 
 // NewServer creates a new SSE server for MCP
-func NewServer(addr string, handler Handler) *Server {
-	return &Server{
+func NewServer(addr string, handler Handler, opts ...ServerOption) *Server {
+	s := &Server{
 		addr:    addr,
 		handler: handler,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // ListenAndServe starts the SSE server
@@ -243,7 +262,12 @@ func (s *Server) handleSSE(w http.ResponseWriter, r *http.Request) {
 
 	// For POST requests, read body and process
 	if r.Method == http.MethodPost {
-		body, err := io.ReadAll(r.Body)
+		body, err := jsonrpc.ReadBody(w, r, s.maxBodySize)
+		var tooLarge *jsonrpc.MessageTooLargeError
+		if errors.As(err, &tooLarge) {
+			jsonrpc.WriteTooLarge(w, tooLarge)
+			return
+		}
 		if err != nil {
 			http.Error(w, "failed to read request", http.StatusBadRequest)
 			return
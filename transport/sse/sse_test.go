@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -187,6 +188,27 @@ func TestServer_handleSSE_POST(t *testing.T) {
 	}
 }
 
+func TestServer_handleSSE_MaxBodySize(t *testing.T) {
+	handler := NewMCPSSEHandler(func(ctx context.Context, req []byte) ([]byte, error) {
+		t.Error("expected oversized body not to reach the handler")
+		return nil, nil
+	})
+
+	server := NewServer(":0", handler, WithMaxBodySize(8))
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("x", 64)))
+	w := httptest.NewRecorder()
+
+	server.handleSSE(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status 413, got %d", w.Code)
+	}
+	if w.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Expected a JSON error body, got %s", w.Header().Get("Content-Type"))
+	}
+}
+
 func TestServer_handleSSE_GET(t *testing.T) {
 	handler := NewMCPSSEHandler(func(ctx context.Context, req []byte) ([]byte, error) {
 		return []byte(`{"status":"ok"}`), nil
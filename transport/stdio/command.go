@@ -0,0 +1,174 @@
+package stdio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/transport"
+)
+
+// commandShutdownTimeout is how long Close waits for the server process to
+// exit after its stdin is closed before killing it
+const commandShutdownTimeout = 5 * time.Second
+
+var _ transport.Transport = (*CommandTransport)(nil)
+
+// CommandTransport launches an MCP server as a subprocess and talks to it
+// over the process's stdin and stdout
+type CommandTransport struct {
+	name string
+	args []string
+	env  []string
+	dir  string
+
+	stderr io.Writer
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout io.ReadCloser
+	exited chan struct{} // Closed once the process has exited
+}
+
+// CommandOption configures a CommandTransport
+type CommandOption func(*CommandTransport)
+
+// NewCommand creates a transport that runs name with args when connected
+func NewCommand(name string, args []string, opts ...CommandOption) *CommandTransport {
+	t := &CommandTransport{
+		name:   name,
+		args:   args,
+		stderr: os.Stderr,
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// WithEnv sets additional environment variables ("KEY=value") for the
+// server process
+func WithEnv(env ...string) CommandOption {
+	return func(t *CommandTransport) {
+		t.env = append(t.env, env...)
+	}
+}
+
+// WithDir sets the server process's working directory
+func WithDir(dir string) CommandOption {
+	return func(t *CommandTransport) {
+		t.dir = dir
+	}
+}
+
+// WithStderr sets where the server process's stderr is written (default:
+// os.Stderr)
+func WithStderr(w io.Writer) CommandOption {
+	return func(t *CommandTransport) {
+		t.stderr = w
+	}
+}
+
+// Connect starts the server process
+func (t *CommandTransport) Connect(_ context.Context) (io.ReadWriteCloser, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.cmd != nil {
+		return nil, fmt.Errorf("command %s already started", t.name)
+	}
+
+	cmd := exec.Command(t.name, t.args...) // #nosec G204 -- the command is chosen by the caller
+	cmd.Dir = t.dir
+	cmd.Stderr = t.stderr
+	if len(t.env) > 0 {
+		cmd.Env = append(os.Environ(), t.env...)
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	// An os.Pipe rather than StdoutPipe, so Wait does not close stdout
+	// before the client has read the server's last messages
+	stdout, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stdout = stdoutWriter
+
+	err = cmd.Start()
+	_ = stdoutWriter.Close()
+	if err != nil {
+		_ = stdout.Close()
+		return nil, fmt.Errorf("failed to start %s: %w", t.name, err)
+	}
+
+	t.cmd = cmd
+	t.stdin = stdin
+	t.stdout = stdout
+	t.exited = make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(t.exited)
+	}()
+
+	return &commandConn{transport: t, stdin: stdin, stdout: stdout}, nil
+}
+
+// SupportsServerPush reports true: either side may write at any time
+func (t *CommandTransport) SupportsServerPush() bool {
+	return true
+}
+
+// Close stops the server process: its stdin is closed, which tells it to
+// exit, and it is killed if it is still running after a grace period
+func (t *CommandTransport) Close() error {
+	t.mu.Lock()
+	cmd, stdin, stdout, exited := t.cmd, t.stdin, t.stdout, t.exited
+	t.mu.Unlock()
+
+	if cmd == nil {
+		return nil
+	}
+
+	_ = stdin.Close()
+	defer func() { _ = stdout.Close() }()
+
+	select {
+	case <-exited:
+		return nil
+	case <-time.After(commandShutdownTimeout):
+		_ = cmd.Process.Kill()
+		<-exited
+		return fmt.Errorf("%s did not exit after stdin was closed and was killed", t.name)
+	}
+}
+
+// commandConn reads the server's stdout and writes its stdin
+type commandConn struct {
+	transport *CommandTransport
+	stdin     io.WriteCloser
+	stdout    io.ReadCloser
+}
+
+func (c *commandConn) Read(p []byte) (int, error) {
+	return c.stdout.Read(p)
+}
+
+func (c *commandConn) Write(p []byte) (int, error) {
+	return c.stdin.Write(p)
+}
+
+// Close stops the server process
+func (c *commandConn) Close() error {
+	return c.transport.Close()
+}
@@ -0,0 +1,55 @@
+package stdio
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os/exec"
+	"testing"
+)
+
+func TestCommandTransport_RoundTrip(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat not available")
+	}
+
+	var stderr bytes.Buffer
+	transport := NewCommand("cat", nil, WithStderr(&stderr))
+	conn, err := transport.Connect(context.Background())
+	if err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+
+	if _, err := conn.Write([]byte("{\"jsonrpc\":\"2.0\",\"method\":\"ping\"}\n")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if line != "{\"jsonrpc\":\"2.0\",\"method\":\"ping\"}\n" {
+		t.Errorf("unexpected echo %q", line)
+	}
+
+	if _, err := transport.Connect(context.Background()); err == nil {
+		t.Error("expected second Connect to fail")
+	}
+
+	if err := conn.Close(); err != nil {
+		t.Errorf("close failed: %v", err)
+	}
+	// Closing again, as a client and its transport both may, is harmless
+	if err := transport.Close(); err != nil {
+		t.Errorf("second close failed: %v", err)
+	}
+}
+
+func TestCommandTransport_StartFailure(t *testing.T) {
+	transport := NewCommand("/nonexistent/mcp-server", nil)
+	if _, err := transport.Connect(context.Background()); err == nil {
+		t.Fatal("expected start failure")
+	}
+	if err := transport.Close(); err != nil {
+		t.Errorf("close after failed start: %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package stdio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/jmcarbo/fullmcp/transport"
+)
+
+var _ transport.Transport = (*Transport)(nil)
+
+func init() {
+	transport.Register("stdio", newFromURL)
+}
+
+// newFromURL creates a transport for a stdio URL. "stdio:" uses the
+// process's own stdin and stdout; "stdio:./server --flag" or
+// "stdio:///usr/bin/server" launches the command as a subprocess.
+func newFromURL(target *url.URL, cfg transport.Config) (transport.Transport, error) {
+	if cfg.HasCredentials() {
+		return nil, fmt.Errorf("stdio transport does not support credentials; pass them through the command's environment")
+	}
+	command := target.Opaque
+	if command == "" {
+		command = target.Path
+	}
+	if command == "" {
+		return New(), nil
+	}
+
+	command, err := url.PathUnescape(command)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stdio command %q: %w", command, err)
+	}
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return New(), nil
+	}
+	return NewCommand(fields[0], fields[1:]), nil
+}
+
+// Connect returns the transport itself, which is already connected
+func (t *Transport) Connect(_ context.Context) (io.ReadWriteCloser, error) {
+	return t, nil
+}
+
+// SupportsServerPush reports true: either side may write at any time
+func (t *Transport) SupportsServerPush() bool {
+	return true
+}
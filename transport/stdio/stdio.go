@@ -2,32 +2,110 @@
 package stdio
 
 import (
+	"bufio"
+	"bytes"
 	"io"
 	"os"
+	"sync"
+
+	"github.com/jmcarbo/fullmcp/internal/jsonrpc"
 )
 
 // Transport implements stdio transport
 type Transport struct {
 	stdin  io.Reader
 	stdout io.Writer
+
+	// framing selects how messages are delimited on stdin and stdout.
+	// Content-Length framed messages are converted to and from the
+	// newline-delimited JSON the client and server exchange.
+	framing jsonrpc.Framing
+	frames  *bufio.Reader
+	pending []byte
+
+	writeMu  sync.Mutex
+	writeBuf bytes.Buffer
+}
+
+// Option configures the stdio transport
+type Option func(*Transport)
+
+// WithFraming selects the message framing used on stdin and stdout
+func WithFraming(framing jsonrpc.Framing) Option {
+	return func(t *Transport) {
+		t.framing = framing
+	}
+}
+
+// WithContentLengthFraming frames messages with LSP-style Content-Length
+// headers instead of newlines
+func WithContentLengthFraming() Option {
+	return WithFraming(jsonrpc.FramingContentLength)
 }
 
 // New creates a stdio transport
-func New() *Transport {
-	return &Transport{
+func New(opts ...Option) *Transport {
+	t := &Transport{
 		stdin:  os.Stdin,
 		stdout: os.Stdout,
 	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
 }
 
 // Read implements io.Reader
 func (t *Transport) Read(p []byte) (int, error) {
-	return t.stdin.Read(p)
+	if t.framing != jsonrpc.FramingContentLength {
+		return t.stdin.Read(p)
+	}
+
+	if len(t.pending) == 0 {
+		if t.frames == nil {
+			t.frames = bufio.NewReader(t.stdin)
+		}
+		body, err := jsonrpc.ReadFrame(t.frames)
+		if err != nil {
+			return 0, err
+		}
+		t.pending = append(body, '\n')
+	}
+
+	n := copy(p, t.pending)
+	t.pending = t.pending[n:]
+	return n, nil
 }
 
 // Write implements io.Writer
 func (t *Transport) Write(p []byte) (int, error) {
-	return t.stdout.Write(p)
+	if t.framing != jsonrpc.FramingContentLength {
+		return t.stdout.Write(p)
+	}
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+
+	// Encoded messages never contain raw newlines, so each complete line is
+	// one message
+	t.writeBuf.Write(p)
+	for {
+		i := bytes.IndexByte(t.writeBuf.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+		line := bytes.TrimSpace(t.writeBuf.Next(i + 1))
+		if len(line) == 0 {
+			continue
+		}
+		if err := jsonrpc.WriteFrame(t.stdout, line); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
 }
 
 // Close implements io.Closer
@@ -3,7 +3,10 @@ package stdio
 import (
 	"bytes"
 	"io"
+	"strconv"
 	"testing"
+
+	"github.com/jmcarbo/fullmcp/internal/jsonrpc"
 )
 
 func TestTransport_New(t *testing.T) {
@@ -144,3 +147,56 @@ func TestTransport_MultipleWrites(t *testing.T) {
 		t.Errorf("expected '%s', got '%s'", expected, output.String())
 	}
 }
+
+func TestTransport_ContentLengthRead(t *testing.T) {
+	body := "{\"jsonrpc\":\"2.0\",\n\"method\":\"ping\"}"
+	input := bytes.NewBufferString("Content-Length: " + strconv.Itoa(len(body)) + "\r\n\r\n" + body)
+
+	transport := &Transport{
+		stdin:   input,
+		stdout:  &bytes.Buffer{},
+		framing: jsonrpc.FramingContentLength,
+	}
+
+	reader := jsonrpc.NewMessageReader(transport)
+	msg, err := reader.Read()
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if msg.Method != "ping" {
+		t.Errorf("expected method ping, got %q", msg.Method)
+	}
+
+	if _, err := reader.Read(); err != io.EOF {
+		t.Errorf("expected EOF, got %v", err)
+	}
+}
+
+func TestTransport_ContentLengthWrite(t *testing.T) {
+	var output bytes.Buffer
+
+	transport := New(WithContentLengthFraming())
+	transport.stdout = &output
+
+	// Partial writes are buffered until the message is complete
+	if _, err := transport.Write([]byte(`{"jsonrpc":"2.0",`)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if output.Len() != 0 {
+		t.Fatalf("expected partial message to be buffered, got %q", output.String())
+	}
+	if _, err := transport.Write([]byte("\"method\":\"ping\"}\n{\"jsonrpc\":\"2.0\",\"method\":\"pong\"}\n")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	reader := jsonrpc.NewMessageReader(&output, jsonrpc.WithFraming(jsonrpc.FramingContentLength))
+	for _, want := range []string{"ping", "pong"} {
+		msg, err := reader.Read()
+		if err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		if msg.Method != want {
+			t.Errorf("expected method %q, got %q", want, msg.Method)
+		}
+	}
+}
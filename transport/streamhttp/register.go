@@ -0,0 +1,34 @@
+package streamhttp
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/jmcarbo/fullmcp/transport"
+)
+
+var _ transport.Transport = (*Transport)(nil)
+
+func init() {
+	transport.Register("http+stream", newFromURL)
+	transport.Register("https+stream", newFromURL)
+}
+
+// newFromURL creates a transport for an http+stream:// or https+stream:// URL
+func newFromURL(target *url.URL, cfg transport.Config) (transport.Transport, error) {
+	opts := []Option{WithHeaders(cfg.Headers)}
+	if cfg.APIKey != "" {
+		opts = append(opts, WithAPIKey(cfg.APIKey))
+	}
+	if cfg.TokenSource != nil {
+		opts = append(opts, WithBearerToken(cfg.TokenSource))
+	}
+	scheme := strings.TrimSuffix(strings.ToLower(target.Scheme), "+stream")
+	return New(transport.BaseURL(target, scheme), opts...), nil
+}
+
+// SupportsServerPush reports true: the GET stream carries server-initiated
+// messages
+func (t *Transport) SupportsServerPush() bool {
+	return true
+}
@@ -9,11 +9,22 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/jmcarbo/fullmcp/discovery"
+	"github.com/jmcarbo/fullmcp/internal/cors"
+	"github.com/jmcarbo/fullmcp/internal/httptransport"
+	"github.com/jmcarbo/fullmcp/internal/jsonrpc"
+	"github.com/jmcarbo/fullmcp/server"
+	"golang.org/x/oauth2"
 )
 
 // Transport implements Streamable HTTP transport for MCP
@@ -29,6 +40,9 @@ type Transport struct {
 	eventIDLock sync.Mutex
 	lastEventID string
 	headers     map[string]string
+	httpConfig  httptransport.Config
+
+	requestFuncs []httptransport.RequestFunc // Applied to every request
 }
 
 // Option configures the Streamable HTTP transport
@@ -38,22 +52,28 @@ type Option func(*Transport)
 func New(url string, opts ...Option) *Transport {
 	ctx, cancel := context.WithCancel(context.Background())
 	t := &Transport{
-		url:      url,
-		client:   &http.Client{},
-		ctx:      ctx,
-		cancel:   cancel,
-		headers:  make(map[string]string),
-		sseReady: make(chan struct{}),
+		url:        url,
+		ctx:        ctx,
+		cancel:     cancel,
+		headers:    make(map[string]string),
+		sseReady:   make(chan struct{}),
+		httpConfig: httptransport.DefaultConfig(),
 	}
 
 	for _, opt := range opts {
 		opt(t)
 	}
 
+	// No overall client timeout: the GET stream is long-lived
+	if t.client == nil {
+		t.client = &http.Client{Transport: httptransport.New(t.httpConfig)}
+	}
+
 	return t
 }
 
-// WithHTTPClient sets a custom HTTP client
+// WithHTTPClient sets a custom HTTP client. Pooling, timeout and proxy
+// options are ignored when a custom client is supplied.
 func WithHTTPClient(client *http.Client) Option {
 	return func(t *Transport) {
 		t.client = client
@@ -83,6 +103,82 @@ func WithAPIKey(apiKey string) Option {
 	}
 }
 
+// WithMaxIdleConns sets the maximum number of idle connections across all hosts
+func WithMaxIdleConns(n int) Option {
+	return func(t *Transport) {
+		t.httpConfig.MaxIdleConns = n
+	}
+}
+
+// WithMaxIdleConnsPerHost sets the maximum number of idle connections per host
+func WithMaxIdleConnsPerHost(n int) Option {
+	return func(t *Transport) {
+		t.httpConfig.MaxIdleConnsPerHost = n
+	}
+}
+
+// WithMaxConnsPerHost limits the total number of connections per host (0 = unlimited)
+func WithMaxConnsPerHost(n int) Option {
+	return func(t *Transport) {
+		t.httpConfig.MaxConnsPerHost = n
+	}
+}
+
+// WithIdleConnTimeout sets how long idle connections stay in the pool
+func WithIdleConnTimeout(d time.Duration) Option {
+	return func(t *Transport) {
+		t.httpConfig.IdleConnTimeout = d
+	}
+}
+
+// WithDialTimeout sets the TCP connect timeout
+func WithDialTimeout(d time.Duration) Option {
+	return func(t *Transport) {
+		t.httpConfig.DialTimeout = d
+	}
+}
+
+// WithTLSHandshakeTimeout sets the TLS handshake timeout
+func WithTLSHandshakeTimeout(d time.Duration) Option {
+	return func(t *Transport) {
+		t.httpConfig.TLSHandshakeTimeout = d
+	}
+}
+
+// WithResponseHeaderTimeout sets how long to wait for response headers after a request is sent
+func WithResponseHeaderTimeout(d time.Duration) Option {
+	return func(t *Transport) {
+		t.httpConfig.ResponseHeaderTimeout = d
+	}
+}
+
+// WithProxy routes requests through the given proxy (nil disables proxying)
+func WithProxy(proxyURL *url.URL) Option {
+	return func(t *Transport) {
+		if proxyURL == nil {
+			t.httpConfig.Proxy = nil
+			return
+		}
+		t.httpConfig.Proxy = http.ProxyURL(proxyURL)
+	}
+}
+
+// WithBearerToken sets the Authorization header on every request from a token
+// source, which refreshes expiring tokens
+func WithBearerToken(ts oauth2.TokenSource) Option {
+	return func(t *Transport) {
+		t.requestFuncs = append(t.requestFuncs, httptransport.BearerToken(ts))
+	}
+}
+
+// WithHeaderFunc registers a function called on every outgoing request, for
+// dynamic headers such as trace IDs
+func WithHeaderFunc(fn func(req *http.Request)) Option {
+	return func(t *Transport) {
+		t.requestFuncs = append(t.requestFuncs, httptransport.HeaderFunc(fn))
+	}
+}
+
 // Connect establishes a Streamable HTTP connection
 func (t *Transport) Connect(_ context.Context) (io.ReadWriteCloser, error) {
 	conn := &streamConn{
@@ -149,6 +245,10 @@ func (t *Transport) openSSEStream() (*sseReader, error) {
 	}
 	t.eventIDLock.Unlock()
 
+	if err := httptransport.Apply(req, t.requestFuncs); err != nil {
+		return nil, err
+	}
+
 	resp, err := t.client.Do(req)
 	if err != nil {
 		return nil, err
@@ -186,6 +286,10 @@ func (t *Transport) post(data []byte) ([]byte, error) {
 		req.Header.Set("Mcp-Session-Id", t.sessionID)
 	}
 
+	if err := httptransport.Apply(req, t.requestFuncs); err != nil {
+		return nil, err
+	}
+
 	resp, err := t.client.Do(req)
 	if err != nil {
 		return nil, err
@@ -447,10 +551,15 @@ func (r *sseReader) Close() error {
 
 // Server provides Streamable HTTP server support for MCP
 type Server struct {
-	handler       http.Handler
-	addr          string
-	sessionStore  *SessionStore
-	allowedOrigin string
+	handler      http.Handler
+	addr         string
+	sessionStore *SessionStore
+	origins      cors.Policy
+	queueSize    int
+	overflow     OverflowPolicy
+	stateless    bool
+	maxBodySize  int64
+	discovery    http.Handler // Set by WithDiscovery
 }
 
 // ServerOption configures the Streamable HTTP server
@@ -462,6 +571,8 @@ func NewServer(addr string, handler http.Handler, opts ...ServerOption) *Server
 		addr:         addr,
 		handler:      handler,
 		sessionStore: NewSessionStore(),
+		queueSize:    DefaultEventQueueSize,
+		overflow:     OverflowDropOldest,
 	}
 
 	for _, opt := range opts {
@@ -471,72 +582,89 @@ func NewServer(addr string, handler http.Handler, opts ...ServerOption) *Server
 	return s
 }
 
-// WithAllowedOrigin sets the allowed origin for CORS
+// WithAllowedOrigin adds an allowed origin pattern for CORS. Patterns may
+// contain a wildcard, such as "https://*.example.com".
 func WithAllowedOrigin(origin string) ServerOption {
 	return func(s *Server) {
-		s.allowedOrigin = origin
+		s.origins.Patterns = append(s.origins.Patterns, origin)
 	}
 }
 
-// matchOrigin checks if an origin matches the allowed pattern (supports wildcards)
-func matchOrigin(origin, pattern string) bool {
-	if pattern == "*" {
-		return true
+// WithAllowedOrigins adds several allowed origin patterns for CORS
+func WithAllowedOrigins(patterns ...string) ServerOption {
+	return func(s *Server) {
+		s.origins.Patterns = append(s.origins.Patterns, patterns...)
 	}
+}
 
-	if pattern == origin {
-		return true
+// WithOriginCheck sets a callback that approves origins not matched by the
+// allowed patterns
+func WithOriginCheck(check func(origin string) bool) ServerOption {
+	return func(s *Server) {
+		s.origins.Check = check
 	}
+}
 
-	// Handle wildcard patterns like "*.example.com" or "https://*.example.com"
-	if !hasWildcard(pattern) {
-		return false
+// WithStateless serves plain request/response POSTs without sessions or an
+// SSE stream, for serverless deployments where no state survives between
+// requests. GET requests are rejected with 405 Method Not Allowed.
+func WithStateless() ServerOption {
+	return func(s *Server) {
+		s.stateless = true
 	}
+}
 
-	// Split pattern into prefix and suffix around the wildcard
-	parts := splitWildcard(pattern)
-	if len(parts) != 2 {
-		return false
+// WithEventQueueSize sets how many outbound events are buffered per session
+// while the SSE stream catches up
+func WithEventQueueSize(n int) ServerOption {
+	return func(s *Server) {
+		if n > 0 {
+			s.queueSize = n
+		}
 	}
+}
 
-	prefix, suffix := parts[0], parts[1]
-
-	// Check if origin starts with prefix and ends with suffix
-	if len(origin) < len(prefix)+len(suffix) {
-		return false
+// WithOverflowPolicy sets what happens when a session's event queue is full
+func WithOverflowPolicy(policy OverflowPolicy) ServerOption {
+	return func(s *Server) {
+		s.overflow = policy
 	}
-
-	return hasPrefix(origin, prefix) && hasSuffix(origin, suffix)
 }
 
-// hasWildcard checks if a pattern contains a wildcard
-func hasWildcard(pattern string) bool {
-	for i := 0; i < len(pattern); i++ {
-		if pattern[i] == '*' {
-			return true
-		}
+// WithMaxBodySize rejects POST bodies larger than n bytes with 413 Content
+// Too Large and a JSON-RPC MessageTooLarge error before they reach the
+// handler
+func WithMaxBodySize(n int64) ServerOption {
+	return func(s *Server) {
+		s.maxBodySize = n
 	}
-	return false
 }
 
-// splitWildcard splits a pattern on the first wildcard
-func splitWildcard(pattern string) []string {
-	for i := 0; i < len(pattern); i++ {
-		if pattern[i] == '*' {
-			return []string{pattern[:i], pattern[i+1:]}
+// WithDiscovery serves doc at /.well-known/mcp.json to any origin, so
+// clients and registries can find out how to connect. A doc without
+// endpoints lists the server itself at "/", since it serves every path.
+func WithDiscovery(doc *discovery.Document) ServerOption {
+	return func(s *Server) {
+		published := *doc
+		if len(published.Endpoints) == 0 {
+			published.Endpoints = []discovery.Endpoint{{Transport: discovery.TransportStreamableHTTP, URL: "/"}}
 		}
+		s.discovery = discovery.Handler(&published)
 	}
-	return []string{pattern}
 }
 
-// hasPrefix checks if s starts with prefix
-func hasPrefix(s, prefix string) bool {
-	return len(s) >= len(prefix) && s[:len(prefix)] == prefix
+// matchOrigin checks if an origin matches the allowed pattern (supports wildcards)
+func matchOrigin(origin, pattern string) bool {
+	return cors.Match(origin, pattern)
 }
 
-// hasSuffix checks if s ends with suffix
-func hasSuffix(s, suffix string) bool {
-	return len(s) >= len(suffix) && s[len(s)-len(suffix):] == suffix
+// defaultOrigin returns the origin advertised to requests without an Origin
+// header: the configured origin when exactly one pattern is set
+func (s *Server) defaultOrigin() string {
+	if len(s.origins.Patterns) == 1 {
+		return s.origins.Patterns[0]
+	}
+	return ""
 }
 
 // setCORSHeaders sets CORS headers on the response
@@ -545,16 +673,16 @@ func (s *Server) setCORSHeaders(w http.ResponseWriter, r *http.Request) {
 
 	// Determine allowed origin to return
 	allowedOrigin := "*"
-	if s.allowedOrigin != "" {
-		// If we have a specific pattern and origin provided, check if it matches
-		if origin != "" && matchOrigin(origin, s.allowedOrigin) {
+	if s.origins.Configured() {
+		// If we have a specific policy and origin provided, check if it matches
+		if origin != "" && s.origins.Allowed(origin) {
 			allowedOrigin = origin
 		} else if origin != "" {
 			// Origin provided but doesn't match - don't set CORS headers
 			return
-		} else {
-			// No origin header - use the configured pattern as-is
-			allowedOrigin = s.allowedOrigin
+		} else if allowedOrigin = s.defaultOrigin(); allowedOrigin == "" {
+			// No origin header and no single configured origin to advertise
+			return
 		}
 	}
 
@@ -569,26 +697,33 @@ func (s *Server) ListenAndServe() error {
 
 // ServeHTTP implements http.Handler
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.discovery != nil && r.URL.Path == discovery.Path {
+		s.discovery.ServeHTTP(w, r)
+		return
+	}
+
 	// Validate origin for security
-	if s.allowedOrigin != "" {
-		origin := r.Header.Get("Origin")
-		if origin != "" && !matchOrigin(origin, s.allowedOrigin) {
-			// Set CORS headers even for forbidden origin so browser can see the error
-			w.Header().Set("Access-Control-Allow-Origin", origin)
-			w.Header().Set("Access-Control-Allow-Credentials", "true")
-			http.Error(w, "forbidden origin", http.StatusForbidden)
-			return
-		}
+	if origin := r.Header.Get("Origin"); origin != "" && !s.origins.Allowed(origin) {
+		// Set CORS headers even for forbidden origin so browser can see the error
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+		http.Error(w, "forbidden origin", http.StatusForbidden)
+		return
 	}
 
 	// Handle CORS preflight
 	if r.Method == http.MethodOptions {
 		allowedOrigin := "*"
-		if s.allowedOrigin != "" {
-			allowedOrigin = s.allowedOrigin
+		if s.origins.Configured() {
+			allowedOrigin = r.Header.Get("Origin")
+			if allowedOrigin == "" {
+				allowedOrigin = s.defaultOrigin()
+			}
+		}
+		if allowedOrigin != "" {
+			w.Header().Set("Access-Control-Allow-Origin", allowedOrigin)
 		}
-		w.Header().Set("Access-Control-Allow-Origin", allowedOrigin)
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Methods", s.allowedMethods())
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Mcp-Session-Id, X-API-Key, Authorization, Last-Event-ID")
 		w.Header().Set("Access-Control-Max-Age", "86400")
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
@@ -599,18 +734,49 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Set CORS headers for actual requests
 	s.setCORSHeaders(w, r)
 
-	switch r.Method {
-	case http.MethodPost:
+	switch {
+	case r.Method == http.MethodPost:
 		s.handlePOST(w, r)
-	case http.MethodGet:
+	case r.Method == http.MethodGet && !s.stateless:
 		s.handleGET(w, r)
 	default:
+		w.Header().Set("Allow", s.allowedMethods())
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 	}
 }
 
+// allowedMethods lists the HTTP methods the server accepts
+func (s *Server) allowedMethods() string {
+	if s.stateless {
+		return "POST, OPTIONS"
+	}
+	return "GET, POST, OPTIONS"
+}
+
 // handlePOST handles POST requests (client-to-server messages)
 func (s *Server) handlePOST(w http.ResponseWriter, r *http.Request) {
+	if s.maxBodySize > 0 {
+		body, err := jsonrpc.ReadBody(w, r, s.maxBodySize)
+		var tooLarge *jsonrpc.MessageTooLargeError
+		if errors.As(err, &tooLarge) {
+			jsonrpc.WriteTooLarge(w, tooLarge)
+			return
+		}
+		if err != nil {
+			http.Error(w, "failed to read request", http.StatusBadRequest)
+			return
+		}
+		// The handler reads the body already checked against the limit
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if s.stateless {
+		if s.handler != nil {
+			s.handler.ServeHTTP(w, s.withConnInfo(r, ""))
+		}
+		return
+	}
+
 	sessionID := r.Header.Get("Mcp-Session-Id")
 
 	// Create or get session
@@ -624,10 +790,20 @@ func (s *Server) handlePOST(w http.ResponseWriter, r *http.Request) {
 
 	// Delegate to the wrapped handler (which includes auth and MCP processing)
 	if s.handler != nil {
-		s.handler.ServeHTTP(w, r)
+		s.handler.ServeHTTP(w, s.withConnInfo(r, session.ID))
 	}
 }
 
+// withConnInfo adds the request's connection metadata to its context, so
+// handlers passing r.Context() to HandleMessage see the session, headers
+// and remote address, and don't advertise notifications in stateless mode
+func (s *Server) withConnInfo(r *http.Request, sessionID string) *http.Request {
+	info := server.ConnInfoFromRequest(r)
+	info.SessionID = sessionID
+	info.Stateless = s.stateless
+	return r.WithContext(server.ContextWithConnInfo(r.Context(), info))
+}
+
 // handleGET handles GET requests (server-to-client SSE stream)
 func (s *Server) handleGET(w http.ResponseWriter, r *http.Request) {
 	sessionID := r.Header.Get("Mcp-Session-Id")
@@ -664,11 +840,10 @@ func (s *Server) handleGET(w http.ResponseWriter, r *http.Request) {
 		_ = lastEventID
 	}
 
-	// Stream events from session
-	session.mu.Lock()
-	session.sseWriter = w
-	session.sseFlusher = flusher
-	session.mu.Unlock()
+	// Stream events from the session queue; this goroutine is the only
+	// writer to the response, so slow clients never block senders
+	queue, detached := session.attach(s.queueSize, s.overflow)
+	defer session.detach(detached)
 
 	// Keep connection alive
 	ticker := time.NewTicker(30 * time.Second)
@@ -678,6 +853,14 @@ func (s *Server) handleGET(w http.ResponseWriter, r *http.Request) {
 		select {
 		case <-r.Context().Done():
 			return
+		case <-detached:
+			return
+		case <-session.closed:
+			s.sessionStore.Delete(session.ID)
+			return
+		case event := <-queue:
+			writeEvent(w, event)
+			flusher.Flush()
 		case <-ticker.C:
 			// Send keep-alive comment
 			_, _ = fmt.Fprintf(w, ": keep-alive\n\n")
@@ -686,6 +869,14 @@ func (s *Server) handleGET(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// writeEvent writes a single SSE event
+func writeEvent(w io.Writer, event sseEvent) {
+	if event.id != "" {
+		_, _ = fmt.Fprintf(w, "id: %s\n", event.id)
+	}
+	_, _ = fmt.Fprintf(w, "data: %s\n\n", event.data)
+}
+
 // SessionStore manages sessions
 type SessionStore struct {
 	sessions map[string]*Session
@@ -720,6 +911,7 @@ func (ss *SessionStore) GetOrCreate(id string) *Session {
 	session := &Session{
 		ID:        id,
 		CreatedAt: time.Now(),
+		closed:    make(chan struct{}),
 	}
 
 	if id != "" {
@@ -743,31 +935,208 @@ func (ss *SessionStore) Delete(id string) {
 	delete(ss.sessions, id)
 }
 
+// IDs returns the IDs of the stored sessions, sorted
+func (ss *SessionStore) IDs() []string {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	ids := make([]string, 0, len(ss.sessions))
+	for id := range ss.sessions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// SessionIDs returns the IDs of the sessions with an open SSE stream, which
+// are the ones that can receive server-initiated messages
+func (s *Server) SessionIDs() []string {
+	ids := s.sessionStore.IDs()
+	streaming := ids[:0]
+	for _, id := range ids {
+		if session := s.sessionStore.Get(id); session != nil && session.streaming() {
+			streaming = append(streaming, id)
+		}
+	}
+	return streaming
+}
+
+// Send queues a message, such as a notification, on a session's SSE stream
+func (s *Server) Send(sessionID string, msg []byte) error {
+	session := s.sessionStore.Get(sessionID)
+	if session == nil {
+		return ErrSessionNotFound
+	}
+	return session.SendEvent(msg, "")
+}
+
+// DefaultEventQueueSize is the default number of buffered outbound events per session
+const DefaultEventQueueSize = 256
+
+// OverflowPolicy decides what happens when a session's event queue is full
+type OverflowPolicy int
+
+// Overflow policies
+const (
+	// OverflowDropOldest discards the oldest queued event to make room
+	OverflowDropOldest OverflowPolicy = iota
+	// OverflowBlock blocks the sender until the stream drains the queue or
+	// disconnects
+	OverflowBlock
+	// OverflowCloseSession closes the session so the client reconnects
+	OverflowCloseSession
+)
+
+// Errors returned by Session.SendEvent and Server.Send
+var (
+	ErrNoStream        = errors.New("no SSE connection")
+	ErrSessionClosed   = errors.New("session closed")
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+// sseEvent is a queued server-to-client event
+type sseEvent struct {
+	id   string
+	data []byte
+}
+
 // Session represents a client session
 type Session struct {
-	ID         string
-	CreatedAt  time.Time
-	mu         sync.Mutex
-	sseWriter  http.ResponseWriter
-	sseFlusher http.Flusher
+	ID        string
+	CreatedAt time.Time
+
+	mu        sync.Mutex
+	queue     chan sseEvent  // Outbound events drained by the GET stream
+	policy    OverflowPolicy // Applied when queue is full
+	detached  chan struct{}  // Closed when the current GET stream ends
+	closed    chan struct{}  // Closed when the session is shut down
+	closeOnce sync.Once
+	dropped   atomic.Uint64
 }
 
-// SendEvent sends an SSE event to the client
+// SendEvent queues an SSE event for the client. It fails when no SSE stream
+// is attached or the session has been closed.
 func (s *Session) SendEvent(data []byte, eventID string) error {
+	event := sseEvent{id: eventID, data: data}
+
+	s.mu.Lock()
+	if s.queue == nil || s.detached == nil {
+		s.mu.Unlock()
+		return ErrNoStream
+	}
+	if s.isClosed() {
+		s.mu.Unlock()
+		return ErrSessionClosed
+	}
+
+	select {
+	case s.queue <- event:
+		s.mu.Unlock()
+		return nil
+	default:
+	}
+
+	switch s.policy {
+	case OverflowBlock:
+		queue, detached := s.queue, s.detached
+		s.mu.Unlock()
+		select {
+		case queue <- event:
+			return nil
+		case <-detached:
+			return ErrNoStream
+		case <-s.closed:
+			return ErrSessionClosed
+		}
+	case OverflowCloseSession:
+		s.mu.Unlock()
+		s.Close()
+		return ErrSessionClosed
+	default:
+		// Drop the oldest events until the new one fits; the lock keeps
+		// other senders from refilling the queue in between
+		defer s.mu.Unlock()
+		for {
+			select {
+			case s.queue <- event:
+				return nil
+			default:
+			}
+			select {
+			case <-s.queue:
+				s.dropped.Add(1)
+			default:
+			}
+		}
+	}
+}
+
+// Dropped returns how many events were discarded because the queue was full
+func (s *Session) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close shuts down the session and ends its SSE stream
+func (s *Session) Close() {
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		if s.closed == nil {
+			s.closed = make(chan struct{})
+		}
+		close(s.closed)
+		s.mu.Unlock()
+	})
+}
+
+// streaming reports whether an SSE stream is attached to the open session
+func (s *Session) streaming() bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return s.queue != nil && s.detached != nil && !s.isClosed()
+}
 
-	if s.sseWriter == nil {
-		return fmt.Errorf("no SSE connection")
+// isClosed reports whether Close was called; callers hold mu
+func (s *Session) isClosed() bool {
+	if s.closed == nil {
+		return false
 	}
+	select {
+	case <-s.closed:
+		return true
+	default:
+		return false
+	}
+}
 
-	if eventID != "" {
-		_, _ = fmt.Fprintf(s.sseWriter, "id: %s\n", eventID)
+// attach connects a GET stream to the session, replacing any previous
+// stream, and returns the queue it must drain
+func (s *Session) attach(queueSize int, policy OverflowPolicy) (<-chan sseEvent, chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed == nil {
+		s.closed = make(chan struct{})
+	}
+	if s.queue == nil {
+		s.queue = make(chan sseEvent, queueSize)
 	}
-	_, _ = fmt.Fprintf(s.sseWriter, "data: %s\n\n", data)
-	s.sseFlusher.Flush()
+	if s.detached != nil {
+		close(s.detached)
+	}
+	s.policy = policy
+	s.detached = make(chan struct{})
+	return s.queue, s.detached
+}
 
-	return nil
+// detach disconnects a GET stream unless it was already replaced
+func (s *Session) detach(detached chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.detached == detached {
+		close(s.detached)
+		s.detached = nil
+	}
 }
 
 // generateSessionID generates a cryptographically secure session ID
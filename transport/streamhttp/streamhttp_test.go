@@ -1,11 +1,21 @@
 package streamhttp
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/jmcarbo/fullmcp/discovery"
+	"github.com/jmcarbo/fullmcp/mcp"
+	"github.com/jmcarbo/fullmcp/server"
+	"golang.org/x/oauth2"
 )
 
 func TestTransport_New(t *testing.T) {
@@ -503,3 +513,409 @@ func TestServer_CORSHeadersNoOrigin(t *testing.T) {
 		t.Errorf("expected Access-Control-Allow-Origin header %q, got %q", want, got)
 	}
 }
+
+func TestTransport_WithConnectionTuning(t *testing.T) {
+	proxyURL, _ := url.Parse("http://proxy.internal:3128")
+	transport := New("http://localhost:8080",
+		WithMaxIdleConnsPerHost(4),
+		WithMaxConnsPerHost(8),
+		WithResponseHeaderTimeout(5*time.Second),
+		WithProxy(proxyURL),
+	)
+
+	tr, ok := transport.client.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected *http.Transport, got %T", transport.client.Transport)
+	}
+
+	if tr.MaxIdleConnsPerHost != 4 || tr.MaxConnsPerHost != 8 {
+		t.Errorf("pool limits not applied: %d/%d", tr.MaxIdleConnsPerHost, tr.MaxConnsPerHost)
+	}
+
+	if tr.ResponseHeaderTimeout != 5*time.Second {
+		t.Errorf("expected response header timeout 5s, got %v", tr.ResponseHeaderTimeout)
+	}
+
+	if tr.Proxy == nil {
+		t.Error("expected proxy to be configured")
+	}
+}
+
+func TestSession_QueueDropOldest(t *testing.T) {
+	session := &Session{ID: "s"}
+	queue, _ := session.attach(2, OverflowDropOldest)
+
+	for _, data := range []string{"1", "2", "3"} {
+		if err := session.SendEvent([]byte(data), ""); err != nil {
+			t.Fatalf("send %s failed: %v", data, err)
+		}
+	}
+
+	if got := session.Dropped(); got != 1 {
+		t.Errorf("expected 1 dropped event, got %d", got)
+	}
+	for _, want := range []string{"2", "3"} {
+		if event := <-queue; string(event.data) != want {
+			t.Errorf("expected event %s, got %s", want, event.data)
+		}
+	}
+}
+
+func TestSession_QueueBlock(t *testing.T) {
+	session := &Session{ID: "s"}
+	queue, _ := session.attach(1, OverflowBlock)
+
+	if err := session.SendEvent([]byte("1"), ""); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+
+	sent := make(chan error, 1)
+	go func() {
+		sent <- session.SendEvent([]byte("2"), "")
+	}()
+
+	select {
+	case err := <-sent:
+		t.Fatalf("expected sender to block, got %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	<-queue
+	select {
+	case err := <-sent:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("sender still blocked after queue drained")
+	}
+}
+
+func TestSession_QueueBlockUnblocksOnDetach(t *testing.T) {
+	session := &Session{ID: "s"}
+	_, detached := session.attach(1, OverflowBlock)
+	_ = session.SendEvent([]byte("1"), "")
+
+	sent := make(chan error, 1)
+	go func() {
+		sent <- session.SendEvent([]byte("2"), "")
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	session.detach(detached)
+
+	select {
+	case err := <-sent:
+		if err != ErrNoStream {
+			t.Errorf("expected ErrNoStream, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("sender still blocked after stream detached")
+	}
+}
+
+func TestSession_QueueCloseSession(t *testing.T) {
+	session := &Session{ID: "s"}
+	session.attach(1, OverflowCloseSession)
+
+	_ = session.SendEvent([]byte("1"), "")
+	if err := session.SendEvent([]byte("2"), ""); err != ErrSessionClosed {
+		t.Fatalf("expected ErrSessionClosed, got %v", err)
+	}
+	if err := session.SendEvent([]byte("3"), ""); err != ErrSessionClosed {
+		t.Errorf("expected closed session to reject events, got %v", err)
+	}
+}
+
+func TestServer_GET_StreamsQueuedEvents(t *testing.T) {
+	srv := NewServer(":0", nil, WithEventQueueSize(4))
+	httpServer := httptest.NewServer(srv)
+	defer httpServer.Close()
+
+	session := srv.sessionStore.GetOrCreate("stream-session")
+
+	req, _ := http.NewRequest("GET", httpServer.URL, nil)
+	req.Header.Set("Mcp-Session-Id", "stream-session")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	deadline := time.Now().Add(time.Second)
+	for session.SendEvent([]byte(`{"jsonrpc":"2.0","method":"ping"}`), "evt-1") == ErrNoStream {
+		if time.Now().After(deadline) {
+			t.Fatal("stream never attached")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	reader := &sseReader{resp: resp, scanner: bufio.NewScanner(resp.Body), transport: New(httpServer.URL)}
+	data, err := reader.ReadEvent()
+	if err != nil {
+		t.Fatalf("failed to read event: %v", err)
+	}
+	if !strings.Contains(string(data), `"method":"ping"`) {
+		t.Errorf("unexpected event data: %s", data)
+	}
+}
+
+func TestServer_AllowedOriginsAndCheck(t *testing.T) {
+	server := NewServer(":8080", nil,
+		WithAllowedOrigins("https://app.example.com", "https://*.staging.example.com"),
+		WithOriginCheck(func(origin string) bool {
+			return origin == "http://localhost:3000"
+		}),
+	)
+
+	tests := []struct {
+		origin  string
+		allowed bool
+	}{
+		{"https://app.example.com", true},
+		{"https://api.staging.example.com", true},
+		{"http://localhost:3000", true},
+		{"https://evil.com", false},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/mcp", nil)
+		req.Header.Set("Origin", tt.origin)
+		w := httptest.NewRecorder()
+
+		server.ServeHTTP(w, req)
+
+		if allowed := w.Code != http.StatusForbidden; allowed != tt.allowed {
+			t.Errorf("origin %q: expected allowed=%v, got status %d", tt.origin, tt.allowed, w.Code)
+		}
+		if tt.allowed && w.Header().Get("Access-Control-Allow-Origin") != tt.origin {
+			t.Errorf("origin %q: expected origin to be echoed, got %q", tt.origin, w.Header().Get("Access-Control-Allow-Origin"))
+		}
+	}
+
+	// With several patterns there is no single origin to advertise
+	req := httptest.NewRequest("POST", "/mcp", nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("expected no CORS origin without an Origin header, got %q", got)
+	}
+}
+
+func TestServer_Stateless(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{}}`))
+	})
+	server := NewServer(":8080", handler, WithStateless())
+
+	req := httptest.NewRequest("POST", "/mcp", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+	if id := w.Header().Get("Mcp-Session-Id"); id != "" {
+		t.Errorf("expected no session ID in stateless mode, got %q", id)
+	}
+	if len(server.sessionStore.sessions) != 0 {
+		t.Errorf("expected no sessions to be stored, got %d", len(server.sessionStore.sessions))
+	}
+
+	req = httptest.NewRequest("GET", "/mcp", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected GET to be rejected with 405, got %d", w.Code)
+	}
+	if allow := w.Header().Get("Allow"); allow != "POST, OPTIONS" {
+		t.Errorf("expected Allow header 'POST, OPTIONS', got %q", allow)
+	}
+
+	req = httptest.NewRequest("OPTIONS", "/mcp", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if methods := w.Header().Get("Access-Control-Allow-Methods"); methods != "POST, OPTIONS" {
+		t.Errorf("expected preflight to advertise POST only, got %q", methods)
+	}
+}
+
+func TestServer_StatelessCapabilities(t *testing.T) {
+	mcpSrv := server.New("test")
+	_ = mcpSrv.AddResource(&server.ResourceHandler{
+		URI:    "file:///readme",
+		Reader: func(_ context.Context) ([]byte, error) { return []byte("hi"), nil },
+	})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg mcp.Message
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		_ = json.NewEncoder(w).Encode(mcpSrv.HandleMessage(r.Context(), &msg))
+	})
+
+	initialize := func(srv *Server) *mcp.ResourcesCapability {
+		req := httptest.NewRequest("POST", "/mcp", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`))
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+
+		var resp struct {
+			Result struct {
+				Capabilities mcp.ServerCapabilities `json:"capabilities"`
+			} `json:"result"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode initialize result: %v", err)
+		}
+		if resp.Result.Capabilities.Resources == nil {
+			t.Fatal("expected the resources capability")
+		}
+		return resp.Result.Capabilities.Resources
+	}
+
+	if caps := initialize(NewServer(":8080", handler)); !caps.Subscribe || !caps.ListChanged {
+		t.Errorf("expected subscriptions with sessions, got %+v", caps)
+	}
+	if caps := initialize(NewServer(":8080", handler, WithStateless())); caps.Subscribe || caps.ListChanged {
+		t.Errorf("expected no subscriptions in stateless mode, got %+v", caps)
+	}
+}
+
+func TestTransport_BearerTokenAndHeaderFunc(t *testing.T) {
+	headers := make(chan http.Header, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			headers <- r.Header.Clone()
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	transport := New(server.URL,
+		WithBearerToken(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "secret"})),
+		WithHeaderFunc(func(req *http.Request) {
+			req.Header.Set("X-Trace-Id", "trace-1")
+		}),
+	)
+	defer func() { _ = transport.Close() }()
+
+	if _, err := transport.post([]byte(`{}`)); err != nil {
+		t.Fatalf("post failed: %v", err)
+	}
+
+	got := <-headers
+	if got.Get("Authorization") != "Bearer secret" {
+		t.Errorf("expected bearer token, got %q", got.Get("Authorization"))
+	}
+	if got.Get("X-Trace-Id") != "trace-1" {
+		t.Errorf("expected trace header, got %q", got.Get("X-Trace-Id"))
+	}
+}
+
+func TestServer_POST_MaxBodySize(t *testing.T) {
+	var received string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		w.WriteHeader(http.StatusAccepted)
+	})
+	server := NewServer(":8080", handler, WithMaxBodySize(32))
+
+	req := httptest.NewRequest("POST", "/mcp", strings.NewReader(strings.Repeat("x", 100)))
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status 413, got %d", w.Code)
+	}
+	if w.Header().Get("Mcp-Session-Id") != "" {
+		t.Error("expected no session for a rejected request")
+	}
+
+	req = httptest.NewRequest("POST", "/mcp", strings.NewReader(`{"jsonrpc":"2.0"}`))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusAccepted || received != `{"jsonrpc":"2.0"}` {
+		t.Errorf("expected handler to read the body, got %d %q", w.Code, received)
+	}
+}
+
+func TestServer_SessionIDsAndSend(t *testing.T) {
+	srv := NewServer(":0", nil)
+	streaming := srv.sessionStore.GetOrCreate("streaming")
+	queue, _ := streaming.attach(4, OverflowDropOldest)
+	srv.sessionStore.GetOrCreate("post-only")
+
+	ids := srv.SessionIDs()
+	if len(ids) != 1 || ids[0] != "streaming" {
+		t.Fatalf("expected only the streaming session, got %v", ids)
+	}
+
+	if err := srv.Send("streaming", []byte(`{"jsonrpc":"2.0","method":"ping"}`)); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	if event := <-queue; !strings.Contains(string(event.data), `"method":"ping"`) {
+		t.Errorf("unexpected event data: %s", event.data)
+	}
+
+	if err := srv.Send("missing", nil); err != ErrSessionNotFound {
+		t.Errorf("expected ErrSessionNotFound, got %v", err)
+	}
+}
+
+func TestServer_Discovery(t *testing.T) {
+	srv := NewServer(":0", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}), WithAllowedOrigin("https://app.example.com"), WithDiscovery(&discovery.Document{Name: "inventory"}))
+	httpServer := httptest.NewServer(srv)
+	defer httpServer.Close()
+
+	// Discovery is open to any origin
+	req, _ := http.NewRequest(http.MethodGet, httpServer.URL+discovery.Path, nil)
+	req.Header.Set("Origin", "https://registry.example.org")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the document, got %d %s", resp.StatusCode, body)
+	}
+	host := strings.TrimPrefix(httpServer.URL, "http://")
+	if !strings.Contains(string(body), `"url":"http+stream://`+host+`/"`) {
+		t.Errorf("expected the server to list itself, got %s", body)
+	}
+}
+
+func TestServer_ConnInfo(t *testing.T) {
+	var got *server.ConnInfo
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = server.ConnInfoFromContext(r.Context())
+		w.WriteHeader(http.StatusAccepted)
+	})
+	srv := NewServer(":8080", handler)
+
+	req := httptest.NewRequest("POST", "/mcp", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize"}`))
+	req.Header.Set("X-Tenant", "acme")
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+
+	sessionID := w.Header().Get("Mcp-Session-Id")
+	if got == nil || sessionID == "" {
+		t.Fatalf("expected connection info and a new session, got %+v", got)
+	}
+	if got.SessionID != sessionID {
+		t.Errorf("expected session %q, got %q", sessionID, got.SessionID)
+	}
+	if got.RemoteAddr != req.RemoteAddr || got.Header.Get("X-Tenant") != "acme" {
+		t.Errorf("expected remote address and headers, got %+v", got)
+	}
+
+	stateless := NewServer(":8080", handler, WithStateless())
+	req = httptest.NewRequest("POST", "/mcp", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+	req.Header.Set("Mcp-Session-Id", "client-made")
+	stateless.ServeHTTP(httptest.NewRecorder(), req)
+	if got == nil || got.SessionID != "" {
+		t.Errorf("expected no session in stateless mode, got %+v", got)
+	}
+}
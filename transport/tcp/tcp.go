@@ -0,0 +1,132 @@
+// Package tcp provides TCP transport for MCP, for servers started with
+// server.ServeListener on a TCP listener. Messages are newline-delimited
+// JSON, as with stdio, optionally compressed (see package transport/compress).
+package tcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+
+	"github.com/jmcarbo/fullmcp/transport"
+	"github.com/jmcarbo/fullmcp/transport/compress"
+)
+
+var _ transport.Transport = (*Transport)(nil)
+
+func init() {
+	transport.Register("tcp", newFromURL)
+}
+
+// Transport connects to an MCP server listening on a TCP address
+type Transport struct {
+	addr        string
+	dialer      net.Dialer
+	compression []string // Algorithms offered to the server
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// Option configures the TCP transport
+type Option func(*Transport)
+
+// New creates a transport for the server at addr ("host:port")
+func New(addr string, opts ...Option) *Transport {
+	t := &Transport{addr: addr}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// WithDialer sets the dialer used to connect, for example to set a timeout
+// or keepalive period
+func WithDialer(dialer net.Dialer) Option {
+	return func(t *Transport) {
+		t.dialer = dialer
+	}
+}
+
+// WithCompression offers the given algorithms, in order of preference, to
+// servers that negotiate compression. The connection stays uncompressed if
+// the server declines.
+func WithCompression(algorithms ...string) Option {
+	return func(t *Transport) {
+		t.compression = algorithms
+	}
+}
+
+// newFromURL creates a transport for "tcp://host:port". A "compress" query
+// parameter, such as "?compress=gzip,deflate", offers compression.
+func newFromURL(target *url.URL, cfg transport.Config) (transport.Transport, error) {
+	if cfg.HasCredentials() {
+		return nil, fmt.Errorf("tcp transport does not support credentials")
+	}
+	if target.Host == "" {
+		return nil, fmt.Errorf("tcp transport URL has no address: %s", target)
+	}
+	return New(target.Host, WithCompression(compress.ParseList(target.Query().Get("compress"))...)), nil
+}
+
+// Connect dials the server and negotiates compression if configured
+func (t *Transport) Connect(ctx context.Context) (io.ReadWriteCloser, error) {
+	if len(t.compression) == 0 {
+		return t.dial(ctx)
+	}
+	conn, err := t.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	negotiated, _, err := compress.OfferConn(ctx, conn, t.compression...)
+	if errors.Is(err, compress.ErrNotSupported) {
+		// A server without negotiation answers the offer with a parse
+		// error, which OfferConn consumed before closing the connection
+		return t.dial(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tcp compression negotiation failed: %w", err)
+	}
+	return negotiated, nil
+}
+
+// dial connects to the server
+func (t *Transport) dial(ctx context.Context) (net.Conn, error) {
+	conn, err := t.dialer.DialContext(ctx, "tcp", t.addr)
+	if err != nil {
+		return nil, fmt.Errorf("tcp dial failed: %w", err)
+	}
+
+	t.mu.Lock()
+	t.conn = conn
+	t.mu.Unlock()
+	return conn, nil
+}
+
+// SupportsServerPush reports true: either side may write at any time
+func (t *Transport) SupportsServerPush() bool {
+	return true
+}
+
+// Close closes the connection
+func (t *Transport) Close() error {
+	t.mu.Lock()
+	conn := t.conn
+	t.conn = nil
+	t.mu.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+	if err := conn.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+		return err
+	}
+	return nil
+}
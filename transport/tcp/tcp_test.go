@@ -0,0 +1,85 @@
+package tcp_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/client"
+	"github.com/jmcarbo/fullmcp/server"
+	"github.com/jmcarbo/fullmcp/transport"
+	"github.com/jmcarbo/fullmcp/transport/compress"
+)
+
+// serveTCP serves srv on a local TCP listener until the test ends
+func serveTCP(t *testing.T, srv *server.Server, opts ...server.ListenerOption) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("tcp listener unavailable: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = srv.ServeListener(ctx, lis, opts...)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return lis.Addr().String()
+}
+
+func TestTransport_Compression(t *testing.T) {
+	srv := server.New("tcp-test")
+	_ = srv.AddTool(&server.ToolHandler{
+		Name: "echo",
+		Handler: func(_ context.Context, args json.RawMessage) (interface{}, error) {
+			return string(args), nil
+		},
+	})
+
+	tests := []struct {
+		name     string
+		url      string
+		accepted []string
+	}{
+		{"plain", "tcp://%s", nil},
+		{"gzip", "tcp://%s?compress=gzip", []string{compress.Gzip}},
+		{"deflate", "tcp://%s?compress=deflate,gzip", []string{compress.Gzip, compress.Deflate}},
+		{"declined", "tcp://%s?compress=gzip", []string{compress.Deflate}},
+		{"server without negotiation", "tcp://%s?compress=gzip", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []server.ListenerOption
+			if tt.accepted != nil {
+				opts = append(opts, server.WithConnCompression(tt.accepted...))
+			}
+			addr := serveTCP(t, srv, opts...)
+
+			c, err := client.Dial(context.Background(), fmt.Sprintf(tt.url, addr))
+			if err != nil {
+				t.Fatalf("dial failed: %v", err)
+			}
+			defer func() { _ = c.Close() }()
+
+			result, err := c.CallTool(context.Background(), "echo", map[string]string{"msg": "hi"})
+			if err != nil {
+				t.Fatalf("call failed: %v", err)
+			}
+			if result != `{"msg":"hi"}` {
+				t.Errorf("unexpected result %v", result)
+			}
+		})
+	}
+}
+
+func TestNewFromURL_MissingAddress(t *testing.T) {
+	if _, err := transport.Open("tcp:", transport.Config{}); err == nil {
+		t.Error("expected error for missing address")
+	}
+}
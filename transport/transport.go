@@ -0,0 +1,129 @@
+// Package transport defines the interface implemented by MCP client
+// transports and a registry mapping URL schemes to transport factories, so
+// built-in and third-party transports can be selected by URL.
+//
+// Built-in transports register themselves when their package is imported:
+//
+//	stdio                        transport/stdio
+//	http, https                  transport/http
+//	http+stream, https+stream    transport/streamhttp
+//	http+sse, https+sse          transport/sse
+//	ws, wss                      transport/websocket
+//	unix                         transport/unix
+//	tcp                          transport/tcp
+package transport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// Transport establishes connections to an MCP peer
+type Transport interface {
+	// Connect opens a connection carrying newline-delimited JSON-RPC messages
+	Connect(ctx context.Context) (io.ReadWriteCloser, error)
+	// Close releases the transport and any open connection
+	Close() error
+}
+
+// ServerPusher is implemented by transports that report whether the peer can
+// send messages, such as notifications and server-to-client requests, at any
+// time rather than only in reply to a client request
+type ServerPusher interface {
+	SupportsServerPush() bool
+}
+
+// SupportsServerPush reports whether t can receive server-initiated messages.
+// Transports that do not implement ServerPusher are assumed not to.
+func SupportsServerPush(t Transport) bool {
+	if p, ok := t.(ServerPusher); ok {
+		return p.SupportsServerPush()
+	}
+	return false
+}
+
+// Config holds settings understood by all registered transports
+type Config struct {
+	APIKey      string             // Sent as the X-API-Key header
+	Headers     map[string]string  // Extra headers for HTTP-based transports
+	TokenSource oauth2.TokenSource // Supplies bearer tokens for the Authorization header
+}
+
+// HasCredentials reports whether cfg carries credentials or headers.
+// Factories for transports that can't send them return an error rather
+// than connecting unauthenticated.
+func (c Config) HasCredentials() bool {
+	return c.APIKey != "" || len(c.Headers) > 0 || c.TokenSource != nil
+}
+
+// Factory creates a transport for a URL with a registered scheme
+type Factory func(target *url.URL, cfg Config) (Transport, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes a transport available for a URL scheme. It panics if the
+// factory is nil or the scheme is already registered.
+func Register(scheme string, factory Factory) {
+	scheme = strings.ToLower(scheme)
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if factory == nil {
+		panic("transport: Register factory is nil")
+	}
+	if _, exists := registry[scheme]; exists {
+		panic("transport: Register called twice for scheme " + scheme)
+	}
+	registry[scheme] = factory
+}
+
+// Schemes returns the registered URL schemes in sorted order
+func Schemes() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	schemes := make([]string, 0, len(registry))
+	for scheme := range registry {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// Open creates a transport for rawURL using the factory registered for its
+// scheme
+func Open(rawURL string, cfg Config) (Transport, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transport URL %q: %w", rawURL, err)
+	}
+
+	scheme := strings.ToLower(target.Scheme)
+	registryMu.RLock()
+	factory, ok := registry[scheme]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unsupported transport scheme %q (registered: %s)", target.Scheme, strings.Join(Schemes(), ", "))
+	}
+	return factory(target, cfg)
+}
+
+// BaseURL returns target with its scheme replaced, for transports registered
+// under schemes such as "http+stream" that dial a plain http:// URL
+func BaseURL(target *url.URL, scheme string) string {
+	u := *target
+	u.Scheme = scheme
+	return u.String()
+}
@@ -0,0 +1,90 @@
+package transport_test
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/transport"
+	_ "github.com/jmcarbo/fullmcp/transport/http"
+	_ "github.com/jmcarbo/fullmcp/transport/sse"
+	_ "github.com/jmcarbo/fullmcp/transport/stdio"
+	_ "github.com/jmcarbo/fullmcp/transport/streamhttp"
+	_ "github.com/jmcarbo/fullmcp/transport/websocket"
+)
+
+type fakeTransport struct {
+	target string
+	cfg    transport.Config
+}
+
+func (f *fakeTransport) Connect(context.Context) (io.ReadWriteCloser, error) { return nil, nil }
+func (f *fakeTransport) Close() error                                        { return nil }
+
+func TestRegisterAndOpen(t *testing.T) {
+	transport.Register("fake", func(target *url.URL, cfg transport.Config) (transport.Transport, error) {
+		return &fakeTransport{target: transport.BaseURL(target, "tcp"), cfg: cfg}, nil
+	})
+
+	tr, err := transport.Open("FAKE://localhost:9000/mcp", transport.Config{APIKey: "key"})
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	fake := tr.(*fakeTransport)
+	if fake.target != "tcp://localhost:9000/mcp" {
+		t.Errorf("unexpected target %q", fake.target)
+	}
+	if fake.cfg.APIKey != "key" {
+		t.Errorf("expected config to be passed, got %+v", fake.cfg)
+	}
+	if transport.SupportsServerPush(tr) {
+		t.Error("transports without SupportsServerPush should not report push support")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected duplicate registration to panic")
+		}
+	}()
+	transport.Register("fake", func(*url.URL, transport.Config) (transport.Transport, error) { return nil, nil })
+}
+
+func TestOpen_UnknownScheme(t *testing.T) {
+	_, err := transport.Open("gopher://example.com", transport.Config{})
+	if err == nil || !strings.Contains(err.Error(), "unsupported transport scheme") {
+		t.Errorf("expected unsupported scheme error, got %v", err)
+	}
+}
+
+func TestBuiltinTransports(t *testing.T) {
+	tests := []struct {
+		url  string
+		push bool
+	}{
+		{"stdio:", true},
+		{"http://localhost:8080/mcp", false},
+		{"https://localhost:8443/mcp", false},
+		{"http+stream://localhost:8080/mcp", true},
+		{"https+sse://localhost:8443/events", true},
+		{"ws://localhost:8080/", true},
+		{"wss://localhost:8443/", true},
+	}
+
+	for _, tt := range tests {
+		tr, err := transport.Open(tt.url, transport.Config{})
+		if err != nil {
+			t.Errorf("%s: open failed: %v", tt.url, err)
+			continue
+		}
+		if got := transport.SupportsServerPush(tr); got != tt.push {
+			t.Errorf("%s: expected server push %v, got %v", tt.url, tt.push, got)
+		}
+		_ = tr.Close()
+	}
+
+	if _, err := transport.Open("https+sse://localhost/events", transport.Config{APIKey: "key"}); err == nil {
+		t.Error("expected sse transport to reject custom headers")
+	}
+}
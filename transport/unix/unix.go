@@ -0,0 +1,135 @@
+// Package unix provides Unix domain socket transport for MCP. Messages are
+// newline-delimited JSON, as with stdio.
+package unix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+
+	"github.com/jmcarbo/fullmcp/transport"
+	"github.com/jmcarbo/fullmcp/transport/compress"
+)
+
+var _ transport.Transport = (*Transport)(nil)
+
+func init() {
+	transport.Register("unix", newFromURL)
+}
+
+// Transport connects to an MCP server listening on a Unix domain socket
+type Transport struct {
+	path        string
+	dialer      net.Dialer
+	compression []string // Algorithms offered to the server
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// Option configures the Unix socket transport
+type Option func(*Transport)
+
+// New creates a transport for the socket at path
+func New(path string, opts ...Option) *Transport {
+	t := &Transport{path: path}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// WithDialer sets the dialer used to connect, for example to set a timeout
+func WithDialer(dialer net.Dialer) Option {
+	return func(t *Transport) {
+		t.dialer = dialer
+	}
+}
+
+// WithCompression offers the given algorithms, in order of preference, to
+// servers that negotiate compression (see package transport/compress). The
+// connection stays uncompressed if the server declines.
+func WithCompression(algorithms ...string) Option {
+	return func(t *Transport) {
+		t.compression = algorithms
+	}
+}
+
+// newFromURL creates a transport for "unix:///path/to.sock" or
+// "unix:relative.sock". A "compress" query parameter, such as
+// "?compress=gzip,deflate", offers compression.
+func newFromURL(target *url.URL, cfg transport.Config) (transport.Transport, error) {
+	if cfg.HasCredentials() {
+		return nil, fmt.Errorf("unix transport does not support credentials")
+	}
+	path := target.Path
+	if path == "" {
+		path = target.Opaque
+	}
+	if path == "" {
+		return nil, fmt.Errorf("unix transport URL has no socket path: %s", target)
+	}
+	return New(path, WithCompression(compress.ParseList(target.Query().Get("compress"))...)), nil
+}
+
+// Connect dials the socket and negotiates compression if configured
+func (t *Transport) Connect(ctx context.Context) (io.ReadWriteCloser, error) {
+	if len(t.compression) == 0 {
+		return t.dial(ctx)
+	}
+	conn, err := t.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	negotiated, _, err := compress.OfferConn(ctx, conn, t.compression...)
+	if errors.Is(err, compress.ErrNotSupported) {
+		// A server without negotiation answers the offer with a parse
+		// error, which OfferConn consumed before closing the connection
+		return t.dial(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unix socket compression negotiation failed: %w", err)
+	}
+	return negotiated, nil
+}
+
+// dial connects to the server
+func (t *Transport) dial(ctx context.Context) (net.Conn, error) {
+	conn, err := t.dialer.DialContext(ctx, "unix", t.path)
+	if err != nil {
+		return nil, fmt.Errorf("unix socket dial failed: %w", err)
+	}
+
+	t.mu.Lock()
+	t.conn = conn
+	t.mu.Unlock()
+	return conn, nil
+}
+
+// SupportsServerPush reports true: either side may write at any time
+func (t *Transport) SupportsServerPush() bool {
+	return true
+}
+
+// Close closes the connection
+func (t *Transport) Close() error {
+	t.mu.Lock()
+	conn := t.conn
+	t.conn = nil
+	t.mu.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+	if err := conn.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package unix
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmcarbo/fullmcp/transport"
+)
+
+func TestTransport_Connect(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp.sock")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		_, _ = conn.Write([]byte(line))
+	}()
+
+	tr, err := transport.Open("unix://"+path, transport.Config{})
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	conn, err := tr.Connect(context.Background())
+	if err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+
+	if _, err := conn.Write([]byte("hello\n")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil || line != "hello\n" {
+		t.Errorf("expected echo, got %q (err=%v)", line, err)
+	}
+
+	_ = conn.Close()
+	if err := tr.Close(); err != nil {
+		t.Errorf("close after connection close: %v", err)
+	}
+}
+
+func TestNewFromURL_MissingPath(t *testing.T) {
+	if _, err := transport.Open("unix:", transport.Config{}); err == nil {
+		t.Error("expected error for missing socket path")
+	}
+}
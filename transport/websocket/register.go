@@ -0,0 +1,37 @@
+package websocket
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/jmcarbo/fullmcp/transport"
+)
+
+var _ transport.Transport = (*Transport)(nil)
+
+func init() {
+	transport.Register("ws", newFromURL)
+	transport.Register("wss", newFromURL)
+}
+
+// newFromURL creates a transport for a ws:// or wss:// URL
+func newFromURL(target *url.URL, cfg transport.Config) (transport.Transport, error) {
+	headers := http.Header{}
+	for k, v := range cfg.Headers {
+		headers.Set(k, v)
+	}
+
+	opts := []Option{WithHeaders(headers)}
+	if cfg.APIKey != "" {
+		opts = append(opts, WithAPIKey(cfg.APIKey))
+	}
+	if cfg.TokenSource != nil {
+		opts = append(opts, WithBearerToken(cfg.TokenSource))
+	}
+	return New(target.String(), opts...), nil
+}
+
+// SupportsServerPush reports true: either side may send at any time
+func (t *Transport) SupportsServerPush() bool {
+	return true
+}
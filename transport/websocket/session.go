@@ -0,0 +1,189 @@
+package websocket
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/jmcarbo/fullmcp/auth"
+)
+
+// ErrConnNotFound is returned by Server.Send for unknown connection IDs
+var ErrConnNotFound = errors.New("websocket connection not found")
+
+// Conn is a client connected to the WebSocket server. It carries the
+// handshake request's context, including claims added by auth middleware,
+// and arbitrary per-connection state.
+type Conn struct {
+	ID        string
+	CreatedAt time.Time
+
+	ctx     context.Context
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+
+	mu     sync.RWMutex
+	values map[string]interface{}
+}
+
+type connContextKey struct{}
+
+// ConnFromContext returns the connection a message was received on, or nil
+// outside a WebSocket message handler
+func ConnFromContext(ctx context.Context) *Conn {
+	c, _ := ctx.Value(connContextKey{}).(*Conn)
+	return c
+}
+
+func newConn(ctx context.Context, ws *websocket.Conn) *Conn {
+	c := &Conn{
+		ID:        generateConnID(),
+		CreatedAt: time.Now(),
+		conn:      ws,
+		values:    make(map[string]interface{}),
+	}
+	c.ctx = context.WithValue(ctx, connContextKey{}, c)
+	return c
+}
+
+// Context returns the connection context, which is cancelled when the
+// connection closes
+func (c *Conn) Context() context.Context {
+	return c.ctx
+}
+
+// Claims returns the claims added by auth middleware during the handshake
+func (c *Conn) Claims() (auth.Claims, bool) {
+	return auth.GetClaims(c.ctx)
+}
+
+// Set stores a per-connection value
+func (c *Conn) Set(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key] = value
+}
+
+// Get retrieves a per-connection value
+func (c *Conn) Get(key string) (interface{}, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	value, ok := c.values[key]
+	return value, ok
+}
+
+// Send writes a message, such as a notification, to the client
+func (c *Conn) Send(msg []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.conn.WriteMessage(websocket.TextMessage, msg)
+}
+
+// Close closes the connection
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// WithConnectHook sets a function called when a client connects, before
+// any of its messages are handled
+func (s *Server) WithConnectHook(hook func(*Conn)) *Server {
+	s.onConnect = hook
+	return s
+}
+
+// WithDisconnectHook sets a function called after a client disconnects
+func (s *Server) WithDisconnectHook(hook func(*Conn)) *Server {
+	s.onDisconnect = hook
+	return s
+}
+
+// Conns returns the currently connected clients
+func (s *Server) Conns() []*Conn {
+	s.connsMu.RLock()
+	defer s.connsMu.RUnlock()
+
+	conns := make([]*Conn, 0, len(s.conns))
+	for _, c := range s.conns {
+		conns = append(conns, c)
+	}
+	return conns
+}
+
+// SessionIDs returns the IDs of the connected clients, sorted
+func (s *Server) SessionIDs() []string {
+	s.connsMu.RLock()
+	defer s.connsMu.RUnlock()
+
+	ids := make([]string, 0, len(s.conns))
+	for id := range s.conns {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Conn returns the connected client with the given ID, or nil
+func (s *Server) Conn(id string) *Conn {
+	s.connsMu.RLock()
+	defer s.connsMu.RUnlock()
+	return s.conns[id]
+}
+
+// Send writes a message to a single connected client
+func (s *Server) Send(id string, msg []byte) error {
+	c := s.Conn(id)
+	if c == nil {
+		return ErrConnNotFound
+	}
+	return c.Send(msg)
+}
+
+// Broadcast writes a message, typically a notification, to every connected
+// client. It returns the errors of clients that could not be reached.
+func (s *Server) Broadcast(msg []byte) error {
+	var errs []error
+	for _, c := range s.Conns() {
+		if err := c.Send(msg); err != nil {
+			errs = append(errs, fmt.Errorf("connection %s: %w", c.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// register tracks a new connection
+func (s *Server) register(c *Conn) {
+	s.connsMu.Lock()
+	if s.conns == nil {
+		s.conns = make(map[string]*Conn)
+	}
+	s.conns[c.ID] = c
+	s.connsMu.Unlock()
+
+	if s.onConnect != nil {
+		s.onConnect(c)
+	}
+}
+
+// unregister stops tracking a closed connection
+func (s *Server) unregister(c *Conn) {
+	s.connsMu.Lock()
+	delete(s.conns, c.ID)
+	s.connsMu.Unlock()
+
+	if s.onDisconnect != nil {
+		s.onDisconnect(c)
+	}
+}
+
+// generateConnID generates a random connection ID
+func generateConnID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,168 @@
+package websocket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmcarbo/fullmcp/auth"
+	"github.com/jmcarbo/fullmcp/server"
+)
+
+func TestServer_ConnectionsAndBroadcast(t *testing.T) {
+	handler := func(ctx context.Context, msg []byte) ([]byte, error) {
+		conn := ConnFromContext(ctx)
+		if conn == nil {
+			t.Error("expected connection in handler context")
+			return msg, nil
+		}
+		conn.Set("last", string(msg))
+		return []byte(conn.ID), nil
+	}
+
+	connected := make(chan *Conn, 2)
+	disconnected := make(chan *Conn, 2)
+	server := NewServer(":0", handler).
+		WithConnectHook(func(c *Conn) { connected <- c }).
+		WithDisconnectHook(func(c *Conn) { disconnected <- c }).
+		WithMiddleware(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctx := auth.WithClaims(r.Context(), auth.Claims{Subject: r.URL.Query().Get("user")})
+				next.ServeHTTP(w, r.WithContext(ctx))
+			})
+		})
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(httpServer.URL, "http")
+
+	alice, err := New(wsURL + "?user=alice").Connect(context.Background())
+	if err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+	bob, err := New(wsURL + "?user=bob").Connect(context.Background())
+	if err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+	defer func() { _ = bob.Close() }()
+
+	subjects := make(map[string]string)
+	for i := 0; i < 2; i++ {
+		select {
+		case c := <-connected:
+			claims, ok := c.Claims()
+			if !ok {
+				t.Fatal("expected claims from middleware")
+			}
+			subjects[claims.Subject] = c.ID
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for connect hook")
+		}
+	}
+	if len(server.Conns()) != 2 {
+		t.Fatalf("expected 2 connections, got %d", len(server.Conns()))
+	}
+	if ids := server.SessionIDs(); len(ids) != 2 || ids[0] > ids[1] {
+		t.Errorf("expected 2 sorted session IDs, got %v", ids)
+	}
+
+	// Messages are handled in the context of their connection
+	if _, err := alice.Write([]byte("hello")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	buf := make([]byte, 1024)
+	n, err := alice.Read(buf)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if string(buf[:n]) != subjects["alice"] {
+		t.Errorf("expected alice's connection ID, got %q", buf[:n])
+	}
+	if last, _ := server.Conn(subjects["alice"]).Get("last"); last != "hello" {
+		t.Errorf("expected per-connection state to be stored, got %v", last)
+	}
+
+	notification := []byte(`{"jsonrpc":"2.0","method":"notifications/tools/list_changed"}`)
+	if err := server.Broadcast(notification); err != nil {
+		t.Fatalf("broadcast failed: %v", err)
+	}
+	for name, conn := range map[string]interface{ Read([]byte) (int, error) }{"alice": alice, "bob": bob} {
+		n, err := conn.Read(buf)
+		if err != nil {
+			t.Fatalf("%s: read failed: %v", name, err)
+		}
+		if string(buf[:n]) != string(notification) {
+			t.Errorf("%s: expected notification, got %s", name, buf[:n])
+		}
+	}
+
+	if err := server.Send(subjects["bob"], []byte("direct")); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	n, err = bob.Read(buf)
+	if err != nil || string(buf[:n]) != "direct" {
+		t.Errorf("expected direct message, got %q (err=%v)", buf[:n], err)
+	}
+
+	if err := server.Send("unknown", []byte("x")); err != ErrConnNotFound {
+		t.Errorf("expected ErrConnNotFound, got %v", err)
+	}
+
+	_ = alice.Close()
+	select {
+	case c := <-disconnected:
+		if c.ID != subjects["alice"] {
+			t.Errorf("expected alice to disconnect, got %s", c.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for disconnect hook")
+	}
+	if server.Conn(subjects["alice"]) != nil {
+		t.Error("expected disconnected client to be removed")
+	}
+}
+
+func TestServer_ConnInfo(t *testing.T) {
+	infos := make(chan *server.ConnInfo, 1)
+	handler := func(ctx context.Context, msg []byte) ([]byte, error) {
+		info, _ := server.ConnInfoFromContext(ctx)
+		infos <- info
+		return []byte(ConnFromContext(ctx).ID), nil
+	}
+	httpServer := httptest.NewServer(NewServer(":0", handler).Handler())
+	defer httpServer.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(httpServer.URL, "http")
+	conn, err := New(wsURL, WithHeaders(http.Header{"X-Tenant": {"acme"}})).Connect(context.Background())
+	if err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if _, err := conn.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}`)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	buf := make([]byte, 64)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+
+	select {
+	case info := <-infos:
+		if info == nil {
+			t.Fatal("expected connection info in handler context")
+		}
+		if info.SessionID != string(buf[:n]) {
+			t.Errorf("expected the connection ID %q as session, got %q", buf[:n], info.SessionID)
+		}
+		if info.RemoteAddr == "" || info.Header.Get("X-Tenant") != "acme" {
+			t.Errorf("expected remote address and handshake headers, got %+v", info)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler was not called")
+	}
+}
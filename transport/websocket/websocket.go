@@ -3,14 +3,23 @@ package websocket
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
 	"sync"
 
 	"github.com/gorilla/websocket"
+	"github.com/jmcarbo/fullmcp/discovery"
+	"github.com/jmcarbo/fullmcp/internal/cors"
+	"github.com/jmcarbo/fullmcp/internal/httptransport"
+	"github.com/jmcarbo/fullmcp/server"
+	"golang.org/x/oauth2"
 )
 
+// Subprotocol is the WebSocket subprotocol negotiated for MCP connections
+const Subprotocol = "mcp"
+
 // Transport implements WebSocket transport for MCP
 type Transport struct {
 	url     string
@@ -21,6 +30,8 @@ type Transport struct {
 	readBuf []byte
 	readMu  sync.Mutex
 	writeMu sync.Mutex
+
+	requestFuncs []httptransport.RequestFunc // Applied to the handshake request
 }
 
 // Option configures the WebSocket transport
@@ -55,9 +66,77 @@ func WithHeaders(headers http.Header) Option {
 	}
 }
 
+// WithAPIKey sets the X-API-Key header on the handshake request
+func WithAPIKey(apiKey string) Option {
+	return func(t *Transport) {
+		if t.headers == nil {
+			t.headers = http.Header{}
+		}
+		t.headers.Set("X-API-Key", apiKey)
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used for wss:// connections, for
+// example to present client certificates or trust a private CA
+func WithTLSConfig(config *tls.Config) Option {
+	return func(t *Transport) {
+		t.dialer = t.cloneDialer()
+		t.dialer.TLSClientConfig = config
+	}
+}
+
+// WithSubprotocols sets the subprotocols offered during the handshake, in
+// order of preference; use Subprotocol to request MCP framing
+func WithSubprotocols(protocols ...string) Option {
+	return func(t *Transport) {
+		t.dialer = t.cloneDialer()
+		t.dialer.Subprotocols = protocols
+	}
+}
+
+// WithCompression offers permessage-deflate compression (RFC 7692) during
+// the handshake. Messages are compressed only if the server accepts.
+func WithCompression() Option {
+	return func(t *Transport) {
+		t.dialer = t.cloneDialer()
+		t.dialer.EnableCompression = true
+	}
+}
+
+// cloneDialer copies the dialer so options never modify a shared dialer such
+// as websocket.DefaultDialer
+func (t *Transport) cloneDialer() *websocket.Dialer {
+	if t.dialer == nil {
+		return &websocket.Dialer{}
+	}
+	d := *t.dialer
+	return &d
+}
+
+// WithBearerToken sets the Authorization header on the handshake request from a token
+// source, which refreshes expiring tokens
+func WithBearerToken(ts oauth2.TokenSource) Option {
+	return func(t *Transport) {
+		t.requestFuncs = append(t.requestFuncs, httptransport.BearerToken(ts))
+	}
+}
+
+// WithHeaderFunc registers a function called on the handshake request, for
+// dynamic headers such as trace IDs
+func WithHeaderFunc(fn func(req *http.Request)) Option {
+	return func(t *Transport) {
+		t.requestFuncs = append(t.requestFuncs, httptransport.HeaderFunc(fn))
+	}
+}
+
 // Connect establishes a WebSocket connection
 func (t *Transport) Connect(ctx context.Context) (io.ReadWriteCloser, error) {
-	conn, _, err := t.dialer.DialContext(ctx, t.url, t.headers)
+	headers, err := t.handshakeHeaders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, _, err := t.dialer.DialContext(ctx, t.url, headers)
 	if err != nil {
 		return nil, fmt.Errorf("websocket dial failed: %w", err)
 	}
@@ -74,6 +153,39 @@ func (t *Transport) Connect(ctx context.Context) (io.ReadWriteCloser, error) {
 	}, nil
 }
 
+// Subprotocol returns the subprotocol selected by the server, or "" when
+// none was negotiated or the transport is not connected
+func (t *Transport) Subprotocol() string {
+	t.connMu.RLock()
+	defer t.connMu.RUnlock()
+
+	if t.conn == nil {
+		return ""
+	}
+	return t.conn.Subprotocol()
+}
+
+// handshakeHeaders returns the handshake headers after applying request functions
+func (t *Transport) handshakeHeaders(ctx context.Context) (http.Header, error) {
+	if len(t.requestFuncs) == 0 {
+		return t.headers, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = t.headers.Clone()
+	if req.Header == nil {
+		req.Header = http.Header{}
+	}
+
+	if err := httptransport.Apply(req, t.requestFuncs); err != nil {
+		return nil, err
+	}
+	return req.Header, nil
+}
+
 // Close closes the transport
 func (t *Transport) Close() error {
 	t.connMu.RLock()
@@ -145,9 +257,22 @@ func (c *wsConn) Close() error {
 
 // Server provides WebSocket server support for MCP
 type Server struct {
-	upgrader websocket.Upgrader
-	handler  MessageHandler
-	addr     string
+	upgrader   websocket.Upgrader
+	handler    MessageHandler
+	addr       string
+	origins    cors.Policy
+	tlsConfig  *tls.Config
+	middleware []func(http.Handler) http.Handler
+	readLimit  int64
+	discovery  http.Handler // Set by WithDiscovery
+	// compressionLevel is the flate level for compressed connections, 0
+	// for the library default
+	compressionLevel int
+
+	connsMu      sync.RWMutex
+	conns        map[string]*Conn // Connected clients by ID
+	onConnect    func(*Conn)
+	onDisconnect func(*Conn)
 }
 
 // MessageHandler processes WebSocket messages
@@ -158,10 +283,12 @@ func NewServer(addr string, handler MessageHandler) *Server {
 	return &Server{
 		addr:    addr,
 		handler: handler,
+		conns:   make(map[string]*Conn),
 		upgrader: websocket.Upgrader{
 			CheckOrigin: func(_ *http.Request) bool {
 				return true // Allow all origins by default
 			},
+			Subprotocols: []string{Subprotocol},
 		},
 	}
 }
@@ -172,26 +299,160 @@ func (s *Server) WithCheckOrigin(checkOrigin func(r *http.Request) bool) *Server
 	return s
 }
 
+// WithAllowedOrigins restricts connections to origins matching the given
+// patterns, which may contain a wildcard such as "https://*.example.com".
+// Requests without an Origin header (non-browser clients) are allowed.
+func (s *Server) WithAllowedOrigins(patterns ...string) *Server {
+	s.origins.Patterns = append(s.origins.Patterns, patterns...)
+	s.upgrader.CheckOrigin = s.checkOrigin
+	return s
+}
+
+// WithOriginCheck sets a callback that approves origins not matched by the
+// allowed patterns
+func (s *Server) WithOriginCheck(check func(origin string) bool) *Server {
+	s.origins.Check = check
+	s.upgrader.CheckOrigin = s.checkOrigin
+	return s
+}
+
+// checkOrigin applies the allowed origin policy to an upgrade request
+func (s *Server) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	return s.origins.Allowed(origin)
+}
+
+// WithSubprotocols sets the subprotocols the server accepts, in order of
+// preference (default: Subprotocol). Clients that offer none are still
+// accepted.
+func (s *Server) WithSubprotocols(protocols ...string) *Server {
+	s.upgrader.Subprotocols = protocols
+	return s
+}
+
+// WithTLSConfig sets the TLS configuration used by ListenAndServeTLS
+func (s *Server) WithTLSConfig(config *tls.Config) *Server {
+	s.tlsConfig = config
+	return s
+}
+
+// WithCompression accepts permessage-deflate compression (RFC 7692) from
+// clients that offer it, which shrinks large tools/list and resource
+// payloads. Other clients are served uncompressed.
+func (s *Server) WithCompression() *Server {
+	s.upgrader.EnableCompression = true
+	return s
+}
+
+// WithCompressionLevel accepts permessage-deflate compression like
+// WithCompression, compressing at a flate level between
+// flate.HuffmanOnly and flate.BestCompression
+func (s *Server) WithCompressionLevel(level int) *Server {
+	s.upgrader.EnableCompression = true
+	s.compressionLevel = level
+	return s
+}
+
+// WithReadLimit closes connections that send a message larger than n bytes,
+// with close code 1009 (message too big)
+func (s *Server) WithReadLimit(n int64) *Server {
+	s.readLimit = n
+	return s
+}
+
+// WithMiddleware wraps the upgrade handler, for example with an auth
+// provider's Middleware to check API keys or bearer tokens during the
+// handshake
+func (s *Server) WithMiddleware(mw ...func(http.Handler) http.Handler) *Server {
+	s.middleware = append(s.middleware, mw...)
+	return s
+}
+
+// WithDiscovery serves doc at /.well-known/mcp.json to any origin, outside
+// the middleware, so clients and registries can find out how to connect. A
+// doc without endpoints lists the server itself at "/", since it upgrades
+// requests on every path.
+func (s *Server) WithDiscovery(doc *discovery.Document) *Server {
+	published := *doc
+	if len(published.Endpoints) == 0 {
+		published.Endpoints = []discovery.Endpoint{{Transport: discovery.TransportWebSocket, URL: "/"}}
+	}
+	s.discovery = discovery.Handler(&published)
+	return s
+}
+
+// Handler returns the HTTP handler that upgrades requests to WebSocket
+// connections, wrapped in the configured middleware
+func (s *Server) Handler() http.Handler {
+	var handler http.Handler = http.HandlerFunc(s.handleWebSocket)
+	for i := len(s.middleware) - 1; i >= 0; i-- {
+		handler = s.middleware[i](handler)
+	}
+	if s.discovery == nil {
+		return handler
+	}
+
+	upgrade := handler
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == discovery.Path {
+			s.discovery.ServeHTTP(w, r)
+			return
+		}
+		upgrade.ServeHTTP(w, r)
+	})
+}
+
 // ListenAndServe starts the WebSocket server
 func (s *Server) ListenAndServe() error {
+	return s.httpServer().ListenAndServe()
+}
+
+// ListenAndServeTLS starts the WebSocket server for wss:// connections. The
+// certificate files may be empty when the TLS config provides certificates.
+func (s *Server) ListenAndServeTLS(certFile, keyFile string) error {
+	return s.httpServer().ListenAndServeTLS(certFile, keyFile)
+}
+
+func (s *Server) httpServer() *http.Server {
 	mux := http.NewServeMux()
-	mux.HandleFunc("/", s.handleWebSocket)
-	return http.ListenAndServe(s.addr, mux)
+	mux.Handle("/", s.Handler())
+	return &http.Server{
+		Addr:      s.addr,
+		Handler:   mux,
+		TLSConfig: s.tlsConfig,
+	}
 }
 
 // handleWebSocket handles WebSocket connections
 func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
-	conn, err := s.upgrader.Upgrade(w, r, nil)
+	ws, err := s.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		http.Error(w, "failed to upgrade connection", http.StatusBadRequest)
 		return
 	}
-	defer func() { _ = conn.Close() }()
+	defer func() { _ = ws.Close() }()
+
+	if s.readLimit > 0 {
+		ws.SetReadLimit(s.readLimit)
+	}
+	if s.compressionLevel != 0 {
+		_ = ws.SetCompressionLevel(s.compressionLevel)
+	}
+
+	conn := newConn(r.Context(), ws)
+	info := server.ConnInfoFromRequest(r)
+	info.SessionID = conn.ID
+	conn.ctx = server.ContextWithConnInfo(conn.ctx, info)
+	s.register(conn)
+	defer s.unregister(conn)
 
-	ctx := r.Context()
+	ctx := conn.Context()
 
 	for {
-		messageType, message, err := conn.ReadMessage()
+		messageType, message, err := ws.ReadMessage()
 		if err != nil {
 			// Check if it's an unexpected close error (could be logged)
 			_ = websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure)
@@ -206,11 +467,11 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		if err != nil {
 			// Send error response
 			errMsg := []byte(fmt.Sprintf(`{"error": "%s"}`, err.Error()))
-			_ = conn.WriteMessage(websocket.TextMessage, errMsg)
+			_ = conn.Send(errMsg)
 			continue
 		}
 
-		if err := conn.WriteMessage(websocket.TextMessage, response); err != nil {
+		if err := conn.Send(response); err != nil {
 			break
 		}
 	}
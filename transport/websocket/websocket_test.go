@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/jmcarbo/fullmcp/discovery"
+	"golang.org/x/oauth2"
 )
 
 func TestNew(t *testing.T) {
@@ -345,3 +347,218 @@ func TestReadAfterPartialBuffer(t *testing.T) {
 		t.Errorf("expected %s, got %s", testMsg, result)
 	}
 }
+
+func TestServerWithAllowedOrigins(t *testing.T) {
+	handler := func(ctx context.Context, msg []byte) ([]byte, error) {
+		return msg, nil
+	}
+
+	server := NewServer(":0", handler).
+		WithAllowedOrigins("https://app.example.com", "https://*.staging.example.com").
+		WithOriginCheck(func(origin string) bool {
+			return origin == "http://localhost:3000"
+		})
+
+	httpServer := httptest.NewServer(http.HandlerFunc(server.handleWebSocket))
+	defer httpServer.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(httpServer.URL, "http")
+
+	tests := []struct {
+		origin  string
+		allowed bool
+	}{
+		{"https://app.example.com", true},
+		{"https://api.staging.example.com", true},
+		{"http://localhost:3000", true},
+		{"https://evil.com", false},
+	}
+
+	for _, tt := range tests {
+		transport := New(wsURL, WithHeaders(http.Header{"Origin": []string{tt.origin}}))
+		conn, err := transport.Connect(context.Background())
+		if err == nil {
+			_ = conn.Close()
+		}
+		if (err == nil) != tt.allowed {
+			t.Errorf("origin %q: expected allowed=%v, got err=%v", tt.origin, tt.allowed, err)
+		}
+	}
+}
+
+func TestTransport_BearerTokenAndHeaderFunc(t *testing.T) {
+	handler := func(ctx context.Context, msg []byte) ([]byte, error) {
+		return msg, nil
+	}
+	server := NewServer(":0", handler)
+
+	headers := make(chan http.Header, 1)
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers <- r.Header.Clone()
+		server.handleWebSocket(w, r)
+	}))
+	defer httpServer.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(httpServer.URL, "http")
+	transport := New(wsURL,
+		WithBearerToken(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "secret"})),
+		WithHeaderFunc(func(req *http.Request) {
+			req.Header.Set("X-Trace-Id", "trace-1")
+		}),
+	)
+
+	conn, err := transport.Connect(context.Background())
+	if err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+	_ = conn.Close()
+
+	got := <-headers
+	if got.Get("Authorization") != "Bearer secret" {
+		t.Errorf("expected bearer token, got %q", got.Get("Authorization"))
+	}
+	if got.Get("X-Trace-Id") != "trace-1" {
+		t.Errorf("expected trace header, got %q", got.Get("X-Trace-Id"))
+	}
+}
+
+func TestTLSSubprotocolAndMiddleware(t *testing.T) {
+	handler := func(ctx context.Context, msg []byte) ([]byte, error) {
+		return msg, nil
+	}
+
+	requireKey := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-API-Key") != "key-1" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	server := NewServer(":0", handler).WithMiddleware(requireKey)
+	httpServer := httptest.NewTLSServer(server.Handler())
+	defer httpServer.Close()
+
+	wsURL := "wss" + strings.TrimPrefix(httpServer.URL, "https")
+	tlsConfig := httpServer.Client().Transport.(*http.Transport).TLSClientConfig
+
+	// Missing API key is rejected by the middleware
+	if _, err := New(wsURL, WithTLSConfig(tlsConfig)).Connect(context.Background()); err == nil {
+		t.Fatal("expected handshake without API key to fail")
+	}
+
+	transport := New(wsURL,
+		WithTLSConfig(tlsConfig),
+		WithAPIKey("key-1"),
+		WithSubprotocols(Subprotocol),
+	)
+	conn, err := transport.Connect(context.Background())
+	if err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if got := transport.Subprotocol(); got != Subprotocol {
+		t.Errorf("expected subprotocol %q, got %q", Subprotocol, got)
+	}
+
+	msg := []byte(`{"jsonrpc":"2.0","method":"ping","id":1}`)
+	if _, err := conn.Write(msg); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	buf := make([]byte, 1024)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if string(buf[:n]) != string(msg) {
+		t.Errorf("expected echo %s, got %s", msg, buf[:n])
+	}
+
+	if websocket.DefaultDialer.TLSClientConfig != nil || websocket.DefaultDialer.Subprotocols != nil {
+		t.Error("options must not modify the default dialer")
+	}
+}
+
+func TestCompression(t *testing.T) {
+	handler := func(ctx context.Context, msg []byte) ([]byte, error) {
+		return msg, nil
+	}
+	server := NewServer(":0", handler).WithCompressionLevel(9)
+	httpServer := httptest.NewServer(http.HandlerFunc(server.handleWebSocket))
+	defer httpServer.Close()
+	wsURL := "ws" + strings.TrimPrefix(httpServer.URL, "http")
+
+	// The server accepts the permessage-deflate offer
+	dialer := &websocket.Dialer{EnableCompression: true}
+	raw, resp, err := dialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	_ = raw.Close()
+	if ext := resp.Header.Get("Sec-Websocket-Extensions"); !strings.Contains(ext, "permessage-deflate") {
+		t.Errorf("expected permessage-deflate to be negotiated, got %q", ext)
+	}
+
+	conn, err := New(wsURL, WithCompression()).Connect(context.Background())
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	testMsg := []byte(`{"jsonrpc":"2.0","method":"tools/list","data":"` + strings.Repeat("schema", 2000) + `","id":1}`)
+	if _, err := conn.Write(testMsg); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	var response []byte
+	buf := make([]byte, 4096)
+	for len(response) < len(testMsg) {
+		n, err := conn.Read(buf)
+		if err != nil {
+			t.Fatalf("failed to read: %v", err)
+		}
+		response = append(response, buf[:n]...)
+	}
+	if string(response) != string(testMsg) {
+		t.Errorf("compressed message mismatch: expected %d bytes, got %d bytes", len(testMsg), len(response))
+	}
+
+	// Without an offer the connection is uncompressed
+	raw, resp, err = websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	_ = raw.Close()
+	if ext := resp.Header.Get("Sec-Websocket-Extensions"); ext != "" {
+		t.Errorf("expected no extensions without an offer, got %q", ext)
+	}
+}
+
+func TestServer_Discovery(t *testing.T) {
+	handler := func(ctx context.Context, msg []byte) ([]byte, error) {
+		return msg, nil
+	}
+	rejectAll := func(http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		})
+	}
+
+	server := NewServer(":0", handler).
+		WithMiddleware(rejectAll).
+		WithDiscovery(&discovery.Document{Name: "inventory"})
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	// Clients read the document before they have credentials
+	doc, err := discovery.Fetch(context.Background(), httpServer.URL, nil)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	want := "ws://" + strings.TrimPrefix(httpServer.URL, "http://") + "/"
+	if len(doc.Endpoints) != 1 || doc.Endpoints[0].URL != want {
+		t.Errorf("expected endpoint %s, got %+v", want, doc.Endpoints)
+	}
+}